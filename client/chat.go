@@ -0,0 +1,98 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/client/connection"
+)
+
+// Chat channels
+const (
+	ChannelGlobal  = "global"
+	ChannelRoom    = "room"
+	ChannelPrivate = "private"
+)
+
+// ChatMessage is something said in one of the chats
+type ChatMessage struct {
+	Channel string // ChannelGlobal, ChannelRoom or ChannelPrivate
+	Room    string // Building room of a room chat message, e.g. "1240"
+	From    string
+	To      string // Who a private message is for
+	Text    string
+	Time    time.Time
+}
+
+// chatKey identifies a message within a channel's history
+type chatKey struct {
+	username  string
+	message   string
+	timestamp int64
+}
+
+// chatLog remembers the last message delivered from each channel. The server sends the
+// recent history of a chat every time, so this is how new messages are told apart.
+type chatLog struct {
+	mu   sync.Mutex
+	last map[string]chatKey // By "global" or building room number
+}
+
+// unseen returns the messages in a channel's history after the last one delivered, and
+// remembers the newest. A history that doesn't contain the last one delivered has moved
+// on past it, so everything newer than it is unseen.
+func (l *chatLog) unseen(channel string, history []chatKey) []chatKey {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(history) == 0 {
+		return nil
+	}
+
+	last, seen := l.last[channel]
+	l.last[channel] = history[len(history)-1]
+	if !seen {
+		return history
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i] == last {
+			return history[i+1:]
+		}
+	}
+	for i, key := range history {
+		if key.timestamp > last.timestamp {
+			return history[i:]
+		}
+	}
+	return nil
+}
+
+// OnChat calls f with every new message in global chat, the chats of building rooms the
+// user is in, and private messages to or from them, and returns a function that stops
+// it. The recent history the server sends on joining counts as new.
+func (c *Client) OnChat(f func(ChatMessage)) (unsubscribe func()) {
+	log := &chatLog{last: make(map[string]chatKey)}
+	return c.mgr.Subscribe(func(event connection.Event) {
+		switch e := event.(type) {
+		case connection.GlobalChatMessagesEvent:
+			history := make([]chatKey, len(e.Messages))
+			for i, msg := range e.Messages {
+				history[i] = chatKey{msg.Username, msg.Message, msg.Timestamp}
+			}
+			for _, key := range log.unseen(ChannelGlobal, history) {
+				f(ChatMessage{Channel: ChannelGlobal, From: key.username, Text: key.message, Time: time.Unix(key.timestamp, 0)})
+			}
+
+		case connection.RoomChatMessagesEvent:
+			history := make([]chatKey, len(e.Messages))
+			for i, msg := range e.Messages {
+				history[i] = chatKey{msg.Username, msg.Message, msg.Timestamp}
+			}
+			for _, key := range log.unseen(e.RoomNumber, history) {
+				f(ChatMessage{Channel: ChannelRoom, Room: e.RoomNumber, From: key.username, Text: key.message, Time: time.Unix(key.timestamp, 0)})
+			}
+
+		case connection.PrivateChatMessageEvent:
+			f(ChatMessage{Channel: ChannelPrivate, From: e.FromUsername, To: e.ToUsername, Text: e.Message, Time: time.Unix(e.Timestamp, 0)})
+		}
+	})
+}
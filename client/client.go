@@ -0,0 +1,240 @@
+// Package client is a headless client for an Always at Morg server, for writing bots,
+// bridges to other chat services, or UIs other than the terminal one. It speaks to the
+// server through the same connection code as the game client, without its UI.
+//
+//	c, err := client.Dial(ctx, "ws://localhost:8080/ws")
+//	if err != nil { ... }
+//	defer c.Close()
+//	c.OnChat(func(m client.ChatMessage) { log.Printf("%s: %s", m.From, m.Text) })
+//	if err := c.Join(ctx, client.JoinOptions{Username: "robot"}); err != nil { ... }
+//	c.Chat("hello from a robot")
+//
+// Callbacks run on the connection's goroutines and shouldn't block for long.
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/always-at-morg/internal/client/connection"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// DefaultRoom is the room joined when JoinOptions.Room is empty
+const DefaultRoom = protocol.DefaultRoomID
+
+// Client is a connection to a server, playing as one user once it has joined a room
+type Client struct {
+	mgr *connection.Manager
+
+	mu       sync.Mutex
+	username string // Set by Join
+}
+
+// JoinOptions say which room to join and who as
+type JoinOptions struct {
+	Room     string // Empty joins DefaultRoom
+	Password string // For a private room
+	Username string
+	Avatar   []int // Head, torso and legs, each 0-5, for a user who hasn't picked one yet; nil picks at random
+}
+
+// Player is someone in the room
+type Player struct {
+	Username string
+	X, Y     int
+	Avatar   []int  // Head, torso and legs
+	Status   string // What they've said they're up to
+	Idle     bool
+}
+
+// State is the room as of the server's last tick
+type State struct {
+	Tick      int64
+	TimeOfDay string // "day", "evening" or "night" in Madison
+	Players   map[string]Player
+}
+
+// ServerError is an error the server sent back
+type ServerError struct {
+	Code    string // Machine-readable reason, if the server gave one, e.g. "room_full"
+	Message string
+}
+
+func (e *ServerError) Error() string {
+	return e.Message
+}
+
+// Dial connects to a server's WebSocket URL, e.g. ws://localhost:8080/ws. Join a room
+// next.
+func Dial(ctx context.Context, serverURL string) (*Client, error) {
+	c := &Client{mgr: connection.NewManager(serverURL)}
+	if err := c.mgr.Connect(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close disconnects from the server
+func (c *Client) Close() {
+	c.mgr.Disconnect()
+}
+
+// Join joins a room and waits until the server has put the user in it, ctx ends, or the
+// server refuses, with a *ServerError saying why. A new user is onboarded with
+// opts.Avatar.
+func (c *Client) Join(ctx context.Context, opts JoinOptions) error {
+	if opts.Username == "" {
+		return errors.New("a username is needed to join")
+	}
+	if opts.Room == "" {
+		opts.Room = DefaultRoom
+	}
+	c.mu.Lock()
+	c.username = opts.Username
+	c.mu.Unlock()
+
+	result := make(chan error, 1)
+	done := func(err error) {
+		select {
+		case result <- err:
+		default:
+		}
+	}
+	unsubscribe := c.mgr.Subscribe(func(event connection.Event) {
+		switch e := event.(type) {
+		case connection.OnboardRequestEvent:
+			avatar := opts.Avatar
+			if avatar == nil {
+				avatar = []int{rand.Intn(6), rand.Intn(6), rand.Intn(6)}
+			}
+			c.mgr.SendOnboardResponse(opts.Username, avatar)
+		case connection.GameStateEvent:
+			if _, ok := c.mgr.GetState().Players[opts.Username]; ok {
+				done(nil)
+			}
+		case connection.ErrorEvent:
+			done(&ServerError{Code: e.Code, Message: e.Message})
+		case connection.DisconnectedEvent:
+			done(disconnectError(e))
+		}
+	})
+	defer unsubscribe()
+
+	if err := c.mgr.JoinRoom(opts.Room, opts.Username, opts.Password); err != nil {
+		return fmt.Errorf("joining %s: %w", opts.Room, err)
+	}
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Username returns who the client joined as, or "" before Join
+func (c *Client) Username() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.username
+}
+
+// Move walks the user to a tile. The server turns down moves into walls or other
+// players' personal space, and the user stays put.
+func (c *Client) Move(x, y int) error {
+	return c.mgr.SendPlayerMove(c.Username(), x, y)
+}
+
+// Step moves the user by dx, dy tiles from where they are
+func (c *Client) Step(dx, dy int) error {
+	me, ok := c.Me()
+	if !ok {
+		return errors.New("not in a room")
+	}
+	return c.Move(me.X+dx, me.Y+dy)
+}
+
+// Chat says something in global chat. "/answer <guess>" guesses the riddle, as it does
+// in the game's chat box.
+func (c *Client) Chat(text string) error {
+	return c.mgr.ProcessChatInput(c.Username(), text)
+}
+
+// RoomChat says something to the players in a building room, e.g. "1240"
+func (c *Client) RoomChat(room, text string) error {
+	return c.mgr.SendRoomChat(c.Username(), room, text)
+}
+
+// Whisper sends a private message to another player
+func (c *Client) Whisper(to, text string) error {
+	return c.mgr.SendPrivateChat(c.Username(), to, text)
+}
+
+// Guess answers the treasure hunt riddle, or with the option's number, a trivia
+// question
+func (c *Client) Guess(answer string) error {
+	return c.mgr.SendTreasureHuntGuess(answer)
+}
+
+// State returns the room as of the last tick
+func (c *Client) State() State {
+	return convertState(c.mgr.GetState())
+}
+
+// Me returns the user's own player, and false if they aren't in a room
+func (c *Client) Me() (Player, bool) {
+	player, ok := c.mgr.GetState().Players[c.Username()]
+	if !ok {
+		return Player{}, false
+	}
+	return convertPlayer(player), true
+}
+
+// OnState calls f with the room after every tick, and returns a function that stops it
+func (c *Client) OnState(f func(State)) (unsubscribe func()) {
+	return connection.SubscribeTo(c.mgr, func(connection.GameStateEvent) {
+		f(c.State())
+	})
+}
+
+// OnDisconnect calls f when the connection to the server is lost or closed, and returns
+// a function that stops it
+func (c *Client) OnDisconnect(f func(err error)) (unsubscribe func()) {
+	return connection.SubscribeTo(c.mgr, func(e connection.DisconnectedEvent) {
+		f(disconnectError(e))
+	})
+}
+
+func disconnectError(e connection.DisconnectedEvent) error {
+	if e.Error != nil {
+		return e.Error
+	}
+	return errors.New("disconnected")
+}
+
+func convertState(state *protocol.GameState) State {
+	players := make(map[string]Player, len(state.Players))
+	for username, player := range state.Players {
+		players[username] = convertPlayer(player)
+	}
+	return State{Tick: state.Tick, TimeOfDay: state.TimeOfDay, Players: players}
+}
+
+func convertPlayer(player protocol.Player) Player {
+	ys, xs, _ := strings.Cut(player.Pos, ":")
+	y, _ := strconv.Atoi(ys)
+	x, _ := strconv.Atoi(xs)
+	return Player{
+		Username: player.Username,
+		X:        x,
+		Y:        y,
+		Avatar:   player.Avatar,
+		Status:   player.Status,
+		Idle:     player.Idle,
+	}
+}
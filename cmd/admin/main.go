@@ -0,0 +1,201 @@
+// Command admin runs a server's admin API from the command line, with the admin token
+// from -token or $MORG_ADMIN_TOKEN.
+//
+//	go build -o morg-admin ./cmd/admin
+//	morg-admin rooms
+//	morg-admin announce "Pizza in the lounge at 6"
+//	morg-admin -room <id> kick <username>
+//	morg-admin riddle            # The current and next riddle, answers included
+//	morg-admin riddle skip       # Or start, stop, void
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+	"github.com/yourusername/always-at-morg/internal/server"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), `Usage: %s [flags] <command>
+
+Commands:
+  rooms                      List the rooms with their players and settings
+  announce <message>         Make a server-wide announcement
+  kick <username>            Disconnect a player from -room and keep them out for 5 minutes
+  riddle                     Show -room's current and next treasure hunt riddle
+  riddle start|stop|skip|void  Control -room's treasure hunt
+
+Flags:
+`, os.Args[0])
+		flag.PrintDefaults()
+	}
+	serverURL := flag.String("server", "http://localhost:8080", "Server to manage")
+	token := flag.String("token", os.Getenv("MORG_ADMIN_TOKEN"), "The server's admin token (default $MORG_ADMIN_TOKEN)")
+	room := flag.String("room", protocol.DefaultRoomID, "Room to kick from or run the treasure hunt of")
+	flag.Parse()
+
+	if *token == "" {
+		fail("No admin token; pass -token or set MORG_ADMIN_TOKEN")
+	}
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	a := &adminClient{
+		base:   strings.TrimSuffix(*serverURL, "/"),
+		token:  *token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+
+	args := flag.Args()
+	var err error
+	switch {
+	case args[0] == "rooms" && len(args) == 1:
+		err = a.rooms()
+	case args[0] == "announce" && len(args) > 1:
+		err = a.post("/admin/announce", nil, strings.Join(args[1:], " "))
+	case args[0] == "kick" && len(args) == 2:
+		err = a.post("/admin/kick", url.Values{"room": {*room}, "user": {args[1]}}, "")
+	case args[0] == "riddle" && len(args) == 1:
+		err = a.riddles(*room)
+	case args[0] == "riddle" && len(args) == 2:
+		err = a.post("/admin/hunt", url.Values{"room": {*room}, "action": {args[1]}}, "")
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fail(err.Error())
+	}
+}
+
+func fail(message string) {
+	fmt.Fprintln(os.Stderr, message)
+	os.Exit(1)
+}
+
+// adminClient makes requests to the server's admin API
+type adminClient struct {
+	base   string
+	token  string
+	client *http.Client
+}
+
+// rooms prints the rooms as a table
+func (a *adminClient) rooms() error {
+	var rooms []protocol.RoomInfo
+	if err := a.get("/admin/rooms", nil, &rooms); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tOWNER\tPLAYERS\tCAPACITY\tPASSWORD")
+	for _, room := range rooms {
+		capacity := "-"
+		if room.Capacity > 0 {
+			capacity = strconv.Itoa(room.Capacity)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", room.ID, room.Name, or(room.Owner, "-"), room.Players, capacity, yesNo(room.HasPassword))
+	}
+	return w.Flush()
+}
+
+// riddles prints a room's current and next riddle as a table
+func (a *adminClient) riddles(room string) error {
+	var riddles struct {
+		Current *server.GeminiRiddle `json:"current"`
+		Next    *server.GeminiRiddle `json:"next"`
+	}
+	if err := a.get("/admin/riddle", url.Values{"room": {room}}, &riddles); err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tQUESTION\tANSWER\tHINT\tCATEGORY\tDIFFICULTY")
+	for _, row := range []struct {
+		name   string
+		riddle *server.GeminiRiddle
+	}{{"current", riddles.Current}, {"next", riddles.Next}} {
+		if row.riddle == nil {
+			fmt.Fprintf(w, "%s\t-\n", row.name)
+			continue
+		}
+		r := row.riddle
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", row.name, oneLine(r.Question), r.Answer, or(oneLine(r.Hint), "-"), r.Category, r.Difficulty)
+	}
+	return w.Flush()
+}
+
+// get reads a JSON answer from the admin API
+func (a *adminClient) get(path string, query url.Values, v any) error {
+	resp, err := a.do(http.MethodGet, path, query, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// post sends a command to the admin API and prints what it answers
+func (a *adminClient) post(path string, query url.Values, body string) error {
+	resp, err := a.do(http.MethodPost, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, err = io.Copy(os.Stdout, resp.Body)
+	return err
+}
+
+// do makes a request with the admin token, turning an answer other than 200 OK into an
+// error with the server's reason
+func (a *adminClient) do(method, path string, query url.Values, body string) (*http.Response, error) {
+	u := a.base + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		reason, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(reason)))
+	}
+	return resp, nil
+}
+
+// oneLine keeps a table row on one line
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func or(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -6,22 +6,71 @@ import (
 	"io"
 	"log"
 	"os"
+	"slices"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yourusername/always-at-morg/internal/client/config"
 	"github.com/yourusername/always-at-morg/internal/client/ui"
+	"github.com/yourusername/always-at-morg/internal/protocol"
 )
 
 func main() {
-	serverURL := flag.String("server", "ws://join.always-at-morg.bid/ws", "WebSocket server URL")
-	screen := flag.String("screen", "", "Screen to display (for testing): loading, username, avatar, game")
+	serverURL := flag.String("server", "", "WebSocket server URL (default: the config file's server, or "+config.DefaultServer+")")
+	screen := flag.String("screen", "", "Screen to display (for testing): loading, username, lobby, avatar, game")
 	debug := flag.Bool("debug", false, "Enable debug mode")
+	join := flag.String("join", "", "Invite link (morg://join/CODE) to join a friend's room on their server")
+	proxy := flag.String("proxy", "", "Proxy to connect through (http://host:port or socks5://host:port); defaults to HTTP_PROXY/HTTPS_PROXY")
+	replay := flag.String("replay", "", "Play back a session journal recorded by the server (.morg) instead of connecting")
+	follow := flag.String("follow", "", "Player the camera follows in a -replay (default: the first one in the room)")
+	ascii := flag.Bool("ascii", ui.DetectASCII(), "Draw with ASCII characters only, for terminals that can't show the avatars and symbols (default: on when the locale isn't UTF-8)")
+	colors := flag.String("colors", "auto", "Colors the terminal shows: truecolor, 256, 16 or none (default: worked out from COLORTERM and TERM)")
+	theme := flag.String("theme", "", "Color theme for this session: "+strings.Join(ui.ThemeNames(), ", ")+" (default: the one picked in the settings)")
+	username := flag.String("username", "", "Username to start with (default: the one last played as)")
+	screenReader := flag.Bool("screen-reader", false, "Describe the game in text instead of drawing the map, for screen readers (default: the settings' choice)")
+	room := flag.String("room", "", "Room to join once the username is in, skipping the lobby (default: the config file's room)")
 	flag.Parse()
 
-	// Allow positional argument as server URL (for backwards compatibility)
+	// Allow positional argument as server URL (for backwards compatibility), or an invite link
 	if flag.NArg() > 0 {
-		url := flag.Arg(0)
-		serverURL = &url
+		arg := flag.Arg(0)
+		if strings.HasPrefix(arg, protocol.InvitePrefix) {
+			join = &arg
+		} else {
+			serverURL = &arg
+		}
+	}
+
+	// Profile and settings from the config file; without one the defaults are used and
+	// saved there once changed. Flags override them for this session only.
+	cfg, configPath := config.Default(), ""
+	if path, err := config.DefaultPath(); err == nil {
+		configPath = path
+		if cfg, err = config.Load(path); err != nil {
+			fmt.Printf("Ignoring config file: %v\n", err)
+		}
+	}
+	if *serverURL != "" {
+		cfg.Profile.Server = *serverURL
+	}
+	if *username != "" {
+		cfg.Profile.Username = *username
+	}
+	if *room != "" {
+		cfg.Profile.Room = *room
 	}
+	if *theme != "" {
+		if !slices.Contains(ui.ThemeNames(), *theme) {
+			fmt.Printf("Unknown theme %q; pick one of %s\n", *theme, strings.Join(ui.ThemeNames(), ", "))
+			os.Exit(1)
+		}
+		cfg.Settings.Theme = *theme
+	}
+	if *screenReader {
+		cfg.Settings.ScreenReader = true
+	}
+	// Before the model is made, so its first messages are in the player's language
+	ui.SetLanguage(cfg.Settings.Language)
 
 	if *debug {
 		fmt.Println("Debug mode enabled")
@@ -41,24 +90,66 @@ func main() {
 			viewState = ui.ViewLoading
 		case "username":
 			viewState = ui.ViewUsernameEntry
+		case "lobby":
+			viewState = ui.ViewLobby
 		case "avatar":
 			viewState = ui.ViewAvatarCustomization
 		case "game":
 			viewState = ui.ViewMainGame
 		default:
 			fmt.Printf("Unknown screen: %s\n", *screen)
-			fmt.Println("Valid screens: loading, username, avatar, game")
+			fmt.Println("Valid screens: loading, username, lobby, avatar, game")
 			os.Exit(1)
 		}
 		model = ui.NewModelWithView(viewState)
+	} else if *replay != "" {
+		journal, err := readJournal(*replay)
+		if err != nil {
+			fmt.Printf("Can't replay %s: %v\n", *replay, err)
+			os.Exit(1)
+		}
+		model, err = ui.NewReplayModel(journal, *follow)
+		if err != nil {
+			fmt.Printf("Can't replay %s: %v\n", *replay, err)
+			os.Exit(1)
+		}
+	} else if *join != "" {
+		// Connect to the invite's server and go straight to its room
+		invite, err := protocol.ParseInvite(*join)
+		if err != nil {
+			fmt.Printf("Can't join: %v\n", err)
+			os.Exit(1)
+		}
+		model = ui.NewModelWithInvite(invite)
 	} else {
 		// Normal flow: start with loading screen and connect to server
-		model = ui.NewModel(*serverURL)
+		model = ui.NewModel(cfg.Profile.Server)
+	}
+
+	model.SetConfig(configPath, cfg)
+	ui.SetASCII(*ascii)
+	if *colors == "auto" {
+		ui.SetColorProfile(ui.DetectColorProfile())
+	} else if profile, ok := ui.ColorProfileNamed(*colors); ok {
+		ui.SetColorProfile(profile)
+	} else {
+		fmt.Printf("Unknown -colors %q; pick truecolor, 256, 16 or none\n", *colors)
+		os.Exit(1)
+	}
+
+	if *proxy != "" {
+		if err := model.SetProxy(*proxy); err != nil {
+			fmt.Printf("Can't use proxy: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
-	// Run Bubble Tea
+	// Run Bubble Tea, with the terminal telling us when it gains and loses focus so
+	// desktop notifications can wait until the player looks away
+	fmt.Print(ui.EnableFocusReports)
 	p := tea.NewProgram(model, tea.WithAltScreen())
 	finalModel, err := p.Run()
+	fmt.Print(ui.DisableFocusReports)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -68,3 +159,13 @@ func main() {
 		m.Disconnect()
 	}
 }
+
+// readJournal loads a session journal file
+func readJournal(path string) ([]protocol.JournalEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return protocol.ReadJournal(f)
+}
@@ -0,0 +1,106 @@
+// Command heatmap draws a room's tile visit counts, as exported by GET /admin/heatmap,
+// over the map so it's easy to see which parts of the building get used.
+//
+//	curl -H "Authorization: Bearer $MORG_ADMIN_TOKEN" "localhost:8080/admin/heatmap?room=default-room" | heatmap > heat.txt
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/yourusername/always-at-morg/internal/gamemap"
+	"github.com/yourusername/always-at-morg/internal/server"
+)
+
+// heatRamp goes from the least to the most visited tiles
+var heatRamp = []rune("░▒▓█")
+
+// ANSI colors for each step of heatRamp when -color is set, from blue to red
+var heatColors = []string{"\x1b[34m", "\x1b[36m", "\x1b[33m", "\x1b[31m"}
+
+func main() {
+	mapPath := flag.String("map", "internal/gamemap/map.txt", "Map file to draw the heatmap over")
+	in := flag.String("in", "-", "Heatmap JSON from /admin/heatmap (- reads stdin)")
+	color := flag.Bool("color", false, "Color the heatmap with ANSI escapes")
+	flag.Parse()
+
+	mapText, err := os.ReadFile(*mapPath)
+	if err != nil {
+		log.Fatalf("Error reading map: %v", err)
+	}
+	heat, err := readHeatmap(*in)
+	if err != nil {
+		log.Fatalf("Error reading heatmap: %v", err)
+	}
+
+	fmt.Print(render(string(mapText), heat, *color))
+	fmt.Fprintf(os.Stderr, "%d tiles visited over %d samples since %s\n",
+		len(heat.Tiles), heat.Samples, heat.Since.Format("2006-01-02 15:04"))
+}
+
+func readHeatmap(path string) (server.Heatmap, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return server.Heatmap{}, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var heat server.Heatmap
+	err := json.NewDecoder(r).Decode(&heat)
+	return heat, err
+}
+
+// render draws the map with each visited tile replaced by a shade of heatRamp. Counts
+// are log-scaled so a few crowded spots don't wash out everything else.
+func render(mapText string, heat server.Heatmap, color bool) string {
+	grid := make([][]rune, gamemap.Height)
+	lines := strings.Split(mapText, "\n")
+	for y := range grid {
+		grid[y] = []rune(strings.Repeat(" ", gamemap.Width))
+		if y < len(lines) {
+			for x, char := range strings.TrimRight(lines[y], "\r") {
+				if x < gamemap.Width {
+					grid[y][x] = char
+				}
+			}
+		}
+	}
+
+	most := 0
+	counts := make(map[[2]int]int, len(heat.Tiles))
+	for _, tile := range heat.Tiles {
+		counts[[2]int{tile.X, tile.Y}] = tile.Count
+		most = max(most, tile.Count)
+	}
+
+	var b strings.Builder
+	for y, row := range grid {
+		for x, char := range row {
+			count := counts[[2]int{x, y}]
+			if count == 0 {
+				b.WriteRune(char)
+				continue
+			}
+			step := int(math.Log1p(float64(count)) / math.Log1p(float64(most)) * float64(len(heatRamp)-1))
+			if color {
+				b.WriteString(heatColors[step])
+			}
+			b.WriteRune(heatRamp[step])
+			if color {
+				b.WriteString("\x1b[0m")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,375 @@
+// Command loadtest puts a crowd of bots on a server to see how it holds up. The bots
+// connect over the ramp, then step and chat faster and faster until the test ends, while
+// the command reports their ping round trips, how often room state reaches them, what
+// they lost, and, given the admin token, how the server's ticks and slow-client
+// handling coped.
+//
+//	loadtest -server ws://localhost:8080/ws -bots 200 -ramp 1m -duration 5m
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/bot"
+	"github.com/yourusername/always-at-morg/internal/client/connection"
+	"github.com/yourusername/always-at-morg/internal/server"
+)
+
+// options are the command line flags
+type options struct {
+	server     string
+	room       string
+	password   string
+	bots       int
+	prefix     string
+	ramp       time.Duration
+	duration   time.Duration
+	moveFrom   time.Duration
+	moveTo     time.Duration
+	chatFrom   time.Duration
+	chatTo     time.Duration
+	guessEvery time.Duration
+	report     time.Duration
+	adminToken string
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.server, "server", "ws://localhost:8080/ws", "WebSocket URL of the server to load")
+	flag.StringVar(&opts.room, "room", "", "Room the bots join (default the default room)")
+	flag.StringVar(&opts.password, "password", "", "Password of a private room")
+	flag.IntVar(&opts.bots, "bots", 50, "Bots to connect")
+	flag.StringVar(&opts.prefix, "prefix", "loadbot", "Bot usernames are this followed by a number")
+	flag.DurationVar(&opts.ramp, "ramp", 30*time.Second, "Time over which the bots connect")
+	flag.DurationVar(&opts.duration, "duration", 2*time.Minute, "How long the test runs, including the ramp")
+	flag.DurationVar(&opts.moveFrom, "move-from", time.Second, "How often each bot steps at the start")
+	flag.DurationVar(&opts.moveTo, "move-to", 100*time.Millisecond, "How often each bot steps by the end")
+	flag.DurationVar(&opts.chatFrom, "chat-from", 30*time.Second, "How often each bot chats at the start (0 never)")
+	flag.DurationVar(&opts.chatTo, "chat-to", 5*time.Second, "How often each bot chats by the end (0 never)")
+	flag.DurationVar(&opts.guessEvery, "guess", 20*time.Second, "How often each bot guesses the riddle (0 never)")
+	flag.DurationVar(&opts.report, "report", 10*time.Second, "How often to print progress")
+	flag.StringVar(&opts.adminToken, "admin-token", os.Getenv("MORG_ADMIN_TOKEN"), "Admin token for the server's tick and backpressure counters (default $MORG_ADMIN_TOKEN, empty skips them)")
+	flag.Parse()
+
+	if opts.bots < 1 {
+		log.Fatal("-bots must be at least 1")
+	}
+	if opts.duration <= 0 || opts.ramp < 0 || opts.ramp > opts.duration {
+		log.Fatal("-duration must be positive and no shorter than -ramp")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	ctx, cancelRun := context.WithTimeout(ctx, opts.duration)
+	defer cancelRun()
+
+	var admin *adminClient
+	if opts.adminToken != "" {
+		var err error
+		if admin, err = newAdminClient(opts.server, opts.adminToken); err != nil {
+			log.Fatalf("Error finding the admin API: %v", err)
+		}
+	}
+	before, err := admin.snapshot()
+	if err != nil {
+		log.Printf("Skipping server counters: %v", err)
+		admin = nil
+	}
+
+	run(ctx, opts, admin, before)
+}
+
+// run connects the bots, speeds them up and reports until ctx ends
+func run(ctx context.Context, opts options, admin *adminClient, before serverStats) {
+	st := newStats()
+	start := time.Now()
+	var (
+		mu   sync.Mutex
+		bots []*bot.Bot
+		wg   sync.WaitGroup
+	)
+
+	// Connect the bots over the ramp
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		spacing := opts.ramp / time.Duration(opts.bots)
+		for i := range opts.bots {
+			if i > 0 && spacing > 0 {
+				select {
+				case <-time.After(spacing):
+				case <-ctx.Done():
+					return
+				}
+			}
+			move, chat := pace(opts, time.Since(start))
+			b := bot.New(bot.Config{
+				Server:     opts.server,
+				Room:       opts.room,
+				Password:   opts.password,
+				Username:   fmt.Sprintf("%s%d", opts.prefix, i+1),
+				MoveEvery:  move,
+				ChatEvery:  chat,
+				GuessEvery: opts.guessEvery,
+			})
+			st.watch(b)
+			mu.Lock()
+			bots = append(bots, b)
+			mu.Unlock()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := b.Run(ctx); err != nil {
+					st.failures.Add(1)
+					log.Printf("%s: %v", b.Username(), err)
+				}
+			}()
+		}
+	}()
+
+	report := time.NewTicker(opts.report)
+	defer report.Stop()
+	repace := time.NewTicker(time.Second)
+	defer repace.Stop()
+	last := before
+	lastMove, lastChat := pace(opts, 0)
+
+	for done := false; !done; {
+		select {
+		case <-ctx.Done():
+			done = true
+
+		case <-repace.C:
+			move, chat := pace(opts, time.Since(start))
+			// Only nudge the bots when the pace has moved noticeably, as changing it
+			// restarts their timers
+			if !closeTo(move, lastMove) || !closeTo(chat, lastChat) {
+				lastMove, lastChat = move, chat
+				mu.Lock()
+				for _, b := range bots {
+					b.SetPace(move, chat)
+				}
+				mu.Unlock()
+			}
+
+		case <-report.C:
+			now, err := admin.snapshot()
+			if err != nil {
+				log.Printf("Error reading server counters: %v", err)
+			}
+			fmt.Printf("%6s  %s  move every %s  %s\n",
+				time.Since(start).Round(time.Second), st.line(), lastMove.Round(time.Millisecond), now.since(last, admin != nil && err == nil))
+			if err == nil {
+				last = now
+			}
+		}
+	}
+
+	wg.Wait()
+	after, err := admin.snapshot()
+	if err != nil {
+		log.Printf("Error reading server counters: %v", err)
+	}
+	fmt.Printf("\n%d bots over %s\n", opts.bots, time.Since(start).Round(time.Second))
+	fmt.Println(st.summary())
+	if admin != nil && err == nil {
+		fmt.Println("Server: " + after.since(before, true))
+		fmt.Printf("Server ticks: slowest %s, average %s\n", after.ticks.SlowestTick, after.ticks.AverageTick)
+	}
+}
+
+// pace works out how often the bots step and chat at a point in the test, moving evenly
+// from the -from to the -to intervals over its duration
+func pace(opts options, elapsed time.Duration) (move, chat time.Duration) {
+	f := min(float64(elapsed)/float64(opts.duration), 1)
+	lerp := func(from, to time.Duration) time.Duration {
+		if from == 0 || to == 0 {
+			return 0
+		}
+		return from + time.Duration(f*float64(to-from))
+	}
+	return lerp(opts.moveFrom, opts.moveTo), lerp(opts.chatFrom, opts.chatTo)
+}
+
+// closeTo reports whether two intervals are within 5% of each other
+func closeTo(a, b time.Duration) bool {
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff*20 <= max(a, b)
+}
+
+// stats collects what the bots see
+type stats struct {
+	connected atomic.Int64
+	failures  atomic.Int64
+	dropped   atomic.Int64 // Messages the bots' outboxes threw away
+	errors    atomic.Int64 // Error messages from the server, such as refused moves
+
+	mu        sync.Mutex
+	rtts      []time.Duration // Since the last report
+	allRTTs   []time.Duration
+	gaps      []time.Duration // Between room states, since the last report
+	allGaps   []time.Duration
+	states    int64
+	lastState map[*connection.Manager]time.Time
+}
+
+func newStats() *stats {
+	return &stats{lastState: make(map[*connection.Manager]time.Time)}
+}
+
+// watch counts a bot's events
+func (s *stats) watch(b *bot.Bot) {
+	mgr := b.Manager()
+	connection.SubscribeTo(mgr, func(connection.ConnectedEvent) {
+		s.connected.Add(1)
+	})
+	connection.SubscribeTo(mgr, func(connection.DisconnectedEvent) {
+		s.connected.Add(-1)
+	})
+	connection.SubscribeTo(mgr, func(e connection.LatencyEvent) {
+		s.mu.Lock()
+		s.rtts = append(s.rtts, e.RTT)
+		s.allRTTs = append(s.allRTTs, e.RTT)
+		s.mu.Unlock()
+	})
+	connection.SubscribeTo(mgr, func(connection.GameStateEvent) {
+		now := time.Now()
+		s.mu.Lock()
+		if last, ok := s.lastState[mgr]; ok {
+			s.gaps = append(s.gaps, now.Sub(last))
+			s.allGaps = append(s.allGaps, now.Sub(last))
+		}
+		s.lastState[mgr] = now
+		s.states++
+		s.mu.Unlock()
+	})
+	connection.SubscribeTo(mgr, func(e connection.MessagesDroppedEvent) {
+		s.dropped.Add(int64(e.Count))
+	})
+	connection.SubscribeTo(mgr, func(connection.ErrorEvent) {
+		s.errors.Add(1)
+	})
+}
+
+// line sums up the bots since the last report and starts the next one
+func (s *stats) line() string {
+	s.mu.Lock()
+	rtts, gaps := s.rtts, s.gaps
+	s.rtts, s.gaps = nil, nil
+	s.mu.Unlock()
+	return fmt.Sprintf("%d connected  %d failed  rtt %s  state gap %s  %d dropped  %d errors",
+		s.connected.Load(), s.failures.Load(), percentiles(rtts), percentiles(gaps), s.dropped.Load(), s.errors.Load())
+}
+
+// summary sums up the bots over the whole test
+func (s *stats) summary() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fmt.Sprintf("Ping round trip: %s\nBetween room states: %s\n%d room states received, %d bots failed, %d queued messages dropped, %d errors from the server",
+		percentiles(s.allRTTs), percentiles(s.allGaps), s.states, s.failures.Load(), s.dropped.Load(), s.errors.Load())
+}
+
+// percentiles formats the p50, p90, p99 and largest of a set of durations
+func percentiles(ds []time.Duration) string {
+	if len(ds) == 0 {
+		return "-"
+	}
+	sorted := slices.Clone(ds)
+	slices.Sort(sorted)
+	at := func(p float64) time.Duration {
+		return sorted[int(p*float64(len(sorted)-1))].Round(100 * time.Microsecond)
+	}
+	return fmt.Sprintf("p50 %s p90 %s p99 %s max %s", at(0.5), at(0.9), at(0.99), sorted[len(sorted)-1].Round(100*time.Microsecond))
+}
+
+// adminClient reads the server's counters from its admin API
+type adminClient struct {
+	base   string
+	token  string
+	client *http.Client
+}
+
+// serverStats are the server's counters at one moment
+type serverStats struct {
+	backpressure server.BackpressureStats
+	ticks        server.TickStats
+}
+
+// newAdminClient finds the admin API on the same host as the WebSocket URL
+func newAdminClient(wsURL, token string) (*adminClient, error) {
+	u, err := url.Parse(wsURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	default:
+		return nil, fmt.Errorf("%s isn't a ws:// or wss:// URL", wsURL)
+	}
+	u.Path, u.RawQuery = "", ""
+	return &adminClient{base: u.String(), token: token, client: &http.Client{Timeout: 5 * time.Second}}, nil
+}
+
+// snapshot reads the counters; a nil client reads nothing
+func (a *adminClient) snapshot() (serverStats, error) {
+	var st serverStats
+	if a == nil {
+		return st, nil
+	}
+	if err := a.get("/admin/backpressure", &st.backpressure); err != nil {
+		return st, err
+	}
+	err := a.get("/admin/ticks", &st.ticks)
+	return st, err
+}
+
+func (a *adminClient) get(path string, v any) error {
+	req, err := http.NewRequest(http.MethodGet, a.base+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %s", path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// since formats how the counters moved from an earlier snapshot, or nothing if they
+// weren't read
+func (s serverStats) since(earlier serverStats, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("ticks %d (%d overran)  states dropped %d  messages dropped %d  lag disconnects %d",
+		s.ticks.Ticks-earlier.ticks.Ticks,
+		s.ticks.Overruns-earlier.ticks.Overruns,
+		s.backpressure.StatesDropped-earlier.backpressure.StatesDropped,
+		s.backpressure.MessagesDropped-earlier.backpressure.MessagesDropped,
+		s.backpressure.LagDisconnects-earlier.backpressure.LagDisconnects)
+}
@@ -1,23 +1,112 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata" // Madison time for the day/night cycle, even without system zoneinfo
 
 	"github.com/yourusername/always-at-morg/internal/server"
+	"github.com/yourusername/always-at-morg/internal/webterm"
+	"google.golang.org/grpc"
 )
 
 func main() {
+	cfg := server.DefaultConfig()
 	addr := flag.String("addr", "0.0.0.0:8080", "HTTP service address")
+	debugAddr := flag.String("debug-addr", "", "Address to serve pprof and expvar on, e.g. localhost:6060 (empty disables it; keep it private)")
+	flag.DurationVar(&cfg.IdleKick, "idle-kick", cfg.IdleKick, "Disconnect clients idle this long, e.g. 30m (0 disables)")
+	flag.StringVar(&cfg.DataDir, "data-dir", cfg.DataDir, "Directory for the leaderboard and other saved data (empty keeps it in memory)")
+	flag.IntVar(&cfg.RoomCapacity, "room-capacity", cfg.RoomCapacity, "Most players a room takes (0 for no limit)")
+	flag.IntVar(&cfg.MaxRooms, "max-rooms", cfg.MaxRooms, "Most rooms the server runs at once; empty ones close after 10 minutes (0 for no limit)")
+	flag.IntVar(&cfg.PersonalSpace, "personal-space", cfg.PersonalSpace, "Tiles players must keep between each other inside rooms (avatars never overlap)")
+	flag.IntVar(&cfg.HallwayPersonalSpace, "hallway-personal-space", cfg.HallwayPersonalSpace, "Tiles players must keep between each other in hallways")
+	flag.DurationVar(&cfg.TickRate, "tick-rate", cfg.TickRate, "How often rooms send state to their players")
+	flag.DurationVar(&cfg.IdleTickRate, "idle-tick-rate", cfg.IdleTickRate, "Slowest state rate, for rooms where nobody has moved or chatted for a while, and the tick of empty rooms")
+	flag.DurationVar(&cfg.Hunt.Round, "hunt-round", cfg.Hunt.Round, "How long players have to solve each treasure hunt riddle")
+	flag.DurationVar(&cfg.Hunt.Hint, "hunt-hint", cfg.Hunt.Hint, "How far into a treasure hunt round the hint is shown")
+	flag.DurationVar(&cfg.Hunt.Cooldown, "hunt-cooldown", cfg.Hunt.Cooldown, "Break between treasure hunt rounds")
+	flag.IntVar(&cfg.Hunt.DailyRounds, "hunt-daily-rounds", cfg.Hunt.DailyRounds, "Treasure hunt riddles played each day")
+	flag.IntVar(&cfg.Hunt.TriviaEvery, "hunt-trivia-every", cfg.Hunt.TriviaEvery, "Make every Nth treasure hunt round multiple-choice trivia (0 disables)")
+	flag.StringVar(&cfg.LLM.Provider, "llm", cfg.LLM.Provider, "Riddle generator: gemini, openai, ollama or static (default picks from GEMINI_API_KEY/OPENAI_API_KEY)")
+	flag.StringVar(&cfg.LLM.Model, "llm-model", cfg.LLM.Model, "Model for the riddle generator (default depends on -llm)")
+	flag.StringVar(&cfg.LLM.URL, "llm-url", cfg.LLM.URL, "Server URL for ollama, or an OpenAI-compatible server for openai")
+	flag.StringVar(&cfg.RecordDir, "record-dir", cfg.RecordDir, "Where session journals of recorded rooms are written (empty disables recording)")
+	flag.StringVar(&cfg.Analytics.Sink, "analytics", cfg.Analytics.Sink, "Where to record analytics events: file:<path> or an http(s) URL to POST them to (empty disables them)")
+	flag.Float64Var(&cfg.Analytics.SampleRate, "analytics-sample", cfg.Analytics.SampleRate, "Fraction of players whose analytics events are recorded, 0-1")
+	flag.StringVar(&cfg.Analytics.Usernames, "analytics-usernames", cfg.Analytics.Usernames, "Usernames in analytics events: hash (salted with $MORG_ANALYTICS_SALT), omit or plain")
+	flag.BoolVar(&cfg.StrictProtocol, "strict-protocol", cfg.StrictProtocol, "Refuse client messages with unknown fields (older clients may send some)")
+	flag.Int64Var(&cfg.Seed, "seed", cfg.Seed, "Seed for spawn spots, riddle picks and other random choices, to replay a run (0 picks one and logs it)")
+	flag.StringVar(&cfg.AdminToken, "admin-token", os.Getenv("MORG_ADMIN_TOKEN"), "Bearer token for the /admin API (default $MORG_ADMIN_TOKEN, empty disables it)")
+	grpcAddr := flag.String("grpc-addr", "", "Address to serve the gRPC admin service on, e.g. localhost:9090 (empty disables it; needs -admin-token)")
+	web := flag.Bool("web", false, "Serve a browser client at /play/ that plays in an in-browser terminal, e.g. for a lab display")
 	flag.Parse()
 
-	srv := server.NewServer()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid server options: %v", err)
+	}
+
+	srv := server.NewServer(cfg)
+
+	if *debugAddr != "" {
+		go func() {
+			log.Printf("Serving pprof and expvar on %s", *debugAddr)
+			if err := http.ListenAndServe(*debugAddr, srv.DebugHandler()); err != nil {
+				log.Printf("Debug server stopped: %v", err)
+			}
+		}()
+	}
 
-	http.HandleFunc("/ws", srv.HandleWebSocket)
+	var grpcServer *grpc.Server
+	if *grpcAddr != "" {
+		if grpcServer = srv.GRPCServer(); grpcServer == nil {
+			log.Fatal("-grpc-addr needs -admin-token (or $MORG_ADMIN_TOKEN) to be set")
+		}
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			log.Fatalf("Can't serve the gRPC admin service: %v", err)
+		}
+		go func() {
+			log.Printf("Serving the gRPC admin service on %s", *grpcAddr)
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Printf("gRPC admin service stopped: %v", err)
+			}
+		}()
+	}
+
+	// Save the rooms on the way down so a deploy doesn't reset them
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	handler := srv.Handler()
+	if *web {
+		// Each browser gets a client run here, playing on this same server in-process
+		mux := http.NewServeMux()
+		mux.Handle("/", handler)
+		mux.Handle("/play/", http.StripPrefix("/play", webterm.Handler(handler)))
+		handler = mux
+	}
+	httpServer := &http.Server{Addr: *addr, Handler: handler}
+	go func() {
+		<-ctx.Done()
+		log.Printf("Shutting down, saving rooms")
+		srv.SaveState()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if grpcServer != nil {
+			grpcServer.Stop() // Metrics streams never finish on their own
+		}
+		httpServer.Shutdown(shutdownCtx)
+	}()
 
 	log.Printf("Starting server on %s", *addr)
-	if err := http.ListenAndServe(*addr, nil); err != nil {
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatal("ListenAndServe: ", err)
 	}
 }
@@ -0,0 +1,332 @@
+// Package bot runs headless players that connect like the real client does, walk the
+// map, chat and guess riddles, to fill the building for testing and demos.
+//
+//	b := bot.New(bot.Config{Server: "ws://localhost:8080/ws", Username: "bot-1"})
+//	err := b.Run(ctx)
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/client/connection"
+	"github.com/yourusername/always-at-morg/internal/gamemap"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// joinTimeout is how long a bot waits for the server to put it in its room
+const joinTimeout = 15 * time.Second
+
+// Chat lines a bot picks from when Config.Messages is empty
+var defaultMessages = []string{
+	"hi all",
+	"anyone at the coffee machine?",
+	"heading to the atrium",
+	"this riddle is tough",
+	"brb",
+	"good luck on the hunt!",
+}
+
+// Words a bot guesses riddles with; it isn't trying to win
+var guessWords = []string{"map", "echo", "time", "shadow", "candle", "piano", "river", "silence", "key", "egg"}
+
+// Config sets what a bot does. Zero intervals turn that behavior off.
+type Config struct {
+	Server   string // WebSocket URL, e.g. ws://localhost:8080/ws
+	Room     string // Room to join; empty joins protocol.DefaultRoomID
+	Password string // For a private room
+	Username string
+	Avatar   []int // Head, torso and legs; nil picks one at random
+
+	MoveEvery  time.Duration // How often the bot takes a step of its random walk
+	ChatEvery  time.Duration // How often it says something in global chat
+	GuessEvery time.Duration // How often it guesses the running riddle; trivia rounds get one answer
+
+	Messages []string // Chat lines to pick from; empty uses a built-in set
+	Seed     int64    // Seeds the bot's choices; 0 seeds from the clock
+}
+
+// Bot is one simulated player
+type Bot struct {
+	cfg Config
+	mgr *connection.Manager
+	rng *rand.Rand
+
+	// mu guards the fields below, the pace in cfg and rng
+	mu       sync.Mutex
+	heading  [2]int   // Direction of the random walk, {dx, dy}
+	riddle   string   // Riddle being played, "" between rounds
+	options  []string // Choices of a trivia round
+	round    int64    // When the round being played ends, which tells rounds apart
+	answered int64    // round of the last trivia question we answered, so we answer once
+
+	joined chan struct{}
+	failed chan error
+	repace chan struct{} // Poked by SetPace
+	once   sync.Once
+}
+
+// New creates a bot; Run connects it
+func New(cfg Config) *Bot {
+	if cfg.Room == "" {
+		cfg.Room = protocol.DefaultRoomID
+	}
+	if len(cfg.Messages) == 0 {
+		cfg.Messages = defaultMessages
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Bot{
+		cfg:    cfg,
+		mgr:    connection.NewManager(cfg.Server),
+		rng:    rand.New(rand.NewSource(seed)),
+		joined: make(chan struct{}),
+		failed: make(chan error, 1),
+		repace: make(chan struct{}, 1),
+	}
+}
+
+// Manager returns the bot's connection, to watch its events or send it other messages
+func (b *Bot) Manager() *connection.Manager {
+	return b.mgr
+}
+
+// Username returns the name the bot plays as
+func (b *Bot) Username() string {
+	return b.cfg.Username
+}
+
+// Run connects the bot, joins its room and plays until ctx is canceled or the
+// connection is lost. It returns nil when ctx ends it.
+func (b *Bot) Run(ctx context.Context) error {
+	unsubscribe := b.mgr.Subscribe(b.handleEvent)
+	defer unsubscribe()
+
+	if err := b.mgr.Connect(ctx); err != nil {
+		return fmt.Errorf("connecting: %w", err)
+	}
+	defer b.mgr.Disconnect()
+
+	if err := b.mgr.JoinRoom(b.cfg.Room, b.cfg.Username, b.cfg.Password); err != nil {
+		return fmt.Errorf("joining %s: %w", b.cfg.Room, err)
+	}
+	select {
+	case <-b.joined:
+	case err := <-b.failed:
+		return err
+	case <-time.After(joinTimeout):
+		return fmt.Errorf("the server didn't put us in %s within %s", b.cfg.Room, joinTimeout)
+	case <-ctx.Done():
+		return nil
+	}
+
+	for {
+		again, err := b.play(ctx)
+		if !again {
+			return err
+		}
+	}
+}
+
+// SetPace changes how often the running bot steps and chats; zero stops it doing that
+func (b *Bot) SetPace(moveEvery, chatEvery time.Duration) {
+	b.mu.Lock()
+	b.cfg.MoveEvery, b.cfg.ChatEvery = moveEvery, chatEvery
+	b.mu.Unlock()
+	select {
+	case b.repace <- struct{}{}:
+	default:
+	}
+}
+
+// play moves, chats and guesses at the bot's current pace until ctx is canceled or the
+// connection is lost, or until the pace changes, when it returns again set
+func (b *Bot) play(ctx context.Context) (again bool, err error) {
+	b.mu.Lock()
+	moveEvery, chatEvery, guessEvery := b.cfg.MoveEvery, b.cfg.ChatEvery, b.cfg.GuessEvery
+	b.mu.Unlock()
+
+	move, stopMove := b.ticker(moveEvery)
+	defer stopMove()
+	chat, stopChat := b.ticker(chatEvery)
+	defer stopChat()
+	guess, stopGuess := b.ticker(guessEvery)
+	defer stopGuess()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case err := <-b.failed:
+			return false, err
+		case <-b.repace:
+			return true, nil
+		case <-move:
+			b.step()
+		case <-chat:
+			b.chat()
+		case <-guess:
+			b.guess()
+		}
+	}
+}
+
+// ticker ticks every d, a little off so a crowd of bots doesn't move in lockstep; a
+// zero d never ticks
+func (b *Bot) ticker(d time.Duration) (<-chan time.Time, func()) {
+	if d <= 0 {
+		return nil, func() {}
+	}
+	b.mu.Lock()
+	jitter := time.Duration(b.rng.Int63n(int64(d)/5 + 1))
+	b.mu.Unlock()
+	t := time.NewTicker(d + jitter)
+	return t.C, t.Stop
+}
+
+// handleEvent follows what the server tells the bot
+func (b *Bot) handleEvent(event connection.Event) {
+	switch e := event.(type) {
+	case connection.OnboardRequestEvent:
+		avatar := b.cfg.Avatar
+		if avatar == nil {
+			b.mu.Lock()
+			avatar = []int{b.rng.Intn(6), b.rng.Intn(6), b.rng.Intn(6)}
+			b.mu.Unlock()
+		}
+		b.mgr.SendOnboardResponse(b.cfg.Username, avatar)
+
+	case connection.GameStateEvent:
+		if _, ok := b.mgr.GetState().Players[b.cfg.Username]; ok {
+			b.once.Do(func() { close(b.joined) })
+		}
+
+	case connection.ErrorEvent:
+		select {
+		case <-b.joined:
+			// Refused moves, rate limits and the like; the bot carries on
+		default:
+			b.fail(fmt.Errorf("joining %s: %s", b.cfg.Room, e.Message))
+		}
+
+	case connection.DisconnectedEvent:
+		err := e.Error
+		if err == nil {
+			err = errors.New("disconnected by the server")
+		}
+		b.fail(err)
+
+	case connection.TreasureHuntStateEvent:
+		b.mu.Lock()
+		b.riddle = ""
+		if e.EndsAt != 0 && !e.Completed {
+			b.riddle = e.ClueText
+		}
+		b.options = e.Options
+		b.round = e.EndsAt
+		b.mu.Unlock()
+	}
+}
+
+func (b *Bot) fail(err error) {
+	select {
+	case b.failed <- err:
+	default:
+	}
+}
+
+// step takes the next step of the bot's random walk: mostly straight on, sometimes
+// turning, and turning whenever a wall or another player is in the way
+func (b *Bot) step() {
+	state := b.mgr.GetState()
+	self, ok := state.Players[b.cfg.Username]
+	if !ok {
+		return
+	}
+	x, y := parsePos(self.Pos)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for try := 0; try < 8; try++ {
+		if b.heading == [2]int{} || b.rng.Intn(10) == 0 || try > 0 {
+			b.heading = [2]int{b.rng.Intn(3) - 1, b.rng.Intn(3) - 1}
+		}
+		nx, ny := x+b.heading[0], y+b.heading[1]
+		if b.heading != [2]int{} && b.canMoveTo(state, x, y, nx, ny) {
+			b.mgr.SendPlayerMove(b.cfg.Username, nx, ny)
+			return
+		}
+	}
+}
+
+// canMoveTo checks a step the way the server will: the avatar has to fit, and the bot
+// can't step closer into someone's personal space
+func (b *Bot) canMoveTo(state *protocol.GameState, x, y, nx, ny int) bool {
+	tiles, err := gamemap.Tiles()
+	if err != nil || !gamemap.AvatarFits(tiles, nx, ny) {
+		return false
+	}
+	hallway := gamemap.RoomNumberAt(tiles, nx, ny) == ""
+	radius := max(gamemap.AvatarReach, b.mgr.GetRoomConfig().PersonalSpaceAt(hallway))
+	for username, player := range state.Players {
+		if username == b.cfg.Username || player.Idle {
+			continue
+		}
+		ox, oy := parsePos(player.Pos)
+		to := max(abs(nx-ox), abs(ny-oy))
+		if to <= radius && to < max(abs(x-ox), abs(y-oy)) {
+			return false
+		}
+	}
+	return true
+}
+
+// chat says one of the bot's lines in global chat
+func (b *Bot) chat() {
+	b.mu.Lock()
+	line := b.cfg.Messages[b.rng.Intn(len(b.cfg.Messages))]
+	b.mu.Unlock()
+	b.mgr.SendGlobalChat(b.cfg.Username, line)
+}
+
+// guess has a go at the running riddle, or answers the trivia question once
+func (b *Bot) guess() {
+	b.mu.Lock()
+	var guess string
+	switch {
+	case len(b.options) > 0:
+		if b.round != b.answered {
+			b.answered = b.round
+			guess = strconv.Itoa(b.rng.Intn(len(b.options)) + 1)
+		}
+	case b.riddle != "":
+		guess = guessWords[b.rng.Intn(len(guessWords))]
+	}
+	b.mu.Unlock()
+
+	if guess != "" {
+		b.mgr.SendTreasureHuntGuess(guess)
+	}
+}
+
+func parsePos(pos string) (x, y int) {
+	ys, xs, _ := strings.Cut(pos, ":")
+	y, _ = strconv.Atoi(ys)
+	x, _ = strconv.Atoi(xs)
+	return x, y
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
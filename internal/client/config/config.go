@@ -0,0 +1,186 @@
+// Package config reads and writes the client's config file, which keeps the player's
+// profile and settings between sessions.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Chat notification behaviors: ring the terminal bell for every chat message from
+// someone else, for private messages and @mentions, only for private messages, or never
+const (
+	NotifyAll      = "all"
+	NotifyMentions = "mentions"
+	NotifyPrivate  = "private"
+	NotifyOff      = "off"
+)
+
+// Desktop notification behaviors: show them for private messages and new treasure hunt
+// rounds while the terminal isn't focused, whether it's focused or not, or never
+const (
+	DesktopUnfocused = "unfocused"
+	DesktopAlways    = "always"
+	DesktopOff       = "off"
+)
+
+// Movement key schemes. KeysAll is every key the client has ever moved with: arrows,
+// WASD, vim keys and the number pad.
+const (
+	KeysAll    = "all"
+	KeysWASD   = "wasd"
+	KeysArrows = "arrows"
+	KeysVim    = "vim"
+)
+
+// LanguageAuto shows the UI in the language LC_ALL, LC_MESSAGES or LANG ask for
+const LanguageAuto = "auto"
+
+// Smallest viewport caps the settings allow, so the game world stays playable
+const (
+	MinViewportWidth  = 40
+	MinViewportHeight = 20
+)
+
+// Settings are the preferences players change on the settings screen
+type Settings struct {
+	Theme             string `toml:"theme"`
+	Language          string `toml:"language"`              // LanguageAuto or a language code such as "es"
+	Timestamps        bool   `toml:"timestamps"`            // Show the time chat messages were sent
+	ChatNotifications string `toml:"chat_notifications"`    // NotifyAll, NotifyMentions, NotifyPrivate or NotifyOff
+	ToastPrivate      bool   `toml:"toast_private"`         // Pop up private messages that arrive in another chat mode
+	ToastMentions     bool   `toml:"toast_mentions"`        // Pop up @mentions that arrive in another chat mode
+	Desktop           string `toml:"desktop_notifications"` // DesktopUnfocused, DesktopAlways or DesktopOff
+	MovementKeys      string `toml:"movement_keys"`         // KeysAll, KeysWASD, KeysArrows or KeysVim
+	ScreenReader      bool   `toml:"screen_reader"`         // Describe the game in text instead of drawing the map
+	MaxViewportWidth  int    `toml:"max_viewport_width"`
+	MaxViewportHeight int    `toml:"max_viewport_height"`
+}
+
+// DefaultServer is the server the client connects to unless told otherwise
+const DefaultServer = "ws://join.always-at-morg.bid/ws"
+
+// Profile is who the player is and where they play. The client fills in the username
+// and avatar as they're picked, so the next session starts with them.
+type Profile struct {
+	Server   string `toml:"server"`   // WebSocket server URL
+	Room     string `toml:"room"`     // Room joined straight after the username screen, "" for the lobby
+	Username string `toml:"username"` // Last username played as
+	Avatar   []int  `toml:"avatar"`   // Head, torso and legs presets last picked
+}
+
+// Config is everything in the config file
+type Config struct {
+	Profile  Profile  `toml:"profile"`
+	Settings Settings `toml:"settings"`
+
+	// Keys rebinds main game actions, e.g. chat = ["enter"]. Actions it leaves out keep
+	// their default keys.
+	Keys map[string][]string `toml:"keys"`
+}
+
+// Default returns the config used when there's no config file
+func Default() Config {
+	return Config{
+		Profile: Profile{
+			Server: DefaultServer,
+		},
+		Settings: Settings{
+			Theme:             "classic",
+			Language:          LanguageAuto,
+			ChatNotifications: NotifyPrivate,
+			ToastPrivate:      true,
+			ToastMentions:     true,
+			Desktop:           DesktopUnfocused,
+			MovementKeys:      KeysAll,
+			MaxViewportWidth:  120,
+			MaxViewportHeight: 60,
+		},
+	}
+}
+
+// DefaultPath returns where the config file lives, e.g. ~/.config/morg/config.toml
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "morg", "config.toml"), nil
+}
+
+// Load reads the config file at path. Anything the file leaves out keeps its default,
+// and a missing file is the default config.
+func Load(path string) (Config, error) {
+	cfg := Default()
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Default(), nil
+		}
+		return Default(), fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	if cfg.Profile.Server == "" {
+		cfg.Profile.Server = DefaultServer
+	}
+	if cfg.Settings.Language == "" {
+		cfg.Settings.Language = LanguageAuto
+	}
+	switch cfg.Settings.ChatNotifications {
+	case NotifyAll, NotifyMentions, NotifyPrivate, NotifyOff:
+	default:
+		cfg.Settings.ChatNotifications = NotifyPrivate
+	}
+	switch cfg.Settings.Desktop {
+	case DesktopUnfocused, DesktopAlways, DesktopOff:
+	default:
+		cfg.Settings.Desktop = DesktopUnfocused
+	}
+	switch cfg.Settings.MovementKeys {
+	case KeysAll, KeysWASD, KeysArrows, KeysVim:
+	default:
+		cfg.Settings.MovementKeys = KeysAll
+	}
+	cfg.Settings.MaxViewportWidth = max(cfg.Settings.MaxViewportWidth, MinViewportWidth)
+	cfg.Settings.MaxViewportHeight = max(cfg.Settings.MaxViewportHeight, MinViewportHeight)
+	return cfg, nil
+}
+
+// Update changes the config file at path, leaving alone everything update doesn't touch.
+// Flags can override the config a session runs with, so saving that whole config would
+// keep the overrides for good.
+func Update(path string, update func(cfg *Config)) error {
+	cfg, err := Load(path)
+	if err != nil {
+		return err
+	}
+	update(&cfg)
+	return Save(path, cfg)
+}
+
+// Save writes cfg to path, creating its directory if needed. It's written to a
+// temporary file first so a crash can't leave half a config behind.
+func Save(path string, cfg Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".config-*.toml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := toml.NewEncoder(tmp).Encode(cfg); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
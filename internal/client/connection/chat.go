@@ -0,0 +1,201 @@
+package connection
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// chatLog is the chat the server has sent us. States only carry the messages posted
+// since the room's last one, so we keep the rest here and ask for the whole history
+// again when a seq is skipped, as it is when we join late or a state is dropped.
+type chatLog struct {
+	mu        sync.Mutex
+	global    []ChatMessage
+	globalSeq int64 // Seq of the newest global message we have
+	rooms     map[string][]RoomChatMessage
+	roomSeq   int64 // Seq of the newest message we have in any building room
+	fetching  bool  // Asked for the history and haven't had it yet
+}
+
+func newChatLog() *chatLog {
+	return &chatLog{rooms: make(map[string][]RoomChatMessage)}
+}
+
+// reset forgets everything, for joining a room
+func (l *chatLog) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.global, l.globalSeq = nil, 0
+	l.rooms, l.roomSeq = make(map[string][]RoomChatMessage), 0
+	l.fetching = false
+}
+
+// startFetch reports whether to ask for the history, which isn't needed if we already have
+func (l *chatLog) startFetch() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.fetching {
+		return false
+	}
+	l.fetching = true
+	return true
+}
+
+// fetchFailed lets the next gap ask for the history again
+func (l *chatLog) fetchFailed() {
+	l.mu.Lock()
+	l.fetching = false
+	l.mu.Unlock()
+}
+
+// addGlobal adds the global messages from a state and returns the whole of global chat,
+// or nil if none of them were new. It returns false if messages before them are
+// missing, unless gaps are allowed.
+func (l *chatLog) addGlobal(messages []protocol.GlobalChatPayload, allowGaps bool) ([]ChatMessage, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	messages = slices.DeleteFunc(slices.Clone(messages), func(msg protocol.GlobalChatPayload) bool {
+		return msg.Seq <= l.globalSeq
+	})
+	if len(messages) == 0 {
+		return nil, true
+	}
+	if messages[0].Seq != l.globalSeq+1 && !allowGaps {
+		return nil, false
+	}
+	for _, msg := range messages {
+		l.global = append(l.global, ChatMessage{Username: msg.Username, Message: msg.Message, Timestamp: msg.Timestamp})
+	}
+	l.globalSeq = messages[len(messages)-1].Seq
+	return slices.Clone(l.global), true
+}
+
+// setGlobal replaces global chat with the history the server sent
+func (l *chatLog) setGlobal(messages []protocol.GlobalChatPayload) []ChatMessage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.global = make([]ChatMessage, len(messages))
+	for i, msg := range messages {
+		l.global[i] = ChatMessage{Username: msg.Username, Message: msg.Message, Timestamp: msg.Timestamp}
+	}
+	if len(messages) > 0 {
+		l.globalSeq = messages[len(messages)-1].Seq
+	}
+	l.fetching = false
+	return slices.Clone(l.global)
+}
+
+// addRooms adds the room messages from a state and returns the whole chat of each
+// building room that got new ones. It returns false if messages before them are
+// missing, unless gaps are allowed.
+func (l *chatLog) addRooms(rooms map[string][]protocol.RoomChatPayload, allowGaps bool) (map[string][]RoomChatMessage, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// The seqs run across building rooms, so put them back in one line
+	var messages []protocol.RoomChatPayload
+	for roomNumber, roomMsgs := range rooms {
+		for _, msg := range roomMsgs {
+			if msg.Seq > l.roomSeq {
+				msg.RoomNumber = roomNumber
+				messages = append(messages, msg)
+			}
+		}
+	}
+	if len(messages) == 0 {
+		return nil, true
+	}
+	slices.SortFunc(messages, func(a, b protocol.RoomChatPayload) int { return cmp.Compare(a.Seq, b.Seq) })
+	if !allowGaps {
+		for i, msg := range messages {
+			if msg.Seq != l.roomSeq+int64(i)+1 {
+				return nil, false
+			}
+		}
+	}
+
+	changed := make(map[string][]RoomChatMessage)
+	for _, msg := range messages {
+		l.rooms[msg.RoomNumber] = append(l.rooms[msg.RoomNumber], RoomChatMessage{
+			RoomNumber: msg.RoomNumber,
+			Username:   msg.Username,
+			Message:    msg.Message,
+			Timestamp:  msg.Timestamp,
+		})
+		changed[msg.RoomNumber] = nil
+	}
+	l.roomSeq = messages[len(messages)-1].Seq
+	for roomNumber := range changed {
+		changed[roomNumber] = slices.Clone(l.rooms[roomNumber])
+	}
+	return changed, true
+}
+
+// setRoom replaces a building room's chat with the history the server sent
+func (l *chatLog) setRoom(payload protocol.RoomChatMessagesPayload) []RoomChatMessage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	messages := make([]RoomChatMessage, len(payload.Messages))
+	for i, msg := range payload.Messages {
+		messages[i] = RoomChatMessage{
+			RoomNumber: msg.RoomNumber,
+			Username:   msg.Username,
+			Message:    msg.Message,
+			Timestamp:  msg.Timestamp,
+		}
+	}
+	l.rooms[payload.RoomNumber] = messages
+	l.roomSeq = max(l.roomSeq, payload.Latest)
+	return slices.Clone(messages)
+}
+
+// SendChatHistoryRequest asks the server for all of global chat and our room's chat
+func (m *Manager) SendChatHistoryRequest() error {
+	return m.sendMessage(protocol.MsgChatHistoryRequest, struct{}{})
+}
+
+// fetchChatHistory asks for the chat history if we aren't already waiting on it. It
+// returns false if it can't be asked for, as when replaying a session.
+func (m *Manager) fetchChatHistory() bool {
+	if !m.chat.startFetch() {
+		return true
+	}
+	if err := m.SendChatHistoryRequest(); err != nil {
+		m.chat.fetchFailed()
+		return false
+	}
+	return true
+}
+
+// handleStateChat adds the chat a state brought, fetching the history instead if some
+// is missing
+func (m *Manager) handleStateChat(payload *protocol.KuluchifiedStatePayload) {
+	if len(payload.ChatMessages) > 0 {
+		messages, ok := m.chat.addGlobal(payload.ChatMessages, false)
+		if !ok && !m.fetchChatHistory() {
+			messages, ok = m.chat.addGlobal(payload.ChatMessages, true)
+		}
+		if ok && messages != nil {
+			m.sendEvent(GlobalChatMessagesEvent{Messages: messages})
+		}
+	}
+
+	if len(payload.RoomChatMessages) > 0 {
+		rooms, ok := m.chat.addRooms(payload.RoomChatMessages, false)
+		if !ok && !m.fetchChatHistory() {
+			rooms, ok = m.chat.addRooms(payload.RoomChatMessages, true)
+		}
+		if !ok {
+			return
+		}
+		for roomNumber, messages := range rooms {
+			m.sendEvent(RoomChatMessagesEvent{RoomNumber: roomNumber, Messages: messages})
+		}
+	}
+}
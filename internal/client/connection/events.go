@@ -1,5 +1,11 @@
 package connection
 
+import (
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
 // Event represents events from the connection manager
 type Event interface {
 	isEvent()
@@ -19,7 +25,9 @@ func (DisconnectedEvent) isEvent() {}
 
 // ErrorEvent is sent when an error occurs
 type ErrorEvent struct {
-	Message string
+	Message    string
+	Code       string // protocol.ErrCode constant, if the server sent one
+	Suggestion string // Room to try instead, with protocol.ErrCodeRoomFull
 }
 
 func (ErrorEvent) isEvent() {}
@@ -43,8 +51,16 @@ func (GlobalChatMessagesEvent) isEvent() {}
 
 // TreasureHuntStateEvent updates the UI with the current clue
 type TreasureHuntStateEvent struct {
-	ClueText  string
-	Completed bool
+	Round      int // Today's round number, from 1
+	ClueText   string
+	Hint       string // Empty until the hint is given; ClueText has it too
+	Completed  bool   // The riddle was solved
+	Category   string // Empty between riddles
+	Difficulty string
+	Points     int
+	EndsAt     int64    // Unix time the riddle's time runs out, 0 if none is being played
+	NextAt     int64    // Unix time the next riddle starts, 0 outside the cooldown
+	Options    []string // Choices of a trivia round, empty for riddles
 }
 
 func (TreasureHuntStateEvent) isEvent() {}
@@ -81,3 +97,159 @@ type RoomChatMessage struct {
 	Message    string
 	Timestamp  int64
 }
+
+// InteractResultEvent is sent when the server replies to an interaction
+type InteractResultEvent struct {
+	Object  string
+	Message string
+}
+
+func (InteractResultEvent) isEvent() {}
+
+// EmoteEvent is sent when a player in the room does something emote-worthy
+type EmoteEvent struct {
+	Username  string
+	Message   string
+	Timestamp int64
+}
+
+func (EmoteEvent) isEvent() {}
+
+// MiniGameInviteEvent is sent when another player challenges us to a game
+type MiniGameInviteEvent struct {
+	From string
+	Game string
+}
+
+func (MiniGameInviteEvent) isEvent() {}
+
+// MiniGameDeclinedEvent is sent when a player we challenged declines
+type MiniGameDeclinedEvent struct {
+	By   string
+	Game string
+}
+
+func (MiniGameDeclinedEvent) isEvent() {}
+
+// MiniGameStateEvent carries the shared board of a game we're playing
+type MiniGameStateEvent struct {
+	GameID   string
+	Game     string
+	Players  [2]string
+	Board    []string
+	Turn     string
+	Winner   string
+	Finished bool
+	Message  string
+	EndsAt   int64 // Unix time a timed game ends, 0 if it has no time limit
+}
+
+func (MiniGameStateEvent) isEvent() {}
+
+// ScavengerStateEvent carries our progress in the scavenger hunt
+type ScavengerStateEvent struct {
+	Active  bool
+	Title   string
+	Clue    string
+	Found   int
+	Total   int
+	Message string
+}
+
+func (ScavengerStateEvent) isEvent() {}
+
+// RoomListEvent carries the rooms the lobby can join, busiest first
+type RoomListEvent struct {
+	Rooms []protocol.RoomInfo
+}
+
+func (RoomListEvent) isEvent() {}
+
+// LeaderboardEvent carries the treasure hunt leaderboard we asked for
+type LeaderboardEvent struct {
+	Entries []protocol.LeaderboardEntry
+}
+
+func (LeaderboardEvent) isEvent() {}
+
+// TreasureHuntHistoryEvent carries the treasure hunt rounds our room played today
+type TreasureHuntHistoryEvent struct {
+	Rounds []protocol.TreasureHuntRoundEntry
+}
+
+func (TreasureHuntHistoryEvent) isEvent() {}
+
+// AnnouncementsEvent carries announcements as they're made, server-wide or the treasure
+// hunt's, oldest first
+type AnnouncementsEvent struct {
+	Announcements []protocol.AnnouncementPayload
+}
+
+func (AnnouncementsEvent) isEvent() {}
+
+// AnnouncementHistoryEvent carries the server-wide announcements made so far, oldest first
+type AnnouncementHistoryEvent struct {
+	Announcements []protocol.AnnouncementPayload
+}
+
+func (AnnouncementHistoryEvent) isEvent() {}
+
+// PointsEvent carries our points balance after it changed
+type PointsEvent struct {
+	Balance int
+	Delta   int
+	Reason  string
+}
+
+func (PointsEvent) isEvent() {}
+
+// LoginStreakEvent carries our login streak, sent when we join
+type LoginStreakEvent struct {
+	Streak int
+	Bonus  int    // Points for today's login, 0 if already claimed
+	Reward string // Cosmetic the streak just earned, if any
+}
+
+func (LoginStreakEvent) isEvent() {}
+
+// TreasureHuntWinnerEvent says someone, in any room, just solved a riddle
+type TreasureHuntWinnerEvent struct {
+	Winner string
+	RoomID string
+	Answer string
+	Points int
+}
+
+func (TreasureHuntWinnerEvent) isEvent() {}
+
+// ShopStateEvent carries the shop catalog and what we own and wear
+type ShopStateEvent struct {
+	Items    []protocol.ShopItem
+	Owned    []string
+	Equipped map[string]string // Kind -> item ID
+	Message  string
+}
+
+func (ShopStateEvent) isEvent() {}
+
+// IdleWarningEvent warns that the server will disconnect us for inactivity soon
+type IdleWarningEvent struct {
+	Seconds int
+}
+
+func (IdleWarningEvent) isEvent() {}
+
+// LatencyEvent carries the round trip time of our last ping to the server
+type LatencyEvent struct {
+	RTT time.Duration
+}
+
+func (LatencyEvent) isEvent() {}
+
+// MessagesDroppedEvent says messages we sent while disconnected were thrown away,
+// because too many piled up or they were too old by the time we reconnected
+type MessagesDroppedEvent struct {
+	Count int
+}
+
+func (MessagesDroppedEvent) isEvent() {}
@@ -0,0 +1,43 @@
+package connection
+
+import (
+	"context"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+const (
+	pingInterval = 2 * time.Second  // How often we measure the round trip to the server
+	readTimeout  = 5 * pingInterval // Silence from the server this long means the connection is dead
+)
+
+// pingLoop pings the server until conn is closed or replaced by a reconnect; each pong
+// comes back as a LatencyEvent. The pongs also keep readPump's read deadline from
+// running out while the server is otherwise quiet.
+func (m *Manager) pingLoop(conn *websocket.Conn) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.sendPing(conn); err != nil {
+			return
+		}
+		<-ticker.C
+	}
+}
+
+// sendPing sends a ping stamped with the time, if conn is still our connection
+func (m *Manager) sendPing(conn *websocket.Conn) error {
+	m.mu.RLock()
+	current := m.connected && m.conn == conn
+	m.mu.RUnlock()
+	if !current {
+		return websocket.ErrCloseSent
+	}
+	// A ping that can't go out before the next one is due isn't worth waiting on
+	ctx, cancel := context.WithTimeout(context.Background(), pingInterval)
+	defer cancel()
+	return m.sendMessageContext(ctx, protocol.MsgPing, protocol.PingPayload{SentAt: time.Now().UnixNano()})
+}
@@ -1,8 +1,15 @@
 package connection
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -11,16 +18,27 @@ import (
 	"github.com/yourusername/always-at-morg/internal/protocol"
 )
 
+const requestTimeout = 10 * time.Second // Longest we wait on a write to the server
+
 // Manager manages the WebSocket connection to the server
 type Manager struct {
 	serverURL         string
 	conn              *websocket.Conn
 	state             *State
-	eventCallback     func(Event)
+	subscribers       []*subscriber
 	connected         bool
 	mu                sync.RWMutex
 	done              chan struct{}
 	lastTreasureState protocol.TreasureHuntStatePayload
+	sessionToken      string          // From the last room_joined, sent back when rejoining after a lost connection
+	writeMu           sync.Mutex      // The connection takes one writer at a time, and pings write from their own goroutine
+	outbox            []queuedMessage // Sent while disconnected, waiting for us to rejoin a room
+	outboxDropped     int             // Queued messages thrown away since the last flush
+	proxy             *url.URL        // Proxy to dial through, nil to use HTTP_PROXY/HTTPS_PROXY
+	chat              *chatLog        // Chat so far, as states only bring what's new
+
+	// Makes the connection in place of the network, e.g. memnet's, nil to dial for real
+	dial func(ctx context.Context, network, addr string) (net.Conn, error)
 }
 
 // NewManager creates a new connection manager
@@ -28,25 +46,60 @@ func NewManager(serverURL string) *Manager {
 	return &Manager{
 		serverURL: serverURL,
 		state:     NewState(),
+		chat:      newChatLog(),
 		connected: false,
 		done:      make(chan struct{}),
 	}
 }
 
-// OnEvent sets the callback for events
+// OnEvent adds a callback for events; see Subscribe
 func (m *Manager) OnEvent(callback func(Event)) {
+	m.Subscribe(callback)
+}
+
+// SetProxy dials the server through an http:// or socks5:// proxy instead of the one in
+// the HTTP_PROXY/HTTPS_PROXY environment variables
+func (m *Manager) SetProxy(proxy string) error {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "socks5") || u.Host == "" {
+		return fmt.Errorf("invalid proxy URL %q: want http://host:port or socks5://host:port", proxy)
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.eventCallback = callback
+	m.proxy = u
+	return nil
+}
+
+// SetDialer makes connections to the server with dial instead of over the network, such
+// as to an in-process server through memnet. The proxy isn't used with it.
+func (m *Manager) SetDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dial = dial
 }
 
-// Connect establishes a WebSocket connection to the server
-func (m *Manager) Connect() error {
+// Connect establishes a WebSocket connection to the server, giving up when ctx is
+// canceled or its deadline passes
+func (m *Manager) Connect(ctx context.Context) error {
 	dialer := websocket.Dialer{
 		HandshakeTimeout: 10 * time.Second,
+		Proxy:            http.ProxyFromEnvironment,
+	}
+	m.mu.RLock()
+	if m.proxy != nil {
+		dialer.Proxy = http.ProxyURL(m.proxy)
+	}
+	if m.dial != nil {
+		dialer.Proxy = nil
+		dialer.NetDialContext = m.dial
 	}
+	m.mu.RUnlock()
 
-	conn, _, err := dialer.Dial(m.serverURL, nil)
+	conn, _, err := dialer.DialContext(ctx, m.serverURL, nil)
 	if err != nil {
 		m.sendEvent(DisconnectedEvent{Error: err})
 		return err
@@ -62,6 +115,7 @@ func (m *Manager) Connect() error {
 
 	// Start read/write loops
 	go m.readPump()
+	go m.pingLoop(conn)
 
 	m.sendEvent(ConnectedEvent{})
 	return nil
@@ -104,14 +158,26 @@ func (m *Manager) IsConnected() bool {
 
 //// FROM CLIENT -> SERVER MESSAGES ////
 
-// JoinRoom sends a join room request
-func (m *Manager) JoinRoom(roomID, userName string) error {
+// JoinRoom sends a join room request; password is only needed for private rooms. After
+// a lost connection it also resumes the session the server gave us last time.
+func (m *Manager) JoinRoom(roomID, userName, password string) error {
+	m.mu.RLock()
+	token := m.sessionToken
+	m.mu.RUnlock()
+
 	return m.sendMessage(protocol.MsgJoinRoom, protocol.JoinRoomPayload{
-		RoomID:   roomID,
-		Username: userName,
+		RoomID:       roomID,
+		Username:     userName,
+		Password:     password,
+		SessionToken: token,
 	})
 }
 
+// SendListRooms asks the server for the rooms we can join from the lobby
+func (m *Manager) SendListRooms() error {
+	return m.sendMessage(protocol.MsgListRooms, struct{}{})
+}
+
 func (m *Manager) SendOnboardResponse(userName string, avatar []int) error {
 	return m.sendMessage(protocol.MsgOnboard, protocol.OnboardPayload{
 		Name:   userName,
@@ -128,17 +194,21 @@ func (m *Manager) SendGlobalChat(userName, message string) error {
 	})
 }
 
-// SendChatMessage sends a private chat message to another player
-func (m *Manager) SendChatMessage(fromPlayerID, toPlayerID, message string) error {
+// SendPrivateChat sends a private chat message to another player in our room. The server
+// knows players by username, so the payload's player IDs are usernames; it echoes the
+// message back to us as a PrivateChatMessageEvent once it's delivered.
+func (m *Manager) SendPrivateChat(fromUsername, toUsername, message string) error {
 	return m.sendMessage(protocol.MsgChatMessage, protocol.ChatMessagePayload{
-		FromPlayerID: fromPlayerID,
-		ToPlayerID:   toPlayerID,
+		FromPlayerID: fromUsername,
+		ToPlayerID:   toUsername,
 		Message:      message,
 		Timestamp:    time.Now().Unix(),
 	})
 }
 
-// SendRoomChat sends a chat message to a specific room
+// SendRoomChat sends a chat message to a building room, which has to be the one the
+// player is standing in. It comes back, with everyone else's, as a RoomChatMessagesEvent
+// once the next state brings it.
 func (m *Manager) SendRoomChat(userName, roomNumber, message string) error {
 	return m.sendMessage(protocol.MsgRoomChat, protocol.RoomChatPayload{
 		RoomNumber: roomNumber,
@@ -171,21 +241,112 @@ func (m *Manager) ProcessChatInput(userName, text string) error {
 	return m.SendGlobalChat(userName, text)
 }
 
-// SendTreasureHuntGuess sends a guess to the server
+// SendTreasureHuntGuess sends a guess at the riddle, or in a trivia round the number of
+// an option ("1"-"4"). Solving a riddle also brings a TreasureHuntWinnerEvent.
 func (m *Manager) SendTreasureHuntGuess(guess string) error {
 	return m.sendMessage(protocol.MsgTreasureHuntGuess, protocol.TreasureHuntGuessPayload{
 		Guess: guess,
 	})
 }
 
-// SendPlayerMove sends a player move request
-func (m *Manager) SendPlayerMove(newX, newY int) error {
+// SendPlayerMove sends a player move request, showing the move in the state straight
+// away; the server's next state confirms or corrects it
+func (m *Manager) SendPlayerMove(userName string, newX, newY int) error {
+	seq := m.state.PredictMove(userName, newX, newY)
 	return m.sendMessage(protocol.MsgPlayerMove, protocol.PlayerMovePayload{
 		NewX: newX,
 		NewY: newY,
+		Seq:  seq,
+	})
+}
+
+// SendInteract asks the server to use the object next to the player
+func (m *Manager) SendInteract() error {
+	return m.sendMessage(protocol.MsgInteract, struct{}{})
+}
+
+// SendLeaderboardRequest asks the server for the treasure hunt leaderboard
+func (m *Manager) SendLeaderboardRequest() error {
+	return m.sendMessage(protocol.MsgLeaderboardRequest, struct{}{})
+}
+
+// SendTreasureHuntHistoryRequest asks the server for the treasure hunt rounds played today
+func (m *Manager) SendTreasureHuntHistoryRequest() error {
+	return m.sendMessage(protocol.MsgTreasureHuntHistoryRequest, struct{}{})
+}
+
+// SendAnnouncementHistoryRequest asks the server for the announcements made before we joined
+func (m *Manager) SendAnnouncementHistoryRequest() error {
+	return m.sendMessage(protocol.MsgAnnouncementHistoryRequest, struct{}{})
+}
+
+// SendShopRequest asks the server for the cosmetics shop
+func (m *Manager) SendShopRequest() error {
+	return m.sendMessage(protocol.MsgShopRequest, struct{}{})
+}
+
+// SendShopBuy buys a shop item with our points
+func (m *Manager) SendShopBuy(itemID string) error {
+	return m.sendMessage(protocol.MsgShopBuy, protocol.ShopItemPayload{ItemID: itemID})
+}
+
+// SendShopEquip wears an owned shop item, or takes it off if we're wearing it
+func (m *Manager) SendShopEquip(itemID string) error {
+	return m.sendMessage(protocol.MsgShopEquip, protocol.ShopItemPayload{ItemID: itemID})
+}
+
+// SendMiniGameChallenge challenges a nearby player to a mini-game
+func (m *Manager) SendMiniGameChallenge(target, game string) error {
+	return m.sendMessage(protocol.MsgMiniGameChallenge, protocol.MiniGameChallengePayload{
+		Target: target,
+		Game:   game,
+	})
+}
+
+// SendMiniGameResponse accepts or declines a challenge
+func (m *Manager) SendMiniGameResponse(from string, accept bool) error {
+	return m.sendMessage(protocol.MsgMiniGameRespond, protocol.MiniGameRespondPayload{
+		From:   from,
+		Accept: accept,
 	})
 }
 
+// SendMiniGameMove makes a move in a running mini-game
+func (m *Manager) SendMiniGameMove(gameID, move string) error {
+	return m.sendMessage(protocol.MsgMiniGameMove, protocol.MiniGameMovePayload{
+		GameID: gameID,
+		Move:   move,
+	})
+}
+
+// SendStatus sets the player's status message; an empty status clears it
+func (m *Manager) SendStatus(status string) error {
+	return m.sendMessage(protocol.MsgSetStatus, protocol.SetStatusPayload{Status: status})
+}
+
+// SendRoomModerate asks the server to run an owner or moderator action on our room
+func (m *Manager) SendRoomModerate(payload protocol.RoomModeratePayload) error {
+	return m.sendMessage(protocol.MsgRoomModerate, payload)
+}
+
+// SendGameMode starts, joins or leaves a room game mode
+func (m *Manager) SendGameMode(mode, action string) error {
+	return m.sendMessage(protocol.MsgGameMode, protocol.GameModePayload{
+		Mode:   mode,
+		Action: action,
+	})
+}
+
+// SendPomodoro starts (or stops) the shared pomodoro timer in the player's room
+func (m *Manager) SendPomodoro(stop bool) error {
+	return m.sendMessage(protocol.MsgPomodoro, protocol.PomodoroPayload{Stop: stop})
+}
+
+// SendScavenger starts the scavenger hunt, asks for our clue, or claims it where we stand
+func (m *Manager) SendScavenger(action string) error {
+	return m.sendMessage(protocol.MsgScavenger, protocol.ScavengerPayload{Action: action})
+}
+
 ////////////////////////////////////////////
 
 // GetState returns the current game state
@@ -193,8 +354,59 @@ func (m *Manager) GetState() *protocol.GameState {
 	return m.state.GetState()
 }
 
-// sendMessage sends a message to the server
+// GetRoomConfig returns the rules of the current room, such as its personal space
+func (m *Manager) GetRoomConfig() protocol.RoomConfig {
+	return m.state.GetRoomConfig()
+}
+
+// GetObjects returns the interactive objects in the current room
+func (m *Manager) GetObjects() []protocol.MapObject {
+	return m.state.GetObjects()
+}
+
+// GetGameMode returns the state of the room's game mode, or nil if none is running
+func (m *Manager) GetGameMode() *protocol.GameModeState {
+	return m.state.GetGameMode()
+}
+
+// GetOccupancy returns how many players are in each building room
+func (m *Manager) GetOccupancy() map[string]int {
+	return m.state.GetOccupancy()
+}
+
+// GetPomodoro returns the pomodoro timer running in a building room, if any
+func (m *Manager) GetPomodoro(roomNumber string) (protocol.PomodoroState, bool) {
+	return m.state.GetPomodoro(roomNumber)
+}
+
+// sendMessage sends a message to the server. While we're disconnected it's queued
+// instead, and sent once we're back in a room.
 func (m *Manager) sendMessage(msgType protocol.MessageType, payload interface{}) error {
+	return m.sendMessageContext(context.Background(), msgType, payload)
+}
+
+// sendMessageContext is sendMessage, giving up on the write when ctx is done. A message
+// given up on isn't queued.
+func (m *Manager) sendMessageContext(ctx context.Context, msgType protocol.MessageType, payload interface{}) error {
+	msg, err := protocol.EncodeMessage(msgType, payload)
+	if err != nil {
+		return err
+	}
+
+	if err = m.write(ctx, msg); err != nil && ctx.Err() == nil && queueable(msgType) {
+		m.enqueue(queuedMessage{data: msg, queuedAt: time.Now()})
+		return nil
+	}
+	return err
+}
+
+// write sends an encoded message on the current connection, within requestTimeout or
+// ctx's deadline, whichever comes first
+func (m *Manager) write(ctx context.Context, msg []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -202,13 +414,27 @@ func (m *Manager) sendMessage(msgType protocol.MessageType, payload interface{})
 		return websocket.ErrCloseSent
 	}
 
-	msg, err := protocol.EncodeMessage(msgType, payload)
-	if err != nil {
-		return err
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+
+	deadline := time.Now().Add(requestTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
 	}
+	m.conn.SetWriteDeadline(deadline)
 
-	m.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
-	return m.conn.WriteMessage(websocket.TextMessage, msg)
+	// Canceling ctx cuts a write that's stuck on a slow connection short
+	conn := m.conn
+	stop := context.AfterFunc(ctx, func() { conn.SetWriteDeadline(time.Now()) })
+	defer stop()
+
+	if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
 }
 
 // readPump reads messages from the WebSocket connection
@@ -228,19 +454,46 @@ func (m *Manager) readPump() {
 		case <-m.done:
 			return
 		default:
+			// A half-open connection never errors on its own, so give up on a server that
+			// stopped answering our pings
+			m.conn.SetReadDeadline(time.Now().Add(readTimeout))
 			_, message, err := m.conn.ReadMessage()
 			if err != nil {
-				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					log.Printf("Server stopped responding for %v, disconnecting", readTimeout)
+				} else if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 					log.Printf("WebSocket error: %v", err)
 				}
 				return
 			}
 
-			m.handleMessage(message)
+			// The server packs messages queued up together into one frame, a line each
+			for _, line := range bytes.Split(message, []byte{'\n'}) {
+				if len(line) > 0 {
+					m.handleMessage(line)
+				}
+			}
 		}
 	}
 }
 
+// treasureHuntEvent turns the treasure hunt's state into the event the UI gets
+func treasureHuntEvent(payload protocol.TreasureHuntStatePayload) TreasureHuntStateEvent {
+	return TreasureHuntStateEvent{
+		Round:      payload.CurrentClueIndex,
+		ClueText:   payload.ClueText,
+		Hint:       payload.Hint,
+		Completed:  payload.Completed,
+		Category:   payload.Category,
+		Difficulty: payload.Difficulty,
+		Points:     payload.Points,
+		EndsAt:     payload.RoundEndsAt,
+		NextAt:     payload.NextRoundAt,
+		Options:    payload.Options,
+	}
+}
+
 // handleMessage processes incoming messages
 func (m *Manager) handleMessage(data []byte) {
 	msg, err := protocol.DecodeMessage(data)
@@ -256,8 +509,19 @@ func (m *Manager) handleMessage(data []byte) {
 			log.Printf("Error unmarshaling room joined: %v", err)
 			return
 		}
+		m.mu.Lock()
+		m.sessionToken = payload.SessionToken
+		m.mu.Unlock()
 		m.state.UpdateState(payload.GameState)
+		m.state.SetObjects(payload.Objects)
+		m.state.SetRoomConfig(payload.Config)
 		m.sendEvent(GameStateEvent{})
+		m.flushOutbox()
+		// States only bring chat posted from now on
+		m.chat.reset()
+		m.fetchChatHistory()
+		// Nor the announcements made before we got here
+		m.SendAnnouncementHistoryRequest()
 		log.Printf("Joined room %s as player %s", payload.RoomID, payload.PlayerID)
 
 	case protocol.MsgError:
@@ -266,7 +530,7 @@ func (m *Manager) handleMessage(data []byte) {
 			log.Printf("Error unmarshaling error payload: %v", err)
 			return
 		}
-		m.sendEvent(ErrorEvent{Message: payload.Message})
+		m.sendEvent(ErrorEvent{Message: payload.Message, Code: payload.Code, Suggestion: payload.Suggestion})
 		log.Printf("Server error: %s", payload.Message)
 
 	case protocol.MsgOnboardRequest:
@@ -282,6 +546,14 @@ func (m *Manager) handleMessage(data []byte) {
 		m.sendEvent(GameStateEvent{})
 		// log.Printf("Received game state update (tick: %d)", payload.Tick)
 
+	case protocol.MsgOccupancy:
+		var payload protocol.OccupancyPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling occupancy: %v", err)
+			return
+		}
+		m.state.SetOccupancy(payload.Rooms)
+
 	case protocol.MsgKuluchifiedState:
 		// Unified per-tick state update - parse and split into separate events
 		var payload protocol.KuluchifiedStatePayload
@@ -290,41 +562,16 @@ func (m *Manager) handleMessage(data []byte) {
 			return
 		}
 
-		// Update game state
+		// Update game state; older servers only sent the players alongside it
+		if payload.GameState.Players == nil {
+			payload.GameState.Players = payload.Players
+		}
 		m.state.UpdateState(&payload.GameState)
+		m.state.SetPomodoros(payload.Pomodoros)
+		m.state.SetGameMode(payload.GameMode)
 		m.sendEvent(GameStateEvent{})
 
-		// Send chat messages event
-		if len(payload.ChatMessages) > 0 {
-			messages := make([]ChatMessage, len(payload.ChatMessages))
-			for i, msg := range payload.ChatMessages {
-				messages[i] = ChatMessage{
-					Username:  msg.Username,
-					Message:   msg.Message,
-					Timestamp: msg.Timestamp,
-				}
-			}
-			m.sendEvent(GlobalChatMessagesEvent{Messages: messages})
-		}
-
-		// Send room chat messages events
-		if len(payload.RoomChatMessages) > 0 {
-			for roomNumber, roomMsgs := range payload.RoomChatMessages {
-				messages := make([]RoomChatMessage, len(roomMsgs))
-				for i, msg := range roomMsgs {
-					messages[i] = RoomChatMessage{
-						RoomNumber: msg.RoomNumber,
-						Username:   msg.Username,
-						Message:    msg.Message,
-						Timestamp:  msg.Timestamp,
-					}
-				}
-				m.sendEvent(RoomChatMessagesEvent{
-					RoomNumber: roomNumber,
-					Messages:   messages,
-				})
-			}
-		}
+		m.handleStateChat(&payload)
 
 		// Update treasure hunt state
 		// If payload has data, update our cache
@@ -334,13 +581,13 @@ func (m *Manager) handleMessage(data []byte) {
 
 		// Always dispatch the current known state if we have one
 		if m.lastTreasureState.ClueText != "" || m.lastTreasureState.Completed {
-			m.sendEvent(TreasureHuntStateEvent{
-				ClueText:  m.lastTreasureState.ClueText,
-				Completed: m.lastTreasureState.Completed,
-			})
+			m.sendEvent(treasureHuntEvent(m.lastTreasureState))
 		}
 
-		// TODO: Handle announcements and players when needed
+		// Older servers send announcements with the state rather than on their own
+		if len(payload.Announcements) > 0 {
+			m.sendEvent(AnnouncementsEvent{Announcements: payload.Announcements})
+		}
 
 	case protocol.MsgGlobalChatMessages:
 		var payload protocol.GlobalChatMessagesPayload
@@ -349,18 +596,8 @@ func (m *Manager) handleMessage(data []byte) {
 			return
 		}
 
-		// Convert protocol messages to event messages
-		messages := make([]ChatMessage, len(payload.Messages))
-		for i, msg := range payload.Messages {
-			messages[i] = ChatMessage{
-				Username:  msg.Username,
-				Message:   msg.Message,
-				Timestamp: msg.Timestamp,
-			}
-		}
-
-		m.sendEvent(GlobalChatMessagesEvent{Messages: messages})
-		// log.Printf("Received %d global chat messages", len(messages))
+		// The whole history, so it replaces what we had
+		m.sendEvent(GlobalChatMessagesEvent{Messages: m.chat.setGlobal(payload.Messages)})
 
 	case protocol.MsgRoomChatMessages:
 		var payload protocol.RoomChatMessagesPayload
@@ -369,20 +606,9 @@ func (m *Manager) handleMessage(data []byte) {
 			return
 		}
 
-		// Convert protocol messages to event messages
-		messages := make([]RoomChatMessage, len(payload.Messages))
-		for i, msg := range payload.Messages {
-			messages[i] = RoomChatMessage{
-				RoomNumber: msg.RoomNumber,
-				Username:   msg.Username,
-				Message:    msg.Message,
-				Timestamp:  msg.Timestamp,
-			}
-		}
-
 		m.sendEvent(RoomChatMessagesEvent{
 			RoomNumber: payload.RoomNumber,
-			Messages:   messages,
+			Messages:   m.chat.setRoom(payload),
 		})
 
 	case protocol.MsgTreasureHuntState:
@@ -396,10 +622,7 @@ func (m *Manager) handleMessage(data []byte) {
 		// Update cache
 		m.lastTreasureState = payload
 		
-		m.sendEvent(TreasureHuntStateEvent{
-			ClueText:  payload.ClueText,
-			Completed: payload.Completed,
-		})
+		m.sendEvent(treasureHuntEvent(payload))
 
 	case protocol.MsgChatMessage:
 		var payload protocol.ChatMessagePayload
@@ -415,6 +638,206 @@ func (m *Manager) handleMessage(data []byte) {
 			Timestamp:    payload.Timestamp,
 		})
 
+	case protocol.MsgInteractResult:
+		var payload protocol.InteractResultPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling interact result: %v", err)
+			return
+		}
+
+		m.sendEvent(InteractResultEvent{
+			Object:  payload.Object,
+			Message: payload.Message,
+		})
+
+	case protocol.MsgPong:
+		var payload protocol.PingPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling pong: %v", err)
+			return
+		}
+		m.sendEvent(LatencyEvent{RTT: time.Since(time.Unix(0, payload.SentAt))})
+
+	case protocol.MsgIdleWarning:
+		var payload protocol.IdleWarningPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling idle warning: %v", err)
+			return
+		}
+
+		m.sendEvent(IdleWarningEvent{Seconds: payload.Seconds})
+
+	case protocol.MsgEmote:
+		var payload protocol.EmotePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling emote: %v", err)
+			return
+		}
+
+		m.sendEvent(EmoteEvent{
+			Username:  payload.Username,
+			Message:   payload.Message,
+			Timestamp: payload.Timestamp,
+		})
+
+	case protocol.MsgMiniGameInvite:
+		var payload protocol.MiniGameInvitePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling mini-game invite: %v", err)
+			return
+		}
+
+		m.sendEvent(MiniGameInviteEvent{
+			From: payload.From,
+			Game: payload.Game,
+		})
+
+	case protocol.MsgMiniGameDeclined:
+		var payload protocol.MiniGameDeclinedPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling mini-game decline: %v", err)
+			return
+		}
+
+		m.sendEvent(MiniGameDeclinedEvent{
+			By:   payload.By,
+			Game: payload.Game,
+		})
+
+	case protocol.MsgMiniGameState:
+		var payload protocol.MiniGameStatePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling mini-game state: %v", err)
+			return
+		}
+
+		m.sendEvent(MiniGameStateEvent{
+			GameID:   payload.GameID,
+			Game:     payload.Game,
+			Players:  payload.Players,
+			Board:    payload.Board,
+			Turn:     payload.Turn,
+			Winner:   payload.Winner,
+			Finished: payload.Finished,
+			Message:  payload.Message,
+			EndsAt:   payload.EndsAt,
+		})
+
+	case protocol.MsgPoints:
+		var payload protocol.PointsPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling points: %v", err)
+			return
+		}
+
+		m.sendEvent(PointsEvent{
+			Balance: payload.Balance,
+			Delta:   payload.Delta,
+			Reason:  payload.Reason,
+		})
+
+	case protocol.MsgLoginStreak:
+		var payload protocol.LoginStreakPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling login streak: %v", err)
+			return
+		}
+
+		m.sendEvent(LoginStreakEvent{
+			Streak: payload.Streak,
+			Bonus:  payload.Bonus,
+			Reward: payload.Reward,
+		})
+
+	case protocol.MsgTreasureHuntWinner:
+		var payload protocol.TreasureHuntWinnerPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling treasure hunt winner: %v", err)
+			return
+		}
+
+		m.sendEvent(TreasureHuntWinnerEvent{
+			Winner: payload.Winner,
+			RoomID: payload.RoomID,
+			Answer: payload.Answer,
+			Points: payload.Points,
+		})
+
+	case protocol.MsgShopState:
+		var payload protocol.ShopStatePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling shop state: %v", err)
+			return
+		}
+
+		m.sendEvent(ShopStateEvent{
+			Items:    payload.Items,
+			Owned:    payload.Owned,
+			Equipped: payload.Equipped,
+			Message:  payload.Message,
+		})
+
+	case protocol.MsgRoomList:
+		var payload protocol.RoomListPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling room list: %v", err)
+			return
+		}
+
+		m.sendEvent(RoomListEvent{Rooms: payload.Rooms})
+
+	case protocol.MsgLeaderboardResponse:
+		var payload protocol.LeaderboardResponsePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling leaderboard: %v", err)
+			return
+		}
+
+		m.sendEvent(LeaderboardEvent{Entries: payload.Entries})
+
+	case protocol.MsgTreasureHuntHistory:
+		var payload protocol.TreasureHuntHistoryPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling treasure hunt history: %v", err)
+			return
+		}
+
+		m.sendEvent(TreasureHuntHistoryEvent{Rounds: payload.Rounds})
+
+	case protocol.MsgAnnouncement:
+		var payload protocol.AnnouncementPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling announcement: %v", err)
+			return
+		}
+
+		m.sendEvent(AnnouncementsEvent{Announcements: []protocol.AnnouncementPayload{payload}})
+
+	case protocol.MsgAnnouncementHistory:
+		var payload protocol.AnnouncementHistoryPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling announcement history: %v", err)
+			return
+		}
+
+		m.sendEvent(AnnouncementHistoryEvent{Announcements: payload.Announcements})
+
+	case protocol.MsgScavengerState:
+		var payload protocol.ScavengerStatePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling scavenger state: %v", err)
+			return
+		}
+
+		m.sendEvent(ScavengerStateEvent{
+			Active:  payload.Active,
+			Title:   payload.Title,
+			Clue:    payload.Clue,
+			Found:   payload.Found,
+			Total:   payload.Total,
+			Message: payload.Message,
+		})
+
 	default:
 		log.Printf("Unhandled message type: %s", msg.Type)
 	}
@@ -423,10 +846,10 @@ func (m *Manager) handleMessage(data []byte) {
 // sendEvent sends an event to the callback if set
 func (m *Manager) sendEvent(event Event) {
 	m.mu.RLock()
-	callback := m.eventCallback
+	subscribers := append([]*subscriber(nil), m.subscribers...)
 	m.mu.RUnlock()
 
-	if callback != nil {
-		callback(event)
+	for _, sub := range subscribers {
+		sub.callback(event)
 	}
 }
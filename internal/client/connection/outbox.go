@@ -0,0 +1,76 @@
+package connection
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+const (
+	maxOutbox    = 50               // Messages kept while disconnected; the oldest go first
+	maxOutboxAge = 30 * time.Second // Older queued messages are stale by the time we're back
+)
+
+// queuedMessage is an encoded message waiting for the connection to come back
+type queuedMessage struct {
+	data     []byte
+	queuedAt time.Time
+}
+
+// queueable reports whether a message is worth sending late. Setting up the session
+// (joining, onboarding, the lobby, fetching chat) happens again after a reconnect
+// anyway, and a late ping would only measure the outage.
+func queueable(msgType protocol.MessageType) bool {
+	switch msgType {
+	case protocol.MsgJoinRoom, protocol.MsgListRooms, protocol.MsgOnboard, protocol.MsgPing, protocol.MsgChatHistoryRequest:
+		return false
+	}
+	return true
+}
+
+// enqueue holds on to a message we couldn't send, making room by dropping the oldest
+func (m *Manager) enqueue(q queuedMessage) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.outbox) >= maxOutbox {
+		m.outbox = m.outbox[1:]
+		m.outboxDropped++
+	}
+	m.outbox = append(m.outbox, q)
+}
+
+// flushOutbox sends what was queued while we were disconnected, now that we're back in
+// a room, and tells the UI about any messages that were thrown away
+func (m *Manager) flushOutbox() {
+	m.mu.Lock()
+	queued := m.outbox
+	dropped := m.outboxDropped
+	m.outbox = nil
+	m.outboxDropped = 0
+	m.mu.Unlock()
+
+	for i, q := range queued {
+		if time.Since(q.queuedAt) > maxOutboxAge {
+			dropped++
+			continue
+		}
+		if err := m.write(context.Background(), q.data); err != nil {
+			// Lost the connection again; keep the rest for the next time
+			for _, rest := range queued[i:] {
+				m.enqueue(rest)
+			}
+			m.mu.Lock()
+			m.outboxDropped += dropped
+			m.mu.Unlock()
+			return
+		}
+	}
+
+	if dropped > 0 {
+		log.Printf("Dropped %d messages queued while disconnected", dropped)
+		m.sendEvent(MessagesDroppedEvent{Count: dropped})
+	}
+}
@@ -0,0 +1,231 @@
+package connection
+
+import (
+	"errors"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// Replay speeds, as multiples of the recorded pace
+const (
+	minReplaySpeed = 0.25
+	maxReplaySpeed = 16
+)
+
+// replaySnapshots are the messages that carry the whole of something, so seeking only
+// needs the last of each before the new position, not everything in between
+var replaySnapshots = map[protocol.MessageType]bool{
+	protocol.MsgRoomJoined:         true,
+	protocol.MsgKuluchifiedState:   true,
+	protocol.MsgGameState:          true,
+	protocol.MsgOccupancy:          true,
+	protocol.MsgTreasureHuntState:  true,
+	protocol.MsgGlobalChatMessages: true,
+}
+
+// replayEntry is a message the server sent the whole room, as recorded
+type replayEntry struct {
+	at      time.Time
+	msgType protocol.MessageType
+	data    []byte
+}
+
+// Replay plays a session journal back through a Manager in place of a live connection,
+// so the UI shows the room the way it was recorded. Only what was sent to everyone is
+// played, so it's the room as a spectator saw it.
+//
+// Every message is handled on the replay's own goroutine; the methods controlling it
+// only ask that goroutine to pause, seek or change speed, so they never block the UI.
+type Replay struct {
+	m       *Manager
+	entries []replayEntry
+
+	mu     sync.Mutex
+	next   int       // Entry to play next
+	at     time.Time // Recorded time played up to
+	paused bool
+	speed  float64
+	seekTo *time.Time // Position asked for by Seek, nil once the goroutine has jumped there
+
+	wake chan struct{}
+	done chan struct{}
+	stop sync.Once
+}
+
+// NewReplay prepares a journal for playing through m; call Start to begin
+func NewReplay(m *Manager, journal []protocol.JournalEntry) (*Replay, error) {
+	r := &Replay{
+		m:     m,
+		speed: 1,
+		wake:  make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+	for _, entry := range journal {
+		if entry.Dir != protocol.JournalOut || entry.User != "" {
+			continue
+		}
+		msg, err := protocol.DecodeMessage(entry.Message)
+		if err != nil {
+			continue
+		}
+		r.entries = append(r.entries, replayEntry{at: entry.Time, msgType: msg.Type, data: entry.Message})
+	}
+	if len(r.entries) == 0 {
+		return nil, errors.New("the journal has nothing to replay")
+	}
+	r.at = r.entries[0].at
+	return r, nil
+}
+
+// Start plays the journal from the beginning
+func (r *Replay) Start() {
+	go r.run()
+}
+
+// Stop ends the replay
+func (r *Replay) Stop() {
+	r.stop.Do(func() { close(r.done) })
+}
+
+// TogglePause pauses or resumes the replay and reports whether it's now paused
+func (r *Replay) TogglePause() bool {
+	r.mu.Lock()
+	r.paused = !r.paused
+	paused := r.paused
+	r.mu.Unlock()
+	r.poke()
+	return paused
+}
+
+// Seek jumps by d from the current position, backwards if it's negative, staying within
+// the recording
+func (r *Replay) Seek(d time.Duration) {
+	r.mu.Lock()
+	from := r.at
+	if r.seekTo != nil {
+		from = *r.seekTo
+	}
+	to := from.Add(d)
+	if first := r.entries[0].at; to.Before(first) {
+		to = first
+	}
+	if last := r.entries[len(r.entries)-1].at; to.After(last) {
+		to = last
+	}
+	r.seekTo = &to
+	r.mu.Unlock()
+	r.poke()
+}
+
+// SetSpeed changes how fast the replay plays, by a factor of the recorded pace, and
+// returns the speed it settled on
+func (r *Replay) SetSpeed(speed float64) float64 {
+	r.mu.Lock()
+	r.speed = min(max(speed, minReplaySpeed), maxReplaySpeed)
+	speed = r.speed
+	r.mu.Unlock()
+	r.poke()
+	return speed
+}
+
+// Position reports how far into the recording the replay is, how long the recording is,
+// and whether it's paused and at what speed
+func (r *Replay) Position() (at, length time.Duration, paused bool, speed float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	start := r.entries[0].at
+	return r.at.Sub(start), r.entries[len(r.entries)-1].at.Sub(start), r.paused, r.speed
+}
+
+func (r *Replay) poke() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run plays entries as their time comes, and carries out seeks
+func (r *Replay) run() {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		r.mu.Lock()
+		if r.seekTo != nil {
+			play := r.seekLocked(*r.seekTo)
+			r.seekTo = nil
+			r.mu.Unlock()
+			for _, entry := range play {
+				r.m.handleMessage(entry.data)
+			}
+			continue
+		}
+
+		wait := time.Duration(-1)
+		if !r.paused && r.next < len(r.entries) {
+			wait = time.Duration(float64(r.entries[r.next].at.Sub(r.at)) / r.speed)
+		}
+		r.mu.Unlock()
+
+		if wait < 0 {
+			// Paused or at the end, until someone resumes or seeks
+			select {
+			case <-r.wake:
+				continue
+			case <-r.done:
+				return
+			}
+		}
+
+		timer.Reset(wait)
+		select {
+		case <-timer.C:
+		case <-r.wake:
+			continue
+		case <-r.done:
+			return
+		}
+
+		r.mu.Lock()
+		if r.paused || r.seekTo != nil || r.next >= len(r.entries) {
+			r.mu.Unlock()
+			continue
+		}
+		entry := r.entries[r.next]
+		r.next++
+		r.at = entry.at
+		r.mu.Unlock()
+		r.m.handleMessage(entry.data)
+	}
+}
+
+// seekLocked moves the replay to the given time and returns what has to be played to
+// show the room as it was then: the last of each kind of snapshot up to that point.
+// One-off messages from before it, such as emotes, are skipped. r.mu must be held.
+func (r *Replay) seekLocked(to time.Time) []replayEntry {
+	next := 0
+	for next < len(r.entries) && !r.entries[next].at.After(to) {
+		next++
+	}
+
+	seen := make(map[protocol.MessageType]bool)
+	var play []replayEntry
+	for i := next - 1; i >= 0; i-- {
+		entry := r.entries[i]
+		if replaySnapshots[entry.msgType] && !seen[entry.msgType] {
+			seen[entry.msgType] = true
+			play = append(play, entry)
+		}
+	}
+	// Oldest first, so a room_joined doesn't undo a later state
+	slices.Reverse(play)
+
+	log.Printf("Replay jumped to %s", to.Sub(r.entries[0].at).Round(time.Second))
+	r.next = next
+	r.at = to
+	return play
+}
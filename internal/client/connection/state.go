@@ -1,14 +1,36 @@
 package connection
 
 import (
+	"fmt"
+	"maps"
 	"sync"
+	"time"
 
 	"github.com/yourusername/always-at-morg/internal/protocol"
 )
 
+// predictionTimeout is how long a predicted move waits for the server to handle it
+// before it's given up on and the server's position is shown again
+const predictionTimeout = time.Second
+
+// pendingMove is a move shown before the server has handled it
+type pendingMove struct {
+	seq    uint64
+	pos    string
+	sentAt time.Time
+}
+
 // State manages the current game state
 type State struct {
 	currentState *protocol.GameState
+	objects      []protocol.MapObject
+	roomConfig   protocol.RoomConfig
+	occupancy    map[string]int // Building room number -> players in it
+	pomodoros    map[string]protocol.PomodoroState
+	gameMode     *protocol.GameModeState
+	self         string        // Username whose moves are predicted
+	moveSeq      uint64        // Number of the last predicted move
+	pending      []pendingMove // Predicted moves the server hasn't handled yet, oldest first
 	mu           sync.RWMutex
 }
 
@@ -19,16 +41,142 @@ func NewState() *State {
 	}
 }
 
-// UpdateState updates the entire game state
+// UpdateState updates the entire game state. Moves the server has handled are done with:
+// its position for us is the right one, whether it took them or not. Any newer ones are
+// put back on top of it.
 func (s *State) UpdateState(state *protocol.GameState) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+
+	if player, ok := state.Players[s.self]; ok && len(s.pending) > 0 {
+		now := time.Now()
+		pending := s.pending[:0]
+		for _, move := range s.pending {
+			if move.seq > player.MoveSeq && now.Sub(move.sentAt) < predictionTimeout {
+				pending = append(pending, move)
+			}
+		}
+		s.pending = pending
+		if len(pending) > 0 {
+			movePlayer(state, s.self, pending[len(pending)-1].pos)
+		}
+	}
 	s.currentState = state
 }
 
+// PredictMove moves the player in the current state right away, without waiting for the
+// server, and returns the number to send the move with
+func (s *State) PredictMove(username string, x, y int) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.self != username {
+		s.self = username
+		s.pending = nil
+	}
+	s.moveSeq++
+	pos := fmt.Sprintf("%d:%d", y, x)
+	s.pending = append(s.pending, pendingMove{seq: s.moveSeq, pos: pos, sentAt: time.Now()})
+
+	// The UI may still hold the current state, so change a copy
+	state := *s.currentState
+	state.Players = maps.Clone(state.Players)
+	state.PosToUsername = maps.Clone(state.PosToUsername)
+	movePlayer(&state, username, pos)
+	s.currentState = &state
+	return s.moveSeq
+}
+
+// movePlayer puts a player at pos in a state that isn't shared yet
+func movePlayer(state *protocol.GameState, username, pos string) {
+	player, ok := state.Players[username]
+	if !ok {
+		return
+	}
+	if state.PosToUsername[player.Pos] == username {
+		delete(state.PosToUsername, player.Pos)
+	}
+	player.Pos = pos
+	state.Players[username] = player
+	if state.PosToUsername != nil {
+		state.PosToUsername[pos] = username
+	}
+}
+
 // GetState returns the current game state (thread-safe copy)
 func (s *State) GetState() *protocol.GameState {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	return s.currentState
 }
+
+// SetObjects replaces the interactive objects for the current room
+func (s *State) SetObjects(objects []protocol.MapObject) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects = objects
+}
+
+// GetObjects returns the interactive objects for the current room
+func (s *State) GetObjects() []protocol.MapObject {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.objects
+}
+
+// SetRoomConfig replaces the rules of the current room
+func (s *State) SetRoomConfig(config protocol.RoomConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.roomConfig = config
+}
+
+// GetRoomConfig returns the rules of the current room
+func (s *State) GetRoomConfig() protocol.RoomConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.roomConfig
+}
+
+// SetOccupancy replaces how many players are in each building room
+func (s *State) SetOccupancy(occupancy map[string]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.occupancy = occupancy
+}
+
+// GetOccupancy returns how many players are in each building room
+func (s *State) GetOccupancy() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.occupancy
+}
+
+// SetPomodoros replaces the running pomodoro timers, keyed by room number
+func (s *State) SetPomodoros(pomodoros map[string]protocol.PomodoroState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pomodoros = pomodoros
+}
+
+// GetPomodoro returns the pomodoro timer running in a room, if any
+func (s *State) GetPomodoro(roomNumber string) (protocol.PomodoroState, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.pomodoros[roomNumber]
+	return p, ok
+}
+
+// SetGameMode replaces the state of the room's game mode (nil when none is running)
+func (s *State) SetGameMode(mode *protocol.GameModeState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.gameMode = mode
+}
+
+// GetGameMode returns the state of the room's game mode, or nil
+func (s *State) GetGameMode() *protocol.GameModeState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.gameMode
+}
@@ -0,0 +1,40 @@
+package connection
+
+// subscriber is a callback listening for events
+type subscriber struct {
+	callback func(Event)
+}
+
+// Subscribe adds a callback that gets every event, in the order they happen, and
+// returns a function that removes it. Each subscriber sees events independently of the
+// others, so the UI, a logger and a notifier can all listen. Callbacks are run on the
+// connection's goroutines and shouldn't block for long.
+func (m *Manager) Subscribe(callback func(Event)) (unsubscribe func()) {
+	sub := &subscriber{callback: callback}
+
+	m.mu.Lock()
+	m.subscribers = append(m.subscribers, sub)
+	m.mu.Unlock()
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for i, s := range m.subscribers {
+			if s == sub {
+				m.subscribers = append(m.subscribers[:i:i], m.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// SubscribeTo adds a callback for one type of event only, e.g.
+//
+//	connection.SubscribeTo(mgr, func(e connection.LatencyEvent) { ... })
+func SubscribeTo[E Event](m *Manager, callback func(E)) (unsubscribe func()) {
+	return m.Subscribe(func(event Event) {
+		if e, ok := event.(E); ok {
+			callback(e)
+		}
+	})
+}
@@ -0,0 +1,310 @@
+package i18n
+
+// spanish translates the UI into Spanish. The keys are the English text exactly as the
+// UI passes it to T and Tf, verbs and all.
+var spanish = map[string]string{
+	// Loading, username, lobby and avatar screens
+	"Waiting %ds before retry %d/%d":      "Esperando %ds antes del reintento %d/%d",
+	"Retrying connection (%d/%d)":         "Reintentando la conexión (%d/%d)",
+	"Connection failed after %d attempts": "La conexión falló tras %d intentos",
+	"Rejoining %s":                        "Volviendo a %s",
+	"Connecting":                          "Conectando",
+	"ESC to quit":                         "ESC para salir",
+	"Enter username:":                     "Nombre de usuario:",
+	"ENTER to continue  •  ESC to quit":   "ENTER para continuar  •  ESC para salir",
+	"(locked)":                            "(con clave)",
+	"Welcome, %s! Pick a room to join":    "¡Hola, %s! Elige una sala",
+	"Loading rooms...":                    "Cargando salas...",
+	"Room":                                "Sala",
+	"Players":                             "Jugadores",
+	"Map":                                 "Mapa",
+	"room-name":                           "nombre-de-sala",
+	"New room name:":                      "Nombre de la sala nueva:",
+	"Password for %s:":                    "Contraseña de %s:",
+	"password":                            "contraseña",
+	"Password for %s (leave empty for a public room):": "Contraseña de %s (vacía para una sala pública):",
+	"no password":   "sin contraseña",
+	"Joining %s...": "Entrando en %s...",
+	"↑/↓: Select  •  ENTER: Join  •  N: New room  •  R: Refresh  •  ESC: Quit": "↑/↓: Elegir  •  ENTER: Entrar  •  N: Sala nueva  •  R: Actualizar  •  ESC: Salir",
+	"ENTER: Next  •  ESC: Back to the list":                                    "ENTER: Siguiente  •  ESC: Volver a la lista",
+	"ENTER: Create and join  •  ESC: Back to the list":                         "ENTER: Crear y entrar  •  ESC: Volver a la lista",
+	"ENTER: Join  •  ESC: Back to the list":                                    "ENTER: Entrar  •  ESC: Volver a la lista",
+	"CUSTOMIZE AVATAR - %s":                                                    "PERSONALIZA TU AVATAR - %s",
+	"HEAD":                                                                     "CABEZA",
+	"TORSO":                                                                    "TORSO",
+	"LEGS":                                                                     "PIERNAS",
+	"Arrows to navigate  •  ENTER to confirm  •  ESC to quit": "Flechas para moverte  •  ENTER para confirmar  •  ESC para salir",
+	"Please enlarge your terminal to at least %dx%d":          "Agranda la terminal hasta al menos %dx%d",
+	"It's %dx%d now": "Ahora mide %dx%d",
+	"CTRL+C to quit": "CTRL+C para salir",
+
+	// Main game: panels and status bar
+	"Room %s":                                   "Sala %s",
+	"QUEST & ANNOUNCEMENTS":                     "MISIÓN Y AVISOS",
+	"Current Clue:":                             "Pista actual:",
+	"Loading clue...":                           "Cargando pista...",
+	"(Press Q, then 1-%d to answer)":            "(Pulsa Q y luego 1-%d para responder)",
+	"(Press Q or type '/guess <text>' in chat)": "(Pulsa Q o escribe '/guess <texto>' en el chat)",
+	"Time left":                                 "Tiempo restante",
+	"Next riddle in":                            "Próximo acertijo en",
+	"Scavenger Hunt (%d/%d):":                   "Gymkana (%d/%d):",
+	"(Stand there and type '/claim')":           "(Ponte allí y escribe '/claim')",
+	"No announcements":                          "No hay avisos",
+	"Hunt":                                      "Tesoro",
+	"Admin":                                     "Admin",
+	"↓ %d newer (%s)":                           "↓ %d más recientes (%s)",
+	"CHAT":                                      "CHAT",
+	"[GLOBAL]":                                  "[GLOBAL]",
+	"[PRIVATE: %s]":                             "[PRIVADO: %s]",
+	"[ROOM %s (%d players)]":                    "[SALA %s (%d jugadores)]",
+	"[ROOM CHAT - Not in a room]":               "[CHAT DE SALA - Fuera de una sala]",
+	"Press 'p' for private, 'o' for room":       "Pulsa 'p' para privado, 'o' para sala",
+	"Press 'p' for private":                     "Pulsa 'p' para privado",
+	"Press 'g' for global":                      "Pulsa 'g' para global",
+	"Press 'p' to select a player":              "Pulsa 'p' para elegir un jugador",
+	"Select a player to chat with:":             "Elige con quién hablar:",
+	"Press ESC to cancel":                       "Pulsa ESC para cancelar",
+	"You must be in a room to use room chat":    "Tienes que estar en una sala para usar el chat de sala",
+	"No messages yet. Press 't' to type.":       "Aún no hay mensajes. Pulsa 't' para escribir.",
+	"No messages with %s. Press 't' to type.":   "No hay mensajes con %s. Pulsa 't' para escribir.",
+	"Press 't' to type...":                      "Pulsa 't' para escribir...",
+	"none":                                      "nadie",
+	"You":                                       "Tú",
+	"Player: %s":                                "Jugador: %s",
+	"ghost":                                     "fantasma",
+	"Break":                                     "Descanso",
+	"Focus":                                     "Foco",
+	"ENTER: Send  •  ESC: Cancel":               "ENTER: Enviar  •  ESC: Cancelar",
+	"Mode":                                      "Modo",
+	"Quit":                                      "Salir",
+
+	// Announcements and other messages
+	"Welcome to Always at Morg!":                            "¡Bienvenido a Always at Morg!",
+	"Replaying a recorded session":                          "Reproduciendo una sesión grabada",
+	"Message from %s":                                       "Mensaje de %s",
+	"%s · %s · %d pts":                                      "%s · %s · %d pts",
+	"Treasure hunt":                                         "Búsqueda del tesoro",
+	"A new round started: %s":                               "Empezó una ronda nueva: %s",
+	"%s challenged you to %s!":                              "¡%s te ha retado a %s!",
+	"/accept or /decline":                                   "/accept o /decline",
+	"%s declined your challenge to %s":                      "%s rechazó tu reto a %s",
+	"+%d points":                                            "+%d puntos",
+	"You solved the riddle! +%d points":                     "¡Resolviste el acertijo! +%d puntos",
+	"%s solved the riddle!":                                 "¡%s resolvió el acertijo!",
+	"Welcome back, %s - %d-day login streak!":               "¡Hola de nuevo, %s! Racha de %d días",
+	"Come back tomorrow for a bigger bonus.":                "Vuelve mañana para un premio mayor.",
+	"Your streak earned the %s! Wear it from the shop ($).": "¡Tu racha te ha dado: %s! Póntelo desde la tienda ($).",
+	"%d messages sent while reconnecting were lost":         "Se perdieron %d mensajes enviados durante la reconexión",
+	"Still there? You'll be disconnected for inactivity in %ds - move or chat to stay.": "¿Sigues ahí? Te desconectaremos por inactividad en %ds; muévete o escribe para quedarte.",
+	"Couldn't save a screenshot: %v":                           "No se pudo guardar la captura: %v",
+	"Screenshots can't be saved from the browser":              "Las capturas no se pueden guardar desde el navegador",
+	"Saved a screenshot to %s, and one without colors to .txt": "Captura guardada en %s, y una sin colores en .txt",
+
+	// Chat commands
+	"Usage: %s":                                           "Uso: %s",
+	"Challenged %s to %s":                                 "Has retado a %s a %s",
+	"Nobody has challenged you":                           "Nadie te ha retado",
+	"Step into a room to start a pomodoro":                "Entra en una sala para empezar un pomodoro",
+	"Status cleared":                                      "Estado borrado",
+	"Status set: %s":                                      "Estado: %s",
+	"Invite link:":                                        "Enlace de invitación:",
+	"Friends can join you with: %s":                       "Tus amigos pueden unirse con: %s",
+	"You guessed: %s":                                     "Has dicho: %s",
+	"You've already answered":                             "Ya has respondido",
+	"You answered: %s":                                    "Has respondido: %s",
+	"Guess the treasure hunt riddle (or /answer)":         "Adivina el acertijo de la búsqueda del tesoro (o /answer)",
+	"Open the treasure hunt panel":                        "Abre el panel de la búsqueda del tesoro",
+	"Today's earlier treasure hunt rounds":                "Las rondas de hoy de la búsqueda del tesoro",
+	"Challenge a player to a mini-game":                   "Reta a un jugador a un minijuego",
+	"Answer a challenge":                                  "Responde a un reto",
+	"Reopen the mini-game you're playing":                 "Vuelve a abrir el minijuego en curso",
+	"Set your status, or clear it":                        "Pon tu estado, o bórralo",
+	"Start or stop your room's 25/5 focus timer":          "Inicia o para el temporizador 25/5 de tu sala",
+	"Play hide-and-seek":                                  "Juega al escondite",
+	"Play tag":                                            "Juega al pilla pilla",
+	"Show your scavenger hunt clue, or start a hunt":      "Muestra tu pista de la gymkana, o empieza una",
+	"Claim the scavenger clue you're standing on":         "Reclama la pista de la gymkana en la que estás",
+	"Open the cosmetics shop":                             "Abre la tienda de cosméticos",
+	"Get an invite link to your room":                     "Consigue un enlace de invitación a tu sala",
+	"Rename your room (owner)":                            "Cambia el nombre de tu sala (dueño)",
+	"Cap how many players your room takes (owner)":        "Limita cuántos jugadores caben en tu sala (dueño)",
+	"Keep a player out for 5 minutes (owner, moderators)": "Echa a un jugador durante 5 minutos (dueño, moderadores)",
+	"Pick your room's moderators (owner)":                 "Elige a los moderadores de tu sala (dueño)",
+	"Empty your room's chat (owner, moderators)":          "Vacía el chat de tu sala (dueño, moderadores)",
+
+	// Key bindings, as the help, settings and status bar show them
+	"Move up":             "Mover arriba",
+	"Move down":           "Mover abajo",
+	"Move left":           "Mover izquierda",
+	"Move right":          "Mover derecha",
+	"Move up-left":        "Mover arriba-izq",
+	"Move up-right":       "Mover arriba-der",
+	"Move down-left":      "Mover abajo-izq",
+	"Move down-right":     "Mover abajo-der",
+	"Chat":                "Chat",
+	"Global chat":         "Chat global",
+	"Room chat":           "Chat de sala",
+	"Private chat":        "Chat privado",
+	"Use/Talk":            "Usar/Hablar",
+	"Shop":                "Tienda",
+	"Quest":               "Misión",
+	"Leaderboard":         "Clasificación",
+	"Settings":            "Ajustes",
+	"Redraw":              "Redibujar",
+	"Help":                "Ayuda",
+	"Debug info":          "Depuración",
+	"Screenshot":          "Captura",
+	"Older announcements": "Avisos anteriores",
+	"Newer announcements": "Avisos más recientes",
+
+	// Help
+	"HELP":                          "AYUDA",
+	"MOVEMENT":                      "MOVIMIENTO",
+	"GAME":                          "JUEGO",
+	"ANNOUNCEMENTS":                 "AVISOS",
+	"CHAT MODES":                    "MODOS DE CHAT",
+	"TREASURE HUNT":                 "BÚSQUEDA DEL TESORO",
+	"COMMANDS":                      "COMANDOS",
+	"Everyone in the building":      "Todo el edificio",
+	"Only players in your room":     "Solo los jugadores de tu sala",
+	"Pick a nearby player (1-9)":    "Elige un jugador cercano (1-9)",
+	"Send what you typed":           "Envía lo que has escrito",
+	"Stop typing":                   "Deja de escribir",
+	"Read the riddle and guess":     "Lee el acertijo y adivina",
+	"Answer a trivia round":         "Responde una ronda de trivial",
+	"Earlier rounds, in the panel":  "Rondas anteriores, en el panel",
+	"Wins, fastest solves, streaks": "Victorias, récords y rachas",
+	"Solving riddles in a row earns a streak bonus": "Resolver acertijos seguidos da un bonus de racha",
+	"(none)":                        "(ninguna)",
+	"↑/↓: Scroll  •  ESC: Close":    "↑/↓: Desplazar  •  ESC: Cerrar",
+	"↑/↓: Scroll  •  %s/ESC: Close": "↑/↓: Desplazar  •  %s/ESC: Cerrar",
+
+	// Settings, with the values they cycle through
+	"SETTINGS":                       "AJUSTES",
+	"KEYS":                           "TECLAS",
+	"Language":                       "Idioma",
+	"Theme":                          "Tema",
+	"Chat timestamps":                "Hora en el chat",
+	"Chat bell":                      "Timbre del chat",
+	"DM popups":                      "Avisos privados",
+	"Mention popups":                 "Avisos menciones",
+	"Desktop alerts":                 "Alertas sistema",
+	"Movement keys":                  "Teclas de mover",
+	"Screen reader":                  "Lector pantalla",
+	"Max view width":                 "Ancho máx. vista",
+	"Max view height":                "Alto máx. vista",
+	"auto":                           "automático",
+	"on":                             "sí",
+	"off":                            "no",
+	"shown":                          "visible",
+	"hidden":                         "oculta",
+	"private":                        "privados",
+	"mentions":                       "menciones",
+	"all":                            "todo",
+	"unfocused":                      "sin foco",
+	"always":                         "siempre",
+	"arrows":                         "flechas",
+	"press a key...":                 "pulsa una tecla...",
+	"Saved to %s":                    "Guardado en %s",
+	"Changes last until you quit":    "Los cambios duran hasta que salgas",
+	"Couldn't save settings: %v":     "No se pudieron guardar los ajustes: %v",
+	"Couldn't save your profile: %v": "No se pudo guardar tu perfil: %v",
+	"↑/↓: Select  •  ←/→: Change  •  ESC: Close":                          "↑/↓: Elegir  •  ←/→: Cambiar  •  ESC: Cerrar",
+	"↑/↓: Select  •  ENTER: Rebind  •  BACKSPACE: Default  •  ESC: Close": "↑/↓: Elegir  •  ENTER: Cambiar tecla  •  BACKSPACE: Por defecto  •  ESC: Cerrar",
+
+	// Mini-games and room game modes
+	"tic-tac-toe":                 "tres en raya",
+	"a trivia battle":             "una batalla de trivial",
+	"No game in progress":         "No hay ninguna partida",
+	"It's a draw!":                "¡Empate!",
+	"You won! 🏆":                  "¡Has ganado! 🏆",
+	"%s won":                      "Ha ganado %s",
+	"Your turn - press 1-9":       "Tu turno: pulsa 1-9",
+	"Waiting for %s...":           "Esperando a %s...",
+	"ESC to close":                "ESC para cerrar",
+	"Waiting for the question...": "Esperando la pregunta...",
+	"TRIVIA BATTLE":               "BATALLA DE TRIVIAL",
+	"Answer locked in - waiting for your opponent...": "Respuesta enviada; esperando a tu rival...",
+	"Press 1-%d  •  %ds left":                         "Pulsa 1-%d  •  quedan %ds",
+	"🙈 Hide & Seek":                                   "🙈 Escondite",
+	"🏃 Tag":                                           "🏃 Pilla pilla",
+	"/%s to join":                                     "/%s para unirte",
+	"starting in":                                     "empieza en",
+	"you're the seeker - wait":                        "te toca buscar; espera",
+	"hide!":                                           "¡escóndete!",
+	"you're it! %s left":                              "¡te toca pillar! quedan %s",
+	"%s left":                                         "quedan %s",
+	"hiding":                                          "escondiéndose",
+	"seeking":                                         "buscando",
+	"chase":                                           "persecución",
+	"joined":                                          "apuntado",
+	"seeker":                                          "buscador",
+	"hider":                                           "escondido",
+	"found":                                           "encontrado",
+	"runner":                                          "corredor",
+
+	// Screen reader mode
+	"Announcements":                    "Avisos",
+	"No messages yet.":                 "Aún no hay mensajes.",
+	"Private chat with %s":             "Chat privado con %s",
+	"Room %s chat":                     "Chat de la sala %s",
+	"Waiting for the game to start...": "Esperando a que empiece el juego...",
+	"You are in room %s.":              "Estás en la sala %s.",
+	"You are in the hallway.":          "Estás en el pasillo.",
+	"Nobody is nearby.":                "No hay nadie cerca.",
+	"%s is %s.":                        "%s está %s.",
+	"The way out is %s.":               "La salida está %s.",
+	"Room %s is %s.":                   "La sala %s está %s.",
+	"1 tile %s":                        "a 1 casilla al %s",
+	"%d tiles %s":                      "a %d casillas al %s",
+	"%s and %s":                        "%s y %s",
+	"right here":                       "aquí mismo",
+	"north":                            "norte",
+	"south":                            "sur",
+	"east":                             "este",
+	"west":                             "oeste",
+
+	// Players, profile, leaderboard, shop and treasure hunt overlays
+	"PLAYERS ONLINE (%d)": "JUGADORES CONECTADOS (%d)",
+	"Hallway":             "Pasillo",
+	"Nobody's here yet":   "Aún no hay nadie",
+	"Room %s (%d)":        "Sala %s (%d)",
+	"Busiest: %s":         "Más llenas: %s",
+	"↑/↓: Select  •  ENTER: Profile  •  ESC: Close": "↑/↓: Elegir  •  ENTER: Perfil  •  ESC: Cerrar",
+	"%s is no longer online":                        "%s ya no está conectado",
+	"No status set":                                 "Sin estado",
+	"ESC: Back":                                     "ESC: Volver",
+	"TREASURE HUNT LEADERBOARD":                     "CLASIFICACIÓN DE LA BÚSQUEDA DEL TESORO",
+	"Loading...":                                    "Cargando...",
+	"No riddles solved yet - be the first!":         "Nadie ha resuelto un acertijo aún: ¡sé el primero!",
+	"Player":                                        "Jugador",
+	"Wins":                                          "Victorias",
+	"Fastest":                                       "Récord",
+	"Streak":                                        "Racha",
+	"Guesses":                                       "Intentos",
+	"Streak is current/best  •  L or ESC: Close": "Racha actual/mejor  •  L o ESC: Cerrar",
+	"COSMETICS SHOP":     "TIENDA DE COSMÉTICOS",
+	"You have ⭐ %d":      "Tienes ⭐ %d",
+	"wearing":            "puesto",
+	"owned":              "comprado",
+	"7-day login streak": "racha de 7 días",
+	"↑/↓: Select  •  ENTER: Buy / Wear / Take off  •  ESC: Close": "↑/↓: Elegir  •  ENTER: Comprar / Poner / Quitar  •  ESC: Cerrar",
+	"Press 1-%d to answer": "Pulsa 1-%d para responder",
+	"Answer locked in - everyone who's right scores when time runs out": "Respuesta enviada; quien acierte puntúa cuando se acabe el tiempo",
+	"Your guess:":                  "Tu respuesta:",
+	"No riddle to guess right now": "Ahora mismo no hay acertijo",
+	"ENTER: Guess  •  TAB: Earlier rounds  •  ESC: Close": "ENTER: Adivinar  •  TAB: Rondas anteriores  •  ESC: Cerrar",
+	"1-%d: Answer  •  TAB: Earlier rounds  •  ESC: Close": "1-%d: Responder  •  TAB: Rondas anteriores  •  ESC: Cerrar",
+	"TODAY'S TREASURE HUNT":                               "LA BÚSQUEDA DEL TESORO DE HOY",
+	"No rounds played yet today":                          "Aún no se ha jugado ninguna ronda hoy",
+	"%s in %.1fs":                                         "%s en %.1fs",
+	"Nobody solved it":                                    "Nadie lo resolvió",
+	"Round %d":                                            "Ronda %d",
+	"Answer:":                                             "Respuesta:",
+	"+ %d earlier rounds":                                 "+ %d rondas anteriores",
+	"TAB: Current riddle  •  ESC: Close":                  "TAB: Acertijo actual  •  ESC: Cerrar",
+
+	// Replays
+	"Following: %s": "Siguiendo a: %s",
+	"SPACE: Pause  •  ←/→: 10s  •  ↓/↑: 1m  •  -/+: Speed  •  TAB: Next player  •  Q: Quit": "ESPACIO: Pausa  •  ←/→: 10s  •  ↓/↑: 1m  •  -/+: Velocidad  •  TAB: Siguiente jugador  •  Q: Salir",
+}
@@ -0,0 +1,81 @@
+// Package i18n translates the client's UI. Strings are looked up by their English text,
+// so English needs no catalog and anything a catalog is missing stays in English.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// English is the language the UI is written in
+const English = "en"
+
+// catalogs are the translations from English, by language code
+var catalogs = map[string]map[string]string{
+	"es": spanish,
+}
+
+// names are what each language calls itself, for the settings screen
+var names = map[string]string{
+	English: "English",
+	"es":    "Español",
+}
+
+// current is the catalog in use, nil for English. Only the Bubble Tea loop draws the UI,
+// so it isn't locked; the browser client bridge lets one client at a time draw.
+var current map[string]string
+
+// Languages returns the codes of the languages the UI can be shown in, English first
+func Languages() []string {
+	return []string{English, "es"}
+}
+
+// Name returns what a language calls itself, e.g. "Español" for "es"
+func Name(code string) string {
+	if name, ok := names[code]; ok {
+		return name
+	}
+	return code
+}
+
+// Supported reports whether the UI can be shown in a language
+func Supported(code string) bool {
+	_, ok := names[code]
+	return ok
+}
+
+// SetLanguage shows the UI in a language from Languages; any other shows it in English
+func SetLanguage(code string) {
+	current = catalogs[code]
+}
+
+// Detect returns the language the environment asks for, from LC_ALL, LC_MESSAGES or
+// LANG (e.g. "es_MX.UTF-8"), or English if it's one the UI doesn't have
+func Detect() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		// The first of these that's set decides the language
+		if locale := os.Getenv(name); locale != "" {
+			code, _, _ := strings.Cut(strings.ToLower(locale), "_")
+			code, _, _ = strings.Cut(code, ".")
+			if Supported(code) {
+				return code
+			}
+			return English
+		}
+	}
+	return English
+}
+
+// T translates message into the current language
+func T(message string) string {
+	if translated, ok := current[message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Tf translates format into the current language, then formats it as fmt.Sprintf does
+func Tf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
@@ -0,0 +1,61 @@
+// Package notifier shows desktop notifications with whatever the OS already has for
+// it: notify-send on Linux and the BSDs, osascript on macOS and a PowerShell toast on
+// Windows.
+package notifier
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// appName is who notifications say they're from
+const appName = "Always at Morg"
+
+// ErrUnavailable means the OS has no way to show a notification that we know of
+var ErrUnavailable = errors.New("no desktop notifier available")
+
+// macScript shows a notification on macOS. The title and body come in through the
+// environment so they never need quoting as AppleScript.
+const macScript = `display notification (system attribute "MORG_BODY") with title (system attribute "MORG_TITLE")`
+
+// windowsScript shows a toast on Windows 10 and later, reading the title and body from
+// the environment too
+const windowsScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode($env:MORG_TITLE)) > $null
+$text.Item(1).AppendChild($template.CreateTextNode($env:MORG_BODY)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('` + appName + `').Show([Windows.UI.Notifications.ToastNotification]::new($template))
+`
+
+// Notify shows a desktop notification and waits for the OS tool to exit, so callers
+// should run it off the UI loop
+func Notify(title, body string) error {
+	cmd, err := command(title, body)
+	if err != nil {
+		return err
+	}
+	return cmd.Run()
+}
+
+// command builds the command that shows a notification on this OS
+func command(title, body string) (*exec.Cmd, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", macScript)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsScript)
+	default:
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return nil, ErrUnavailable
+		}
+		// "--" so a body starting with a dash isn't read as an option
+		return exec.Command("notify-send", "--app-name="+appName, "--", title, body), nil
+	}
+	cmd.Env = append(os.Environ(), "MORG_TITLE="+title, "MORG_BODY="+body)
+	return cmd, nil
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"slices"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+const (
+	maxAnnouncements = 200 // Announcements kept to scroll back through
+	announceStep     = 3   // Announcements scrolled per key press
+)
+
+// announcementKind is where an announcement came from, which decides its tag
+type announcementKind int
+
+const (
+	announceLocal announcementKind = iota // The client's own notices, e.g. command results and errors
+	announceHunt                          // Treasure hunt winners, guesses and answers
+	announceAdmin                         // Server-wide announcements made by an admin
+)
+
+// announcement is a line in the announcements panel and when it was made
+type announcement struct {
+	text string
+	at   time.Time
+	kind announcementKind
+}
+
+// render draws the announcement as a line: its time, its tag if it has one, then its text
+func (a announcement) render() string {
+	line := mutedStyle.Render(a.at.Format("15:04")) + " "
+	switch a.kind {
+	case announceHunt:
+		line += lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700")).Bold(true).Render("["+i18n.T("Hunt")+"]") + " "
+	case announceAdmin:
+		line += lipgloss.NewStyle().Foreground(primaryColor).Bold(true).Render("["+i18n.T("Admin")+"]") + " "
+	}
+	return line + a.text
+}
+
+// pushAnnouncement appends one of the client's own notices to the announcements panel
+func (m *Model) pushAnnouncement(text string) {
+	m.addAnnouncement(announceLocal, text)
+}
+
+// addAnnouncement appends a line to the announcements panel, keeping only the most recent
+// ones. If the panel is scrolled back it stays on the lines it shows.
+func (m *Model) addAnnouncement(kind announcementKind, text string) {
+	m.announcements = append(m.announcements, announcement{text: text, at: time.Now(), kind: kind})
+	if len(m.announcements) > maxAnnouncements {
+		m.announcements = m.announcements[len(m.announcements)-maxAnnouncements:]
+	}
+	if m.announceScroll > 0 {
+		m.announceScroll = min(m.announceScroll+1, len(m.announcements)-1)
+	}
+}
+
+// addServerAnnouncements adds announcements the server made at the time it made them,
+// skipping any the panel already has; the history is sent again on every room join
+func (m *Model) addServerAnnouncements(announcements []protocol.AnnouncementPayload) {
+	for _, a := range announcements {
+		kind := announceAdmin
+		if a.Source == protocol.AnnouncementHunt {
+			kind = announceHunt
+		}
+		at := time.Unix(a.Timestamp, 0)
+		if slices.ContainsFunc(m.announcements, func(b announcement) bool {
+			return b.kind == kind && b.text == a.Message && b.at.Equal(at)
+		}) {
+			continue
+		}
+		m.addAnnouncement(kind, a.Message)
+		m.announcements[len(m.announcements)-1].at = at
+	}
+}
+
+// addAnnouncementHistory adds the announcements made before we joined. They arrive after
+// our own notices, so the panel is put in order of time.
+func (m *Model) addAnnouncementHistory(announcements []protocol.AnnouncementPayload) {
+	m.addServerAnnouncements(announcements)
+	slices.SortStableFunc(m.announcements, func(a, b announcement) int {
+		return a.at.Compare(b.at)
+	})
+}
+
+// scrollAnnouncements scrolls the announcements panel by delta lines, back in time for
+// a positive delta
+func (m *Model) scrollAnnouncements(delta int) {
+	m.announceScroll = min(max(m.announceScroll+delta, 0), max(len(m.announcements)-1, 0))
+}
+
+// visibleAnnouncements renders the announcements that fit in count lines, the newest at
+// the bottom. Scrolled back, the last line says how many newer ones are hidden.
+func (m Model) visibleAnnouncements(count int) []string {
+	count = max(count, 1)
+	scroll := 0
+	if len(m.announcements) > count && m.announceScroll > 0 {
+		count = max(count-1, 1) // Room for the line about the newer ones
+		scroll = min(m.announceScroll, len(m.announcements)-count)
+	}
+
+	end := len(m.announcements) - scroll
+	var lines []string
+	for _, a := range m.announcements[max(end-count, 0):end] {
+		lines = append(lines, a.render())
+	}
+	if scroll > 0 {
+		lines = append(lines, mutedStyle.Render(i18n.Tf("↓ %d newer (%s)", scroll, m.keys.AnnounceDown.Help().Key)))
+	}
+	return lines
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// asciiMode draws the UI with ASCII characters only, for terminals whose fonts can't
+// show the avatars, borders and symbols
+var asciiMode bool
+
+// SetASCII switches ASCII-only drawing on or off
+func SetASCII(on bool) {
+	asciiMode = on
+}
+
+// DetectASCII guesses whether the terminal can only show ASCII: its locale isn't UTF-8,
+// or it's the Linux console, whose font has few symbols
+func DetectASCII() bool {
+	if os.Getenv("TERM") == "linux" {
+		return true
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		// The first of these that's set decides the character set
+		if locale := os.Getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return !strings.Contains(locale, "utf-8") && !strings.Contains(locale, "utf8")
+		}
+	}
+	return false // No locale says nothing either way; most terminals today are UTF-8
+}
+
+// asciiGlyphs are the ASCII stand-ins for the characters the UI draws. Avatar parts keep
+// their shape where they can so players still recognize each other.
+var asciiGlyphs = map[rune]string{
+	// Avatars
+	'ō': "o", '◡': "u", '◉': "O", '∩': "n", '∧': "A", '⌐': "L",
+
+	// Borders
+	'─': "-", '━': "-", '═': "=", '│': "|", '┃': "|", '║': "|",
+	'╭': "+", '╮': "+", '╰': "+", '╯': "+", '┌': "+", '┐': "+", '└': "+", '┘': "+",
+	'╔': "+", '╗': "+", '╚': "+", '╝': "+", '├': "+", '┤': "+", '┬': "+", '┴': "+", '┼': "+",
+
+	// Map tiles
+	'░': "#", '▬': "=", '█': "#",
+
+	// Symbols
+	'•': "*", '·': ".", '×': "x", '˚': "'", '✦': "*", '✧': "+",
+	'↑': "^", '↓': "v", '←': "<", '→': ">", '◀': "<", '▶': ">", '⏸': "||",
+	'⭐': "*", '🔥': "~", '🏆': "#1", '✅': "ok", '🍅': "@", '🎉': "!!", '🎁': "[]",
+	'✉': "*", '…': ".", '⏰': "!", '⏱': "t", '⏳': "t", '☕': "c", '🙈': "?!", '🏃': "=>", '👻': "oo", '📶': "ms",
+}
+
+// asciiStandIns holds the stand-ins already worked out, padded to width
+var asciiStandIns = make(map[rune]string)
+
+// toASCII replaces every character outside ASCII, keeping the width the layout was
+// worked out with
+func toASCII(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if r < 0x80 {
+			b.WriteRune(r)
+		} else {
+			b.WriteString(asciiStandIn(r))
+		}
+	}
+	return b.String()
+}
+
+// asciiStandIn returns the stand-in for r, padded or cut to r's width. Characters
+// without one become question marks.
+func asciiStandIn(r rune) string {
+	if standIn, ok := asciiStandIns[r]; ok {
+		return standIn
+	}
+	width := lipgloss.Width(string(r))
+	glyph, ok := asciiGlyphs[r]
+	if !ok {
+		glyph = strings.Repeat("?", width)
+	}
+	if len(glyph) > width {
+		glyph = glyph[:width]
+	}
+	standIn := glyph + strings.Repeat(" ", width-len(glyph))
+	asciiStandIns[r] = standIn
+	return standIn
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/yourusername/always-at-morg/internal/gamemap"
+)
+
+// colorProfile is how many colors the terminal shows. Hex colors are matched to the
+// nearest it has, except the map's on 16 colors, which has tiles16 instead.
+var colorProfile = lipgloss.ColorProfile()
+
+// colorProfileNames are the -colors flag's values
+var colorProfileNames = map[string]termenv.Profile{
+	"truecolor": termenv.TrueColor,
+	"256":       termenv.ANSI256,
+	"16":        termenv.ANSI,
+	"none":      termenv.Ascii,
+}
+
+// ColorProfileNamed returns the color profile for a -colors flag value: truecolor, 256,
+// 16 or none
+func ColorProfileNamed(name string) (termenv.Profile, bool) {
+	profile, ok := colorProfileNames[name]
+	return profile, ok
+}
+
+// DetectColorProfile works out how many colors the terminal shows from COLORTERM and
+// TERM, leaving it to lipgloss when TERM isn't set
+func DetectColorProfile() termenv.Profile {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return termenv.TrueColor
+	}
+
+	term := strings.ToLower(os.Getenv("TERM"))
+	switch {
+	case term == "":
+		return lipgloss.ColorProfile()
+	case term == "dumb":
+		return termenv.Ascii
+	case strings.Contains(term, "direct") || strings.Contains(term, "truecolor") ||
+		strings.HasPrefix(term, "xterm-kitty") || strings.HasPrefix(term, "wezterm") || strings.HasPrefix(term, "alacritty"):
+		return termenv.TrueColor
+	case strings.Contains(term, "256color"):
+		return termenv.ANSI256
+	}
+	return termenv.ANSI
+}
+
+// SetColorProfile draws the UI for a terminal with the profile's colors
+func SetColorProfile(profile termenv.Profile) {
+	if profile == colorProfile {
+		return
+	}
+	colorProfile = profile
+	lipgloss.SetColorProfile(profile)
+
+	// Everything already rendered has the old profile's color codes
+	clear(styledGlyphs)
+	initStyledCache()
+	buildStyledCache()
+}
+
+// tileColors16 is how a map tile looks with 16 colors
+type tileColors16 struct {
+	bg lipgloss.Color
+	fg lipgloss.Color // For the tile's glyph, if it has one
+}
+
+// tiles16 picks a different ANSI color for every kind of tile that sits next to another,
+// where matching the hex colors would make walls, floors and furniture run together
+var tiles16 = map[gamemap.Tile]tileColors16{
+	gamemap.TileHallway:      {bg: "7"},
+	gamemap.TileRoomFloor:    {bg: "6"},
+	gamemap.TileRoomWall:     {bg: "2"},
+	gamemap.TileOuterWall:    {bg: "3"},
+	gamemap.TileInaccessible: {bg: "8"},
+	gamemap.TileEntrance:     {bg: "10"},
+	gamemap.TileOutside:      {bg: "0", fg: "8"},
+	gamemap.TileBlocked:      {bg: "0", fg: "2"},
+	gamemap.TileTelevision:   {bg: "0"},
+	gamemap.TileTable:        {bg: "11"},
+	gamemap.TilePlant:        {bg: "10", fg: "2"},
+	gamemap.TileWhiteboard:   {bg: "15"},
+	gamemap.TileDarkFloor:    {bg: "1"},
+	gamemap.TileCouch:        {bg: "4"},
+}
+
+// tile16Background returns a tile's background with 16 colors
+func tile16Background(tile gamemap.Tile) lipgloss.Color {
+	if colors, ok := tiles16[tile]; ok {
+		return colors.bg
+	}
+	return tiles16[gamemap.TileHallway].bg
+}
+
+// tile16Style renders a map tile with 16 colors, which have no tint for the time of day
+func tile16Style(tile gamemap.Tile) string {
+	style := lipgloss.NewStyle().Background(tile16Background(tile))
+	glyph := " "
+	if tileGlyph, ok := tileGlyphs[tile]; ok {
+		glyph = tileGlyph.glyph
+		if fg := tiles16[tile].fg; fg != "" {
+			style = style.Foreground(fg)
+		}
+	}
+	return style.Render(glyph)
+}
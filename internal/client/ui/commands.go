@@ -0,0 +1,234 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// slashCommands lists the chat-box commands for the help overlay, which translates the
+// descriptions. Keep it in step with handleSlashCommand, which runs them.
+var slashCommands = []struct {
+	usage string
+	desc  string
+}{
+	{"/guess <answer>", "Guess the treasure hunt riddle (or /answer)"},
+	{"/hunt", "Open the treasure hunt panel"},
+	{"/history", "Today's earlier treasure hunt rounds"},
+	{"/challenge <username> [tictactoe|trivia]", "Challenge a player to a mini-game"},
+	{"/accept, /decline", "Answer a challenge"},
+	{"/board", "Reopen the mini-game you're playing"},
+	{"/status [text]", "Set your status, or clear it"},
+	{"/pomodoro [stop]", "Start or stop your room's 25/5 focus timer"},
+	{"/hideseek [start|join|leave]", "Play hide-and-seek"},
+	{"/tag [start|join|leave]", "Play tag"},
+	{"/scavenger [start]", "Show your scavenger hunt clue, or start a hunt"},
+	{"/claim", "Claim the scavenger clue you're standing on"},
+	{"/shop", "Open the cosmetics shop"},
+	{"/invite", "Get an invite link to your room"},
+	{"/rename <name>", "Rename your room (owner)"},
+	{"/capacity <players>", "Cap how many players your room takes (owner)"},
+	{"/kick <username>", "Keep a player out for 5 minutes (owner, moderators)"},
+	{"/mod, /unmod <username>", "Pick your room's moderators (owner)"},
+	{"/clearchat", "Empty your room's chat (owner, moderators)"},
+}
+
+// handleSlashCommand runs a chat-box command such as "/challenge alice".
+// Returns false if the input isn't a known command so it can be sent as chat.
+func (m *Model) handleSlashCommand(input string) bool {
+	fields := strings.Fields(input)
+	if len(fields) == 0 || m.connMgr == nil {
+		return false
+	}
+
+	switch fields[0] {
+	case "/challenge":
+		// /challenge <username> [game]
+		if len(fields) < 2 {
+			m.pushAnnouncement(mutedStyle.Render(i18n.Tf("Usage: %s", "/challenge <username> [tictactoe|trivia]")))
+			return true
+		}
+		game := "tictactoe"
+		if len(fields) > 2 {
+			game = fields[2]
+		}
+		m.connMgr.SendMiniGameChallenge(fields[1], game)
+		m.pushAnnouncement(mutedStyle.Render(i18n.Tf("Challenged %s to %s", fields[1], miniGameName(game))))
+		return true
+
+	case "/accept", "/decline":
+		if m.pendingInvite == "" {
+			m.pushAnnouncement(mutedStyle.Render(i18n.T("Nobody has challenged you")))
+			return true
+		}
+		m.connMgr.SendMiniGameResponse(m.pendingInvite, fields[0] == "/accept")
+		m.pendingInvite = ""
+		return true
+
+	case "/pomodoro":
+		// /pomodoro [stop]
+		if !m.isPlayerInRoom() {
+			m.pushAnnouncement(mutedStyle.Render(i18n.T("Step into a room to start a pomodoro")))
+			return true
+		}
+		m.connMgr.SendPomodoro(len(fields) > 1 && fields[1] == "stop")
+		return true
+
+	case "/status":
+		// /status <text>, or just /status to clear it
+		status := strings.TrimSpace(strings.TrimPrefix(input, "/status"))
+		m.connMgr.SendStatus(status)
+		if status == "" {
+			m.pushAnnouncement(mutedStyle.Render(i18n.T("Status cleared")))
+		} else {
+			m.pushAnnouncement(mutedStyle.Render(i18n.Tf("Status set: %s", status)))
+		}
+		return true
+
+	case "/" + protocol.GameModeHideAndSeek, "/" + protocol.GameModeTag:
+		// /hideseek [start|join|leave] - with no action, join the running game or start one
+		modeName := strings.TrimPrefix(fields[0], "/")
+		action := "start"
+		if mode := m.connMgr.GetGameMode(); mode != nil && mode.Mode == modeName {
+			action = "join"
+		}
+		if len(fields) > 1 {
+			action = fields[1]
+		}
+		m.connMgr.SendGameMode(modeName, action)
+		return true
+
+	case "/scavenger":
+		// /scavenger [start] - with no action, show my current clue
+		action := "clue"
+		if len(fields) > 1 {
+			action = fields[1]
+		}
+		m.connMgr.SendScavenger(action)
+		return true
+
+	case "/claim":
+		m.connMgr.SendScavenger("claim")
+		return true
+
+	case "/shop":
+		m.openShop()
+		return true
+
+	case "/hunt":
+		m.openTreasureHunt()
+		return true
+
+	case "/history":
+		m.openHuntHistory()
+		return true
+
+	case "/rename":
+		name := strings.TrimSpace(strings.TrimPrefix(input, "/rename"))
+		if name == "" {
+			m.pushAnnouncement(mutedStyle.Render(i18n.Tf("Usage: %s", "/rename <name>")))
+			return true
+		}
+		m.connMgr.SendRoomModerate(protocol.RoomModeratePayload{Action: protocol.RoomActionRename, Name: name})
+		return true
+
+	case "/capacity":
+		capacity := 0
+		if len(fields) > 1 {
+			capacity, _ = strconv.Atoi(fields[1])
+		}
+		if capacity < 1 {
+			m.pushAnnouncement(mutedStyle.Render(i18n.Tf("Usage: %s", "/capacity <players>")))
+			return true
+		}
+		m.connMgr.SendRoomModerate(protocol.RoomModeratePayload{Action: protocol.RoomActionCapacity, Capacity: capacity})
+		return true
+
+	case "/kick", "/mod", "/unmod":
+		if len(fields) < 2 {
+			m.pushAnnouncement(mutedStyle.Render(i18n.Tf("Usage: %s", fields[0]+" <username>")))
+			return true
+		}
+		action := map[string]string{
+			"/kick":  protocol.RoomActionKick,
+			"/mod":   protocol.RoomActionMod,
+			"/unmod": protocol.RoomActionUnmod,
+		}[fields[0]]
+		m.connMgr.SendRoomModerate(protocol.RoomModeratePayload{Action: action, Username: fields[1]})
+		return true
+
+	case "/clearchat":
+		m.connMgr.SendRoomModerate(protocol.RoomModeratePayload{Action: protocol.RoomActionClearChat})
+		return true
+
+	case "/invite":
+		invite := protocol.Invite{Server: m.serverURL, Room: m.roomID, Password: m.roomPassword}
+		m.pushAnnouncement(highlightStyle.Render(i18n.T("Invite link:")+" ") + invite.Link())
+		m.pushAnnouncement(mutedStyle.Render(i18n.Tf("Friends can join you with: %s", "always-at-morg -join <link>")))
+		return true
+
+	case "/board":
+		// Reopen the board of a running game
+		if m.miniGame != nil {
+			m.overlay = OverlayMiniGame
+		}
+		return true
+	}
+
+	return false
+}
+
+const maxGuessLength = 50 // Longest treasure hunt guess the overlay lets you type
+
+// treasureHuntGuess returns the guess in a "/guess <answer>" (or "/answer <answer>") command
+func treasureHuntGuess(input string) (string, bool) {
+	for _, prefix := range []string{"/guess ", "/answer "} {
+		if guess, ok := strings.CutPrefix(input, prefix); ok {
+			guess = strings.TrimSpace(guess)
+			return guess, guess != ""
+		}
+	}
+	return "", false
+}
+
+// sendTreasureHuntGuess guesses at the riddle and echoes the guess back to us. In a
+// trivia round the guess is the number of an option.
+func (m *Model) sendTreasureHuntGuess(guess string) {
+	if m.connMgr == nil || !m.connMgr.IsConnected() {
+		return
+	}
+	if choice, err := strconv.Atoi(guess); err == nil && choice >= 1 && choice <= len(m.clueOptions) {
+		m.answerTrivia(choice)
+		return
+	}
+	m.connMgr.SendTreasureHuntGuess(guess)
+	m.addAnnouncement(announceHunt, mutedStyle.Render(i18n.Tf("You guessed: %s", guess)))
+}
+
+// answerTrivia picks an option (counting from 1) in a trivia round; only the first
+// answer counts, so later ones aren't sent
+func (m *Model) answerTrivia(choice int) {
+	if m.huntChoice != 0 {
+		m.pushAnnouncement(mutedStyle.Render(i18n.T("You've already answered")))
+		return
+	}
+	m.huntChoice = choice
+	m.connMgr.SendTreasureHuntGuess(strconv.Itoa(choice))
+	m.addAnnouncement(announceHunt, mutedStyle.Render(i18n.Tf("You answered: %s", m.clueOptions[choice-1])))
+}
+
+// openTreasureHunt opens the treasure hunt panel with an empty guess
+func (m *Model) openTreasureHunt() {
+	m.huntGuess = ""
+	m.overlay = OverlayTreasureHunt
+}
+
+// openHuntHistory lists the treasure hunt rounds played today, fetching the latest
+func (m *Model) openHuntHistory() {
+	if m.connMgr != nil && m.connMgr.IsConnected() {
+		m.connMgr.SendTreasureHuntHistoryRequest()
+	}
+	m.overlay = OverlayHuntHistory
+}
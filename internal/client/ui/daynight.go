@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/always-at-morg/internal/gamemap"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// currentTimeOfDay is the phase the map palette is drawn for, as last sent by the server
+var currentTimeOfDay = protocol.TimeOfDayDay
+
+// timeOfDayTint is the color the map is blended toward for a phase, and by how much
+type timeOfDayTint struct {
+	color  string
+	amount float64
+}
+
+var timeOfDayTints = map[string]timeOfDayTint{
+	protocol.TimeOfDayEvening: {color: "#4B3B6B", amount: 0.30}, // Dusky purple
+	protocol.TimeOfDayNight:   {color: "#0B1330", amount: 0.55}, // Deep navy
+}
+
+// tileGlyph is the character (and its color) drawn on a tile that isn't just a background
+type tileGlyph struct {
+	glyph string
+	fg    string
+}
+
+// tileGlyphs mirrors the glyphs of the daytime tile styles in screen_main.go
+var tileGlyphs = map[gamemap.Tile]tileGlyph{
+	gamemap.TileRoomWall:  {glyph: "░"},
+	gamemap.TileOutside:   {glyph: "·", fg: "#000000"},
+	gamemap.TileBlocked:   {glyph: "^", fg: "#6A8D6A"},
+	gamemap.TilePlant:     {glyph: "*", fg: "#6A8D6A"},
+	gamemap.TileRoomFloor: {glyph: "~"},
+	gamemap.TileCouch:     {glyph: "▬"},
+}
+
+// setTimeOfDay switches the map palette, rebuilding the tile cache when the phase changes
+func setTimeOfDay(phase string) {
+	if phase == "" {
+		phase = protocol.TimeOfDayDay
+	}
+	if phase == currentTimeOfDay {
+		return
+	}
+
+	currentTimeOfDay = phase
+	initStyledCache()
+	buildStyledCache()
+}
+
+// shadeColor blends a color toward the tint of the current time of day
+func shadeColor(color lipgloss.Color) lipgloss.Color {
+	tint, ok := timeOfDayTints[currentTimeOfDay]
+	if !ok {
+		return color
+	}
+
+	r1, g1, b1, ok1 := parseHexColor(string(color))
+	r2, g2, b2, ok2 := parseHexColor(tint.color)
+	if !ok1 || !ok2 {
+		return color
+	}
+
+	blend := func(a, b int) int {
+		return int(float64(a)*(1-tint.amount) + float64(b)*tint.amount)
+	}
+	return lipgloss.Color(fmt.Sprintf("#%02X%02X%02X", blend(r1, r2), blend(g1, g2), blend(b1, b2)))
+}
+
+// parseHexColor parses "#RRGGBB" into its components
+func parseHexColor(hex string) (r, g, b int, ok bool) {
+	if len(hex) != 7 || hex[0] != '#' {
+		return 0, 0, 0, false
+	}
+	value, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return int(value >> 16 & 0xFF), int(value >> 8 & 0xFF), int(value & 0xFF), true
+}
+
+// shadedTileStyle renders a map tile in the current time-of-day palette
+func shadedTileStyle(tile gamemap.Tile) string {
+	glyph := " "
+	style := lipgloss.NewStyle().Background(getBackgroundColorFromRoomValue(tile))
+
+	if tileGlyph, ok := tileGlyphs[tile]; ok {
+		glyph = tileGlyph.glyph
+		if tileGlyph.fg != "" {
+			style = style.Foreground(shadeColor(lipgloss.Color(tileGlyph.fg)))
+		}
+	}
+
+	return style.Render(glyph)
+}
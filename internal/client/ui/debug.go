@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/always-at-morg/internal/gamemap"
+)
+
+// debugStats counts the frames drawn for the debug overlay. It's shared by the copies of
+// the model, since View gets one by value.
+type debugStats struct {
+	frames int       // Frames drawn since start
+	start  time.Time // When the current one-second window began
+	fps    float64   // Frames per second over the last full window
+}
+
+// countFrame notes a frame being drawn
+func (s *debugStats) countFrame() {
+	now := time.Now()
+	if s.start.IsZero() {
+		s.start = now
+	}
+	s.frames++
+	if elapsed := now.Sub(s.start); elapsed >= time.Second {
+		s.fps = float64(s.frames) / elapsed.Seconds()
+		s.frames = 0
+		s.start = now
+	}
+}
+
+// debugLines is how many lines the debug overlay takes at the top of the game panel
+const debugLines = 2
+
+// renderDebugOverlay shows what's needed to look into lag and desync reports: the server
+// tick, round trip, frame rate and event backlog, then where the camera and player are
+// and what the map has under the player
+func (m Model) renderDebugOverlay(width int) string {
+	tick := "-"
+	player := "-"
+	tile := "-"
+	if m.connMgr != nil {
+		if state := m.connMgr.GetState(); state != nil {
+			tick = fmt.Sprint(state.Tick)
+			if p, ok := state.Players[m.userName]; ok && p.Pos != "" {
+				x, y := parsePosition(p.Pos)
+				player = fmt.Sprintf("%d,%d", x, y)
+				tile = tileUnder(x, y)
+			}
+		}
+	}
+	cameraX, cameraY := m.calculateViewport()
+
+	fps := 0.0
+	if m.stats != nil {
+		fps = m.stats.fps
+	}
+
+	style := lipgloss.NewStyle().Foreground(mutedColor).Width(width).MaxWidth(width)
+	return style.Render(fmt.Sprintf("tick %s  •  rtt %dms  •  %.0f fps  •  events %d/%d",
+		tick, m.latency.Milliseconds(), fps, len(m.eventChan), cap(m.eventChan))) + "\n" +
+		style.Render(fmt.Sprintf("camera %d,%d  •  player %s  •  map %s", cameraX, cameraY, player, tile))
+}
+
+// tileUnder returns the room map's value at x, y: the room number on a room's floor,
+// or the map character
+func tileUnder(x, y int) string {
+	roomMap, err := getRoomMap()
+	if err != nil || x < 0 || x >= gamemap.Width || y < 0 || y >= gamemap.Height {
+		return "-"
+	}
+	if tile := roomMap.At(x, y); tile != gamemap.TileRoomFloor {
+		return tile.String()
+	}
+	return roomMap.RoomNumberAt(x, y)
+}
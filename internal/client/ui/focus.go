@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Terminals that support focus reporting send focus-in and focus-out sequences once
+// EnableFocusReports is written, until DisableFocusReports is. Bubble Tea doesn't know
+// these sequences, so they arrive as unknown CSI messages, told apart by how they print.
+const (
+	EnableFocusReports  = "\x1b[?1004h"
+	DisableFocusReports = "\x1b[?1004l"
+
+	focusInSequence  = "?CSI[73]?" // ESC [ I
+	focusOutSequence = "?CSI[79]?" // ESC [ O
+)
+
+// focusChange reports whether msg says the terminal gained or lost focus
+func focusChange(msg tea.Msg) (focused, ok bool) {
+	if _, isKey := msg.(tea.KeyMsg); isKey {
+		return false, false
+	}
+	stringer, ok := msg.(fmt.Stringer)
+	if !ok {
+		return false, false
+	}
+	switch stringer.String() {
+	case focusInSequence:
+		return true, true
+	case focusOutSequence:
+		return false, true
+	}
+	return false, false
+}
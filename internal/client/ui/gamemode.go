@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// gameModeTitles are the status bar labels for each room game mode, in English
+var gameModeTitles = map[string]string{
+	protocol.GameModeHideAndSeek: "🙈 Hide & Seek",
+	protocol.GameModeTag:         "🏃 Tag",
+}
+
+// renderGameModeStatus renders the running game mode, its phase countdown and our role
+func (m Model) renderGameModeStatus(mode protocol.GameModeState) string {
+	remaining := time.Until(time.Unix(mode.EndsAt, 0))
+	if remaining < 0 {
+		remaining = 0
+	}
+	clock := fmt.Sprintf("%d:%02d", int(remaining.Minutes()), int(remaining.Seconds())%60)
+
+	title := i18n.T(gameModeTitles[mode.Mode])
+	if title == "" {
+		title = mode.Mode
+	}
+
+	role, playing := mode.Roles[m.userName]
+	var detail string
+	switch {
+	case mode.Phase == "lobby" && !playing:
+		detail = i18n.Tf("/%s to join", mode.Mode) + " " + clock
+	case mode.Phase == "lobby":
+		detail = i18n.T("starting in") + " " + clock
+	case !playing:
+		detail = i18n.T(mode.Phase) + " " + clock
+	case mode.Phase == "hiding" && role == "seeker":
+		detail = i18n.T("you're the seeker - wait") + " " + clock
+	case mode.Phase == "hiding":
+		detail = i18n.T("hide!") + " " + clock
+	case role == "it":
+		detail = i18n.Tf("you're it! %s left", clock)
+	default:
+		detail = i18n.T(role) + " • " + i18n.Tf("%s left", clock)
+	}
+
+	return lipgloss.NewStyle().Foreground(accentColor).Bold(true).Render(title + ": " + detail)
+}
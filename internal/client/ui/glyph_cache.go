@@ -0,0 +1,48 @@
+package ui
+
+import "github.com/charmbracelet/lipgloss"
+
+// cellStyle is how a character drawn over the map looks. Unlike lipgloss.Style it can
+// key a map, so the rendered characters can be cached.
+type cellStyle struct {
+	fg, bg lipgloss.Color
+	bold   bool
+	italic bool
+}
+
+// glyphKey is a character in a style
+type glyphKey struct {
+	style cellStyle
+	glyph rune
+}
+
+// maxStyledGlyphs caps the glyph cache. Names, shop colors and time-of-day tints keep
+// adding keys, so it's started over when full rather than growing for the whole session.
+const maxStyledGlyphs = 8192
+
+// styledGlyphs holds characters already rendered. Only the Bubble Tea loop draws the
+// game view, so it isn't locked; the browser client bridge lets one client at a time draw.
+var styledGlyphs = make(map[glyphKey]string)
+
+// styledGlyph returns glyph rendered in style, asking lipgloss only the first time
+func styledGlyph(style cellStyle, glyph rune) string {
+	key := glyphKey{style: style, glyph: glyph}
+	if styled, ok := styledGlyphs[key]; ok {
+		return styled
+	}
+
+	render := lipgloss.NewStyle().Bold(style.bold).Italic(style.italic)
+	if style.fg != "" {
+		render = render.Foreground(style.fg)
+	}
+	if style.bg != "" {
+		render = render.Background(style.bg)
+	}
+	styled := render.Render(string(glyph))
+
+	if len(styledGlyphs) >= maxStyledGlyphs {
+		clear(styledGlyphs)
+	}
+	styledGlyphs[key] = styled
+	return styled
+}
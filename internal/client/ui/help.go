@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
+)
+
+// helpPage is how far page up and page down scroll the help
+const helpPage = 10
+
+// updateHelpOverlay scrolls the help; the help key closes it again
+func (m Model) updateHelpOverlay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxScroll := m.maxHelpScroll()
+	switch msg.String() {
+	case "up", "k", "w":
+		m.helpScroll = max(m.helpScroll-1, 0)
+	case "down", "j", "s":
+		m.helpScroll = min(m.helpScroll+1, maxScroll)
+	case "pgup":
+		m.helpScroll = max(m.helpScroll-helpPage, 0)
+	case "pgdown", " ":
+		m.helpScroll = min(m.helpScroll+helpPage, maxScroll)
+	default:
+		if key.Matches(msg, m.keys.Help) {
+			m.overlay = OverlayNone
+		}
+	}
+	return m, nil
+}
+
+// helpVisibleLines is how many lines of help fit under the title, in the game panel
+// viewMainGame lays out
+func (m Model) helpVisibleLines() int {
+	contentHeight := max(m.height-10, 10)
+	return max(contentHeight-5, 1) // Title above, controls below
+}
+
+// maxHelpScroll is as far as the help scrolls before its last line reaches the bottom
+func (m Model) maxHelpScroll() int {
+	return max(len(m.helpLines())-m.helpVisibleLines(), 0)
+}
+
+// renderHelpOverlay shows the part of the help scrolled to
+func (m Model) renderHelpOverlay(height int) string {
+	lines := m.helpLines()
+	visible := max(height-5, 1)
+	start := min(m.helpScroll, max(len(lines)-visible, 0))
+	end := min(start+visible, len(lines))
+
+	controls := i18n.T("↑/↓: Scroll  •  ESC: Close")
+	if m.keys.Help.Enabled() {
+		controls = i18n.Tf("↑/↓: Scroll  •  %s/ESC: Close", m.keys.Help.Help().Key)
+	}
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		titleStyle.Render(i18n.T("HELP")),
+		strings.Join(lines[start:end], "\n"),
+		"",
+		mutedStyle.Render(controls),
+	)
+}
+
+// helpLines lists the player's keys, the chat modes, the treasure hunt and the chat
+// commands. The keys come from the keymap, so rebinding them changes the help too.
+// Titles and descriptions are translated as they're added.
+func (m Model) helpLines() []string {
+	var lines []string
+	section := func(title string) {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, highlightStyle.Render(i18n.T(title)))
+	}
+	row := func(keys, desc string) {
+		lines = append(lines, "  "+selectedOptionStyle.Render(fmt.Sprintf("%-14s", keys))+" "+i18n.T(desc))
+	}
+	rowFor := func(binding key.Binding, desc string) {
+		if binding.Enabled() {
+			row(helpKeys(binding), desc)
+		}
+	}
+
+	for i, title := range []string{"MOVEMENT", "CHAT", "ANNOUNCEMENTS", "GAME"} {
+		section(title)
+		for _, binding := range m.keys.FullHelp()[i] {
+			if binding.Enabled() {
+				row(helpKeys(binding), binding.Help().Desc)
+			} else {
+				row(i18n.T("(none)"), binding.Help().Desc)
+			}
+		}
+	}
+
+	section("CHAT MODES")
+	rowFor(m.keys.GlobalChat, "Everyone in the building")
+	rowFor(m.keys.RoomChat, "Only players in your room")
+	rowFor(m.keys.PrivateChat, "Pick a nearby player (1-9)")
+	row("enter", "Send what you typed")
+	row("esc", "Stop typing")
+
+	section("TREASURE HUNT")
+	rowFor(m.keys.Quest, "Read the riddle and guess")
+	row("1-4", "Answer a trivia round")
+	row("tab", "Earlier rounds, in the panel")
+	rowFor(m.keys.Leaderboard, "Wins, fastest solves, streaks")
+	lines = append(lines, mutedStyle.Render("  "+i18n.T("Solving riddles in a row earns a streak bonus")))
+
+	section("COMMANDS")
+	for _, command := range slashCommands {
+		lines = append(lines, "  "+selectedOptionStyle.Render(command.usage))
+		lines = append(lines, "      "+mutedStyle.Render(i18n.T(command.desc)))
+	}
+	return lines
+}
+
+// helpKeys lists a binding's keys for the help, leaving out a letter's capital when the
+// letter is bound too
+func helpKeys(binding key.Binding) string {
+	keys := binding.Keys()
+	var shown []string
+	for _, k := range keys {
+		if lower := strings.ToLower(k); lower != k && slices.Contains(keys, lower) {
+			continue
+		}
+		shown = append(shown, k)
+	}
+	return strings.Join(shown, "/")
+}
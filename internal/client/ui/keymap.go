@@ -0,0 +1,209 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yourusername/always-at-morg/internal/client/config"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
+)
+
+// keyMap holds the main game's key bindings. The defaults come from the player's movement
+// scheme and the config file's [keys] table rebinds any of them.
+type keyMap struct {
+	Up, Down, Left, Right                key.Binding
+	UpLeft, UpRight, DownLeft, DownRight key.Binding
+
+	Chat, GlobalChat, RoomChat, PrivateChat key.Binding
+
+	Interact, Players, Shop, Quest, Leaderboard, Settings, Refresh, Help, Debug, Screenshot key.Binding
+
+	AnnounceUp, AnnounceDown key.Binding
+}
+
+// keyAction is a binding with the names the config file and settings screen know it by
+type keyAction struct {
+	name    string // Key in the config file's [keys] table
+	label   string // Row on the settings screen, and the binding's help, in English
+	binding *key.Binding
+}
+
+// actions lists the bindings in the order the settings screen shows them
+func (k *keyMap) actions() []keyAction {
+	return []keyAction{
+		{"up", "Move up", &k.Up},
+		{"down", "Move down", &k.Down},
+		{"left", "Move left", &k.Left},
+		{"right", "Move right", &k.Right},
+		{"up_left", "Move up-left", &k.UpLeft},
+		{"up_right", "Move up-right", &k.UpRight},
+		{"down_left", "Move down-left", &k.DownLeft},
+		{"down_right", "Move down-right", &k.DownRight},
+		{"chat", "Chat", &k.Chat},
+		{"global_chat", "Global chat", &k.GlobalChat},
+		{"room_chat", "Room chat", &k.RoomChat},
+		{"private_chat", "Private chat", &k.PrivateChat},
+		{"interact", "Use/Talk", &k.Interact},
+		{"players", "Players", &k.Players},
+		{"shop", "Shop", &k.Shop},
+		{"quest", "Quest", &k.Quest},
+		{"leaderboard", "Leaderboard", &k.Leaderboard},
+		{"settings", "Settings", &k.Settings},
+		{"refresh", "Redraw", &k.Refresh},
+		{"help", "Help", &k.Help},
+		{"debug", "Debug info", &k.Debug},
+		{"screenshot", "Screenshot", &k.Screenshot},
+		{"announce_up", "Older announcements", &k.AnnounceUp},
+		{"announce_down", "Newer announcements", &k.AnnounceDown},
+	}
+}
+
+// movementKeys are each movement scheme's keys for the eight directions
+var movementKeys = map[string]map[string][]string{
+	config.KeysAll: {
+		"up":         {"up", "w", "W", "k", "K", "8"},
+		"down":       {"down", "s", "S", "j", "J", "2"},
+		"left":       {"left", "a", "A", "h", "H", "4"},
+		"right":      {"right", "d", "D", "l", "6"},
+		"up_left":    {"y", "Y", "7"},
+		"up_right":   {"u", "U", "9"},
+		"down_left":  {"b", "B", "1"},
+		"down_right": {"n", "N", "3"},
+	},
+	config.KeysWASD: {
+		"up":    {"w", "W", "up"},
+		"down":  {"s", "S", "down"},
+		"left":  {"a", "A", "left"},
+		"right": {"d", "D", "right"},
+	},
+	config.KeysArrows: {
+		"up":         {"up", "8"},
+		"down":       {"down", "2"},
+		"left":       {"left", "4"},
+		"right":      {"right", "6"},
+		"up_left":    {"7"},
+		"up_right":   {"9"},
+		"down_left":  {"1"},
+		"down_right": {"3"},
+	},
+	config.KeysVim: {
+		"up":         {"k", "up"},
+		"down":       {"j", "down"},
+		"left":       {"h", "left"},
+		"right":      {"l", "right"},
+		"up_left":    {"y"},
+		"up_right":   {"u"},
+		"down_left":  {"b"},
+		"down_right": {"n"},
+	},
+}
+
+// defaultKeys are the keys for everything but movement
+var defaultKeys = map[string][]string{
+	"chat":          {"t", "T"},
+	"global_chat":   {"g", "G"},
+	"room_chat":     {"o", "O"},
+	"private_chat":  {"p", "P"},
+	"interact":      {"e", "E"},
+	"players":       {"tab"},
+	"shop":          {"$"},
+	"quest":         {"Q"},
+	"leaderboard":   {"L"},
+	"settings":      {","},
+	"refresh":       {"r", "R"},
+	"help":          {"?"},
+	"debug":         {"f3"},
+	"screenshot":    {"f2"},
+	"announce_up":   {"pgup", "["},
+	"announce_down": {"pgdown", "]"},
+}
+
+// newKeyMap builds the key bindings for cfg. A key the player bound to one action is
+// taken from every other action's defaults, so it only ever does one thing.
+func newKeyMap(cfg config.Config) keyMap {
+	var k keyMap
+	taken := make(map[string]bool)
+	for _, action := range k.actions() {
+		for _, bound := range cfg.Keys[action.name] {
+			taken[bound] = true
+		}
+	}
+
+	for _, action := range k.actions() {
+		keys, ok := cfg.Keys[action.name]
+		if !ok {
+			keys = movementKeys[cfg.Settings.MovementKeys][action.name]
+			if keys == nil {
+				keys = defaultKeys[action.name]
+			}
+			keys = slices.DeleteFunc(slices.Clone(keys), func(s string) bool { return taken[s] })
+		}
+		*action.binding = key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpKey(keys), action.label))
+	}
+	return k
+}
+
+// helpKey is how help text shows a binding: its first key, e.g. "T" or "TAB"
+func helpKey(keys []string) string {
+	if len(keys) == 0 {
+		return ""
+	}
+	return strings.ToUpper(keys[0])
+}
+
+// movementStep returns the step a key takes, if it's bound to a direction
+func (k keyMap) movementStep(msg tea.KeyMsg) (dx, dy int, ok bool) {
+	steps := []struct {
+		binding key.Binding
+		dx, dy  int
+	}{
+		{k.Up, 0, -1}, {k.Down, 0, 1}, {k.Left, -1, 0}, {k.Right, 1, 0},
+		{k.UpLeft, -1, -1}, {k.UpRight, 1, -1}, {k.DownLeft, -1, 1}, {k.DownRight, 1, 1},
+	}
+	for _, step := range steps {
+		if key.Matches(msg, step.binding) {
+			return step.dx, step.dy, true
+		}
+	}
+	return 0, 0, false
+}
+
+// FullHelp groups the bindings for the help overlay: movement, chat, the announcements,
+// then everything else
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Left, k.Right, k.UpLeft, k.UpRight, k.DownLeft, k.DownRight},
+		{k.Chat, k.GlobalChat, k.RoomChat, k.PrivateChat},
+		{k.AnnounceUp, k.AnnounceDown},
+		{k.Interact, k.Players, k.Shop, k.Quest, k.Leaderboard, k.Settings, k.Refresh, k.Help, k.Debug, k.Screenshot},
+	}
+}
+
+// statusHelp is the status bar's list of controls, with the chat modes sharing one entry
+func (k keyMap) statusHelp() string {
+	var items []string
+	add := func(binding key.Binding) {
+		if binding.Enabled() {
+			items = append(items, binding.Help().Key+": "+i18n.T(binding.Help().Desc))
+		}
+	}
+
+	add(k.Chat)
+	var modes []string
+	for _, mode := range []key.Binding{k.GlobalChat, k.RoomChat, k.PrivateChat} {
+		if mode.Enabled() {
+			modes = append(modes, mode.Help().Key)
+		}
+	}
+	if len(modes) > 0 {
+		items = append(items, strings.Join(modes, "/")+": "+i18n.T("Mode"))
+	}
+	add(k.Interact)
+	add(k.Quest)
+	add(k.Players)
+	add(k.Settings)
+	add(k.Help)
+	return strings.Join(append(items, "CTRL+C: "+i18n.T("Quit")), "  •  ")
+}
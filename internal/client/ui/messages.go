@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -26,11 +27,17 @@ type tickMsg time.Time
 // retryMsg is sent after a delay to trigger reconnection
 type retryMsg struct{}
 
-// connectCmd attempts to connect using the existing connection manager
-func connectCmd(mgr *connection.Manager) tea.Cmd {
+const connectTimeout = 15 * time.Second // Longest a single connection attempt may hang
+
+// connectCmd attempts to connect using the existing connection manager; canceling ctx
+// aborts the attempt
+func connectCmd(ctx context.Context, mgr *connection.Manager) tea.Cmd {
 	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, connectTimeout)
+		defer cancel()
+
 		// Use the existing manager - don't create a new one!
-		if err := mgr.Connect(); err != nil {
+		if err := mgr.Connect(ctx); err != nil {
 			return connectionErrorMsg{err: err}
 		}
 		return connectionSuccessMsg{}
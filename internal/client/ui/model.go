@@ -1,8 +1,16 @@
 package ui
 
 import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yourusername/always-at-morg/internal/client/config"
 	"github.com/yourusername/always-at-morg/internal/client/connection"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
+	"github.com/yourusername/always-at-morg/internal/protocol"
 )
 
 // ViewState represents the current view in the TUI
@@ -11,6 +19,7 @@ type ViewState int
 const (
 	ViewLoading ViewState = iota
 	ViewUsernameEntry
+	ViewLobby
 	ViewAvatarCustomization
 	ViewMainGame
 )
@@ -29,31 +38,69 @@ type Model struct {
 	viewState ViewState
 	connMgr   *connection.Manager   // Single connection manager, reused throughout session
 	eventChan chan connection.Event // Channel for connection events
+	ctx       context.Context       // Lives as long as the program; connection attempts give up when it's canceled
+	cancel    context.CancelFunc
 
 	usernameInput string
 	avatar        Avatar
 	avatarCursor  int
 	width         int
 	height        int
+	tooSmall      bool // Terminal is smaller than minWidth x minHeight, so only a notice is drawn
 	err           error
 
 	GameWorldHeight int        // Height of the game world
 	GameWorldWidth  int        // Width of the game world
 	GameWorldGrid   [][]string // 2D grid representing the game world (rendered from room map)
 
+	// Settings screen
+	config         config.Config // Player's settings, saved to configPath when changed
+	configPath     string        // Config file, "" to keep changes for this session only
+	settingsCursor int           // Selected row on the settings screen
+	settingsError  string        // Why the settings couldn't be saved
+	keys           keyMap        // Main game key bindings, from the config
+	rebinding      string        // Action waiting for its new key on the settings screen
+
+	// Help overlay
+	helpScroll int // First line of the help shown
+
+	// Notifications
+	toast      string    // Private message or mention shown in the status bar
+	toastUntil time.Time // When the toast goes away
+	unfocused  bool      // Terminal reported losing focus, so desktop notifications show
+
+	// Debug overlay
+	debug bool        // Show the debug overlay over the game world
+	stats *debugStats // Frame rate, shared by the copies of the model
+
+	// Game world render cache
+	stateVersion uint64     // Bumped whenever something drawn in the game world may have changed
+	frame        *gameFrame // Last game world drawn, shared by the copies of the model
+
 	// Loading screen
 	loadingDots      int
 	serverURL        string
-	roomID           string // Room to join
+	roomID           string           // Room picked in the lobby, "" until we ask to join one
+	roomPassword     string           // Password we joined roomID with, for /invite
+	invite           *protocol.Invite // Room to join right after the username, from an invite link
 	userName         string
 	reconnectAttempt int  // Current reconnection attempt (0-5)
 	maxReconnects    int  // Maximum reconnection attempts
 	waitingToRetry   bool // True when waiting for retry delay
+	rejoining        bool // Lost the connection mid-game, so rejoin roomID once it's back
+
+	// Recorded session played instead of a live connection, nil when connected
+	replay *connection.Replay
+
+	// Run by the server for a player in a browser, so nothing happens on this machine
+	// for them: no desktop notifications or screenshots
+	hosted bool
 
 	// Chat system
 	chatMode           ChatMode
 	chatTarget         string              // Username for private chat
-	announcements      []string            // Server-wide announcements
+	announcements      []announcement      // Server-wide announcements and the client's own notices
+	announceScroll     int                 // Announcements scrolled back from the newest
 	globalChatMessages []string            // Global chat messages
 	privateChatHistory map[string][]string // Private chat messages per user (key: username)
 	roomChatMessages   map[string][]string // Room chat messages per room (key: room number)
@@ -62,8 +109,46 @@ type Model struct {
 
 	// Treasure Hunt
 	currentClue string
+	clueKind    string   // Category, difficulty and points of the riddle, empty between riddles
+	clueEndsAt  int64    // Unix time the riddle's time runs out, 0 if none is being played
+	clueNextAt  int64    // Unix time the next riddle starts, 0 outside the cooldown
+	huntGuess   string   // Guess being typed in the treasure hunt overlay
+	clueOptions []string // Choices of a trivia round, empty for riddles
+	huntChoice  int      // Option we picked in the trivia round (1-4), 0 until we answer
+	huntSeen    bool     // Had the hunt's state before, so a new EndsAt is a round starting, not us joining
+
+	celebration      string    // Winner banner shown over the game panel title
+	celebrationUntil time.Time // When the banner goes away
 	playerSelectActive bool                // True when selecting a player for private chat
 	nearbyPlayers      []string            // List of nearby players for selection
+
+	// Overlays and mini-games
+	overlay       Overlay                           // Panel currently drawn over the game world
+	miniGame      *connection.MiniGameStateEvent    // Current (or last finished) mini-game
+	pendingInvite string                            // Username of the player who challenged us
+	triviaAnswer  string                            // Our answer in the current trivia battle, "" until we pick one
+	playerCursor  int                               // Selected row in the player list
+	profileUser   string                            // Player whose profile is open
+	leaderboard   []protocol.LeaderboardEntry       // Last leaderboard the server sent, nil until it arrives
+	huntHistory   []protocol.TreasureHuntRoundEntry // Today's past treasure hunt rounds, nil until they arrive
+	shop          *connection.ShopStateEvent        // Shop catalog and what we own, nil until it arrives
+	shopCursor    int                               // Selected row in the shop
+
+	scavenger *connection.ScavengerStateEvent // Our scavenger hunt progress, nil when no hunt is running
+	points    int                             // Our points balance
+	streak    int                             // Consecutive days we've logged in
+	latency   time.Duration                   // Round trip to the server from the last ping, 0 until measured
+
+	// Lobby room browser
+	lobbyRooms       []protocol.RoomInfo // Rooms the server listed, nil until they arrive
+	lobbyCursor      int                 // Selected row in the room list
+	lobbyCreating    bool                // True while typing the name of a new room
+	lobbyInput       string              // Name of the new room being typed
+	lobbyError       string              // Why the server wouldn't let us join, shown under the list
+	lobbyPasswordFor string              // Room we're typing a password for, "" when not asked
+	lobbyNewRoom     bool                // The password is for a room we're creating, so it's optional
+	lobbyPassword    string              // Password being typed
+	lobbySuggestion  string              // Room the server offered when ours was full, picked once the list arrives
 }
 
 // NewModel creates a new Bubble Tea model with a connection manager
@@ -79,6 +164,8 @@ func NewModel(serverURL string) Model {
 		eventChan <- event
 	})
 
+	ctx, cancel := context.WithCancel(context.Background())
+
 	return Model{
 		viewState:        ViewLoading,
 		connMgr:          connMgr,
@@ -89,22 +176,35 @@ func NewModel(serverURL string) Model {
 		width:            80,
 		height:           24,
 		serverURL:        serverURL,
-		roomID:           "default-room", // Default room
 		loadingDots:      0,
 		reconnectAttempt: 0,
 		maxReconnects:      5,
 		chatMode:           ChatModeGlobal,
 		chatTarget:         "",
-		announcements:      []string{"Welcome to Always at Morg!"},
+		announcements:      []announcement{{text: i18n.T("Welcome to Always at Morg!"), at: time.Now()}},
 		globalChatMessages: []string{},
 		privateChatHistory: make(map[string][]string),
 		roomChatMessages:   make(map[string][]string),
 		chatInput:          "",
 		chatInputActive:    false,
-		currentClue:        "Loading clue...",
+		currentClue:        i18n.T("Loading clue..."),
+		frame:              &gameFrame{},
+		stats:              &debugStats{},
+		config:             config.Default(),
+		keys:               newKeyMap(config.Default()),
+		ctx:                ctx,
+		cancel:             cancel,
 	}
 }
 
+// NewModelWithInvite creates a model that connects to the invite's server and joins its
+// room as soon as the player has picked a username
+func NewModelWithInvite(invite protocol.Invite) Model {
+	m := NewModel(invite.Server)
+	m.invite = &invite
+	return m
+}
+
 // NewModelWithView creates a model starting at a specific view (for testing)
 func NewModelWithView(view ViewState) Model {
 	m := NewModel("ws://localhost:8080/ws")
@@ -118,11 +218,15 @@ func NewModelWithView(view ViewState) Model {
 
 // Init initializes the model
 func (m Model) Init() tea.Cmd {
+	if m.replay != nil {
+		m.replay.Start()
+		return listenForEventsCmd(m.connMgr, m.eventChan)
+	}
 	// Start connection attempt on loading screen using the existing connection manager
 	if m.viewState == ViewLoading && m.connMgr != nil {
 		return tea.Batch(
-			connectCmd(m.connMgr), // Connect to server
-			tickCmd(),             // Tick for animations
+			connectCmd(m.ctx, m.connMgr), // Connect to server
+			tickCmd(),                    // Tick for animations
 			listenForEventsCmd(m.connMgr, m.eventChan), // Listen for server events
 		)
 	}
@@ -131,40 +235,34 @@ func (m Model) Init() tea.Cmd {
 
 // Update handles messages and updates the model
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Whether the terminal has focus decides if desktop notifications show
+	if focused, ok := focusChange(msg); ok {
+		m.unfocused = !focused
+		return m, nil
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-
-		// Changes dynamically the game world size based on terminal size
-		// Cap viewport to reasonable maximums for performance
-		maxWidth := 120  // Maximum viewport width
-		maxHeight := 60  // Maximum viewport height
-
-		gameWidth := int(0.8 * float64(msg.Width)) // 80% of terminal width because of chat panel
-		if gameWidth > maxWidth {
-			gameWidth = maxWidth
-		}
-		m.GameWorldWidth = gameWidth
-
-		gameHeight := msg.Height
-		if gameHeight > maxHeight {
-			gameHeight = maxHeight
-		}
-		m.GameWorldHeight = gameHeight
-
-		// Populate grids from game world and room map data
-		m.populateGrids()
-
+		m.tooSmall = m.width < minWidth || m.height < minHeight
+		m.resizeGameWorld()
 		return m, nil
 
 	case tea.KeyMsg:
+		// Nothing on screen to act on until the terminal is big enough, so only quitting works
+		if m.tooSmall {
+			return m.updateTooSmall(msg)
+		}
+
 		// Route to appropriate screen update handler
 		switch m.viewState {
 		case ViewLoading:
 			return m.updateLoading(msg)
 		case ViewUsernameEntry:
 			return m.updateUsernameEntry(msg)
+		case ViewLobby:
+			return m.updateLobby(msg)
 		case ViewAvatarCustomization:
 			return m.updateAvatarCustomization(msg)
 		case ViewMainGame:
@@ -176,10 +274,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.reconnectAttempt = 0 // Reset retry counter
 		m.waitingToRetry = false
 		m.err = nil
+		if m.rejoining {
+			// Back to the room we were in; the game view returns when the server answers
+			m.joinRoom(m.roomID, m.roomPassword)
+			return m, nil
+		}
 		m.viewState = ViewUsernameEntry
 		return m, nil
 
 	case connectionErrorMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil // We're quitting
+		}
+
 		// Connection failed
 		m.err = msg.err
 		m.reconnectAttempt++
@@ -202,7 +309,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Time to retry connection after delay
 		if m.viewState == ViewLoading && m.reconnectAttempt < m.maxReconnects {
 			m.waitingToRetry = false
-			return m, connectCmd(m.connMgr)
+			return m, connectCmd(m.ctx, m.connMgr)
 		}
 		return m, nil
 
@@ -222,13 +329,55 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// resizeGameWorld sizes the game world to the terminal, within the viewport caps set in
+// the settings
+func (m *Model) resizeGameWorld() {
+	// Changes dynamically the game world size based on terminal size
+	// Cap viewport to reasonable maximums for performance
+	maxWidth := m.config.Settings.MaxViewportWidth   // Maximum viewport width
+	maxHeight := m.config.Settings.MaxViewportHeight // Maximum viewport height
+
+	gameWidth := int(0.8 * float64(m.width)) // 80% of terminal width because of chat panel
+	if gameWidth > maxWidth {
+		gameWidth = maxWidth
+	}
+	m.GameWorldWidth = gameWidth
+
+	gameHeight := m.height
+	if gameHeight > maxHeight {
+		gameHeight = maxHeight
+	}
+	m.GameWorldHeight = gameHeight
+
+	// Populate grids from game world and room map data
+	m.populateGrids()
+	m.stateVersion++
+}
+
 // View renders the current view
 func (m Model) View() string {
+	if m.stats != nil {
+		m.stats.countFrame()
+	}
+	if asciiMode {
+		return toASCII(m.view())
+	}
+	return m.view()
+}
+
+// view renders the current view with whatever characters it's drawn with
+func (m Model) view() string {
+	if m.tooSmall {
+		return m.viewTooSmall()
+	}
+
 	switch m.viewState {
 	case ViewLoading:
 		return m.viewLoading()
 	case ViewUsernameEntry:
 		return m.viewUsernameEntry()
+	case ViewLobby:
+		return m.viewLobby()
 	case ViewAvatarCustomization:
 		return m.viewAvatarCustomization()
 	case ViewMainGame:
@@ -237,8 +386,27 @@ func (m Model) View() string {
 	return ""
 }
 
-// Disconnect safely disconnects the connection manager
+// SetProxy makes the client connect through a proxy; see connection.Manager.SetProxy
+func (m *Model) SetProxy(proxy string) error {
+	return m.connMgr.SetProxy(proxy)
+}
+
+// SetHosted runs the client on the server for a player in a browser, connecting to the
+// game with dial; see connection.Manager.SetDialer
+func (m *Model) SetHosted(dial func(ctx context.Context, network, addr string) (net.Conn, error)) {
+	m.hosted = true
+	m.connMgr.SetDialer(dial)
+}
+
+// Disconnect safely disconnects the connection manager, aborting a connection attempt
+// that's still in flight
 func (m *Model) Disconnect() {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	if m.replay != nil {
+		m.replay.Stop()
+	}
 	if m.connMgr != nil {
 		m.connMgr.Disconnect()
 	}
@@ -253,61 +421,126 @@ func (m Model) handleConnectionEvent(event connection.Event) (tea.Model, tea.Cmd
 		return m, listenForEventsCmd(m.connMgr, m.eventChan)
 
 	case connection.DisconnectedEvent:
-		// Lost connection - go back to loading screen
+		if e.Error != nil && m.viewState == ViewLoading {
+			// A failed connection attempt, connectionErrorMsg retries it
+			return m, listenForEventsCmd(m.connMgr, m.eventChan)
+		}
+
+		// Lost connection - go back to loading screen and reconnect. Mid-game we keep our
+		// name, avatar and chats, and rejoin the room once the connection is back.
+		if m.viewState != ViewLoading {
+			m.rejoining = m.viewState == ViewMainGame && m.roomID != ""
+		}
 		m.viewState = ViewLoading
 		m.err = e.Error
-		return m, nil
-
-	case connection.ErrorEvent:
-		// Server sent error - show it but stay on current screen
+		m.reconnectAttempt = 0
+		m.waitingToRetry = false
+		m.chatInputActive = false
 		return m, tea.Batch(
-			tea.Println("Server error:", e.Message),
+			tickCmd(),
+			connectCmd(m.ctx, m.connMgr),
 			listenForEventsCmd(m.connMgr, m.eventChan),
 		)
 
+	case connection.ErrorEvent:
+		// Server sent error - show it in the announcements panel but stay on current screen
+		m.pushAnnouncement(errorStyle.Render(e.Message))
+		if m.rejoining {
+			// The room won't take us back (it filled up, we were kicked, ...), so pick another
+			m.rejoining = false
+			m.openLobby()
+		}
+		if e.Code == protocol.ErrCodeRoomFull && m.viewState != ViewMainGame {
+			// Back to the lobby, with the room the server suggested picked out
+			m.openLobby()
+			m.lobbySuggestion = e.Suggestion
+		}
+		if m.viewState == ViewLobby {
+			if e.Code == protocol.ErrCodePasswordRequired || e.Code == protocol.ErrCodeWrongPassword {
+				m.askRoomPassword(m.roomID, false)
+			}
+			m.lobbyError = e.Message // The lobby has no announcements panel
+		}
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
 	// ============================================
 	// GAME STATE EVENTS
 	// ============================================
 	case connection.GameStateEvent:
 		// Server sent game state update - recalculate viewport and re-render
 		m.viewState = ViewMainGame
+		m.rejoining = false
+		if gameState := m.connMgr.GetState(); gameState != nil {
+			setTimeOfDay(gameState.TimeOfDay)
+		}
+		if m.replay != nil {
+			m.followReplayPlayer(0) // The player we follow may have left
+		}
 		m.populateGrids() // Recalculate viewport based on current player position
+		m.stateVersion++
 		return m, listenForEventsCmd(m.connMgr, m.eventChan)
 
 	case connection.GlobalChatMessagesEvent:
 		// Receive all global chat messages from server (replace, don't append)
+		// Notify about new messages, but not the history we get on joining
+		var bell tea.Cmd
+		if old := len(m.globalChatMessages); len(e.Messages) > old && old > 0 {
+			for _, msg := range e.Messages[old:] {
+				if msg.Username != m.userName {
+					if cmd := m.notifyChat(m.publicChatKind(msg.Message), msg.Username, msg.Message, m.chatMode == ChatModeGlobal); cmd != nil {
+						bell = cmd
+					}
+				}
+			}
+		}
 		m.globalChatMessages = make([]string, 0, len(e.Messages))
 		for _, msg := range e.Messages {
 			// Format: [Username] Message
-			formattedMsg := highlightStyle.Render("["+msg.Username+"]") + " " + msg.Message
+			formattedMsg := m.formatChatLine(msg.Username, msg.Message, msg.Timestamp)
 			m.globalChatMessages = append(m.globalChatMessages, formattedMsg)
 		}
-		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+		return m, tea.Batch(bell, listenForEventsCmd(m.connMgr, m.eventChan))
 
 	case connection.RoomChatMessagesEvent:
 		// Receive all room chat messages for a specific room (replace, don't append)
+		var bell tea.Cmd
+		if old := len(m.roomChatMessages[e.RoomNumber]); len(e.Messages) > old && old > 0 {
+			shown := m.chatMode == ChatModeRoom && m.getCurrentPlayerRoom() == e.RoomNumber
+			for _, msg := range e.Messages[old:] {
+				if msg.Username != m.userName {
+					if cmd := m.notifyChat(m.publicChatKind(msg.Message), msg.Username, msg.Message, shown); cmd != nil {
+						bell = cmd
+					}
+				}
+			}
+		}
 		m.roomChatMessages[e.RoomNumber] = make([]string, 0, len(e.Messages))
 		for _, msg := range e.Messages {
 			// Format: [Username] Message
-			formattedMsg := highlightStyle.Render("["+msg.Username+"]") + " " + msg.Message
+			formattedMsg := m.formatChatLine(msg.Username, msg.Message, msg.Timestamp)
 			m.roomChatMessages[e.RoomNumber] = append(m.roomChatMessages[e.RoomNumber], formattedMsg)
 		}
-		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+		return m, tea.Batch(bell, listenForEventsCmd(m.connMgr, m.eventChan))
 
 	case connection.PrivateChatMessageEvent:
 		// Received a private message - append to private chat history for the relevant user
 		// Determine which user's chat history to update (the other person, not ourselves)
 		var otherUser string
 		var formattedMsg string
+		var bell tea.Cmd
 
 		if e.FromUsername == m.userName {
 			// Sent by me to someone else
 			otherUser = e.ToUsername
-			formattedMsg = highlightStyle.Render("[You]") + " " + e.Message
+			formattedMsg = m.formatChatLine(i18n.T("You"), e.Message, e.Timestamp)
 		} else {
 			// Received from someone else
 			otherUser = e.FromUsername
-			formattedMsg = highlightStyle.Render("["+e.FromUsername+"]") + " " + e.Message
+			formattedMsg = m.formatChatLine(e.FromUsername, e.Message, e.Timestamp)
+			bell = tea.Batch(
+				m.notifyChat(chatPrivate, e.FromUsername, e.Message, m.chatMode == ChatModePrivate && m.chatTarget == e.FromUsername),
+				m.desktopNotify(i18n.Tf("Message from %s", e.FromUsername), e.Message),
+			)
 		}
 
 		// Append to this user's private chat history
@@ -315,7 +548,7 @@ func (m Model) handleConnectionEvent(event connection.Event) (tea.Model, tea.Cmd
 			m.privateChatHistory[otherUser] = []string{}
 		}
 		m.privateChatHistory[otherUser] = append(m.privateChatHistory[otherUser], formattedMsg)
-		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+		return m, tea.Batch(bell, listenForEventsCmd(m.connMgr, m.eventChan))
 
 	case connection.OnboardRequestEvent:
 		// Server requests onboarding - transition to avatar customization screen
@@ -324,6 +557,136 @@ func (m Model) handleConnectionEvent(event connection.Event) (tea.Model, tea.Cmd
 
 	case connection.TreasureHuntStateEvent:
 		m.currentClue = e.ClueText
+		m.clueKind = ""
+		if e.Category != "" {
+			m.clueKind = i18n.Tf("%s · %s · %d pts", e.Category, e.Difficulty, e.Points)
+		}
+		var notify tea.Cmd
+		if e.EndsAt != 0 && e.EndsAt != m.clueEndsAt {
+			m.huntChoice = 0 // A new round, or one restarted after the hunt was stopped
+			if m.huntSeen {
+				notify = m.desktopNotify(i18n.T("Treasure hunt"), i18n.Tf("A new round started: %s", e.ClueText))
+			}
+		}
+		m.huntSeen = true
+		m.clueEndsAt = e.EndsAt
+		m.clueNextAt = e.NextAt
+		m.clueOptions = e.Options
+		return m, tea.Batch(notify, listenForEventsCmd(m.connMgr, m.eventChan))
+
+	case connection.InteractResultEvent:
+		// Flavor text is only for us
+		m.pushAnnouncement(mutedStyle.Render(e.Message))
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.MiniGameInviteEvent:
+		m.pendingInvite = e.From
+		m.pushAnnouncement(highlightStyle.Render(i18n.Tf("%s challenged you to %s!", e.From, miniGameName(e.Game))) +
+			mutedStyle.Render(" "+i18n.T("/accept or /decline")))
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.MiniGameDeclinedEvent:
+		m.pushAnnouncement(mutedStyle.Render(i18n.Tf("%s declined your challenge to %s", e.By, miniGameName(e.Game))))
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.MiniGameStateEvent:
+		if m.miniGame == nil || m.miniGame.GameID != e.GameID {
+			m.triviaAnswer = ""
+		}
+		m.miniGame = &e
+		m.overlay = OverlayMiniGame
+		if e.Message != "" {
+			m.pushAnnouncement(mutedStyle.Render(e.Message))
+		}
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.PointsEvent:
+		m.points = e.Balance
+		if e.Delta > 0 {
+			m.pushAnnouncement(highlightStyle.Render(i18n.Tf("+%d points", e.Delta)) + mutedStyle.Render(" - "+e.Reason))
+		}
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.TreasureHuntWinnerEvent:
+		if e.Winner == m.userName {
+			m.celebration = i18n.Tf("You solved the riddle! +%d points", e.Points)
+		} else {
+			m.celebration = i18n.Tf("%s solved the riddle!", e.Winner)
+		}
+		m.celebrationUntil = time.Now().Add(celebrationDuration) // The hunt announces the winner itself
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.LoginStreakEvent:
+		m.streak = e.Streak
+		// Greet the player with their streak as soon as they're in
+		if e.Streak > 1 {
+			m.pushAnnouncement(highlightStyle.Render("🔥 "+i18n.Tf("Welcome back, %s - %d-day login streak!", m.userName, e.Streak)) +
+				mutedStyle.Render(" "+i18n.T("Come back tomorrow for a bigger bonus.")))
+		}
+		if e.Reward != "" {
+			m.pushAnnouncement(highlightStyle.Render("🎁 " + i18n.Tf("Your streak earned the %s! Wear it from the shop ($).", e.Reward)))
+		}
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.ShopStateEvent:
+		m.shop = &e
+		if e.Message != "" {
+			m.pushAnnouncement(highlightStyle.Render(e.Message))
+		}
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.RoomListEvent:
+		m.lobbyRooms = e.Rooms
+		if m.lobbyRooms == nil {
+			m.lobbyRooms = []protocol.RoomInfo{} // Loaded, just empty
+		}
+		m.lobbyCursor = min(m.lobbyCursor, max(len(m.lobbyRooms)-1, 0))
+		m.selectSuggestedRoom()
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.LeaderboardEvent:
+		m.leaderboard = e.Entries
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.AnnouncementsEvent:
+		m.addServerAnnouncements(e.Announcements)
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.AnnouncementHistoryEvent:
+		m.addAnnouncementHistory(e.Announcements)
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.TreasureHuntHistoryEvent:
+		m.huntHistory = e.Rounds
+		if m.huntHistory == nil {
+			m.huntHistory = []protocol.TreasureHuntRoundEntry{} // Loaded, just empty
+		}
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.ScavengerStateEvent:
+		m.scavenger = &e
+		if !e.Active {
+			m.scavenger = nil
+		}
+		if e.Message != "" {
+			m.pushAnnouncement(highlightStyle.Render(e.Message))
+		}
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.MessagesDroppedEvent:
+		m.pushAnnouncement(errorStyle.Render(i18n.Tf("%d messages sent while reconnecting were lost", e.Count)))
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.LatencyEvent:
+		m.latency = e.RTT
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.IdleWarningEvent:
+		m.pushAnnouncement(errorStyle.Render(i18n.Tf("Still there? You'll be disconnected for inactivity in %ds - move or chat to stay.", e.Seconds)))
+		return m, listenForEventsCmd(m.connMgr, m.eventChan)
+
+	case connection.EmoteEvent:
+		m.pushAnnouncement(highlightStyle.Render("* ") + e.Message)
 		return m, listenForEventsCmd(m.connMgr, m.eventChan)
 
 	default:
@@ -331,3 +694,4 @@ func (m Model) handleConnectionEvent(event connection.Event) (tea.Model, tea.Cmd
 		return m, listenForEventsCmd(m.connMgr, m.eventChan)
 	}
 }
+
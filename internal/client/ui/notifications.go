@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"log"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yourusername/always-at-morg/internal/client/config"
+	"github.com/yourusername/always-at-morg/internal/client/notifier"
+)
+
+// toastDuration is how long a notification stays in the status bar
+const toastDuration = 6 * time.Second
+
+// toastLength caps the message quoted in a notification, so it fits beside the controls
+const toastLength = 40
+
+// chatKind is what sort of chat message a notification is for
+type chatKind int
+
+const (
+	chatPublic  chatKind = iota // Global or room chat
+	chatMention                 // Global or room chat that @mentions us
+	chatPrivate                 // A private message
+)
+
+// mentions reports whether message @mentions username, e.g. "@alice" but not "@alice2"
+// or "mail@alice.com"
+func mentions(message, username string) bool {
+	if username == "" {
+		return false
+	}
+	message, mention := strings.ToLower(message), "@"+strings.ToLower(username)
+	for start := 0; ; {
+		i := strings.Index(message[start:], mention)
+		if i < 0 {
+			return false
+		}
+		i += start
+		before, _ := utf8.DecodeLastRuneInString(message[:i])
+		after, _ := utf8.DecodeRuneInString(message[i+len(mention):])
+		if (i == 0 || !isNameRune(before)) && (i+len(mention) == len(message) || !isNameRune(after)) {
+			return true
+		}
+		start = i + len(mention)
+	}
+}
+
+// publicChatKind says whether a global or room chat message mentions us
+func (m Model) publicChatKind(message string) chatKind {
+	if mentions(message, m.userName) {
+		return chatMention
+	}
+	return chatPublic
+}
+
+// isNameRune reports whether r can be part of a username
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-'
+}
+
+// notifyChat lets the player know about a chat message from someone else: a toast in
+// the status bar if it's a private message or mention they can't see in the chat mode
+// they're in, and the bell if they want one. shown says whether the chat box shows it.
+func (m *Model) notifyChat(kind chatKind, from, message string, shown bool) tea.Cmd {
+	if !shown {
+		switch {
+		case kind == chatPrivate && m.config.Settings.ToastPrivate:
+			m.showToast("✉ " + from + ": " + message)
+		case kind == chatMention && m.config.Settings.ToastMentions:
+			m.showToast("@ " + from + ": " + message)
+		}
+	}
+	return m.chatBell(kind)
+}
+
+// showToast puts a notification in the status bar for a few seconds
+func (m *Model) showToast(text string) {
+	if runes := []rune(text); len(runes) > toastLength {
+		text = string(runes[:toastLength-1]) + "…"
+	}
+	m.toast = text
+	m.toastUntil = time.Now().Add(toastDuration)
+}
+
+// activeToast returns the notification to show in the status bar, if there is one
+func (m Model) activeToast() (string, bool) {
+	return m.toast, m.toast != "" && time.Now().Before(m.toastUntil)
+}
+
+// desktopNotify shows a desktop notification if the player wants one now: always, or
+// while the terminal isn't focused. Replays never show them.
+func (m Model) desktopNotify(title, body string) tea.Cmd {
+	switch m.config.Settings.Desktop {
+	case config.DesktopAlways:
+	case config.DesktopUnfocused:
+		if !m.unfocused {
+			return nil
+		}
+	default:
+		return nil
+	}
+	if m.replay != nil || m.hosted {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := notifier.Notify(title, body); err != nil {
+			log.Printf("Desktop notification failed: %v", err)
+		}
+		return nil
+	}
+}
+
+// chatBell rings the terminal bell for a chat message from someone else, if the player
+// wants to hear about that kind of message
+func (m Model) chatBell(kind chatKind) tea.Cmd {
+	switch m.config.Settings.ChatNotifications {
+	case config.NotifyAll:
+	case config.NotifyMentions:
+		if kind == chatPublic {
+			return nil
+		}
+	case config.NotifyPrivate:
+		if kind != chatPrivate {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return func() tea.Msg {
+		// The bell is a single control character, so it can't garble the frame being drawn
+		os.Stderr.WriteString("\a")
+		return nil
+	}
+}
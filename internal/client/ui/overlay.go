@@ -0,0 +1,699 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// Overlay identifies a panel drawn in place of the game world in the main view
+type Overlay int
+
+const (
+	OverlayNone Overlay = iota
+	OverlayMiniGame
+	OverlayPlayers
+	OverlayProfile
+	OverlayLeaderboard
+	OverlayShop
+	OverlayTreasureHunt
+	OverlayHuntHistory
+	OverlaySettings
+	OverlayHelp
+)
+
+// updateOverlay handles keys while an overlay is open
+func (m Model) updateOverlay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.Disconnect()
+		return m, tea.Quit
+
+	case "esc":
+		// Esc while rebinding a key only stops waiting for the key
+		if m.overlay == OverlaySettings && m.rebinding != "" {
+			m.rebinding = ""
+			return m, nil
+		}
+		// Closing a finished game forgets it; a running game can be reopened with /board
+		if m.overlay == OverlayMiniGame && m.miniGame != nil && m.miniGame.Finished {
+			m.miniGame = nil
+		}
+		// A profile opened from the player list goes back to the list
+		if m.overlay == OverlayProfile {
+			m.overlay = OverlayPlayers
+			return m, nil
+		}
+		m.overlay = OverlayNone
+		return m, nil
+	}
+
+	switch m.overlay {
+	case OverlayMiniGame:
+		return m.updateMiniGameOverlay(msg)
+	case OverlayPlayers:
+		return m.updatePlayersOverlay(msg)
+	case OverlayLeaderboard:
+		if key.Matches(msg, m.keys.Leaderboard) {
+			m.overlay = OverlayNone
+		}
+	case OverlayShop:
+		return m.updateShopOverlay(msg)
+	case OverlayTreasureHunt:
+		return m.updateTreasureHuntOverlay(msg)
+	case OverlayHuntHistory:
+		if msg.Type == tea.KeyTab {
+			m.openTreasureHunt()
+		}
+	case OverlaySettings:
+		return m.updateSettingsOverlay(msg)
+	case OverlayHelp:
+		return m.updateHelpOverlay(msg)
+	}
+	return m, nil
+}
+
+// renderOverlay renders the open overlay in the game panel area
+func (m Model) renderOverlay(width, height int) string {
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, m.overlayContent(width, height))
+}
+
+// overlayContent renders the open overlay's content for an area of width x height
+func (m Model) overlayContent(width, height int) string {
+	var content string
+	switch m.overlay {
+	case OverlayMiniGame:
+		content = m.renderMiniGameOverlay()
+	case OverlayPlayers:
+		content = m.renderPlayersOverlay(height)
+	case OverlayProfile:
+		content = m.renderProfileOverlay()
+	case OverlayLeaderboard:
+		content = m.renderLeaderboardOverlay()
+	case OverlayShop:
+		content = m.renderShopOverlay()
+	case OverlayTreasureHunt:
+		content = m.renderTreasureHuntOverlay(width)
+	case OverlayHuntHistory:
+		content = m.renderHuntHistoryOverlay(width, height)
+	case OverlaySettings:
+		content = m.renderSettingsOverlay(height)
+	case OverlayHelp:
+		content = m.renderHelpOverlay(height)
+	}
+	return content
+}
+
+// miniGameName returns the display name for a mini-game kind
+func miniGameName(kind string) string {
+	switch kind {
+	case "tictactoe":
+		return i18n.T("tic-tac-toe")
+	case "trivia":
+		return i18n.T("a trivia battle")
+	}
+	return kind
+}
+
+// updateMiniGameOverlay sends moves for the shared board (cells 1-9)
+func (m Model) updateMiniGameOverlay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.miniGame != nil && m.miniGame.Game == "trivia" {
+		return m.updateTriviaOverlay(msg)
+	}
+	if m.miniGame == nil || m.miniGame.Finished || m.miniGame.Turn != m.userName {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if m.connMgr != nil && m.connMgr.IsConnected() {
+			m.connMgr.SendMiniGameMove(m.miniGame.GameID, msg.String())
+		}
+	}
+	return m, nil
+}
+
+// renderMiniGameOverlay draws the shared tic-tac-toe board
+func (m Model) renderMiniGameOverlay() string {
+	game := m.miniGame
+	if game == nil {
+		return mutedStyle.Render(i18n.T("No game in progress"))
+	}
+	if game.Game == "trivia" {
+		return m.renderTriviaOverlay()
+	}
+
+	title := titleStyle.Render(strings.ToUpper(miniGameName(game.Game)))
+	players := highlightStyle.Render(game.Players[0]+" (X)") + mutedStyle.Render("  vs  ") +
+		highlightStyle.Render(game.Players[1]+" (O)")
+
+	// Empty cells show their key number so players know what to press
+	var rows []string
+	for row := 0; row < 3; row++ {
+		var cells []string
+		for col := 0; col < 3; col++ {
+			i := row*3 + col
+			cell := mutedStyle.Render(fmt.Sprintf("%d", i+1))
+			if i < len(game.Board) && game.Board[i] != "" {
+				cell = selectedOptionStyle.Render(game.Board[i])
+			}
+			cells = append(cells, " "+cell+" ")
+		}
+		rows = append(rows, strings.Join(cells, "│"))
+		if row < 2 {
+			rows = append(rows, "───┼───┼───")
+		}
+	}
+	board := avatarBoxStyle.Render(strings.Join(rows, "\n"))
+
+	var status string
+	switch {
+	case game.Finished && game.Winner == "":
+		status = highlightStyle.Render(i18n.T("It's a draw!"))
+	case game.Finished && game.Winner == m.userName:
+		status = highlightStyle.Render(i18n.T("You won! 🏆"))
+	case game.Finished:
+		status = errorStyle.Render(i18n.Tf("%s won", game.Winner))
+	case game.Turn == m.userName:
+		status = highlightStyle.Render(i18n.T("Your turn - press 1-9"))
+	default:
+		status = mutedStyle.Render(i18n.Tf("Waiting for %s...", game.Turn))
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Center,
+		title,
+		players,
+		board,
+		status,
+		"",
+		mutedStyle.Render(i18n.T("ESC to close")),
+	)
+}
+
+// updateTriviaOverlay sends our answer (choices 1-4); it can't be changed once sent
+func (m Model) updateTriviaOverlay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.miniGame.Finished || m.triviaAnswer != "" {
+		return m, nil
+	}
+
+	switch key := msg.String(); key {
+	case "1", "2", "3", "4":
+		if m.connMgr != nil && m.connMgr.IsConnected() {
+			m.connMgr.SendMiniGameMove(m.miniGame.GameID, key)
+			m.triviaAnswer = key
+		}
+	}
+	return m, nil
+}
+
+// renderTriviaOverlay draws the question, its choices and, once it's over, the results.
+// The board is the question, then the choices, then result lines when finished.
+func (m Model) renderTriviaOverlay() string {
+	game := m.miniGame
+	if len(game.Board) < 2 {
+		return mutedStyle.Render(i18n.T("Waiting for the question..."))
+	}
+
+	title := titleStyle.Render(i18n.T("TRIVIA BATTLE"))
+	players := highlightStyle.Render(game.Players[0]) + mutedStyle.Render("  vs  ") + highlightStyle.Render(game.Players[1])
+	question := lipgloss.NewStyle().Width(50).Align(lipgloss.Center).Bold(true).Render(game.Board[0])
+
+	// The choices are all but the result lines appended once the battle is over
+	choices := game.Board[1:]
+	var results []string
+	if game.Finished && len(choices) > 4 {
+		choices, results = choices[:4], choices[4:]
+	}
+
+	var rows []string
+	for i, choice := range choices {
+		key := fmt.Sprintf("%d", i+1)
+		if key == m.triviaAnswer {
+			rows = append(rows, selectedOptionStyle.Render("> "+key+". "+choice))
+		} else {
+			rows = append(rows, "  "+mutedStyle.Render(key+".")+" "+choice)
+		}
+	}
+
+	var status string
+	switch {
+	case game.Finished && game.Winner == "":
+		status = highlightStyle.Render(i18n.T("It's a draw!"))
+	case game.Finished && game.Winner == m.userName:
+		status = highlightStyle.Render(i18n.T("You won! 🏆"))
+	case game.Finished:
+		status = errorStyle.Render(i18n.Tf("%s won", game.Winner))
+	case m.triviaAnswer != "":
+		status = mutedStyle.Render(i18n.T("Answer locked in - waiting for your opponent..."))
+	default:
+		left := max(int(time.Until(time.Unix(game.EndsAt, 0)).Seconds()), 0)
+		status = highlightStyle.Render(i18n.Tf("Press 1-%d  •  %ds left", len(choices), left))
+	}
+
+	lines := []string{title, players, "", question, avatarBoxStyle.Render(strings.Join(rows, "\n"))}
+	for _, result := range results {
+		lines = append(lines, mutedStyle.Render(result))
+	}
+	lines = append(lines, status, "", mutedStyle.Render(i18n.T("ESC to close")))
+
+	return lipgloss.JoinVertical(lipgloss.Center, lines...)
+}
+
+// onlinePlayers returns everyone in the game sorted by username
+func (m Model) onlinePlayers() []protocol.Player {
+	if m.connMgr == nil {
+		return nil
+	}
+	gameState := m.connMgr.GetState()
+	if gameState == nil {
+		return nil
+	}
+
+	players := make([]protocol.Player, 0, len(gameState.Players))
+	for _, player := range gameState.Players {
+		players = append(players, player)
+	}
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Username < players[j].Username
+	})
+	return players
+}
+
+// roomLabel describes where a position is ("Room 5" or "Hallway")
+func roomLabel(pos string) string {
+	roomData, err := getRoomMap()
+	if err != nil {
+		return ""
+	}
+	x, y := parsePosition(pos)
+	if roomNumber := roomData.RoomNumberAt(x, y); roomNumber != "" {
+		return i18n.Tf("Room %s", roomNumber)
+	}
+	return i18n.T("Hallway")
+}
+
+// updatePlayersOverlay moves the cursor through the player list and opens profiles
+func (m Model) updatePlayersOverlay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	players := m.onlinePlayers()
+
+	switch msg.String() {
+	case "up", "k", "w":
+		if m.playerCursor > 0 {
+			m.playerCursor--
+		}
+	case "down", "j", "s":
+		if m.playerCursor < len(players)-1 {
+			m.playerCursor++
+		}
+	case "tab":
+		m.overlay = OverlayNone
+	case "enter":
+		if m.playerCursor < len(players) {
+			m.profileUser = players[m.playerCursor].Username
+			m.overlay = OverlayProfile
+		}
+	}
+	return m, nil
+}
+
+// renderPlayersOverlay lists everyone online with their room and status
+func (m Model) renderPlayersOverlay(height int) string {
+	players := m.onlinePlayers()
+	title := titleStyle.Render(i18n.Tf("PLAYERS ONLINE (%d)", len(players)))
+
+	// Keep the cursor on screen when the list is longer than the panel
+	visible := max(height-8, 1)
+	start := 0
+	if m.playerCursor >= visible {
+		start = m.playerCursor - visible + 1
+	}
+
+	var rows []string
+	for i := start; i < len(players) && i < start+visible; i++ {
+		player := players[i]
+		name := fmt.Sprintf("%-16s", player.Username)
+		if i == m.playerCursor {
+			name = selectedOptionStyle.Render("> " + name)
+		} else {
+			name = "  " + highlightStyle.Render(name)
+		}
+
+		row := name + " " + mutedStyle.Render(fmt.Sprintf("%-8s", roomLabel(player.Pos)))
+		if player.Status != "" {
+			row += "  " + player.Status
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		rows = append(rows, mutedStyle.Render(i18n.T("Nobody's here yet")))
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		m.renderBusyRooms(),
+		"",
+		strings.Join(rows, "\n"),
+		"",
+		mutedStyle.Render(i18n.T("↑/↓: Select  •  ENTER: Profile  •  ESC: Close")),
+	)
+}
+
+// busyRoomsShown is how many of the busiest building rooms the players overlay names
+const busyRoomsShown = 3
+
+// renderBusyRooms names the building rooms with the most players in them
+func (m Model) renderBusyRooms() string {
+	if m.connMgr == nil {
+		return ""
+	}
+	occupancy := m.connMgr.GetOccupancy()
+	rooms := slices.SortedFunc(maps.Keys(occupancy), func(a, b string) int {
+		if occupancy[a] != occupancy[b] {
+			return occupancy[b] - occupancy[a]
+		}
+		return strings.Compare(a, b)
+	})
+	if len(rooms) == 0 {
+		return ""
+	}
+
+	var busy []string
+	for _, room := range rooms[:min(len(rooms), busyRoomsShown)] {
+		busy = append(busy, i18n.Tf("Room %s (%d)", room, occupancy[room]))
+	}
+	return mutedStyle.Render(i18n.Tf("Busiest: %s", strings.Join(busy, ", ")))
+}
+
+// renderProfileOverlay shows a single player's avatar, location and status
+func (m Model) renderProfileOverlay() string {
+	var player protocol.Player
+	found := false
+	for _, p := range m.onlinePlayers() {
+		if p.Username == m.profileUser {
+			player, found = p, true
+			break
+		}
+	}
+	if !found {
+		return mutedStyle.Render(i18n.Tf("%s is no longer online", m.profileUser))
+	}
+
+	status := mutedStyle.Render(i18n.T("No status set"))
+	if player.Status != "" {
+		status = player.Status
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Center,
+		titleStyle.Render(player.Username),
+		avatarBoxStyle.Render(createAvatarFromIndices(player.Avatar).Render()),
+		mutedStyle.Render(roomLabel(player.Pos)),
+		"",
+		status,
+		"",
+		mutedStyle.Render(i18n.T("ESC: Back")),
+	)
+}
+
+// renderLeaderboardOverlay ranks players by treasure hunt wins, then fastest solve
+func (m Model) renderLeaderboardOverlay() string {
+	title := titleStyle.Render(i18n.T("TREASURE HUNT LEADERBOARD"))
+
+	var rows []string
+	if m.leaderboard == nil {
+		rows = append(rows, mutedStyle.Render(i18n.T("Loading...")))
+	} else if len(m.leaderboard) == 0 {
+		rows = append(rows, mutedStyle.Render(i18n.T("No riddles solved yet - be the first!")))
+	} else {
+		rows = append(rows, mutedStyle.Render(fmt.Sprintf("%-4s %-16s %5s %8s %7s %7s", "#", i18n.T("Player"), i18n.T("Wins"), i18n.T("Fastest"), i18n.T("Streak"), i18n.T("Guesses"))))
+		for i, entry := range m.leaderboard {
+			row := fmt.Sprintf("%-4d %-16s %5d %7.1fs %3d/%-3d %7d", i+1, entry.Username, entry.Wins,
+				float64(entry.FastestSolveMs)/1000, entry.Streak, entry.BestStreak, entry.Guesses)
+			if entry.Username == m.userName {
+				row = selectedOptionStyle.Render(row)
+			} else {
+				row = highlightStyle.Render(row)
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		"",
+		strings.Join(rows, "\n"),
+		"",
+		mutedStyle.Render(i18n.T("Streak is current/best  •  L or ESC: Close")),
+	)
+}
+
+// openShop opens the cosmetics shop, fetching the catalog and what we own
+func (m *Model) openShop() {
+	if m.connMgr != nil && m.connMgr.IsConnected() {
+		m.connMgr.SendShopRequest()
+	}
+	m.shopCursor = 0
+	m.overlay = OverlayShop
+}
+
+// updateShopOverlay moves through the catalog; enter buys the item, or wears it if we own it
+func (m Model) updateShopOverlay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.shop == nil {
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k", "w":
+		if m.shopCursor > 0 {
+			m.shopCursor--
+		}
+	case "down", "j", "s":
+		if m.shopCursor < len(m.shop.Items)-1 {
+			m.shopCursor++
+		}
+	case "$":
+		m.overlay = OverlayNone
+	case "enter":
+		if m.shopCursor >= len(m.shop.Items) || m.connMgr == nil || !m.connMgr.IsConnected() {
+			return m, nil
+		}
+		item := m.shop.Items[m.shopCursor]
+		if slices.Contains(m.shop.Owned, item.ID) {
+			m.connMgr.SendShopEquip(item.ID)
+		} else {
+			m.connMgr.SendShopBuy(item.ID)
+		}
+	}
+	return m, nil
+}
+
+// renderShopOverlay lists the cosmetics with their price and whether we own or wear them
+func (m Model) renderShopOverlay() string {
+	title := titleStyle.Render(i18n.T("COSMETICS SHOP"))
+	balance := highlightStyle.Render(i18n.Tf("You have ⭐ %d", m.points))
+	if m.shop == nil {
+		return lipgloss.JoinVertical(lipgloss.Center, title, mutedStyle.Render(i18n.T("Loading...")))
+	}
+
+	var rows []string
+	for i, item := range m.shop.Items {
+		// Preview the accessory glyph, or the name color on a swatch
+		preview := lipgloss.NewStyle().Bold(true).Render(item.Value + " ")
+		if item.Kind == "name_color" {
+			preview = lipgloss.NewStyle().Foreground(lipgloss.Color(item.Value)).Bold(true).Render("Aa")
+		}
+
+		var tag string
+		switch {
+		case m.shop.Equipped[item.Kind] == item.ID:
+			tag = highlightStyle.Render(i18n.T("wearing"))
+		case slices.Contains(m.shop.Owned, item.ID):
+			tag = mutedStyle.Render(i18n.T("owned"))
+		case item.Reward:
+			tag = mutedStyle.Render(i18n.T("7-day login streak"))
+		default:
+			tag = fmt.Sprintf("⭐ %d", item.Price)
+		}
+
+		name := fmt.Sprintf("%-16s", item.Name)
+		if i == m.shopCursor {
+			name = selectedOptionStyle.Render("> " + name)
+		} else {
+			name = "  " + name
+		}
+		rows = append(rows, name+" "+preview+"  "+tag)
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		balance,
+		"",
+		strings.Join(rows, "\n"),
+		"",
+		mutedStyle.Render(i18n.T("↑/↓: Select  •  ENTER: Buy / Wear / Take off  •  ESC: Close")),
+	)
+}
+
+// updateTreasureHuntOverlay types a guess at the riddle; enter sends it. A trivia round
+// is answered with a single keypress instead.
+func (m Model) updateTreasureHuntOverlay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyTab {
+		m.openHuntHistory()
+		return m, nil
+	}
+	if len(m.clueOptions) > 0 {
+		if m.clueEndsAt != 0 && m.connMgr != nil && m.connMgr.IsConnected() {
+			if choice := msg.String(); len(choice) == 1 && choice[0] >= '1' && int(choice[0]-'0') <= len(m.clueOptions) {
+				m.answerTrivia(int(choice[0] - '0'))
+			}
+		}
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.KeyEnter:
+		guess := strings.TrimSpace(m.huntGuess)
+		if guess != "" {
+			m.sendTreasureHuntGuess(guess)
+		}
+		m.huntGuess = ""
+	case tea.KeyBackspace:
+		if m.huntGuess != "" {
+			runes := []rune(m.huntGuess)
+			m.huntGuess = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		if len(m.huntGuess) < maxGuessLength {
+			m.huntGuess += " "
+		}
+	case tea.KeyRunes:
+		if len(m.huntGuess) < maxGuessLength {
+			m.huntGuess += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+// renderTreasureHuntOverlay shows the riddle (or who solved it), its countdown and the guess box
+func (m Model) renderTreasureHuntOverlay(width int) string {
+	title := titleStyle.Render(i18n.T("TREASURE HUNT"))
+
+	lines := []string{title}
+	if m.clueKind != "" {
+		lines = append(lines, mutedStyle.Render(m.clueKind))
+	}
+	if countdown := m.renderClueCountdown(); countdown != "" {
+		lines = append(lines, countdown)
+	}
+	lines = append(lines, "")
+
+	// The clue text already reads as the hint, winner, cooldown or game over message
+	clueWidth := max(min(width-8, 60), 20)
+	lines = append(lines, lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFACD")).Width(clueWidth).Render(m.currentClue), "")
+
+	switch {
+	case len(m.clueOptions) > 0:
+		lines = append(lines, m.renderClueOptions(), "")
+		switch {
+		case m.clueEndsAt == 0:
+			// The results are in the clue text
+		case m.huntChoice == 0:
+			lines = append(lines, highlightStyle.Render(i18n.Tf("Press 1-%d to answer", len(m.clueOptions))))
+		default:
+			lines = append(lines, mutedStyle.Render(i18n.T("Answer locked in - everyone who's right scores when time runs out")))
+		}
+	case m.clueEndsAt != 0:
+		input := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(accentColor).
+			Width(clueWidth - 2).
+			Render(m.huntGuess + "█")
+		lines = append(lines, highlightStyle.Render(i18n.T("Your guess:")), input)
+	default:
+		lines = append(lines, mutedStyle.Render(i18n.T("No riddle to guess right now")))
+	}
+
+	help := i18n.T("ENTER: Guess  •  TAB: Earlier rounds  •  ESC: Close")
+	if len(m.clueOptions) > 0 {
+		help = i18n.Tf("1-%d: Answer  •  TAB: Earlier rounds  •  ESC: Close", len(m.clueOptions))
+	}
+	lines = append(lines, "", mutedStyle.Render(help))
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// renderHuntHistoryOverlay lists the rounds played today, newest first, so players who
+// joined late can see the riddles they missed and who solved them
+func (m Model) renderHuntHistoryOverlay(width, height int) string {
+	title := titleStyle.Render(i18n.T("TODAY'S TREASURE HUNT"))
+	textWidth := max(min(width-8, 60), 20)
+
+	var rows []string
+	switch {
+	case m.huntHistory == nil:
+		rows = append(rows, mutedStyle.Render(i18n.T("Loading...")))
+	case len(m.huntHistory) == 0:
+		rows = append(rows, mutedStyle.Render(i18n.T("No rounds played yet today")))
+	default:
+		// Each round takes about five lines; show as many of the latest as fit
+		shown := min(len(m.huntHistory), max((height-6)/5, 1))
+		for i := len(m.huntHistory) - 1; i >= len(m.huntHistory)-shown; i-- {
+			round := m.huntHistory[i]
+			var result string
+			switch {
+			case len(round.Correct) > 0:
+				result = fmt.Sprintf("✅ %s (%.1fs)", strings.Join(round.Correct, ", "), float64(round.SolveMs)/1000)
+			case round.Winner != "":
+				result = "✅ " + i18n.Tf("%s in %.1fs", round.Winner, float64(round.SolveMs)/1000)
+			default:
+				result = "⏰ " + i18n.T("Nobody solved it")
+			}
+			if len(rows) > 0 {
+				rows = append(rows, "")
+			}
+			rows = append(rows,
+				highlightStyle.Render(i18n.Tf("Round %d", round.Round))+
+					mutedStyle.Render(fmt.Sprintf("  %s · %s · %s", round.Category, round.Difficulty, time.Unix(round.PlayedAt, 0).Format("3:04 PM"))),
+				lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFACD")).Width(textWidth).Render(round.Question),
+				i18n.T("Answer:")+" "+selectedOptionStyle.Render(round.Answer)+"  "+result,
+			)
+		}
+		if earlier := len(m.huntHistory) - shown; earlier > 0 {
+			rows = append(rows, "", mutedStyle.Render(i18n.Tf("+ %d earlier rounds", earlier)))
+		}
+	}
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		"",
+		strings.Join(rows, "\n"),
+		"",
+		mutedStyle.Render(i18n.T("TAB: Current riddle  •  ESC: Close")),
+	)
+}
+
+// renderClueOptions lists a trivia round's choices, marking the one we picked
+func (m Model) renderClueOptions() string {
+	rows := make([]string, len(m.clueOptions))
+	for i, option := range m.clueOptions {
+		row := fmt.Sprintf("  %d) %s", i+1, option)
+		if i+1 == m.huntChoice {
+			row = highlightStyle.Render(fmt.Sprintf("▶ %d) %s", i+1, option))
+		}
+		rows[i] = row
+	}
+	return strings.Join(rows, "\n")
+}
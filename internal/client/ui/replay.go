@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/always-at-morg/internal/client/connection"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// Replay seek steps
+const (
+	replaySeekShort = 10 * time.Second
+	replaySeekLong  = time.Minute
+)
+
+// NewReplayModel creates a model that plays a recorded session journal instead of
+// connecting to a server. The camera follows the given player, or the first one in the
+// room if they aren't there.
+func NewReplayModel(journal []protocol.JournalEntry, follow string) (Model, error) {
+	m := NewModel("")
+	replay, err := connection.NewReplay(m.connMgr, journal)
+	if err != nil {
+		return m, err
+	}
+	m.replay = replay
+	m.viewState = ViewMainGame
+	m.userName = follow
+	m.announcements = []announcement{{text: i18n.T("Replaying a recorded session"), at: time.Now()}}
+	return m, nil
+}
+
+// updateReplay handles the keys while replaying, which control playback instead of a
+// player
+func (m Model) updateReplay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q", "Q":
+		m.Disconnect()
+		return m, tea.Quit
+
+	case "r", "R":
+		return m, tea.ClearScreen
+
+	case " ", "p", "P":
+		m.replay.TogglePause()
+
+	case "left", "h":
+		m.replay.Seek(-replaySeekShort)
+	case "right", "l":
+		m.replay.Seek(replaySeekShort)
+	case "down", "j":
+		m.replay.Seek(-replaySeekLong)
+	case "up", "k":
+		m.replay.Seek(replaySeekLong)
+
+	case "+", "=":
+		_, _, _, speed := m.replay.Position()
+		m.replay.SetSpeed(speed * 2)
+	case "-", "_":
+		_, _, _, speed := m.replay.Position()
+		m.replay.SetSpeed(speed / 2)
+
+	case "tab", "]":
+		m.followReplayPlayer(1)
+	case "shift+tab", "[":
+		m.followReplayPlayer(-1)
+	}
+	return m, nil
+}
+
+// followReplayPlayer moves the camera step players along, in name order, or keeps it on
+// the player it follows with a step of 0. If that player isn't in the room it moves to
+// the first one who is.
+func (m *Model) followReplayPlayer(step int) {
+	state := m.connMgr.GetState()
+	if state == nil || len(state.Players) == 0 {
+		return
+	}
+	names := slices.Sorted(maps.Keys(state.Players))
+	i := slices.Index(names, m.userName)
+	if i < 0 {
+		m.userName = names[0]
+		return
+	}
+	m.userName = names[(i+step+len(names))%len(names)]
+}
+
+// renderReplayStatusBar shows where the replay is and how to control it, in place of
+// the player's status bar
+func (m Model) renderReplayStatusBar() string {
+	at, length, paused, speed := m.replay.Position()
+	state := "▶"
+	if paused {
+		state = "⏸"
+	}
+	progress := lipgloss.NewStyle().
+		Foreground(accentColor).
+		Bold(true).
+		Render(fmt.Sprintf("%s %s / %s  ×%g", state, replayClock(at), replayClock(length), speed))
+
+	following := lipgloss.NewStyle().
+		Foreground(successColor).
+		Bold(true).
+		Render(i18n.Tf("Following: %s", m.userName))
+
+	controls := mutedStyle.Render(i18n.T("SPACE: Pause  •  ←/→: 10s  •  ↓/↑: 1m  •  -/+: Speed  •  TAB: Next player  •  Q: Quit"))
+
+	return lipgloss.NewStyle().
+		Foreground(fgColor).
+		Width(m.width).
+		Padding(1, 0).
+		Align(lipgloss.Center).
+		Render(progress + "  " + following + "  •  " + controls)
+}
+
+// replayClock formats a position in the recording as m:ss, or h:mm:ss for long ones
+func replayClock(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d >= time.Hour {
+		return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
@@ -1,17 +1,18 @@
 package ui
 
 import (
-	"fmt"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
 )
 
 // updateLoading handles loading screen updates
 func (m Model) updateLoading(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c", "esc", "q":
+		m.Disconnect() // Don't leave a connection attempt hanging
 		return m, tea.Quit
 	}
 	return m, nil
@@ -38,19 +39,23 @@ func (m Model) viewLoading() string {
 				}
 				statusText = lipgloss.NewStyle().
 					Foreground(mutedColor).
-					Render(fmt.Sprintf("Waiting %ds before retry %d/%d%s%s", delay, m.reconnectAttempt, m.maxReconnects, dots, spaces))
+					Render(i18n.Tf("Waiting %ds before retry %d/%d", delay, m.reconnectAttempt, m.maxReconnects) + dots + spaces)
 			} else {
 				statusText = lipgloss.NewStyle().
 					Foreground(mutedColor).
-					Render(fmt.Sprintf("Retrying connection (%d/%d)%s%s", m.reconnectAttempt, m.maxReconnects, dots, spaces))
+					Render(i18n.Tf("Retrying connection (%d/%d)", m.reconnectAttempt, m.maxReconnects) + dots + spaces)
 			}
 		} else {
-			statusText = errorStyle.Render(fmt.Sprintf("Connection failed after %d attempts", m.maxReconnects))
+			statusText = errorStyle.Render(i18n.Tf("Connection failed after %d attempts", m.maxReconnects))
 		}
+	} else if m.rejoining && m.connMgr != nil && m.connMgr.IsConnected() {
+		statusText = lipgloss.NewStyle().
+			Foreground(mutedColor).
+			Render(i18n.Tf("Rejoining %s", m.roomID) + dots + spaces)
 	} else {
 		statusText = lipgloss.NewStyle().
 			Foreground(mutedColor).
-			Render("Connecting" + dots + spaces)
+			Render(i18n.T("Connecting") + dots + spaces)
 	}
 
 	// Main content - just title and status
@@ -62,7 +67,7 @@ func (m Model) viewLoading() string {
 	)
 
 	// Simple instructions
-	instructions := mutedStyle.Render("ESC to quit")
+	instructions := mutedStyle.Render(i18n.T("ESC to quit"))
 
 	// Layout
 	centeredMain := lipgloss.Place(m.width, m.height-3, lipgloss.Center, lipgloss.Center, mainContent)
@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// openLobby shows the room browser, fetching the rooms from the server
+func (m *Model) openLobby() {
+	m.viewState = ViewLobby
+	m.lobbyCreating = false
+	m.lobbyPasswordFor = ""
+	m.lobbyError = ""
+	if m.connMgr != nil && m.connMgr.IsConnected() {
+		m.connMgr.SendListRooms()
+	}
+}
+
+// joinRoom asks the server to put us in a room; it answers with the game state, with
+// onboarding if we're new, or with an error if the room wants a password
+func (m *Model) joinRoom(roomID, password string) {
+	if m.connMgr == nil || !m.connMgr.IsConnected() {
+		return
+	}
+	if err := m.connMgr.JoinRoom(roomID, m.userName, password); err != nil {
+		m.lobbyError = err.Error()
+		return
+	}
+	m.roomID = roomID
+	m.roomPassword = password
+	m.lobbyError = ""
+}
+
+// selectSuggestedRoom moves the cursor to the room the server suggested when the one we
+// wanted was full, or starts creating it if it doesn't exist yet
+func (m *Model) selectSuggestedRoom() {
+	if m.lobbySuggestion == "" {
+		return
+	}
+	suggestion := m.lobbySuggestion
+	m.lobbySuggestion = ""
+	for i, room := range m.lobbyRooms {
+		if room.ID == suggestion {
+			m.lobbyCursor = i
+			return
+		}
+	}
+	m.lobbyCreating = true
+	m.lobbyInput = suggestion
+}
+
+// askRoomPassword prompts for the password of a private room, or of one we're creating
+func (m *Model) askRoomPassword(roomID string, newRoom bool) {
+	m.lobbyCreating = false
+	m.lobbyPasswordFor = roomID
+	m.lobbyNewRoom = newRoom
+	m.lobbyPassword = ""
+	m.lobbyError = ""
+	m.roomID = "" // Not joining anything until the password is in
+}
+
+// updateLobby moves through the room list; enter joins the selected room and N names a new one
+func (m Model) updateLobby(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.lobbyPasswordFor != "" {
+		return m.updateLobbyPassword(msg)
+	}
+	if m.lobbyCreating {
+		return m.updateLobbyCreate(msg)
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		return m, tea.Quit
+
+	case "up", "k":
+		if m.lobbyCursor > 0 {
+			m.lobbyCursor--
+		}
+
+	case "down", "j":
+		if m.lobbyCursor < len(m.lobbyRooms)-1 {
+			m.lobbyCursor++
+		}
+
+	case "enter":
+		if m.lobbyCursor < len(m.lobbyRooms) {
+			room := m.lobbyRooms[m.lobbyCursor]
+			if room.HasPassword {
+				m.askRoomPassword(room.ID, false)
+			} else {
+				m.joinRoom(room.ID, "")
+			}
+		}
+
+	case "n", "N":
+		m.lobbyCreating = true
+		m.lobbyInput = ""
+		m.lobbyError = ""
+		m.roomID = ""
+
+	case "r", "R":
+		m.openLobby()
+	}
+	return m, nil
+}
+
+// updateLobbyCreate types the name of a new room; enter moves on to its optional password
+func (m Model) updateLobbyCreate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.lobbyCreating = false
+	case tea.KeyEnter:
+		if m.lobbyInput != "" {
+			m.askRoomPassword(m.lobbyInput, true)
+		}
+	case tea.KeyBackspace:
+		if m.lobbyInput != "" {
+			m.lobbyInput = m.lobbyInput[:len(m.lobbyInput)-1]
+		}
+	case tea.KeyRunes:
+		// Only what the server accepts in a room name
+		for _, r := range msg.Runes {
+			if len(m.lobbyInput) < protocol.MaxRoomIDLength && r < unicode.MaxASCII &&
+				(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_') {
+				m.lobbyInput += string(r)
+			}
+		}
+	}
+	return m, nil
+}
+
+// updateLobbyPassword types a room's password; enter joins with it. A new room can be
+// left without one to make it public.
+func (m Model) updateLobbyPassword(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.lobbyPasswordFor = ""
+		m.lobbyError = ""
+	case tea.KeyEnter:
+		if m.lobbyPassword != "" || m.lobbyNewRoom {
+			m.joinRoom(m.lobbyPasswordFor, m.lobbyPassword)
+		}
+	case tea.KeyBackspace:
+		if m.lobbyPassword != "" {
+			runes := []rune(m.lobbyPassword)
+			m.lobbyPassword = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		for _, r := range msg.Runes {
+			if utf8.RuneCountInString(m.lobbyPassword) < protocol.MaxRoomPasswordLength && unicode.IsPrint(r) {
+				m.lobbyPassword += string(r)
+			}
+		}
+	}
+	return m, nil
+}
+
+// lobbyRoomName is the room's name, marked if it needs a password
+func lobbyRoomName(room protocol.RoomInfo) string {
+	if room.HasPassword {
+		return room.Name + " " + i18n.T("(locked)")
+	}
+	return room.Name
+}
+
+// lobbyRoomPlayers shows how full the room is
+func lobbyRoomPlayers(room protocol.RoomInfo) string {
+	if room.Capacity == 0 {
+		return fmt.Sprint(room.Players)
+	}
+	return fmt.Sprintf("%d/%d", room.Players, room.Capacity)
+}
+
+// viewLobby renders the room browser
+func (m Model) viewLobby() string {
+	title := titleStyle.Render("ALWAYS AT MORG")
+	subtitle := subtitleStyle.Render(i18n.Tf("Welcome, %s! Pick a room to join", m.userName))
+
+	var rows []string
+	switch {
+	case m.lobbyRooms == nil:
+		rows = append(rows, mutedStyle.Render(i18n.T("Loading rooms...")))
+	default:
+		rows = append(rows, mutedStyle.Render(fmt.Sprintf("   %-24s %7s  %s", i18n.T("Room"), i18n.T("Players"), i18n.T("Map"))))
+		for i, room := range m.lobbyRooms {
+			row := fmt.Sprintf("%-24s %7s  %s", lobbyRoomName(room), lobbyRoomPlayers(room), room.Map)
+			if i == m.lobbyCursor && !m.lobbyCreating && m.lobbyPasswordFor == "" {
+				row = selectedOptionStyle.Render("> " + row)
+			} else {
+				row = "   " + highlightStyle.Render(row) // Lines up with the selected row's padding
+			}
+			rows = append(rows, row)
+		}
+	}
+
+	// Left-aligned as a block so the columns line up once it's centered
+	lines := []string{title, subtitle, "", lipgloss.JoinVertical(lipgloss.Left, rows...), ""}
+	if m.lobbyCreating {
+		input := mutedStyle.Render(i18n.T("room-name"))
+		if m.lobbyInput != "" {
+			input = highlightStyle.Render(m.lobbyInput) + cursorStyle.Render("|")
+		}
+		lines = append(lines, i18n.T("New room name:"), inputBoxStyle.Render(input))
+	}
+	if m.lobbyPasswordFor != "" {
+		prompt := i18n.Tf("Password for %s:", m.lobbyPasswordFor)
+		placeholder := i18n.T("password")
+		if m.lobbyNewRoom {
+			prompt = i18n.Tf("Password for %s (leave empty for a public room):", m.lobbyPasswordFor)
+			placeholder = i18n.T("no password")
+		}
+		input := mutedStyle.Render(placeholder)
+		if m.lobbyPassword != "" {
+			input = highlightStyle.Render(strings.Repeat("*", utf8.RuneCountInString(m.lobbyPassword))) + cursorStyle.Render("|")
+		}
+		lines = append(lines, prompt, inputBoxStyle.Render(input))
+	}
+	if m.roomID != "" && m.lobbyError == "" {
+		lines = append(lines, mutedStyle.Render(i18n.Tf("Joining %s...", m.roomID)))
+	}
+	if m.lobbyError != "" {
+		lines = append(lines, errorStyle.Render(m.lobbyError))
+	}
+	mainContent := lipgloss.JoinVertical(lipgloss.Center, lines...)
+
+	instructions := mutedStyle.Render(i18n.T("↑/↓: Select  •  ENTER: Join  •  N: New room  •  R: Refresh  •  ESC: Quit"))
+	switch {
+	case m.lobbyCreating:
+		instructions = mutedStyle.Render(i18n.T("ENTER: Next  •  ESC: Back to the list"))
+	case m.lobbyPasswordFor != "" && m.lobbyNewRoom:
+		instructions = mutedStyle.Render(i18n.T("ENTER: Create and join  •  ESC: Back to the list"))
+	case m.lobbyPasswordFor != "":
+		instructions = mutedStyle.Render(i18n.T("ENTER: Join  •  ESC: Back to the list"))
+	}
+
+	centeredMain := lipgloss.Place(m.width, m.height-3, lipgloss.Center, lipgloss.Center, mainContent)
+	bottomInstructions := lipgloss.Place(m.width, 2, lipgloss.Center, lipgloss.Bottom, instructions)
+	return centeredMain + "\n" + bottomInstructions
+}
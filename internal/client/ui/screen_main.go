@@ -1,62 +1,31 @@
 package ui
 
 import (
-	_ "embed"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
+	"github.com/yourusername/always-at-morg/internal/gamemap"
 	"github.com/yourusername/always-at-morg/internal/protocol"
 )
 
-//go:embed game_assets/map.txt
-var embeddedMap string
-
-type RoomCoord struct {
-	X    int
-	Y    int
-	Name string
-}
-
-// Room coordinates - each is a starting point for flood fill
-var roomCoordinates = []RoomCoord{
-	{X: 55, Y: 16, Name: "1"},
-	{X: 60, Y: 25, Name: "2"},
-	{X: 64, Y: 35, Name: "3"},
-	{X: 98, Y: 35, Name: "4"},
-	{X: 120, Y: 34, Name: "5"},
-	{X: 144, Y: 36, Name: "6"},
-	{X: 164, Y: 36, Name: "7"},
-	{X: 178, Y: 36, Name: "8"},
-	{X: 190, Y: 36, Name: "9"},
-	{X: 205, Y: 36, Name: "10"},
-	{X: 226, Y: 36, Name: "11"},
-	{X: 260, Y: 36, Name: "12"},
-	{X: 47, Y: 51, Name: "13"},
-	{X: 61, Y: 66, Name: "14"},
-	{X: 48, Y: 87, Name: "15"},
-	{X: 16, Y: 120, Name: "16"},
-	{X: 41, Y: 145, Name: "17"},
-	{X: 38, Y: 164, Name: "18"},
-	{X: 17, Y: 181, Name: "19"},
-	{X: 31, Y: 181, Name: "20"},
-	{X: 51, Y: 181, Name: "21"},
-	{X: 56, Y: 223, Name: "22"},
-	{X: 96, Y: 218, Name: "23"},
-}
-
 var (
-	roomMap        [250][400]string
+	roomMap        *gamemap.Grid
 	roomMapOnce    sync.Once
 	roomMapErr     error
-	styledCache    map[string]string
+	styledCache    [gamemap.NumTiles]string
 	styleCacheOnce sync.Once
 )
 
-func getRoomMap() ([250][400]string, error) {
+func getRoomMap() (*gamemap.Grid, error) {
 	roomMapOnce.Do(func() {
 		roomMap, roomMapErr = fillRoomMap()
 	})
@@ -64,28 +33,23 @@ func getRoomMap() ([250][400]string, error) {
 }
 
 func initStyledCache() {
-	styleCacheOnce.Do(func() {
-		styledCache = make(map[string]string)
-		// Pre-cache common values
-		styledCache["r"] = roomStyle
-		styledCache["o"] = wallStyle
-		styledCache["i"] = inaccessibleStyle
-		styledCache["e"] = entranceStyle
-		styledCache["b"] = backgroundOutsideStyle
-		styledCache["B"] = backgroundBStyle
-		styledCache["T"] = televisionStyle
-		styledCache["t"] = tableStyle
-		styledCache["p"] = plantStyle
-		styledCache["W"] = whiteboardStyle
-		styledCache["@"] = darkBrownStyle
-		styledCache["c"] = couchStyle
-		styledCache[" "] = backgroundStyle
-		styledCache["-1"] = backgroundStyle
-		// Pre-cache room numbers 1-50 (more than enough)
-		for i := 1; i <= 50; i++ {
-			styledCache[strconv.Itoa(i)] = roomFloorStyle
+	styleCacheOnce.Do(buildStyledCache)
+}
+
+// buildStyledCache (re)builds the tile cache for the current time of day
+func buildStyledCache() {
+	renderTileStyles()
+	for tile := range styledCache {
+		// 16 colors can't tint, so they keep to their own palette. Evening and night
+		// otherwise use tinted versions of the same tiles.
+		if colorProfile == termenv.ANSI {
+			styledCache[tile] = tile16Style(gamemap.Tile(tile))
+		} else if currentTimeOfDay != protocol.TimeOfDayDay {
+			styledCache[tile] = shadedTileStyle(gamemap.Tile(tile))
+		} else {
+			styledCache[tile] = dayTileStyle(gamemap.Tile(tile))
 		}
-	})
+	}
 }
 
 // parsePosition parses a position string "Y:X" into integer coordinates
@@ -112,13 +76,18 @@ func isWalkable(x, y int) bool {
 		return false
 	}
 
-	// Wall characters ("r", "o", "i") are not walkable
-	// "e" (entrances), "-1" (hallways), and room numbers ("1", "2", "3", ...) are walkable
-	value := roomMap[y][x]
-	return value != "r" && value != "o" && value != "i"
+	// Walls are not walkable; entrances, hallways and room floors are
+	switch roomMap.Tiles[y][x] {
+	case gamemap.TileRoomWall, gamemap.TileOuterWall, gamemap.TileInaccessible:
+		return false
+	}
+	return true
 }
 
-// hasPlayerNearby checks if there's a player within 4 tiles (Chebyshev distance)
+// hasPlayerNearby checks if moving to newX, newY would overlap another player or step
+// further into their personal space, which the room sets separately for hallways. The
+// server turns such moves down too; checking here saves predicting a move only to take
+// it back.
 func (m *Model) hasPlayerNearby(newX, newY int) bool {
 	if m.connMgr == nil {
 		return false
@@ -128,25 +97,31 @@ func (m *Model) hasPlayerNearby(newX, newY int) bool {
 	if gameState == nil {
 		return false
 	}
+	self, ok := gameState.Players[m.userName]
+	if !ok {
+		return false
+	}
+	currentX, currentY := parsePosition(self.Pos)
+	hallway := true
+	if roomData, err := getRoomMap(); err == nil {
+		hallway = roomData.RoomAt(newX, newY) == 0
+	}
+	radius := max(gamemap.AvatarReach, m.connMgr.GetRoomConfig().PersonalSpaceAt(hallway))
 
 	// Check all players
 	for username, player := range gameState.Players {
-		// Skip self
-		if username == m.userName {
+		// Skip self, and idle players so an AFK avatar can't wall off a corridor
+		if username == m.userName || player.Idle {
 			continue
 		}
 
 		// Parse player position
 		playerX, playerY := parsePosition(player.Pos)
 
-		// Calculate Chebyshev distance (max of abs differences)
-		// This creates a square area around each player
-		dx := abs(newX - playerX)
-		dy := abs(newY - playerY)
-		distance := max(dx, dy)
-
-		// If any player is within 4 tiles, can't move there
-		if distance <= 4 {
+		// Chebyshev distance (max of abs differences) makes a square area around each
+		// player. Moving away is always fine so nobody gets stuck.
+		distance := max(abs(newX-playerX), abs(newY-playerY))
+		if distance <= radius && distance < max(abs(currentX-playerX), abs(currentY-playerY)) {
 			return true
 		}
 	}
@@ -181,39 +156,16 @@ func canAvatarFitAt(x, y int) bool {
 	if err != nil {
 		return false
 	}
-
-	// Check all 9 tiles in the 3x3 footprint - must be walkable
-	for dy := -1; dy <= 1; dy++ {
-		for dx := -1; dx <= 1; dx++ {
-			checkX := x + dx
-			checkY := y + dy
-
-			// Check bounds
-			if checkY < 0 || checkY >= 250 || checkX < 0 || checkX >= 400 {
-				return false // Out of bounds
-			}
-
-			// Check if tile is walkable: ' ' (hallway), 'e' (entrance), "-1" (outside), '@' (walkable), or room numbers ("1", "2", etc.)
-			value := roomMap[checkY][checkX]
-			if value == " " || value == "e" || value == "-1" || value == "@" {
-				// Explicitly walkable
-				continue
-			}
-			// Check if it's a room number (numeric string)
-			if _, err := strconv.Atoi(value); err == nil {
-				// It's a room number - walkable
-				continue
-			}
-			// Not walkable (walls, inaccessible areas, furniture T/t/W, etc.)
-			return false
-		}
-	}
-
-	return true // All tiles in 3x3 grid are walkable
+	return roomMap.AvatarFits(x, y)
 }
 
 // canMoveTo checks if the player can move to a position
 func (m *Model) canMoveTo(newX, newY int) bool {
+	// Ghosts (admins looking around unseen) go through walls and players
+	if m.isGhost() {
+		return newX >= 0 && newX < 400 && newY >= 0 && newY < 250
+	}
+
 	// Check if the 3x3 avatar footprint fits at the new position
 	if !canAvatarFitAt(newX, newY) {
 		return false
@@ -227,6 +179,15 @@ func (m *Model) canMoveTo(newX, newY int) bool {
 	return true
 }
 
+// isGhost reports whether an admin has put us in ghost mode
+func (m *Model) isGhost() bool {
+	if m.connMgr == nil {
+		return false
+	}
+	gameState := m.connMgr.GetState()
+	return gameState != nil && gameState.Players[m.userName].Ghost
+}
+
 // createAvatarFromIndices creates an Avatar from protocol avatar indices
 func createAvatarFromIndices(indices []int) Avatar {
 	if len(indices) != 3 {
@@ -241,6 +202,11 @@ func createAvatarFromIndices(indices []int) Avatar {
 
 // updateMainGame handles main game screen
 func (m Model) updateMainGame(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// A replay only takes playback controls
+	if m.replay != nil {
+		return m.updateReplay(msg)
+	}
+
 	// Handle player selection if active
 	if m.playerSelectActive {
 		switch msg.String() {
@@ -278,18 +244,20 @@ func (m Model) updateMainGame(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if len(m.chatInput) > 0 {
 				if m.connMgr != nil && m.connMgr.IsConnected() {
 					// Check for commands
-					if strings.HasPrefix(m.chatInput, "/answer ") {
+					if guess, ok := treasureHuntGuess(m.chatInput); ok {
 						// Handle treasure hunt guess
-						guess := strings.TrimPrefix(m.chatInput, "/answer ")
-						m.connMgr.SendTreasureHuntGuess(guess)
-						// Add local feedback
-						m.globalChatMessages = append(m.globalChatMessages, mutedStyle.Render("You guessed: "+guess))
+						m.sendTreasureHuntGuess(guess)
+					} else if strings.HasPrefix(m.chatInput, "/") && m.handleSlashCommand(m.chatInput) {
+						// Commands close the input so their result (e.g. a game board) gets the keys
+						m.chatInput = ""
+						m.chatInputActive = false
+						return m, nil
 					} else if m.chatMode == ChatModeGlobal {
 						// Send global chat message
 						m.connMgr.SendGlobalChat(m.userName, m.chatInput)
 					} else if m.chatMode == ChatModePrivate && m.chatTarget != "" {
 						// Send private message to selected player
-						m.connMgr.SendChatMessage(m.userName, m.chatTarget, m.chatInput)
+						m.connMgr.SendPrivateChat(m.userName, m.chatTarget, m.chatInput)
 					} else if m.chatMode == ChatModeRoom {
 						// Send room chat message
 						roomNum := m.getCurrentPlayerRoom()
@@ -297,7 +265,7 @@ func (m Model) updateMainGame(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 							m.connMgr.SendRoomChat(m.userName, roomNum, m.chatInput)
 						} else {
 							// Add local feedback that they're not in a room
-							m.globalChatMessages = append(m.globalChatMessages, mutedStyle.Render("You must be in a room to use room chat"))
+							m.globalChatMessages = append(m.globalChatMessages, mutedStyle.Render(i18n.T("You must be in a room to use room chat")))
 						}
 					}
 				}
@@ -331,38 +299,72 @@ func (m Model) updateMainGame(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	// Normal game controls
-	switch msg.String() {
-	case "ctrl+c":
-		if m.connMgr != nil {
-			m.connMgr.Disconnect()
-		}
+	// Overlays capture keys while they're open
+	if m.overlay != OverlayNone {
+		return m.updateOverlay(msg)
+	}
+
+	// Normal game controls, bound to the player's keys
+	switch {
+	case msg.String() == "ctrl+c":
+		m.Disconnect()
 		return m, tea.Quit
 
-	case "r", "R":
+	case key.Matches(msg, m.keys.Refresh):
 		// Refresh screen - clear and redraw
 		return m, tea.ClearScreen
 
+	case key.Matches(msg, m.keys.Interact):
+		// Use the object next to us (coffee machine, vending machine, ...)
+		if m.connMgr != nil && m.connMgr.IsConnected() {
+			m.connMgr.SendInteract()
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Players):
+		// Open the player list
+		m.playerCursor = 0
+		m.overlay = OverlayPlayers
+		return m, nil
+
+	case key.Matches(msg, m.keys.Shop):
+		// Open the cosmetics shop
+		m.openShop()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Quest):
+		// Open the treasure hunt panel to read the riddle and guess
+		m.openTreasureHunt()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Leaderboard):
+		// Open the treasure hunt leaderboard, fetching the latest standings
+		if m.connMgr != nil && m.connMgr.IsConnected() {
+			m.connMgr.SendLeaderboardRequest()
+		}
+		m.overlay = OverlayLeaderboard
+		return m, nil
+
 	// Chat controls
-	case "t", "T":
+	case key.Matches(msg, m.keys.Chat):
 		// Start typing in chat
 		m.chatInputActive = true
 		m.chatInput = ""
 		return m, func() tea.Msg { return tea.ClearScreen() }
 
-	case "g", "G":
+	case key.Matches(msg, m.keys.GlobalChat):
 		// Switch to global chat
 		m.chatMode = ChatModeGlobal
 		m.chatTarget = ""
 		return m, nil
 
-	case "o", "O":
+	case key.Matches(msg, m.keys.RoomChat):
 		// Switch to room chat
 		m.chatMode = ChatModeRoom
 		m.chatTarget = ""
 		return m, nil
 
-	case "p", "P":
+	case key.Matches(msg, m.keys.PrivateChat):
 		// Switch to private chat and find nearby players
 		m.chatMode = ChatModePrivate
 
@@ -398,24 +400,48 @@ func (m Model) updateMainGame(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
-	// Movement keys - WASD and arrow keys (with diagonal support via number pad)
-	// Diagonal movement using number pad or vim-style keys
-	case "7", "y", "Y": // Up-Left
-		m.handleMovement(-1, -1)
-	case "8", "up", "w", "W", "k", "K": // Up
-		m.handleMovement(0, -1)
-	case "9", "u", "U": // Up-Right
-		m.handleMovement(1, -1)
-	case "4", "left", "a", "A", "h", "H": // Left
-		m.handleMovement(-1, 0)
-	case "6", "right", "d", "D", "l", "L": // Right
-		m.handleMovement(1, 0)
-	case "1", "b", "B": // Down-Left
-		m.handleMovement(-1, 1)
-	case "2", "down", "s", "S", "j", "J": // Down
-		m.handleMovement(0, 1)
-	case "3", "n", "N": // Down-Right
-		m.handleMovement(1, 1)
+	case key.Matches(msg, m.keys.Settings):
+		// Open the settings screen
+		m.openSettings()
+		return m, nil
+
+	case key.Matches(msg, m.keys.Screenshot):
+		// Save the game world as drawn, to share
+		if m.hosted {
+			m.pushAnnouncement(mutedStyle.Render(i18n.T("Screenshots can't be saved from the browser")))
+		} else if name, err := m.saveScreenshot(time.Now()); err != nil {
+			m.pushAnnouncement(errorStyle.Render(i18n.Tf("Couldn't save a screenshot: %v", err)))
+		} else {
+			m.pushAnnouncement(mutedStyle.Render(i18n.Tf("Saved a screenshot to %s, and one without colors to .txt", name)))
+		}
+		return m, nil
+
+	case key.Matches(msg, m.keys.Debug):
+		// Show or hide the debug overlay
+		m.debug = !m.debug
+		return m, nil
+
+	case key.Matches(msg, m.keys.AnnounceUp):
+		// Scroll back through older announcements
+		m.scrollAnnouncements(announceStep)
+		return m, nil
+
+	case key.Matches(msg, m.keys.AnnounceDown):
+		m.scrollAnnouncements(-announceStep)
+		return m, nil
+
+	case key.Matches(msg, m.keys.Help):
+		// Open the list of keys and commands
+		m.helpScroll = 0
+		m.overlay = OverlayHelp
+		return m, nil
+
+	default:
+		// Movement keys, which default to the settings' scheme of WASD, arrows, vim keys
+		// or the number pad
+		if dx, dy, ok := m.keys.movementStep(msg); ok {
+			m.handleMovement(dx, dy)
+		}
 	}
 
 	return m, nil
@@ -451,15 +477,16 @@ func (m *Model) handleMovement(dx, dy int) {
 		return // Invalid move, do nothing
 	}
 
-	// Send move request to server
-	m.connMgr.SendPlayerMove(newX, newY)
+	// Send move request to server; we're drawn at the new position until it answers
+	m.connMgr.SendPlayerMove(m.userName, newX, newY)
+	m.stateVersion++
 }
 
 // viewMainGame renders the split-screen main game view
 func (m Model) viewMainGame() string {
-	// Repopulate grids to ensure viewport is current (player may have moved)
-	mPtr := &m
-	mPtr.populateGrids()
+	if m.config.Settings.ScreenReader {
+		return m.viewScreenReader()
+	}
 
 	// Calculate dimensions (70% game, 30% right panel)
 	gameWidth := int(float64(m.width) * 0.7)
@@ -502,7 +529,11 @@ func (m Model) viewMainGame() string {
 	)
 
 	// Game section (left 70%) - extends to match full right section height
+	// An open overlay (e.g. a mini-game board) takes the place of the game world
 	gameContent := m.renderGamePanel(gameWidth, contentHeight)
+	if m.overlay != OverlayNone {
+		gameContent = m.renderOverlay(gameWidth, contentHeight)
+	}
 	gameBox := gameBoxStyle.
 		Width(gameWidth).
 		Height(contentHeight + 3).
@@ -525,65 +556,96 @@ func (m Model) viewMainGame() string {
 	return centeredMain + bottomStatus
 }
 
+// Daytime map tile styles, rendered by renderTileStyles for the current color profile
 var (
+	wallStyle              string
+	inaccessibleStyle      string
+	roomStyle              string
+	entranceStyle          string
+	backgroundStyle        string
+	backgroundOutsideStyle string
+	roomFloorStyle         string
+	backgroundBStyle       string
+	televisionStyle        string
+	tableStyle             string
+	plantStyle             string
+	whiteboardStyle        string
+	darkBrownStyle         string
+	couchStyle             string
+)
+
+// renderTileStyles renders the daytime map tiles
+func renderTileStyles() {
 	wallStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("#8B6F47")). // Brighter warm brown (walls)
-			Render(" ")
+		Background(lipgloss.Color("#8B6F47")). // Brighter warm brown (walls)
+		Render(" ")
 
 	inaccessibleStyle = lipgloss.NewStyle().
-				Background(lipgloss.Color("#9B8B6A")). // Brighter tan-brown (inaccessible)
-				Render(" ")
+		Background(lipgloss.Color("#9B8B6A")). // Brighter tan-brown (inaccessible)
+		Render(" ")
 
 	roomStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("#6A8D6A")). // Brighter sage green (rooms)
-			Render("░")                            // Light shade (U+2591, single-width)
+		Background(lipgloss.Color("#6A8D6A")). // Brighter sage green (rooms)
+		Render("░")                            // Light shade (U+2591, single-width)
 
 	entranceStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("#7A9D7A")). // Brighter light sage green (entrances)
-			Render(" ")
+		Background(lipgloss.Color("#7A9D7A")). // Brighter light sage green (entrances)
+		Render(" ")
 
 	backgroundStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("#FFF8DC")). // Pale yellow (floor/hallway)
-			Render(" ")
+		Background(lipgloss.Color("#FFF8DC")). // Pale yellow (floor/hallway)
+		Render(" ")
 
 	backgroundOutsideStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#000000")). // Black dot
-				Background(lipgloss.Color("#B3D9FF")). // Pale blue background
-				Render("·")
+		Foreground(lipgloss.Color("#000000")). // Black dot
+		Background(lipgloss.Color("#B3D9FF")). // Pale blue background
+		Render("·")
 
 	roomFloorStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("#B8C5D4")). // Brighter grey-blue
-			Render("~")
+		Background(lipgloss.Color("#B8C5D4")). // Brighter grey-blue
+		Render("~")
 
 	backgroundBStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("#6A8D6A")). // Sage green
-				Background(lipgloss.Color("#2a2a2a")). // Dark grey - internal inaccessible ('B')
-				Render("^")
+		Foreground(lipgloss.Color("#6A8D6A")). // Sage green
+		Background(lipgloss.Color("#2a2a2a")). // Dark grey - internal inaccessible ('B')
+		Render("^")
 
 	televisionStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("#000000")). // Black - television ('T')
-			Render(" ")
+		Background(lipgloss.Color("#000000")). // Black - television ('T')
+		Render(" ")
 
 	tableStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("#C4A082")). // Brighter brown - table ('t')
-			Render(" ")
+		Background(lipgloss.Color("#C4A082")). // Brighter brown - table ('t')
+		Render(" ")
 
 	plantStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#6A8D6A")).
-			Background(lipgloss.Color("#7A9D7A")). // Brighter light sage green - plant ('p')
-			Render("*")
+		Foreground(lipgloss.Color("#6A8D6A")).
+		Background(lipgloss.Color("#7A9D7A")). // Brighter light sage green - plant ('p')
+		Render("*")
 
 	whiteboardStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("#FFFFFF")). // White - whiteboard ('W')
-			Render(" ")
+		Background(lipgloss.Color("#FFFFFF")). // White - whiteboard ('W')
+		Render(" ")
 
 	darkBrownStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("#5C4033")). // Dark brown ('@')
-			Render(" ")
+		Background(lipgloss.Color("#5C4033")). // Dark brown ('@')
+		Render(" ")
 
 	couchStyle = lipgloss.NewStyle().
-			Background(lipgloss.Color("#4A5568")). // Navy blue-grey - couch ('c')
-			Render("▬")                            // Horizontal bar for couch
+		Background(lipgloss.Color("#4A5568")). // Navy blue-grey - couch ('c')
+		Render("▬")                            // Horizontal bar for couch
+}
+
+var (
+	itMarkerStyle = cellStyle{
+		fg:   lipgloss.Color("#D00000"), // Red - "it" in tag
+		bold: true,
+	}
+
+	objectStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFD700")). // Gold glyph
+			Background(lipgloss.Color("#5C4033")). // Dark brown - interactive object
+			Bold(true)
 
 	transparentStyle = lipgloss.NewStyle().
 				Render(" ") // Transparent - no background color
@@ -613,238 +675,97 @@ type StyledCell struct {
 	HasContent   bool
 }
 
-// getStyledCharFromRoomValue converts a room map value to a styled string for rendering
+// getStyledCharFromRoomValue converts a room map tile to a styled string for rendering
 // Uses a cache to avoid recreating styled strings on every frame
-func getStyledCharFromRoomValue(value string) string {
+func getStyledCharFromRoomValue(tile gamemap.Tile) string {
 	// Initialize cache if not already done
 	initStyledCache()
+	return styledCache[tile]
+}
 
-	// Check cache first
-	if cached, ok := styledCache[value]; ok {
-		return cached
+// dayTileStyle returns the daytime styled string for a room map tile
+func dayTileStyle(tile gamemap.Tile) string {
+	switch tile {
+	case gamemap.TileRoomWall:
+		return roomStyle
+	case gamemap.TileOuterWall:
+		return wallStyle
+	case gamemap.TileInaccessible:
+		return inaccessibleStyle
+	case gamemap.TileEntrance:
+		return entranceStyle
+	case gamemap.TileOutside:
+		return backgroundOutsideStyle
+	case gamemap.TileBlocked:
+		return backgroundBStyle
+	case gamemap.TileTelevision:
+		return televisionStyle
+	case gamemap.TileTable:
+		return tableStyle
+	case gamemap.TilePlant:
+		return plantStyle
+	case gamemap.TileWhiteboard:
+		return whiteboardStyle
+	case gamemap.TileDarkFloor:
+		return darkBrownStyle
+	case gamemap.TileCouch:
+		return couchStyle
+	case gamemap.TileRoomFloor:
+		// Room floors render as brighter grey-blue with wavy pattern
+		return roomFloorStyle
+	default:
+		// Hallways and anything else - use beige background
+		return backgroundStyle
 	}
+}
 
-	// Not in cache - create and cache it
-	var styled string
-	// Check if it's a room number (numeric string)
-	if _, err := strconv.Atoi(value); err == nil {
-		// It's a room number - render as brighter grey-blue with wavy pattern
-		styled = roomFloorStyle
-	} else {
-		// Any other character - use beige background
-		styled = backgroundStyle
+// getBackgroundColorFromRoomValue returns the background color for a given room map tile,
+// tinted for the current time of day
+func getBackgroundColorFromRoomValue(tile gamemap.Tile) lipgloss.Color {
+	if colorProfile == termenv.ANSI {
+		return tile16Background(tile)
 	}
-
-	styledCache[value] = styled
-	return styled
+	return shadeColor(baseBackgroundColor(tile))
 }
 
-// getBackgroundColorFromRoomValue returns the background color for a given room map value
-func getBackgroundColorFromRoomValue(value string) lipgloss.Color {
-	switch value {
-	case "r": // room wall
+// baseBackgroundColor returns the daytime background color for a given room map tile
+func baseBackgroundColor(tile gamemap.Tile) lipgloss.Color {
+	switch tile {
+	case gamemap.TileRoomWall:
 		return lipgloss.Color("#6A8D6A") // Brighter sage green
-	case "o": // outer wall
+	case gamemap.TileOuterWall:
 		return lipgloss.Color("#8B6F47") // Brighter brown
-	case "i": // inaccessible
+	case gamemap.TileInaccessible:
 		return lipgloss.Color("#9B8B6A") // Brighter tan-brown
-	case "e": // entrance
+	case gamemap.TileEntrance:
 		return lipgloss.Color("#7A9D7A") // Brighter light sage
-	case "b": // background/outside
+	case gamemap.TileOutside:
 		return lipgloss.Color("#000000") // Black
-	case "B": // internal inaccessible
+	case gamemap.TileBlocked: // internal inaccessible
 		return lipgloss.Color("#2a2a2a") // Dark grey
-	case "T": // television
+	case gamemap.TileTelevision:
 		return lipgloss.Color("#000000") // Black
-	case "t": // table
+	case gamemap.TileTable:
 		return lipgloss.Color("#C4A082") // Brighter brown
-	case "p": // plant
+	case gamemap.TilePlant:
 		return lipgloss.Color("#7A9D7A") // Brighter light sage green
-	case "W": // whiteboard
+	case gamemap.TileWhiteboard:
 		return lipgloss.Color("#FFFFFF") // White
-	case "@": // dark brown
+	case gamemap.TileDarkFloor:
 		return lipgloss.Color("#5C4033") // Dark brown
-	case "c": // couch
+	case gamemap.TileCouch:
 		return lipgloss.Color("#4A5568") // Navy blue-grey (couch)
-	case " ": // walkable space (hallways)
-		return lipgloss.Color("#FFF8DC") // Pale yellow
-	case "-1": // outside/hallway
-		return lipgloss.Color("#FFF8DC") // Pale yellow
+	case gamemap.TileRoomFloor:
+		return lipgloss.Color("#B8C5D4") // Brighter grey-blue
 	default:
-		// Check if it's a room number (numeric string)
-		if _, err := strconv.Atoi(value); err == nil {
-			// It's a room number - render as brighter grey-blue
-			return lipgloss.Color("#B8C5D4")
-		}
-		// Any other character - use pale yellow
+		// Hallways and any other character - use pale yellow
 		return lipgloss.Color("#FFF8DC") // Default pale yellow
 	}
 }
 
-// fillRoomMap fills the room map with string annotations.
-// Returns map characters as keys ('r', 'o', 'i', 'e'), "-1" for spaces not in rooms, room number strings ("1", "2", ...) for spaces in rooms.
-// Rooms are defined by four walls ('r' or 'e' characters), and adjacent rooms are separated by 'r'/'e' walls.
-func fillRoomMap() ([250][400]string, error) {
-	lines := strings.Split(embeddedMap, "\n")
-	var result [250][400]string
-	var mapChars [250][400]rune
-
-	// Initialize all cells and read map characters
-	for i, line := range lines {
-		if i >= 250 {
-			break
-		}
-		line = strings.TrimRight(line, " \t\r")
-
-		for j := range result[i] {
-			result[i][j] = "" // Uninitialized marker
-			if j < len(line) {
-				mapChars[i][j] = rune(line[j])
-			} else {
-				mapChars[i][j] = ' '
-			}
-		}
-	}
-
-	// Copy all non-space characters (walls, inaccessible areas, furniture, etc.)
-	for i := 0; i < 250; i++ {
-		for j := 0; j < 400; j++ {
-			char := mapChars[i][j]
-		// Mark wall characters and furniture as themselves
-		if char == 'r' || char == 'o' || char == 'i' || char == 'e' || char == 'b' || char == 'B' || char == 'T' || char == 't' || char == 'p' || char == 'W' || char == '@' || char == 'c' {
-			result[i][j] = string(char)
-		}
-			// Leave spaces as "" for now - they'll be filled by flood fill
-		}
-	}
-
-	// Step 1: Mark outside spaces (not enclosed by walls) as "-1"
-	// Start from top-left corner (0,0) which should be outside any rooms
-	markOutsideSpaces(&result, &mapChars, 0, 0)
-
-	// Step 2: Flood fill each room using the predefined room coordinates
-	for _, room := range roomCoordinates {
-		// Check if this coordinate is valid and unmarked
-		if room.Y >= 0 && room.Y < 250 && room.X >= 0 && room.X < 400 {
-			if result[room.Y][room.X] == "" && mapChars[room.Y][room.X] == ' ' {
-				// Flood fill this room with its name
-				floodFillRoom(&result, &mapChars, room.Y, room.X, room.Name)
-			}
-		}
-	}
-
-	// Step 3: Mark any remaining unmarked spaces as hallways ("-1")
-	// These are spaces that aren't in defined rooms
-	for i := 0; i < 250; i++ {
-		for j := 0; j < 400; j++ {
-			if result[i][j] == "" && mapChars[i][j] == ' ' {
-				result[i][j] = "-1" // Hallway
-			}
-		}
-	}
-
-	return result, nil
-}
-
-// markOutsideSpaces marks spaces outside 'r'/'e' boundaries as "-1" using flood fill
-// Only 'r' and 'e' characters block the flood fill - 'o' and 'i' don't block it
-// This ensures that only spaces enclosed by 'r'/'e' boundaries are considered rooms
-func markOutsideSpaces(result *[250][400]string, mapChars *[250][400]rune, startY, startX int) {
-	type point struct {
-		y, x int
-	}
-	stack := []point{{startY, startX}}
-
-	for len(stack) > 0 {
-		p := stack[len(stack)-1]
-		stack = stack[:len(stack)-1]
-
-		// Check bounds
-		if p.y < 0 || p.y >= 250 || p.x < 0 || p.x >= 400 {
-			continue
-		}
-
-		// Skip if already marked
-		if result[p.y][p.x] == "-1" {
-			continue
-		}
-
-		// Skip if already set (preserves 'b', 'B', 'T', 't', 'W', etc.)
-		if result[p.y][p.x] != "" {
-			continue
-		}
-
-		// All wall characters ('r', 'e', 'o', 'i') block the flood fill
-		// This preserves wall colors and prevents flood fill from marking them as hallways
-		if mapChars[p.y][p.x] == 'r' || mapChars[p.y][p.x] == 'e' ||
-			mapChars[p.y][p.x] == 'o' || mapChars[p.y][p.x] == 'i' {
-			// This is a wall character - don't mark it as outside, don't continue flood fill
-			continue
-		}
-
-		// Mark as outside (not enclosed by wall boundaries)
-		result[p.y][p.x] = "-1"
-
-		// Add neighbors to stack (only if not wall characters)
-		if p.y > 0 && mapChars[p.y-1][p.x] != 'r' && mapChars[p.y-1][p.x] != 'e' &&
-			mapChars[p.y-1][p.x] != 'o' && mapChars[p.y-1][p.x] != 'i' {
-			stack = append(stack, point{p.y - 1, p.x}) // up
-		}
-		if p.y < 249 && mapChars[p.y+1][p.x] != 'r' && mapChars[p.y+1][p.x] != 'e' &&
-			mapChars[p.y+1][p.x] != 'o' && mapChars[p.y+1][p.x] != 'i' {
-			stack = append(stack, point{p.y + 1, p.x}) // down
-		}
-		if p.x > 0 && mapChars[p.y][p.x-1] != 'r' && mapChars[p.y][p.x-1] != 'e' &&
-			mapChars[p.y][p.x-1] != 'o' && mapChars[p.y][p.x-1] != 'i' {
-			stack = append(stack, point{p.y, p.x - 1}) // left
-		}
-		if p.x < 399 && mapChars[p.y][p.x+1] != 'r' && mapChars[p.y][p.x+1] != 'e' &&
-			mapChars[p.y][p.x+1] != 'o' && mapChars[p.y][p.x+1] != 'i' {
-			stack = append(stack, point{p.y, p.x + 1}) // right
-		}
-	}
-}
-
-// floodFillRoom assigns a room number to all connected spaces starting from (startY, startX)
-// This ensures all spaces in the same enclosed region get the same room number
-func floodFillRoom(result *[250][400]string, mapChars *[250][400]rune, startY, startX int, roomNumStr string) {
-	type point struct {
-		y, x int
-	}
-	stack := []point{{startY, startX}}
-
-	for len(stack) > 0 {
-		p := stack[len(stack)-1]
-		stack = stack[:len(stack)-1]
-
-		// Check bounds
-		if p.y < 0 || p.y >= 250 || p.x < 0 || p.x >= 400 {
-			continue
-		}
-
-		// Skip if already marked (wall, outside, or already in a room)
-		// Also skip 'e' characters - they act like 'r' for boundaries but are marked as 'e'
-		if result[p.y][p.x] != "" || mapChars[p.y][p.x] == 'e' {
-			continue
-		}
-
-		// Assign room number
-		result[p.y][p.x] = roomNumStr
-
-		// Add neighbors to stack (only unvisited spaces, not walls or 'e' characters)
-		if p.y > 0 && result[p.y-1][p.x] == "" && mapChars[p.y-1][p.x] != 'e' {
-			stack = append(stack, point{p.y - 1, p.x}) // up
-		}
-		if p.y < 249 && result[p.y+1][p.x] == "" && mapChars[p.y+1][p.x] != 'e' {
-			stack = append(stack, point{p.y + 1, p.x}) // down
-		}
-		if p.x > 0 && result[p.y][p.x-1] == "" && mapChars[p.y][p.x-1] != 'e' {
-			stack = append(stack, point{p.y, p.x - 1}) // left
-		}
-		if p.x < 399 && result[p.y][p.x+1] == "" && mapChars[p.y][p.x+1] != 'e' {
-			stack = append(stack, point{p.y, p.x + 1}) // right
-		}
-	}
+// fillRoomMap annotates the embedded map with walls, furniture and room numbers
+func fillRoomMap() (*gamemap.Grid, error) {
+	return gamemap.FillGrid(gamemap.Text())
 }
 
 // renderGamePanel renders the game world panel (left 70%)
@@ -856,6 +777,11 @@ func (m Model) renderGamePanel(width, height int) string {
 		Align(lipgloss.Center).
 		Render("Morgridge Hall")
 
+	// A riddle winner's banner takes the title's place for a few seconds
+	if time.Now().Before(m.celebrationUntil) {
+		gameTitle = m.renderCelebration(width)
+	}
+
 	// Get current room number (if any) using existing function
 	roomNum := m.getCurrentPlayerRoom()
 	var roomLabel string
@@ -864,7 +790,7 @@ func (m Model) renderGamePanel(width, height int) string {
 			Foreground(lipgloss.Color("#FFD700")). // Gold color for room name
 			Width(width).
 			Align(lipgloss.Center).
-			Render("Room " + roomNum)
+			Render(i18n.Tf("Room %s", roomNum))
 	}
 
 	// Calculate actual viewport dimensions (accounting for borders and padding)
@@ -875,6 +801,11 @@ func (m Model) renderGamePanel(width, height int) string {
 	if roomLabel != "" {
 		viewportHeight -= 1 // Make room for the label
 	}
+	var debugInfo string
+	if m.debug {
+		debugInfo = m.renderDebugOverlay(width)
+		viewportHeight -= debugLines
+	}
 
 	// Use the capped GameWorldWidth/Height instead of the full viewport dimensions
 	// This ensures the game grid is rendered at the capped size
@@ -899,21 +830,15 @@ func (m Model) renderGamePanel(width, height int) string {
 		gameGrid,
 	)
 
-	// Join title, room label (if present), and grid
+	// Join title, room label and debug overlay (if present), and grid
+	parts := []string{gameTitle}
 	if roomLabel != "" {
-		return lipgloss.JoinVertical(
-			lipgloss.Left,
-			gameTitle,
-			roomLabel,
-			centeredGrid,
-		)
+		parts = append(parts, roomLabel)
 	}
-
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		gameTitle,
-		centeredGrid,
-	)
+	if debugInfo != "" {
+		parts = append(parts, debugInfo)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, append(parts, centeredGrid)...)
 }
 
 // calculateViewport calculates the camera position centered on the current player
@@ -971,20 +896,21 @@ func (m *Model) calculateViewport() (cameraX, cameraY int) {
 
 // populateGrids fills GameWorldGrid from the room map (consolidated - only room map is used)
 func (m *Model) populateGrids() {
-	roomData, err := getRoomMap()
-	if err != nil {
-		// If error, initialize empty grid
+	// Initialize grid, reusing the last frame's while the viewport stays the same size
+	if len(m.GameWorldGrid) != m.GameWorldHeight || (m.GameWorldHeight > 0 && len(m.GameWorldGrid[0]) != m.GameWorldWidth) {
 		m.GameWorldGrid = make([][]string, m.GameWorldHeight)
 		for i := range m.GameWorldGrid {
 			m.GameWorldGrid[i] = make([]string, m.GameWorldWidth)
 		}
-		return
 	}
 
-	// Initialize grid
-	m.GameWorldGrid = make([][]string, m.GameWorldHeight)
-	for i := range m.GameWorldGrid {
-		m.GameWorldGrid[i] = make([]string, m.GameWorldWidth)
+	roomData, err := getRoomMap()
+	if err != nil {
+		// If error, leave the grid empty
+		for _, row := range m.GameWorldGrid {
+			clear(row)
+		}
+		return
 	}
 
 	// Populate from room map (viewport centered on player)
@@ -1019,8 +945,7 @@ func (m *Model) populateGrids() {
 				continue
 			}
 			// Render directly from room map value
-			roomValue := roomData[sourceY][sourceX]
-			m.GameWorldGrid[y][x] = getStyledCharFromRoomValue(roomValue)
+			m.GameWorldGrid[y][x] = getStyledCharFromRoomValue(roomData.Tiles[sourceY][sourceX])
 		}
 	}
 }
@@ -1049,21 +974,13 @@ func (m *Model) getCurrentPlayerRoom() string {
 		return ""
 	}
 
-	// Get room value from roomMap
+	// Get room number from roomMap
 	roomData, err := getRoomMap()
 	if err != nil {
 		return ""
 	}
 
-	roomValue := roomData[playerY][playerX]
-	// Return room number if it's a numeric string (room), empty string otherwise
-	if roomValue != "" && roomValue != "-1" && roomValue != "r" && roomValue != "o" && roomValue != "i" && roomValue != "e" {
-		// Check if it's a valid room number
-		if _, err := strconv.Atoi(roomValue); err == nil {
-			return roomValue
-		}
-	}
-	return ""
+	return roomData.RoomNumberAt(playerX, playerY)
 }
 
 // isPlayerInRoom checks if the player is in any room (room number string is not empty)
@@ -1100,6 +1017,10 @@ func (m *Model) countPlayersInRoom(roomNumber string) int {
 	if err != nil {
 		return 0
 	}
+	room, err := strconv.ParseUint(roomNumber, 10, 8)
+	if err != nil || room == 0 {
+		return 0
+	}
 
 	// Iterate through all players and check their positions
 	for _, player := range gameState.Players {
@@ -1113,8 +1034,7 @@ func (m *Model) countPlayersInRoom(roomNumber string) int {
 		}
 
 		// Check if this player is in the specified room
-		value := roomData[y][x]
-		if value == roomNumber {
+		if roomData.Rooms[y][x] == uint8(room) {
 			count++
 		}
 	}
@@ -1155,6 +1075,25 @@ func (m *Model) renderPlayerToOverlay(
 	}
 	isBold := isCurrentPlayer
 
+	// Shop cosmetics: a name color and an accessory above the name
+	nameColor := foregroundColor
+	if player.NameColor != "" && !player.Idle {
+		nameColor = lipgloss.Color(player.NameColor)
+	}
+	if player.Accessory != "" {
+		m.renderMarkerToOverlay(overlay, player.Accessory, vx+1, vy, cameraX, cameraY,
+			cellStyle{fg: nameColor, bold: true})
+	}
+
+	// Idle players are drawn dimmed with a "zzz" above their name
+	if player.Idle {
+		foregroundColor = lipgloss.Color("#9A9A9A")
+		isBold = false
+
+		m.renderMarkerToOverlay(overlay, "zzz", vx, vy, cameraX, cameraY,
+			cellStyle{fg: foregroundColor, italic: true})
+	}
+
 	// Truncate username to 5 characters (using runes for Unicode support)
 	displayUsername := username
 	usernameRunes := []rune(displayUsername)
@@ -1176,19 +1115,12 @@ func (m *Model) renderPlayerToOverlay(
 				// Get background color from tile underneath
 				bgColor := lipgloss.Color("#FFF8DC") // Default pale yellow
 				if worldY >= 0 && worldY < 250 && worldX >= 0 && worldX < 400 {
-					tileValue := roomData[worldY][worldX]
-					bgColor = getBackgroundColorFromRoomValue(tileValue)
+					bgColor = getBackgroundColorFromRoomValue(roomData.Tiles[worldY][worldX])
 				}
 
-				// Create style with per-character background
-				charStyle := lipgloss.NewStyle().
-					Foreground(foregroundColor).
-					Background(bgColor)
-				if isBold {
-					charStyle = charStyle.Bold(true)
-				}
-
-				overlay[usernameY][charX].StyledString = charStyle.Render(string(ch))
+				// Per-character background, rendered once per color and character
+				charStyle := cellStyle{fg: nameColor, bg: bgColor, bold: isBold}
+				overlay[usernameY][charX].StyledString = styledGlyph(charStyle, ch)
 				overlay[usernameY][charX].HasContent = true
 			}
 		}
@@ -1217,25 +1149,62 @@ func (m *Model) renderPlayerToOverlay(
 			// Get background color from tile underneath
 			bgColor := lipgloss.Color("#D2B48C") // Default beige
 			if worldY >= 0 && worldY < 250 && worldX >= 0 && worldX < 400 {
-				tileValue := roomData[worldY][worldX]
-				bgColor = getBackgroundColorFromRoomValue(tileValue)
+				bgColor = getBackgroundColorFromRoomValue(roomData.Tiles[worldY][worldX])
 			}
 
-			// Create style with per-character background
-			charStyle := lipgloss.NewStyle().
-				Foreground(foregroundColor).
-				Background(bgColor)
-			if isBold {
-				charStyle = charStyle.Bold(true)
-			}
-
-			styledChar := charStyle.Render(string(avatarRunes[charIdx]))
-			overlay[avatarY][avatarX].StyledString = styledChar
+			// Per-character background, rendered once per color and character
+			charStyle := cellStyle{fg: foregroundColor, bg: bgColor, bold: isBold}
+			overlay[avatarY][avatarX].StyledString = styledGlyph(charStyle, avatarRunes[charIdx])
 			overlay[avatarY][avatarX].HasContent = true
 		}
 	}
 }
 
+// renderMarkerToOverlay draws a short marker (e.g. "zzz") on the row above a player's
+// name, where (vx, vy) is the player's viewport position
+func (m *Model) renderMarkerToOverlay(overlay [][]StyledCell, text string, vx, vy, cameraX, cameraY int, style cellStyle) {
+	roomData, err := getRoomMap()
+	if err != nil {
+		return
+	}
+
+	markerY := vy - 3
+	if markerY < 0 || markerY >= len(overlay) {
+		return
+	}
+	for i, ch := range []rune(text) {
+		charX := vx + i
+		if charX < 0 || charX >= len(overlay[0]) {
+			continue
+		}
+		worldX := cameraX + charX
+		worldY := cameraY + markerY
+
+		// Match the tile underneath like the username does
+		bgColor := lipgloss.Color("#FFF8DC") // Default pale yellow
+		if worldY >= 0 && worldY < 250 && worldX >= 0 && worldX < 400 {
+			bgColor = getBackgroundColorFromRoomValue(roomData.Tiles[worldY][worldX])
+		}
+
+		style.bg = bgColor
+		overlay[markerY][charX].StyledString = styledGlyph(style, ch)
+		overlay[markerY][charX].HasContent = true
+	}
+}
+
+// renderObjectToOverlay draws an interactive object's glyph on its tile
+func (m *Model) renderObjectToOverlay(overlay [][]StyledCell, obj protocol.MapObject, cameraX, cameraY int) {
+	objX, objY := parsePosition(obj.Pos)
+	vx := objX - cameraX
+	vy := objY - cameraY
+	if vy < 0 || vy >= len(overlay) || vx < 0 || vx >= len(overlay[vy]) {
+		return
+	}
+
+	overlay[vy][vx].StyledString = objectStyle.Render(obj.Glyph)
+	overlay[vy][vx].HasContent = true
+}
+
 // compositePlayerLayer creates an overlay grid with all players rendered
 func (m *Model) compositePlayerLayer(cameraX, cameraY int) [][]StyledCell {
 	// Create empty overlay grid
@@ -1254,7 +1223,18 @@ func (m *Model) compositePlayerLayer(cameraX, cameraY int) [][]StyledCell {
 		return overlay
 	}
 
-	// Render other players first (z-order: back)
+	// Render interactive objects first (z-order: behind players)
+	for _, obj := range m.connMgr.GetObjects() {
+		m.renderObjectToOverlay(overlay, obj, cameraX, cameraY)
+	}
+
+	// NPCs are drawn exactly like other players
+	for _, npc := range gameState.NPCs {
+		npcPlayer := protocol.Player{Username: npc.Name, Pos: npc.Pos, Avatar: npc.Avatar}
+		m.renderPlayerToOverlay(overlay, npcPlayer, npc.Name, cameraX, cameraY, false)
+	}
+
+	// Render other players next
 	for username, player := range gameState.Players {
 		if username == m.userName {
 			continue // Skip current player, render last
@@ -1267,38 +1247,104 @@ func (m *Model) compositePlayerLayer(cameraX, cameraY int) [][]StyledCell {
 		m.renderPlayerToOverlay(overlay, currentPlayer, m.userName, cameraX, cameraY, true)
 	}
 
+	// In tag, mark whoever is "it" in red
+	if mode := m.connMgr.GetGameMode(); mode != nil && mode.Mode == protocol.GameModeTag {
+		for username, role := range mode.Roles {
+			if player, exists := gameState.Players[username]; exists && role == "it" {
+				x, y := parsePosition(player.Pos)
+				m.renderMarkerToOverlay(overlay, "IT!", x-cameraX, y-cameraY, cameraX, cameraY, itMarkerStyle)
+			}
+		}
+	}
+
 	return overlay
 }
 
-// renderGameWorld creates a grid representation of the game world with players
+// gameFrame is the game world renderGameWorld last drew, and what it drew it from
+type gameFrame struct {
+	version       uint64
+	width, height int
+	userName      string // Player the camera followed
+	rows          []frameRow
+	rendered      string
+}
+
+// frameRow is one row of the game world as drawn, with the cells it was drawn from
+type frameRow struct {
+	cells    []string
+	rendered string
+}
+
+// renderGameWorld creates a grid representation of the game world with players. Nothing
+// in it changes between state updates, moves and resizes, so until one of them bumps
+// stateVersion the last frame is drawn again.
 func (m Model) renderGameWorld(width, height int) string {
-	var builder strings.Builder
+	frame := m.frame
+	if frame != nil && frame.rendered != "" && frame.version == m.stateVersion &&
+		frame.width == width && frame.height == height && frame.userName == m.userName {
+		return frame.rendered
+	}
 
-	// Get camera position and player overlay
+	// Repopulate grids to ensure viewport is current (player may have moved)
 	mPtr := &m
+	mPtr.populateGrids()
+
+	// Get camera position and player overlay
 	cameraX, cameraY := mPtr.calculateViewport()
 	playerOverlay := mPtr.compositePlayerLayer(cameraX, cameraY)
 
-	// Render each cell: background + player overlay
+	// cell picks what's drawn at a position: player overlay, else background
+	cell := func(x, y int) string {
+		if y < len(playerOverlay) && x < len(playerOverlay[y]) && playerOverlay[y][x].HasContent {
+			return playerOverlay[y][x].StyledString
+		}
+		if m.GameWorldGrid[y][x] == "" {
+			return transparentStyle
+		}
+		return m.GameWorldGrid[y][x]
+	}
+
+	// Rows whose cells are the same as last frame's are reused instead of built again
+	var lastRows []frameRow
+	if frame != nil {
+		lastRows = frame.rows
+	}
+	rows := make([]frameRow, 0, height)
+	size := 0
 	for y := 0; y < height && y < len(m.GameWorldGrid); y++ {
-		for x := 0; x < width && x < len(m.GameWorldGrid[y]); x++ {
-			// Check if there's a player overlay at this position
-			if y < len(playerOverlay) && x < len(playerOverlay[y]) && playerOverlay[y][x].HasContent {
-				// Render player overlay
-				builder.WriteString(playerOverlay[y][x].StyledString)
-			} else if m.GameWorldGrid[y][x] == "" {
-				// Render transparent background
-				builder.WriteString(transparentStyle)
-			} else {
-				// Render background
-				builder.WriteString(m.GameWorldGrid[y][x])
-			}
+		cells := make([]string, min(width, len(m.GameWorldGrid[y])))
+		for x := range cells {
+			cells[x] = cell(x, y)
 		}
+		row := frameRow{cells: cells}
+		if y < len(lastRows) && slices.Equal(lastRows[y].cells, cells) {
+			row.rendered = lastRows[y].rendered
+		} else {
+			row.rendered = strings.Join(cells, "")
+		}
+		rows = append(rows, row)
+		size += len(row.rendered) + 1 // Newline
+	}
+
+	var builder strings.Builder
+	builder.Grow(size)
+	for y, row := range rows {
+		builder.WriteString(row.rendered)
 		if y < height-1 {
 			builder.WriteString("\n")
 		}
 	}
 
+	if frame != nil {
+		*frame = gameFrame{
+			version:  m.stateVersion,
+			width:    width,
+			height:   height,
+			userName: m.userName,
+			rows:     rows,
+			rendered: builder.String(),
+		}
+	}
 	return builder.String()
 }
 
@@ -1310,33 +1356,45 @@ func (m Model) renderQuestBox(width, height int) string {
 		Bold(true).
 		Width(width).
 		Align(lipgloss.Center).
-		Render("QUEST & ANNOUNCEMENTS")
+		Render(i18n.T("QUEST & ANNOUNCEMENTS"))
 
 	// Treasure Hunt Clue
-	clueHeader := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700")).Bold(true).Render("Current Clue:")
+	clueHeader := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700")).Bold(true).Render(i18n.T("Current Clue:"))
 	clueText := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFACD")).Render(m.currentClue)
-	hintText := mutedStyle.Render("(Type '/answer <text>' in chat)")
+	hintText := mutedStyle.Render(m.clueHelp())
 
 	var contentLines []string
 	contentLines = append(contentLines, clueHeader)
+	if m.clueKind != "" {
+		contentLines = append(contentLines, mutedStyle.Render(m.clueKind))
+	}
+	if countdown := m.renderClueCountdown(); countdown != "" {
+		contentLines = append(contentLines, countdown)
+	}
 	contentLines = append(contentLines, clueText)
+	if len(m.clueOptions) > 0 {
+		contentLines = append(contentLines, m.renderClueOptions())
+	}
 	contentLines = append(contentLines, hintText)
 	contentLines = append(contentLines, "") // Spacer
 
+	// Scavenger hunt clue, if we're taking part in one
+	if m.scavenger != nil {
+		scavengerHeader := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700")).Bold(true).
+			Render(i18n.Tf("Scavenger Hunt (%d/%d):", m.scavenger.Found, m.scavenger.Total))
+		contentLines = append(contentLines, scavengerHeader)
+		contentLines = append(contentLines, lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFACD")).Render(m.scavenger.Clue))
+		contentLines = append(contentLines, mutedStyle.Render(i18n.T("(Stand there and type '/claim')")))
+		contentLines = append(contentLines, "") // Spacer
+	}
+
 	// Announcements
-	displayCount := height - 6 // Reserve space for title, clue, and padding
+	displayCount := height - 2 - len(contentLines) // Reserve space for title, clues, and padding
 	if displayCount < 1 {
 		displayCount = 1
 	}
 
-	startIdx := 0
-	if len(m.announcements) > displayCount {
-		startIdx = len(m.announcements) - displayCount
-	}
-
-	for i := startIdx; i < len(m.announcements); i++ {
-		contentLines = append(contentLines, m.announcements[i])
-	}
+	contentLines = append(contentLines, m.visibleAnnouncements(displayCount)...)
 
 	questContent := lipgloss.NewStyle().
 		Width(width).
@@ -1356,21 +1414,21 @@ func (m Model) renderChatBox(width, height int) string {
 	// Determine mode indicator
 	var modeIndicator string
 	if m.chatMode == ChatModeGlobal {
-		modeIndicator = highlightStyle.Render("[GLOBAL]") + mutedStyle.Render(" Press 'p' for private, 'o' for room")
+		modeIndicator = highlightStyle.Render(i18n.T("[GLOBAL]")) + mutedStyle.Render(" "+i18n.T("Press 'p' for private, 'o' for room"))
 	} else if m.chatMode == ChatModePrivate {
 		if m.chatTarget != "" {
-			modeIndicator = highlightStyle.Render("[PRIVATE: "+m.chatTarget+"]") + mutedStyle.Render(" Press 'g' for global")
+			modeIndicator = highlightStyle.Render(i18n.Tf("[PRIVATE: %s]", m.chatTarget)) + mutedStyle.Render(" "+i18n.T("Press 'g' for global"))
 		} else {
-			modeIndicator = mutedStyle.Render("Press 'p' to select a player")
+			modeIndicator = mutedStyle.Render(i18n.T("Press 'p' to select a player"))
 		}
 	} else if m.chatMode == ChatModeRoom {
 		roomNum := m.getCurrentPlayerRoom()
 		if roomNum != "" {
 			// Count players in the room
 			playerCount := m.countPlayersInRoom(roomNum)
-			modeIndicator = highlightStyle.Render(fmt.Sprintf("[ROOM %s (%d players)]", roomNum, playerCount)) + mutedStyle.Render(" Press 'g' for global")
+			modeIndicator = highlightStyle.Render(i18n.Tf("[ROOM %s (%d players)]", roomNum, playerCount)) + mutedStyle.Render(" "+i18n.T("Press 'g' for global"))
 		} else {
-			modeIndicator = mutedStyle.Render("[ROOM CHAT - Not in a room]") + mutedStyle.Render(" Press 'g' for global")
+			modeIndicator = mutedStyle.Render(i18n.T("[ROOM CHAT - Not in a room]")) + mutedStyle.Render(" "+i18n.T("Press 'g' for global"))
 		}
 	}
 
@@ -1380,7 +1438,7 @@ func (m Model) renderChatBox(width, height int) string {
 		Bold(true).
 		Width(width).
 		Align(lipgloss.Center).
-		Render("CHAT")
+		Render(i18n.T("CHAT"))
 
 	modeBar := lipgloss.NewStyle().
 		Width(width).
@@ -1396,7 +1454,7 @@ func (m Model) renderChatBox(width, height int) string {
 
 	// Show player selection if active
 	if m.playerSelectActive {
-		messageLines = append(messageLines, highlightStyle.Render("Select a player to chat with:"))
+		messageLines = append(messageLines, highlightStyle.Render(i18n.T("Select a player to chat with:")))
 		messageLines = append(messageLines, "")
 		for i, player := range m.nearbyPlayers {
 			if i < 9 { // Limit to 9 players (1-9 keys)
@@ -1408,35 +1466,10 @@ func (m Model) renderChatBox(width, height int) string {
 			}
 		}
 		messageLines = append(messageLines, "")
-		messageLines = append(messageLines, mutedStyle.Render("Press ESC to cancel"))
+		messageLines = append(messageLines, mutedStyle.Render(i18n.T("Press ESC to cancel")))
 	} else {
 		// Show messages based on current chat mode
-		var messages []string
-		if m.chatMode == ChatModeGlobal {
-			// Show global chat messages
-			messages = m.globalChatMessages
-		} else if m.chatMode == ChatModePrivate {
-			// Show private chat history with the selected user
-			if m.chatTarget != "" {
-				messages = m.privateChatHistory[m.chatTarget]
-				if messages == nil {
-					messages = []string{} // Initialize empty slice if no history yet
-				}
-			} else {
-				messages = []string{} // No target selected, show nothing
-			}
-		} else if m.chatMode == ChatModeRoom {
-			// Show room chat history for current room
-			roomNum := m.getCurrentPlayerRoom()
-			if roomNum != "" {
-				messages = m.roomChatMessages[roomNum]
-				if messages == nil {
-					messages = []string{} // Initialize empty slice if no history yet
-				}
-			} else {
-				messages = []string{mutedStyle.Render("You must be in a room to use room chat")}
-			}
-		}
+		messages := m.chatMessages()
 
 		// Show most recent messages
 		startIdx := 0
@@ -1451,13 +1484,13 @@ func (m Model) renderChatBox(width, height int) string {
 		// If no messages, show placeholder
 		if len(messageLines) == 0 {
 			if m.chatMode == ChatModeGlobal {
-				messageLines = append(messageLines, mutedStyle.Render("No messages yet. Press 't' to type."))
+				messageLines = append(messageLines, mutedStyle.Render(i18n.T("No messages yet. Press 't' to type.")))
 			} else if m.chatMode == ChatModeRoom {
-				messageLines = append(messageLines, mutedStyle.Render("No messages yet. Press 't' to type."))
+				messageLines = append(messageLines, mutedStyle.Render(i18n.T("No messages yet. Press 't' to type.")))
 			} else if m.chatTarget != "" {
-				messageLines = append(messageLines, mutedStyle.Render("No messages with "+m.chatTarget+". Press 't' to type."))
+				messageLines = append(messageLines, mutedStyle.Render(i18n.Tf("No messages with %s. Press 't' to type.", m.chatTarget)))
 			} else {
-				messageLines = append(messageLines, mutedStyle.Render("Press 'p' to select a player"))
+				messageLines = append(messageLines, mutedStyle.Render(i18n.T("Press 'p' to select a player")))
 			}
 		}
 	}
@@ -1476,6 +1509,26 @@ func (m Model) renderChatBox(width, height int) string {
 	)
 }
 
+// chatMessages returns the messages the current chat mode shows, oldest first
+func (m Model) chatMessages() []string {
+	switch m.chatMode {
+	case ChatModePrivate:
+		// Private chat history with the selected user, if there is one
+		if m.chatTarget != "" {
+			return m.privateChatHistory[m.chatTarget]
+		}
+	case ChatModeRoom:
+		// Room chat history for the current room
+		if roomNum := m.getCurrentPlayerRoom(); roomNum != "" {
+			return m.roomChatMessages[roomNum]
+		}
+		return []string{mutedStyle.Render(i18n.T("You must be in a room to use room chat"))}
+	default:
+		return m.globalChatMessages
+	}
+	return nil
+}
+
 // renderChatPanel renders the chat panel (right 30%)
 // DEPRECATED: Keeping for compatibility, but split into renderQuestBox and renderChatBox
 func (m Model) renderChatPanel(width, height int) string {
@@ -1504,36 +1557,38 @@ func (m Model) renderAnnouncementsSection(width, height int) string {
 		Bold(true).
 		Width(width).
 		Align(lipgloss.Center).
-		Render("QUEST & ANNOUNCEMENTS")
+		Render(i18n.T("QUEST & ANNOUNCEMENTS"))
 
 	// Announcements content
 	var announcementLines []string
 
 	// Add Treasure Hunt Clue at the top
-	clueHeader := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700")).Bold(true).Render("Current Clue:")
+	clueHeader := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700")).Bold(true).Render(i18n.T("Current Clue:"))
 	clueText := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFACD")).Render(m.currentClue)
-	hintText := mutedStyle.Render("(Type '/answer <text>' in chat)")
+	hintText := mutedStyle.Render(m.clueHelp())
 
 	announcementLines = append(announcementLines, clueHeader)
+	if m.clueKind != "" {
+		announcementLines = append(announcementLines, mutedStyle.Render(m.clueKind))
+	}
+	if countdown := m.renderClueCountdown(); countdown != "" {
+		announcementLines = append(announcementLines, countdown)
+	}
 	announcementLines = append(announcementLines, clueText)
+	if len(m.clueOptions) > 0 {
+		announcementLines = append(announcementLines, m.renderClueOptions())
+	}
 	announcementLines = append(announcementLines, hintText)
 	announcementLines = append(announcementLines, "") // Spacer
 
 	displayCount := height - 3 // Reserve space for title and padding
 
 	// Show most recent announcements
-	startIdx := 0
-	if len(m.announcements) > displayCount {
-		startIdx = len(m.announcements) - displayCount
-	}
-
-	for i := startIdx; i < len(m.announcements); i++ {
-		announcementLines = append(announcementLines, mutedStyle.Render("• "+m.announcements[i]))
-	}
+	announcementLines = append(announcementLines, m.visibleAnnouncements(displayCount)...)
 
 	// If no announcements, show placeholder
 	if len(announcementLines) == 0 {
-		announcementLines = append(announcementLines, mutedStyle.Render("No announcements"))
+		announcementLines = append(announcementLines, mutedStyle.Render(i18n.T("No announcements")))
 	}
 
 	announcementContent := lipgloss.NewStyle().
@@ -1554,13 +1609,13 @@ func (m Model) renderChatSection(width, height int) string {
 	// Chat mode indicator
 	var modeIndicator string
 	if m.chatMode == ChatModeGlobal {
-		modeIndicator = highlightStyle.Render("[GLOBAL]") + mutedStyle.Render(" Press 'p' for private")
+		modeIndicator = highlightStyle.Render(i18n.T("[GLOBAL]")) + mutedStyle.Render(" "+i18n.T("Press 'p' for private"))
 	} else {
 		target := m.chatTarget
 		if target == "" {
-			target = "none"
+			target = i18n.T("none")
 		}
-		modeIndicator = highlightStyle.Render("[PRIVATE: "+target+"]") + mutedStyle.Render(" Press 'g' for global")
+		modeIndicator = highlightStyle.Render(i18n.Tf("[PRIVATE: %s]", target)) + mutedStyle.Render(" "+i18n.T("Press 'g' for global"))
 	}
 
 	chatTitle := lipgloss.NewStyle().
@@ -1568,7 +1623,7 @@ func (m Model) renderChatSection(width, height int) string {
 		Bold(true).
 		Width(width).
 		Align(lipgloss.Center).
-		Render("CHAT")
+		Render(i18n.T("CHAT"))
 
 	modeBar := lipgloss.NewStyle().
 		Width(width).
@@ -1584,7 +1639,7 @@ func (m Model) renderChatSection(width, height int) string {
 
 	// Show player selection if active
 	if m.playerSelectActive {
-		messageLines = append(messageLines, highlightStyle.Render("Select a player to chat with:"))
+		messageLines = append(messageLines, highlightStyle.Render(i18n.T("Select a player to chat with:")))
 		messageLines = append(messageLines, "")
 		for i, player := range m.nearbyPlayers {
 			if i < 9 { // Limit to 9 players (1-9 keys)
@@ -1596,7 +1651,7 @@ func (m Model) renderChatSection(width, height int) string {
 			}
 		}
 		messageLines = append(messageLines, "")
-		messageLines = append(messageLines, mutedStyle.Render("Press ESC to cancel"))
+		messageLines = append(messageLines, mutedStyle.Render(i18n.T("Press ESC to cancel")))
 	} else {
 		// Show messages based on current chat mode
 		var messages []string
@@ -1628,11 +1683,11 @@ func (m Model) renderChatSection(width, height int) string {
 		// If no messages, show placeholder
 		if len(messageLines) == 0 {
 			if m.chatMode == ChatModeGlobal {
-				messageLines = append(messageLines, mutedStyle.Render("No messages yet. Press 't' to type."))
+				messageLines = append(messageLines, mutedStyle.Render(i18n.T("No messages yet. Press 't' to type.")))
 			} else if m.chatTarget != "" {
-				messageLines = append(messageLines, mutedStyle.Render("No messages with "+m.chatTarget+". Press 't' to type."))
+				messageLines = append(messageLines, mutedStyle.Render(i18n.Tf("No messages with %s. Press 't' to type.", m.chatTarget)))
 			} else {
-				messageLines = append(messageLines, mutedStyle.Render("Press 'p' to select a player"))
+				messageLines = append(messageLines, mutedStyle.Render(i18n.T("Press 'p' to select a player")))
 			}
 		}
 	}
@@ -1665,7 +1720,7 @@ func (m Model) renderChatInputBox(width int) string {
 		}
 	} else {
 		if inputText == "" {
-			inputText = mutedStyle.Render("Press 't' to type...")
+			inputText = mutedStyle.Render(i18n.T("Press 't' to type..."))
 		}
 	}
 
@@ -1679,20 +1734,53 @@ func (m Model) renderChatInputBox(width int) string {
 
 // renderStatusBar renders the bottom status bar
 func (m Model) renderStatusBar() string {
+	if m.replay != nil {
+		return m.renderReplayStatusBar()
+	}
+
 	playerInfo := lipgloss.NewStyle().
 		Foreground(successColor).
 		Bold(true).
-		Render("Player: " + m.userName)
+		Render(i18n.Tf("Player: %s", m.userName))
+
+	points := lipgloss.NewStyle().
+		Foreground(accentColor).
+		Bold(true).
+		Render(fmt.Sprintf("⭐ %d", m.points))
+	if m.streak > 1 {
+		points += lipgloss.NewStyle().Foreground(primaryColor).Bold(true).Render(fmt.Sprintf("  🔥 %d", m.streak))
+	}
+
+	if m.latency > 0 {
+		points += "  " + m.renderLatency()
+	}
+	if m.isGhost() {
+		points += "  " + mutedStyle.Render("👻 "+i18n.T("ghost"))
+	}
 
 	avatarDisplay := lipgloss.NewStyle().
 		Foreground(secondaryColor).
 		Render(strings.ReplaceAll(m.avatar.Render(), "\n", " "))
 
+	// Shared room timer, e.g. "🍅 Focus 12:34", and the running game mode
+	pomodoro := ""
+	if m.connMgr != nil {
+		if p, ok := m.connMgr.GetPomodoro(m.getCurrentPlayerRoom()); ok {
+			pomodoro = m.renderPomodoro(p) + "  •  "
+		}
+		if mode := m.connMgr.GetGameMode(); mode != nil {
+			pomodoro += m.renderGameModeStatus(*mode) + "  •  "
+		}
+	}
+
 	var controls string
-	if m.chatInputActive {
-		controls = mutedStyle.Render("ENTER: Send  •  ESC: Cancel")
+	if toast, ok := m.activeToast(); ok {
+		// A private message or mention from another chat mode stands in for the controls
+		controls = highlightStyle.Render(toast)
+	} else if m.chatInputActive {
+		controls = mutedStyle.Render(i18n.T("ENTER: Send  •  ESC: Cancel"))
 	} else {
-		controls = mutedStyle.Render("T: Chat  •  G/P: Mode  •  CTRL+C: Quit")
+		controls = mutedStyle.Render(m.keys.statusHelp())
 	}
 
 	return lipgloss.NewStyle().
@@ -1700,5 +1788,98 @@ func (m Model) renderStatusBar() string {
 		Width(m.width).
 		Padding(1, 0).
 		Align(lipgloss.Center).
-		Render(playerInfo + "  " + avatarDisplay + "  •  " + controls)
+		Render(playerInfo + "  " + avatarDisplay + "  " + points + "  •  " + pomodoro + controls)
+}
+
+// Round trips above these make movement feel sluggish, then laggy
+const (
+	latencySlow  = 100 * time.Millisecond
+	latencyLaggy = 250 * time.Millisecond
+)
+
+// renderLatency renders the round trip to the server, colored by how much lag it adds
+func (m Model) renderLatency() string {
+	style := lipgloss.NewStyle().Foreground(successColor)
+	switch {
+	case m.latency >= latencyLaggy:
+		style = errorStyle
+	case m.latency >= latencySlow:
+		style = lipgloss.NewStyle().Foreground(primaryColor)
+	}
+	return style.Render(fmt.Sprintf("📶 %dms", m.latency.Milliseconds()))
+}
+
+const celebrationDuration = 6 * time.Second // How long a riddle winner's banner stays up
+
+// Confetti around the winner banner
+var (
+	confettiGlyphs = []string{"*", "·", "✦", "+", "•", "˚", "✧", " "}
+	confettiColors = []lipgloss.Color{"#FFD700", "#E07B7B", "#7EBB81", "#8EC5E8", "#D8A8E8", "#F0DEB4"}
+)
+
+// renderCelebration renders the winner banner with confetti that moves every frame
+func (m Model) renderCelebration(width int) string {
+	message := " 🎉 " + m.celebration + " 🎉 "
+	side := max((width-lipgloss.Width(message))/2, 0)
+	frame := time.Now().UnixMilli() / 150
+
+	confetti := func(offset int) string {
+		var b strings.Builder
+		for i := 0; i < side; i++ {
+			n := int(frame) + i*7 + offset
+			glyph := confettiGlyphs[n%len(confettiGlyphs)]
+			b.WriteString(lipgloss.NewStyle().Foreground(confettiColors[(n/3)%len(confettiColors)]).Render(glyph))
+		}
+		return b.String()
+	}
+
+	banner := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFD700")).Bold(true).Render(message)
+	return lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(confetti(0) + banner + confetti(3))
+}
+
+// clueHelp tells players how to answer the clue in the side panel
+func (m Model) clueHelp() string {
+	if len(m.clueOptions) > 0 {
+		return i18n.Tf("(Press Q, then 1-%d to answer)", len(m.clueOptions))
+	}
+	return i18n.T("(Press Q or type '/guess <text>' in chat)")
+}
+
+// renderClueCountdown renders the time left to solve the riddle, or until the next one
+func (m Model) renderClueCountdown() string {
+	var label string
+	var until int64
+	switch {
+	case m.clueEndsAt != 0:
+		label, until = "⏱ "+i18n.T("Time left"), m.clueEndsAt
+	case m.clueNextAt != 0:
+		label, until = "⏳ "+i18n.T("Next riddle in"), m.clueNextAt
+	default:
+		return ""
+	}
+
+	remaining := time.Until(time.Unix(until, 0))
+	if remaining < 0 {
+		remaining = 0
+	}
+	clock := fmt.Sprintf("%d:%02d", int(remaining.Minutes()), int(remaining.Seconds())%60)
+
+	if m.clueEndsAt != 0 && remaining <= 10*time.Second {
+		return errorStyle.Render(label + " " + clock) // Hurry up
+	}
+	return lipgloss.NewStyle().Foreground(primaryColor).Bold(true).Render(label + " " + clock)
+}
+
+// renderPomodoro renders the phase and time left of a room's pomodoro timer
+func (m Model) renderPomodoro(p protocol.PomodoroState) string {
+	remaining := time.Until(time.Unix(p.EndsAt, 0))
+	if remaining < 0 {
+		remaining = 0
+	}
+	clock := fmt.Sprintf("%02d:%02d", int(remaining.Minutes()), int(remaining.Seconds())%60)
+
+	if p.Phase == "break" {
+		return lipgloss.NewStyle().Foreground(successColor).Bold(true).Render("☕ " + i18n.T("Break") + " " + clock)
+	}
+	return lipgloss.NewStyle().Foreground(primaryColor).Bold(true).Render("🍅 " + i18n.T("Focus") + " " + clock)
 }
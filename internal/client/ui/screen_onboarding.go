@@ -1,11 +1,11 @@
 package ui
 
 import (
-	"fmt"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
 )
 
 // updateAvatarCustomization handles avatar customization screen
@@ -68,6 +68,8 @@ func (m Model) updateAvatarCustomization(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.err = err
 				return m, nil
 			}
+			m.config.Profile.Avatar = avatarSelection
+			m.saveProfile()
 
 			// Server will respond with the GameState event
 		}
@@ -80,12 +82,12 @@ func (m Model) updateAvatarCustomization(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 // viewAvatarCustomization renders the avatar customization screen
 func (m Model) viewAvatarCustomization() string {
 	// Title
-	title := titleStyle.Render(fmt.Sprintf("CUSTOMIZE AVATAR - %s", strings.ToUpper(m.userName)))
+	title := titleStyle.Render(i18n.Tf("CUSTOMIZE AVATAR - %s", strings.ToUpper(m.userName)))
 
 	// Avatar preview with cursor indicators
 	var avatarLines []string
 	avatarParts := strings.Split(m.avatar.Render(), "\n")
-	rowLabels := []string{"HEAD", "TORSO", "LEGS"}
+	rowLabels := []string{i18n.T("HEAD"), i18n.T("TORSO"), i18n.T("LEGS")}
 
 	for i, part := range avatarParts {
 		cursor := "  "
@@ -157,7 +159,7 @@ func (m Model) viewAvatarCustomization() string {
 	)
 
 	// Instructions at the bottom
-	instructions := mutedStyle.Render("Arrows to navigate  •  ENTER to confirm  •  ESC to quit")
+	instructions := mutedStyle.Render(i18n.T("Arrows to navigate  •  ENTER to confirm  •  ESC to quit"))
 
 	// Calculate positions
 	centeredMain := lipgloss.Place(m.width, m.height-3, lipgloss.Center, lipgloss.Center, mainContent)
@@ -0,0 +1,231 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
+	"github.com/yourusername/always-at-morg/internal/gamemap"
+)
+
+// Screen reader mode draws the main game as lines of text instead of the tile grid and
+// side panels: where the player is, who and what is around and the way out, then the
+// clue, the announcements and the chat one after another. It leaves out the clocks, so
+// nothing changes until something happens and a screen reader isn't kept re-reading it.
+const (
+	nearbyRadius   = 20 // Tiles away other players, NPCs and objects are still described
+	maxNearby      = 5  // Most of them described, closest first
+	exitSearch     = 80 // Steps the way out of a room, or into the nearest one, is looked for
+	srAnnouncement = 4  // Announcements shown above the chat
+)
+
+// nearbyThing is a player, NPC or object near the player, dx, dy tiles away
+type nearbyThing struct {
+	name   string
+	dx, dy int
+}
+
+// viewScreenReader renders the main game for screen readers, one line after another
+func (m Model) viewScreenReader() string {
+	var lines []string
+	section := func(title string) {
+		lines = append(lines, "", highlightStyle.Render(title))
+	}
+
+	if m.overlay != OverlayNone {
+		// An overlay takes the place of the surroundings, as it does the game world's
+		lines = append(lines, m.overlayContent(m.width, m.height-4))
+	} else {
+		lines = append(lines, m.describeSurroundings()...)
+
+		section(i18n.T("Current Clue:"))
+		if m.clueKind != "" {
+			lines = append(lines, m.clueKind)
+		}
+		lines = append(lines, m.currentClue)
+		for i, option := range m.clueOptions {
+			lines = append(lines, fmt.Sprintf("%d. %s", i+1, option))
+		}
+		lines = append(lines, mutedStyle.Render(m.clueHelp()))
+
+		section(i18n.T("Announcements"))
+		lines = append(lines, m.visibleAnnouncements(srAnnouncement)...)
+	}
+
+	// Whatever space is left goes to the chat, newest at the bottom
+	section(m.chatModeName())
+	var chat []string
+	if m.playerSelectActive {
+		chat = append(chat, i18n.T("Select a player to chat with:"))
+		for i, player := range m.nearbyPlayers[:min(len(m.nearbyPlayers), 9)] {
+			chat = append(chat, fmt.Sprintf("%d. %s", i+1, player))
+		}
+		chat = append(chat, mutedStyle.Render(i18n.T("Press ESC to cancel")))
+	} else {
+		chat = m.chatMessages()
+		if len(chat) == 0 {
+			chat = []string{mutedStyle.Render(i18n.T("No messages yet."))}
+		}
+	}
+	room := max(m.height-len(lines)-3, 1) // Input and controls below
+	lines = append(lines, chat[max(len(chat)-room, 0):]...)
+
+	input := mutedStyle.Render(i18n.T("Press 't' to type..."))
+	if m.chatInputActive {
+		input = "> " + m.chatInput + cursorStyle.Render("|")
+	}
+	controls := m.keys.statusHelp()
+	if toast, ok := m.activeToast(); ok {
+		controls = toast
+	} else if m.chatInputActive {
+		controls = i18n.T("ENTER: Send  •  ESC: Cancel")
+	}
+	lines = append(lines, "", input, mutedStyle.Render(controls))
+
+	return lipgloss.NewStyle().Width(m.width).MaxHeight(m.height).Render(strings.Join(lines, "\n"))
+}
+
+// chatModeName names the chat mode and who it reaches, as the chat box's title
+func (m Model) chatModeName() string {
+	switch m.chatMode {
+	case ChatModePrivate:
+		if m.chatTarget == "" {
+			return i18n.T("Private chat")
+		}
+		return i18n.Tf("Private chat with %s", m.chatTarget)
+	case ChatModeRoom:
+		if roomNum := m.getCurrentPlayerRoom(); roomNum != "" {
+			return i18n.Tf("Room %s chat", roomNum)
+		}
+		return i18n.T("Room chat")
+	}
+	return i18n.T("Global chat")
+}
+
+// describeSurroundings says where the player is, who and what is nearby, and the way
+// out of the room they're in or into the nearest room
+func (m Model) describeSurroundings() []string {
+	if m.connMgr == nil {
+		return []string{i18n.T("Waiting for the game to start...")}
+	}
+	state := m.connMgr.GetState()
+	if state == nil {
+		return []string{i18n.T("Waiting for the game to start...")}
+	}
+	me, ok := state.Players[m.userName]
+	if !ok || me.Pos == "" {
+		return []string{i18n.T("Waiting for the game to start...")}
+	}
+	roomMap, err := getRoomMap()
+	if err != nil {
+		return nil
+	}
+	x, y := parsePosition(me.Pos)
+	room := roomMap.RoomNumberAt(x, y)
+
+	var lines []string
+	if room != "" {
+		lines = append(lines, i18n.Tf("You are in room %s.", room))
+	} else {
+		lines = append(lines, i18n.T("You are in the hallway."))
+	}
+
+	var things []nearbyThing
+	add := func(name, pos string) {
+		tx, ty := parsePosition(pos)
+		if dx, dy := tx-x, ty-y; max(abs(dx), abs(dy)) <= nearbyRadius {
+			things = append(things, nearbyThing{name, dx, dy})
+		}
+	}
+	for name, player := range state.Players {
+		if name != m.userName && player.Pos != "" && !player.Ghost {
+			add(name, player.Pos)
+		}
+	}
+	for _, npc := range state.NPCs {
+		add(npc.Name, npc.Pos)
+	}
+	for _, object := range m.connMgr.GetObjects() {
+		add(object.Name, object.Pos)
+	}
+	slices.SortFunc(things, func(a, b nearbyThing) int {
+		return cmp.Or(cmp.Compare(max(abs(a.dx), abs(a.dy)), max(abs(b.dx), abs(b.dy))), strings.Compare(a.name, b.name))
+	})
+	if len(things) == 0 {
+		lines = append(lines, i18n.T("Nobody is nearby."))
+	}
+	for _, thing := range things[:min(len(things), maxNearby)] {
+		lines = append(lines, i18n.Tf("%s is %s.", thing.name, describeOffset(thing.dx, thing.dy)))
+	}
+
+	if ex, ey, ok := nearestOtherArea(roomMap, x, y); ok {
+		if room != "" {
+			lines = append(lines, i18n.Tf("The way out is %s.", describeOffset(ex-x, ey-y)))
+		} else {
+			lines = append(lines, i18n.Tf("Room %s is %s.", roomMap.RoomNumberAt(ex, ey), describeOffset(ex-x, ey-y)))
+		}
+	}
+	return lines
+}
+
+// nearestOtherArea finds the closest spot an avatar can walk to that's in a different
+// area from x, y: the hallway from a room, or a room from the hallway
+func nearestOtherArea(roomMap *gamemap.Grid, x, y int) (int, int, bool) {
+	type step struct{ x, y, dist int }
+	start := roomMap.RoomNumberAt(x, y)
+	seen := map[[2]int]bool{{x, y}: true}
+	queue := []step{{x, y, 0}}
+	for len(queue) > 0 {
+		at := queue[0]
+		queue = queue[1:]
+		if roomMap.RoomNumberAt(at.x, at.y) != start {
+			return at.x, at.y, true
+		}
+		if at.dist == exitSearch {
+			continue
+		}
+		for _, d := range [][2]int{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+			next := [2]int{at.x + d[0], at.y + d[1]}
+			if !seen[next] && roomMap.AvatarFits(next[0], next[1]) {
+				seen[next] = true
+				queue = append(queue, step{next[0], next[1], at.dist + 1})
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// describeOffset says how far away something dx, dy tiles off is, e.g. "3 tiles north"
+// or "4 tiles south and 1 tile east"
+func describeOffset(dx, dy int) string {
+	tiles := func(n int, direction string) string {
+		if n == 1 {
+			return i18n.Tf("1 tile %s", i18n.T(direction))
+		}
+		return i18n.Tf("%d tiles %s", n, i18n.T(direction))
+	}
+
+	var parts []string
+	switch {
+	case dy < 0:
+		parts = append(parts, tiles(-dy, "north"))
+	case dy > 0:
+		parts = append(parts, tiles(dy, "south"))
+	}
+	switch {
+	case dx < 0:
+		parts = append(parts, tiles(-dx, "west"))
+	case dx > 0:
+		parts = append(parts, tiles(dx, "east"))
+	}
+	switch len(parts) {
+	case 0:
+		return i18n.T("right here")
+	case 1:
+		return parts[0]
+	}
+	return i18n.Tf("%s and %s", parts[0], parts[1])
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
+)
+
+// Smallest terminal the screens can be laid out in. Below it, the game and chat panels
+// squeeze past their borders and the layout falls apart.
+const (
+	minWidth  = 80
+	minHeight = 24
+)
+
+// updateTooSmall handles keys while the terminal is too small to draw the UI
+func (m Model) updateTooSmall(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.Disconnect()
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+// viewTooSmall asks for a bigger terminal. It wraps to whatever width there is, since
+// it's the one screen drawn when nothing else fits.
+func (m Model) viewTooSmall() string {
+	message := strings.Join([]string{
+		highlightStyle.Render("ALWAYS AT MORG"),
+		"",
+		i18n.Tf("Please enlarge your terminal to at least %dx%d", minWidth, minHeight),
+		mutedStyle.Render(i18n.Tf("It's %dx%d now", m.width, m.height)),
+		"",
+		mutedStyle.Render(i18n.T("CTRL+C to quit")),
+	}, "\n")
+	message = lipgloss.NewStyle().Width(max(m.width, 1)).Align(lipgloss.Center).Render(message)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, message)
+}
@@ -3,6 +3,8 @@ package ui
 import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
+	"github.com/yourusername/always-at-morg/internal/protocol"
 )
 
 // updateUsernameEntry handles username entry screen
@@ -14,15 +16,17 @@ func (m Model) updateUsernameEntry(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "enter":
 		if len(m.usernameInput) > 0 {
 			m.userName = m.usernameInput
+			m.config.Profile.Username = m.userName
+			m.saveProfile()
 
-			// Joiin the room
-			if m.connMgr != nil && m.connMgr.IsConnected() {
-				err := m.connMgr.JoinRoom(m.roomID, m.userName)
-				if err != nil {
-					m.err = err
-					return m, nil
-				}
-				// Server will respond with an event, which will trigger screen transition
+			// Pick a room to join in the lobby, unless an invite or the config file already
+			// picked one. If it can't be joined, the lobby shows why.
+			m.openLobby()
+			if m.invite != nil {
+				m.joinRoom(m.invite.Room, m.invite.Password)
+				m.invite = nil
+			} else if m.config.Profile.Room != "" {
+				m.joinRoom(m.config.Profile.Room, "")
 			}
 		}
 		return m, nil
@@ -33,8 +37,8 @@ func (m Model) updateUsernameEntry(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 	default:
-		// Add character to username (limit to 20 chars)
-		if len(msg.String()) == 1 && len(m.usernameInput) < 20 {
+		// Add character to username, up to what the server accepts
+		if len(msg.String()) == 1 && len(m.usernameInput) < protocol.MaxUsernameLength {
 			m.usernameInput += msg.String()
 		}
 	}
@@ -51,7 +55,7 @@ func (m Model) viewUsernameEntry() string {
 	promptText := lipgloss.NewStyle().
 		Foreground(secondaryColor).
 		Margin(1, 0).
-		Render("Enter username:")
+		Render(i18n.T("Enter username:"))
 
 	// Input field with cursor
 	inputText := m.usernameInput
@@ -72,7 +76,7 @@ func (m Model) viewUsernameEntry() string {
 	)
 
 	// Instructions at the bottom
-	instructions := mutedStyle.Render("ENTER to continue  •  ESC to quit")
+	instructions := mutedStyle.Render(i18n.T("ENTER to continue  •  ESC to quit"))
 
 	// Calculate positions - main content in center, instructions at bottom
 	centeredMain := lipgloss.Place(m.width, m.height-3, lipgloss.Center, lipgloss.Center, mainContent)
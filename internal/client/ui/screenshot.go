@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// screenshotName is the file name screenshots are saved under, by when they're taken
+const screenshotName = "morg-2006-01-02-150405"
+
+// saveScreenshot writes the game world as last drawn to a timestamped .ans file in the
+// current directory, keeping its colors for cat or a terminal to show, and to a .txt file
+// without them for pasting. It returns the .ans file's name.
+func (m Model) saveScreenshot(now time.Time) (string, error) {
+	frame := m.frame.rendered
+	if frame == "" {
+		return "", errors.New("the game world hasn't been drawn yet")
+	}
+	if asciiMode {
+		frame = toASCII(frame)
+	}
+
+	name := now.Format(screenshotName)
+	if err := os.WriteFile(name+".ans", []byte(frame+ansi.ResetStyle+"\n"), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(name+".txt", []byte(ansi.Strip(frame)+"\n"), 0o644); err != nil {
+		return "", err
+	}
+	return name + ".ans", nil
+}
@@ -0,0 +1,347 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yourusername/always-at-morg/internal/client/config"
+	"github.com/yourusername/always-at-morg/internal/client/i18n"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// setting is one row of the settings screen
+type setting struct {
+	label  string
+	value  func(s config.Settings) string
+	change func(s *config.Settings, dir int) // Step the value forward (1) or back (-1)
+}
+
+// cycle steps through options from current, wrapping around at either end
+func cycle(options []string, current string, dir int) string {
+	i := slices.Index(options, current)
+	return options[(i+dir+len(options))%len(options)]
+}
+
+// onOff shows a setting that's either on or off
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// viewportStep is how much a viewport cap changes per keypress
+const viewportStep = 10
+
+var settingsRows = []setting{
+	{
+		label:  "Theme",
+		value:  func(s config.Settings) string { return s.Theme },
+		change: func(s *config.Settings, dir int) { s.Theme = cycle(ThemeNames(), s.Theme, dir) },
+	},
+	{
+		label: "Language",
+		value: func(s config.Settings) string {
+			if s.Language == config.LanguageAuto {
+				return "auto"
+			}
+			return i18n.Name(s.Language)
+		},
+		change: func(s *config.Settings, dir int) {
+			s.Language = cycle(append([]string{config.LanguageAuto}, i18n.Languages()...), s.Language, dir)
+		},
+	},
+	{
+		label: "Chat timestamps",
+		value: func(s config.Settings) string {
+			if s.Timestamps {
+				return "shown"
+			}
+			return "hidden"
+		},
+		change: func(s *config.Settings, dir int) { s.Timestamps = !s.Timestamps },
+	},
+	{
+		label: "Chat bell",
+		value: func(s config.Settings) string { return s.ChatNotifications },
+		change: func(s *config.Settings, dir int) {
+			s.ChatNotifications = cycle([]string{config.NotifyPrivate, config.NotifyMentions, config.NotifyAll, config.NotifyOff}, s.ChatNotifications, dir)
+		},
+	},
+	{
+		label:  "DM popups",
+		value:  func(s config.Settings) string { return onOff(s.ToastPrivate) },
+		change: func(s *config.Settings, dir int) { s.ToastPrivate = !s.ToastPrivate },
+	},
+	{
+		label:  "Mention popups",
+		value:  func(s config.Settings) string { return onOff(s.ToastMentions) },
+		change: func(s *config.Settings, dir int) { s.ToastMentions = !s.ToastMentions },
+	},
+	{
+		label: "Desktop alerts",
+		value: func(s config.Settings) string { return s.Desktop },
+		change: func(s *config.Settings, dir int) {
+			s.Desktop = cycle([]string{config.DesktopUnfocused, config.DesktopAlways, config.DesktopOff}, s.Desktop, dir)
+		},
+	},
+	{
+		label: "Movement keys",
+		value: func(s config.Settings) string { return s.MovementKeys },
+		change: func(s *config.Settings, dir int) {
+			s.MovementKeys = cycle([]string{config.KeysAll, config.KeysWASD, config.KeysArrows, config.KeysVim}, s.MovementKeys, dir)
+		},
+	},
+	{
+		label:  "Screen reader",
+		value:  func(s config.Settings) string { return onOff(s.ScreenReader) },
+		change: func(s *config.Settings, dir int) { s.ScreenReader = !s.ScreenReader },
+	},
+	{
+		label: "Max view width",
+		value: func(s config.Settings) string { return fmt.Sprint(s.MaxViewportWidth) },
+		change: func(s *config.Settings, dir int) {
+			s.MaxViewportWidth = max(s.MaxViewportWidth+dir*viewportStep, config.MinViewportWidth)
+		},
+	},
+	{
+		label: "Max view height",
+		value: func(s config.Settings) string { return fmt.Sprint(s.MaxViewportHeight) },
+		change: func(s *config.Settings, dir int) {
+			s.MaxViewportHeight = max(s.MaxViewportHeight+dir*viewportStep/2, config.MinViewportHeight)
+		},
+	},
+}
+
+// SetConfig gives the model the player's config and where to save it when the settings
+// change. With an empty path, changes last only for this session. The saved username
+// and avatar are where the username and avatar screens start.
+func (m *Model) SetConfig(path string, cfg config.Config) {
+	if !slices.Contains(ThemeNames(), cfg.Settings.Theme) {
+		cfg.Settings.Theme = ThemeNames()[0]
+	}
+	m.configPath = path
+	m.config = cfg
+	m.keys = newKeyMap(cfg)
+	applyTheme(cfg.Settings.Theme)
+
+	if m.usernameInput == "" && len(cfg.Profile.Username) <= protocol.MaxUsernameLength {
+		m.usernameInput = cfg.Profile.Username
+	}
+	if avatar := cfg.Profile.Avatar; len(avatar) == 3 &&
+		avatar[0] >= 0 && avatar[0] < len(HeadOptions) &&
+		avatar[1] >= 0 && avatar[1] < len(TorsoOptions) &&
+		avatar[2] >= 0 && avatar[2] < len(LegOptions) {
+		m.avatar = createAvatarFromIndices(avatar)
+	}
+}
+
+// SetLanguage shows the UI in a language from i18n.Languages, or with config.LanguageAuto
+// the one the locale asks for. Languages the UI doesn't have fall back to English.
+func SetLanguage(code string) {
+	if code == config.LanguageAuto {
+		code = i18n.Detect()
+	}
+	i18n.SetLanguage(code)
+}
+
+// UseSettings puts back this model's theme, language and time of day. They're kept in
+// package variables, so a process running several models, like the browser client
+// bridge, calls it from each before it updates or draws, one model at a time.
+func (m Model) UseSettings() {
+	if themeName != m.config.Settings.Theme {
+		applyTheme(m.config.Settings.Theme)
+	}
+	SetLanguage(m.config.Settings.Language)
+	if m.connMgr != nil {
+		if gameState := m.connMgr.GetState(); gameState != nil {
+			setTimeOfDay(gameState.TimeOfDay)
+		}
+	}
+}
+
+// openSettings opens the settings screen
+func (m *Model) openSettings() {
+	m.settingsCursor = 0
+	m.settingsError = ""
+	m.rebinding = ""
+	m.overlay = OverlaySettings
+}
+
+// updateSettingsOverlay moves through the settings and changes the selected one,
+// saving the config file after every change. Below the settings come the key bindings,
+// which take the next key pressed.
+func (m Model) updateSettingsOverlay(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.rebinding != "" {
+		if msg.String() != "esc" {
+			m.bindKey(m.rebinding, msg.String())
+			m.saveSettings()
+		}
+		m.rebinding = ""
+		return m, nil
+	}
+
+	actions := m.keys.actions()
+	onKeys := m.settingsCursor >= len(settingsRows)
+	dir := 0
+	switch msg.String() {
+	case "up", "k", "w":
+		if m.settingsCursor > 0 {
+			m.settingsCursor--
+		}
+	case "down", "j", "s":
+		if m.settingsCursor < len(settingsRows)+len(actions)-1 {
+			m.settingsCursor++
+		}
+	case "backspace", "delete":
+		if onKeys {
+			delete(m.config.Keys, actions[m.settingsCursor-len(settingsRows)].name)
+			m.keys = newKeyMap(m.config)
+			m.saveSettings()
+		}
+	case "right", "l", "d", "enter", " ":
+		if onKeys {
+			m.rebinding = actions[m.settingsCursor-len(settingsRows)].name
+			return m, nil
+		}
+		dir = 1
+	case "left", "h", "a":
+		if !onKeys {
+			dir = -1
+		}
+	default:
+		if key.Matches(msg, m.keys.Settings) {
+			m.overlay = OverlayNone
+		}
+	}
+	if dir == 0 {
+		return m, nil
+	}
+
+	settingsRows[m.settingsCursor].change(&m.config.Settings, dir)
+	applyTheme(m.config.Settings.Theme)
+	SetLanguage(m.config.Settings.Language)
+	m.keys = newKeyMap(m.config)
+	m.resizeGameWorld()
+	m.saveSettings()
+	return m, nil
+}
+
+// bindKey makes pressed the only key for action, taking it from any action it was bound to
+func (m *Model) bindKey(action, pressed string) {
+	if m.config.Keys == nil {
+		m.config.Keys = make(map[string][]string)
+	}
+	for name, keys := range m.config.Keys {
+		m.config.Keys[name] = slices.DeleteFunc(keys, func(k string) bool { return k == pressed })
+	}
+	m.config.Keys[action] = []string{pressed}
+	m.keys = newKeyMap(m.config)
+}
+
+// saveSettings writes the settings and key bindings to the config file, if there is one
+func (m *Model) saveSettings() {
+	m.settingsError = ""
+	if m.configPath == "" {
+		return
+	}
+	err := config.Update(m.configPath, func(cfg *config.Config) {
+		cfg.Settings = m.config.Settings
+		cfg.Keys = m.config.Keys
+	})
+	if err != nil {
+		m.settingsError = i18n.Tf("Couldn't save settings: %v", err)
+	}
+}
+
+// saveProfile remembers the profile's username and avatar in the config file for the
+// next session
+func (m *Model) saveProfile() {
+	if m.configPath == "" {
+		return
+	}
+	err := config.Update(m.configPath, func(cfg *config.Config) {
+		cfg.Profile.Username = m.config.Profile.Username
+		cfg.Profile.Avatar = m.config.Profile.Avatar
+	})
+	if err != nil {
+		m.settingsError = i18n.Tf("Couldn't save your profile: %v", err)
+	}
+}
+
+// renderSettingsOverlay lists the settings with their current values, then the key
+// bindings, translating the labels and values as it goes
+func (m Model) renderSettingsOverlay(height int) string {
+	title := titleStyle.Render(i18n.T("SETTINGS"))
+
+	var rows []string
+	selectable, cursorRow := 0, 0
+	addRow := func(line string) {
+		if selectable == m.settingsCursor {
+			cursorRow = len(rows)
+			line = selectedOptionStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		rows = append(rows, line)
+		selectable++
+	}
+	for _, row := range settingsRows {
+		addRow(fmt.Sprintf("%-16s ◀ %s ▶", i18n.T(row.label), i18n.T(row.value(m.config.Settings))))
+	}
+	rows = append(rows, "", "  "+mutedStyle.Render(i18n.T("KEYS")))
+	for _, action := range m.keys.actions() {
+		bound := strings.Join(action.binding.Keys(), ", ")
+		switch {
+		case action.name == m.rebinding:
+			bound = highlightStyle.Render(i18n.T("press a key..."))
+		case bound == "":
+			bound = mutedStyle.Render(i18n.T("(none)"))
+		}
+		addRow(fmt.Sprintf("%-16s %s", i18n.T(action.label), bound))
+	}
+
+	// Keep the cursor on screen when the list is longer than the panel
+	visible := max(height-5, 1)
+	start := 0
+	if cursorRow >= visible {
+		start = cursorRow - visible + 1
+	}
+	rows = rows[start:min(start+visible, len(rows))]
+
+	saved := mutedStyle.Render(i18n.Tf("Saved to %s", m.configPath))
+	switch {
+	case m.settingsError != "":
+		saved = errorStyle.Render(m.settingsError)
+	case m.configPath == "":
+		saved = mutedStyle.Render(i18n.T("Changes last until you quit"))
+	}
+
+	controls := i18n.T("↑/↓: Select  •  ←/→: Change  •  ESC: Close")
+	if m.settingsCursor >= len(settingsRows) {
+		controls = i18n.T("↑/↓: Select  •  ENTER: Rebind  •  BACKSPACE: Default  •  ESC: Close")
+	}
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		strings.Join(rows, "\n"),
+		"",
+		saved,
+		mutedStyle.Render(controls),
+	)
+}
+
+// formatChatLine formats a chat message for the chat box, with the time it was sent if
+// timestamps are shown
+func (m Model) formatChatLine(sender, message string, timestamp int64) string {
+	line := highlightStyle.Render("["+sender+"]") + " " + message
+	if m.config.Settings.Timestamps && timestamp > 0 {
+		line = mutedStyle.Render(time.Unix(timestamp, 0).Format("15:04")) + " " + line
+	}
+	return line
+}
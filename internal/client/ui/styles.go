@@ -2,93 +2,214 @@ package ui
 
 import "github.com/charmbracelet/lipgloss"
 
-// Color palette - Earthy tones (lighter for dark backgrounds)
+// theme is a palette the UI can be drawn in. The map keeps its own colors, which are
+// drawn on their own backgrounds and so read the same on any terminal.
+type theme struct {
+	name      string
+	primary   lipgloss.Color
+	secondary lipgloss.Color
+	accent    lipgloss.Color
+	success   lipgloss.Color
+	muted     lipgloss.Color
+	fg        lipgloss.Color
+	highlight lipgloss.Color
+	danger    lipgloss.Color
+}
+
+// themes are the built-in palettes, the first being the default
+var themes = []theme{
+	{
+		name:      "classic", // Earthy tones (lighter for dark backgrounds)
+		primary:   "#E8C4A0", // Light warm beige
+		secondary: "#7EBB81", // Light forest green
+		accent:    "#A8C9A4", // Soft sage green
+		success:   "#B5D99C", // Bright sage
+		muted:     "#B8A890", // Light taupe
+		fg:        "#F5F3ED", // Warm white
+		highlight: "#F0DEB4", // Cream highlight
+		danger:    "#E07B7B", // Soft red
+	},
+	{
+		name:      "dark",    // Cool tones for dark backgrounds
+		primary:   "#8EC5E8", // Sky blue
+		secondary: "#9FA8DA", // Periwinkle
+		accent:    "#80CBC4", // Teal
+		success:   "#A5D6A7", // Mint
+		muted:     "#90A4AE", // Blue grey
+		fg:        "#ECEFF1", // Cool white
+		highlight: "#FFE082", // Amber
+		danger:    "#EF9A9A", // Light red
+	},
+	{
+		name:      "light",   // Deep earthy tones for light backgrounds
+		primary:   "#8B5A2B", // Saddle brown
+		secondary: "#2E7D32", // Forest green
+		accent:    "#00695C", // Deep teal
+		success:   "#33691E", // Olive green
+		muted:     "#6D6258", // Dark taupe
+		fg:        "#1F1B16", // Near black
+		highlight: "#7A4F00", // Dark amber
+		danger:    "#B71C1C", // Dark red
+	},
+	{
+		name:      "high-contrast", // Pure colors on any background
+		primary:   "#FFFF00",       // Yellow
+		secondary: "#00FFFF",       // Cyan
+		accent:    "#FFFFFF",       // White
+		success:   "#00FF00",       // Green
+		muted:     "#C0C0C0",       // Silver
+		fg:        "#FFFFFF",       // White
+		highlight: "#FFFF00",       // Yellow
+		danger:    "#FF5555",       // Red
+	},
+}
+
+// ThemeNames returns the names of the built-in themes, the default first
+func ThemeNames() []string {
+	names := make([]string, len(themes))
+	for i, t := range themes {
+		names[i] = t.name
+	}
+	return names
+}
+
+// Color palette, set from the theme by applyTheme
 var (
-	primaryColor   = lipgloss.Color("#E8C4A0") // Light warm beige
-	secondaryColor = lipgloss.Color("#7EBB81") // Light forest green
-	accentColor    = lipgloss.Color("#A8C9A4") // Soft sage green
-	successColor   = lipgloss.Color("#B5D99C") // Bright sage
-	mutedColor     = lipgloss.Color("#B8A890") // Light taupe
-	fgColor        = lipgloss.Color("#F5F3ED") // Warm white
-	highlightColor = lipgloss.Color("#F0DEB4") // Cream highlight
+	primaryColor   lipgloss.Color
+	secondaryColor lipgloss.Color
+	accentColor    lipgloss.Color
+	successColor   lipgloss.Color
+	mutedColor     lipgloss.Color
+	fgColor        lipgloss.Color
+	highlightColor lipgloss.Color
+	errorColor     lipgloss.Color
 )
 
-// Styles
+// Styles, built from the palette by applyTheme
 var (
+	titleStyle          lipgloss.Style
+	subtitleStyle       lipgloss.Style
+	boxStyle            lipgloss.Style
+	inputBoxStyle       lipgloss.Style
+	highlightStyle      lipgloss.Style
+	mutedStyle          lipgloss.Style
+	instructionStyle    lipgloss.Style
+	avatarBoxStyle      lipgloss.Style
+	optionStyle         lipgloss.Style
+	selectedOptionStyle lipgloss.Style
+	cursorStyle         lipgloss.Style
+	gameBoxStyle        lipgloss.Style
+	chatBoxStyle        lipgloss.Style
+	centerStyle         lipgloss.Style
+	spinnerStyle        lipgloss.Style
+	errorStyle          lipgloss.Style
+)
+
+// themeName is the theme the styles were last built for
+var themeName string
+
+func init() {
+	applyTheme(themes[0].name)
+}
+
+// applyTheme switches the UI to the named theme, or the default one if there's no such
+// theme
+func applyTheme(name string) {
+	t := themes[0]
+	for _, candidate := range themes {
+		if candidate.name == name {
+			t = candidate
+		}
+	}
+
+	primaryColor = t.primary
+	secondaryColor = t.secondary
+	accentColor = t.accent
+	successColor = t.success
+	mutedColor = t.muted
+	fgColor = t.fg
+	highlightColor = t.highlight
+	errorColor = t.danger
+	themeName = t.name
+	buildStyles()
+}
+
+// buildStyles (re)builds the styles from the current palette
+func buildStyles() {
 	titleStyle = lipgloss.NewStyle().
-			Foreground(primaryColor).
-			Bold(true).
-			Padding(1, 2).
-			Align(lipgloss.Center)
+		Foreground(primaryColor).
+		Bold(true).
+		Padding(1, 2).
+		Align(lipgloss.Center)
 
 	subtitleStyle = lipgloss.NewStyle().
-			Foreground(secondaryColor).
-			Italic(true).
-			Align(lipgloss.Center)
+		Foreground(secondaryColor).
+		Italic(true).
+		Align(lipgloss.Center)
 
 	boxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(primaryColor).
-			Padding(1, 2).
-			Margin(1, 0)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1, 2).
+		Margin(1, 0)
 
 	inputBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.DoubleBorder()).
-			BorderForeground(accentColor).
-			Padding(0, 1).
-			Width(30)
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(accentColor).
+		Padding(0, 1).
+		Width(30)
 
 	highlightStyle = lipgloss.NewStyle().
-			Foreground(successColor).
-			Bold(true)
+		Foreground(successColor).
+		Bold(true)
 
 	mutedStyle = lipgloss.NewStyle().
-			Foreground(mutedColor)
+		Foreground(mutedColor)
 
 	instructionStyle = lipgloss.NewStyle().
-				Foreground(mutedColor).
-				Italic(true).
-				Margin(1, 0)
+		Foreground(mutedColor).
+		Italic(true).
+		Margin(1, 0)
 
 	avatarBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(secondaryColor).
-			Padding(1, 3).
-			Align(lipgloss.Center)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(secondaryColor).
+		Padding(1, 3).
+		Align(lipgloss.Center)
 
 	optionStyle = lipgloss.NewStyle().
-			Foreground(fgColor).
-			Padding(0, 1)
+		Foreground(fgColor).
+		Padding(0, 1)
 
 	selectedOptionStyle = lipgloss.NewStyle().
-				Foreground(successColor).
-				Bold(true).
-				Padding(0, 1)
+		Foreground(successColor).
+		Bold(true).
+		Padding(0, 1)
 
 	cursorStyle = lipgloss.NewStyle().
-			Foreground(primaryColor).
-			Bold(true)
+		Foreground(primaryColor).
+		Bold(true)
 
 	gameBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(primaryColor).
-			Padding(1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(1)
 
 	chatBoxStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(accentColor).
-			Padding(1)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(accentColor).
+		Padding(1)
 
 	centerStyle = lipgloss.NewStyle().
-			Align(lipgloss.Center).
-			Foreground(mutedColor).
-			Italic(true)
+		Align(lipgloss.Center).
+		Foreground(mutedColor).
+		Italic(true)
 
 	spinnerStyle = lipgloss.NewStyle().
-			Foreground(successColor).
-			Bold(true)
+		Foreground(successColor).
+		Bold(true)
 
 	errorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#E07B7B")).
-			Bold(true)
-)
+		Foreground(errorColor).
+		Bold(true)
+}
@@ -0,0 +1,34 @@
+package gamemap
+
+import (
+	_ "embed"
+	"sync"
+)
+
+//go:embed map.txt
+var mapText string
+
+var (
+	tiles     [Height][Width]string
+	tilesErr  error
+	tilesOnce sync.Once
+)
+
+// AvatarReach is how far an avatar's 3x3 footprint reaches from its center, so two
+// players overlap when their centers are this close
+const AvatarReach = 2
+
+// Text returns the Morgridge Hall map file the server, client and bots all build the
+// building from
+func Text() string {
+	return mapText
+}
+
+// Tiles returns the map annotated by FillRoomMap, built the first time it's asked for.
+// It's shared, so it must never be written to.
+func Tiles() (*[Height][Width]string, error) {
+	tilesOnce.Do(func() {
+		tiles, tilesErr = FillRoomMap(mapText)
+	})
+	return &tiles, tilesErr
+}
@@ -0,0 +1,252 @@
+// Package gamemap holds the Morgridge Hall map file and turns it into per-tile
+// annotations shared by the client (rendering), the server (room membership) and bots
+// (collisions).
+package gamemap
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Map dimensions in tiles
+const (
+	Height = 250
+	Width  = 400
+)
+
+// RoomCoord is a named room and a point inside it
+type RoomCoord struct {
+	X    int
+	Y    int
+	Name string
+}
+
+// RoomCoordinates - each is a starting point for flood fill
+var RoomCoordinates = []RoomCoord{
+	{X: 55, Y: 16, Name: "1"},
+	{X: 60, Y: 25, Name: "2"},
+	{X: 64, Y: 35, Name: "3"},
+	{X: 98, Y: 35, Name: "4"},
+	{X: 120, Y: 34, Name: "5"},
+	{X: 144, Y: 36, Name: "6"},
+	{X: 164, Y: 36, Name: "7"},
+	{X: 178, Y: 36, Name: "8"},
+	{X: 190, Y: 36, Name: "9"},
+	{X: 205, Y: 36, Name: "10"},
+	{X: 226, Y: 36, Name: "11"},
+	{X: 260, Y: 36, Name: "12"},
+	{X: 47, Y: 51, Name: "13"},
+	{X: 61, Y: 66, Name: "14"},
+	{X: 48, Y: 87, Name: "15"},
+	{X: 16, Y: 120, Name: "16"},
+	{X: 41, Y: 145, Name: "17"},
+	{X: 38, Y: 164, Name: "18"},
+	{X: 17, Y: 181, Name: "19"},
+	{X: 31, Y: 181, Name: "20"},
+	{X: 51, Y: 181, Name: "21"},
+	{X: 56, Y: 223, Name: "22"},
+	{X: 96, Y: 218, Name: "23"},
+}
+
+// FillRoomMap fills the room map with string annotations.
+// Returns map characters as keys ('r', 'o', 'i', 'e'), "-1" for spaces not in rooms, room number strings ("1", "2", ...) for spaces in rooms.
+// Rooms are defined by four walls ('r' or 'e' characters), and adjacent rooms are separated by 'r'/'e' walls.
+func FillRoomMap(mapText string) ([Height][Width]string, error) {
+	lines := strings.Split(mapText, "\n")
+	var result [Height][Width]string
+	var mapChars [Height][Width]rune
+
+	// Initialize all cells and read map characters
+	for i, line := range lines {
+		if i >= 250 {
+			break
+		}
+		line = strings.TrimRight(line, " \t\r")
+
+		for j := range result[i] {
+			result[i][j] = "" // Uninitialized marker
+			if j < len(line) {
+				mapChars[i][j] = rune(line[j])
+			} else {
+				mapChars[i][j] = ' '
+			}
+		}
+	}
+
+	// Copy all non-space characters (walls, inaccessible areas, furniture, etc.)
+	for i := 0; i < 250; i++ {
+		for j := 0; j < 400; j++ {
+			char := mapChars[i][j]
+			// Mark wall characters and furniture as themselves
+			if char == 'r' || char == 'o' || char == 'i' || char == 'e' || char == 'b' || char == 'B' || char == 'T' || char == 't' || char == 'p' || char == 'W' || char == '@' || char == 'c' {
+				result[i][j] = string(char)
+			}
+			// Leave spaces as "" for now - they'll be filled by flood fill
+		}
+	}
+
+	// Step 1: Mark outside spaces (not enclosed by walls) as "-1"
+	// Start from top-left corner (0,0) which should be outside any rooms
+	markOutsideSpaces(&result, &mapChars, 0, 0)
+
+	// Step 2: Flood fill each room using the predefined room coordinates
+	for _, room := range RoomCoordinates {
+		// Check if this coordinate is valid and unmarked
+		if room.Y >= 0 && room.Y < 250 && room.X >= 0 && room.X < 400 {
+			if result[room.Y][room.X] == "" && mapChars[room.Y][room.X] == ' ' {
+				// Flood fill this room with its name
+				floodFillRoom(&result, &mapChars, room.Y, room.X, room.Name)
+			}
+		}
+	}
+
+	// Step 3: Mark any remaining unmarked spaces as hallways ("-1")
+	// These are spaces that aren't in defined rooms
+	for i := 0; i < 250; i++ {
+		for j := 0; j < 400; j++ {
+			if result[i][j] == "" && mapChars[i][j] == ' ' {
+				result[i][j] = "-1" // Hallway
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// RoomNumberAt returns the room number at (x, y) in a filled map, or "" for hallways and walls
+func RoomNumberAt(roomMap *[Height][Width]string, x, y int) string {
+	if y < 0 || y >= Height || x < 0 || x >= Width {
+		return ""
+	}
+	value := roomMap[y][x]
+	if _, err := strconv.Atoi(value); err == nil && value != "-1" {
+		return value
+	}
+	return ""
+}
+
+// Walkable reports whether players can stand on a tile. It takes either a raw map
+// character (" ", "e", "@") or a filled map annotation ("-1" or a room number), so the
+// server and client share one rule whichever map they hold.
+func Walkable(tile string) bool {
+	switch tile {
+	case " ", "e", "-1", "@":
+		return true
+	}
+	_, err := strconv.Atoi(tile)
+	return err == nil
+}
+
+// AvatarFits reports whether a 3x3 avatar centered on (x, y) stands only on walkable tiles
+func AvatarFits(tiles *[Height][Width]string, x, y int) bool {
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			tx, ty := x+dx, y+dy
+			if ty < 0 || ty >= Height || tx < 0 || tx >= Width || !Walkable(tiles[ty][tx]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// markOutsideSpaces marks spaces outside 'r'/'e' boundaries as "-1" using flood fill
+// Only 'r' and 'e' characters block the flood fill - 'o' and 'i' don't block it
+// This ensures that only spaces enclosed by 'r'/'e' boundaries are considered rooms
+func markOutsideSpaces(result *[250][400]string, mapChars *[250][400]rune, startY, startX int) {
+	type point struct {
+		y, x int
+	}
+	stack := []point{{startY, startX}}
+
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		// Check bounds
+		if p.y < 0 || p.y >= 250 || p.x < 0 || p.x >= 400 {
+			continue
+		}
+
+		// Skip if already marked
+		if result[p.y][p.x] == "-1" {
+			continue
+		}
+
+		// Skip if already set (preserves 'b', 'B', 'T', 't', 'W', etc.)
+		if result[p.y][p.x] != "" {
+			continue
+		}
+
+		// All wall characters ('r', 'e', 'o', 'i') block the flood fill
+		// This preserves wall colors and prevents flood fill from marking them as hallways
+		if mapChars[p.y][p.x] == 'r' || mapChars[p.y][p.x] == 'e' ||
+			mapChars[p.y][p.x] == 'o' || mapChars[p.y][p.x] == 'i' {
+			// This is a wall character - don't mark it as outside, don't continue flood fill
+			continue
+		}
+
+		// Mark as outside (not enclosed by wall boundaries)
+		result[p.y][p.x] = "-1"
+
+		// Add neighbors to stack (only if not wall characters)
+		if p.y > 0 && mapChars[p.y-1][p.x] != 'r' && mapChars[p.y-1][p.x] != 'e' &&
+			mapChars[p.y-1][p.x] != 'o' && mapChars[p.y-1][p.x] != 'i' {
+			stack = append(stack, point{p.y - 1, p.x}) // up
+		}
+		if p.y < 249 && mapChars[p.y+1][p.x] != 'r' && mapChars[p.y+1][p.x] != 'e' &&
+			mapChars[p.y+1][p.x] != 'o' && mapChars[p.y+1][p.x] != 'i' {
+			stack = append(stack, point{p.y + 1, p.x}) // down
+		}
+		if p.x > 0 && mapChars[p.y][p.x-1] != 'r' && mapChars[p.y][p.x-1] != 'e' &&
+			mapChars[p.y][p.x-1] != 'o' && mapChars[p.y][p.x-1] != 'i' {
+			stack = append(stack, point{p.y, p.x - 1}) // left
+		}
+		if p.x < 399 && mapChars[p.y][p.x+1] != 'r' && mapChars[p.y][p.x+1] != 'e' &&
+			mapChars[p.y][p.x+1] != 'o' && mapChars[p.y][p.x+1] != 'i' {
+			stack = append(stack, point{p.y, p.x + 1}) // right
+		}
+	}
+}
+
+// floodFillRoom assigns a room number to all connected spaces starting from (startY, startX)
+// This ensures all spaces in the same enclosed region get the same room number
+func floodFillRoom(result *[250][400]string, mapChars *[250][400]rune, startY, startX int, roomNumStr string) {
+	type point struct {
+		y, x int
+	}
+	stack := []point{{startY, startX}}
+
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		// Check bounds
+		if p.y < 0 || p.y >= 250 || p.x < 0 || p.x >= 400 {
+			continue
+		}
+
+		// Skip if already marked (wall, outside, or already in a room)
+		// Also skip 'e' characters - they act like 'r' for boundaries but are marked as 'e'
+		if result[p.y][p.x] != "" || mapChars[p.y][p.x] == 'e' {
+			continue
+		}
+
+		// Assign room number
+		result[p.y][p.x] = roomNumStr
+
+		// Add neighbors to stack (only unvisited spaces, not walls or 'e' characters)
+		if p.y > 0 && result[p.y-1][p.x] == "" && mapChars[p.y-1][p.x] != 'e' {
+			stack = append(stack, point{p.y - 1, p.x}) // up
+		}
+		if p.y < 249 && result[p.y+1][p.x] == "" && mapChars[p.y+1][p.x] != 'e' {
+			stack = append(stack, point{p.y + 1, p.x}) // down
+		}
+		if p.x > 0 && result[p.y][p.x-1] == "" && mapChars[p.y][p.x-1] != 'e' {
+			stack = append(stack, point{p.y, p.x - 1}) // left
+		}
+		if p.x < 399 && result[p.y][p.x+1] == "" && mapChars[p.y][p.x+1] != 'e' {
+			stack = append(stack, point{p.y, p.x + 1}) // right
+		}
+	}
+}
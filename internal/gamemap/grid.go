@@ -0,0 +1,145 @@
+package gamemap
+
+import "strconv"
+
+// Tile is what a map tile is, one byte per tile where FillRoomMap uses a string
+type Tile byte
+
+// Tile kinds. TileRoomFloor is any room's floor; Grid.Rooms says which room it's in.
+const (
+	TileUnknown      Tile = iota // A map character that means nothing in particular
+	TileHallway                  // Open floor outside the rooms ("-1")
+	TileRoomFloor                // Open floor inside a numbered room
+	TileRoomWall                 // 'r'
+	TileOuterWall                // 'o'
+	TileInaccessible             // 'i'
+	TileEntrance                 // 'e'
+	TileOutside                  // 'b'
+	TileBlocked                  // 'B', inaccessible areas inside the building
+	TileTelevision               // 'T'
+	TileTable                    // 't'
+	TilePlant                    // 'p'
+	TileWhiteboard               // 'W'
+	TileDarkFloor                // '@'
+	TileCouch                    // 'c'
+
+	NumTiles // Number of tile kinds, for tables indexed by Tile
+)
+
+// tileChars maps the map characters FillRoomMap keeps to their tiles
+var tileChars = map[string]Tile{
+	"r": TileRoomWall,
+	"o": TileOuterWall,
+	"i": TileInaccessible,
+	"e": TileEntrance,
+	"b": TileOutside,
+	"B": TileBlocked,
+	"T": TileTelevision,
+	"t": TileTable,
+	"p": TilePlant,
+	"W": TileWhiteboard,
+	"@": TileDarkFloor,
+	"c": TileCouch,
+}
+
+// Walkable reports whether players can stand on the tile, the same rule as the
+// package-level Walkable
+func (t Tile) Walkable() bool {
+	switch t {
+	case TileHallway, TileRoomFloor, TileEntrance, TileDarkFloor:
+		return true
+	}
+	return false
+}
+
+// String returns the map character the tile comes from: "-1" for the hallway, and
+// "room" for a room's floor, whose number is Grid.RoomNumberAt
+func (t Tile) String() string {
+	switch t {
+	case TileHallway:
+		return "-1"
+	case TileRoomFloor:
+		return "room"
+	}
+	for char, tile := range tileChars {
+		if tile == t {
+			return char
+		}
+	}
+	return "?"
+}
+
+// Grid is a filled map kept as bytes: the tile kinds, and the room number of every
+// room floor tile. It's about 200KB where the string map is over 1.5MB.
+type Grid struct {
+	Tiles [Height][Width]Tile
+	Rooms [Height][Width]uint8 // Room number, 0 outside the rooms
+}
+
+// FillGrid fills the map like FillRoomMap and packs it into a Grid
+func FillGrid(mapText string) (*Grid, error) {
+	filled, err := FillRoomMap(mapText)
+	if err != nil {
+		return nil, err
+	}
+	return NewGrid(&filled), nil
+}
+
+// NewGrid packs a map filled by FillRoomMap into a Grid
+func NewGrid(filled *[Height][Width]string) *Grid {
+	grid := new(Grid)
+	for y := range filled {
+		for x, value := range filled[y] {
+			if tile, ok := tileChars[value]; ok {
+				grid.Tiles[y][x] = tile
+				continue
+			}
+			if value == "-1" {
+				grid.Tiles[y][x] = TileHallway
+				continue
+			}
+			if room, err := strconv.ParseUint(value, 10, 8); err == nil && room > 0 {
+				grid.Tiles[y][x] = TileRoomFloor
+				grid.Rooms[y][x] = uint8(room)
+			}
+		}
+	}
+	return grid
+}
+
+// At returns the tile at (x, y), or TileUnknown off the map
+func (g *Grid) At(x, y int) Tile {
+	if y < 0 || y >= Height || x < 0 || x >= Width {
+		return TileUnknown
+	}
+	return g.Tiles[y][x]
+}
+
+// RoomAt returns the room number at (x, y), or 0 for hallways, walls and off the map
+func (g *Grid) RoomAt(x, y int) uint8 {
+	if y < 0 || y >= Height || x < 0 || x >= Width {
+		return 0
+	}
+	return g.Rooms[y][x]
+}
+
+// RoomNumberAt returns the room number at (x, y) like the package-level RoomNumberAt,
+// or "" for hallways and walls
+func (g *Grid) RoomNumberAt(x, y int) string {
+	if room := g.RoomAt(x, y); room != 0 {
+		return strconv.Itoa(int(room))
+	}
+	return ""
+}
+
+// AvatarFits reports whether a 3x3 avatar centered on (x, y) stands only on walkable tiles
+func (g *Grid) AvatarFits(x, y int) bool {
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if !g.At(x+dx, y+dy).Walkable() {
+				return false
+			}
+		}
+	}
+	return true
+}
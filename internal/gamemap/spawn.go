@@ -0,0 +1,35 @@
+package gamemap
+
+import "fmt"
+
+// SpawnZone is an area of the map where players can appear when they join
+type SpawnZone struct {
+	Name string
+	X, Y int // Top-left tile
+	W, H int
+}
+
+// SpawnZones are the open areas new players are placed in, away from furniture and doors
+var SpawnZones = []SpawnZone{
+	{Name: "Atrium", X: 115, Y: 55, W: 25, H: 10},
+	{Name: "Atrium west", X: 78, Y: 50, W: 30, H: 12},
+	{Name: "Front hallway", X: 75, Y: 39, W: 150, H: 2},
+}
+
+// Center returns the tile in the middle of the zone
+func (z SpawnZone) Center() (x, y int) {
+	return z.X + z.W/2, z.Y + z.H/2
+}
+
+// ValidateSpawnZones checks that every zone is on the map and has room for an avatar
+func ValidateSpawnZones(tiles *[Height][Width]string) error {
+	for _, zone := range SpawnZones {
+		if zone.W <= 0 || zone.H <= 0 || zone.X < 0 || zone.Y < 0 || zone.X+zone.W > Width || zone.Y+zone.H > Height {
+			return fmt.Errorf("spawn zone %q is off the map", zone.Name)
+		}
+		if x, y := zone.Center(); !AvatarFits(tiles, x, y) {
+			return fmt.Errorf("spawn zone %q is blocked in the middle", zone.Name)
+		}
+	}
+	return nil
+}
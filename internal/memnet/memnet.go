@@ -0,0 +1,76 @@
+// Package memnet is an in-process network for running the server and clients together
+// without sockets, e.g. in end-to-end tests. Connections are net.Pipe pairs.
+//
+//	l := memnet.Listen()
+//	go http.Serve(l, srv.Handler())
+//	mgr := connection.NewManager("ws://memnet/ws")
+//	mgr.SetDialer(l.DialContext)
+package memnet
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+)
+
+// Listener is a net.Listener whose connections come from its own DialContext
+type Listener struct {
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+// addr is the address of every memnet listener
+type addr struct{}
+
+func (addr) Network() string { return "memnet" }
+func (addr) String() string  { return "memnet" }
+
+// Listen returns a listener nothing is connected to yet
+func Listen() *Listener {
+	return &Listener{
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+}
+
+// Accept waits for the next connection dialed to the listener
+func (l *Listener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close stops the listener; connections already made stay open
+func (l *Listener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return nil
+}
+
+// Addr returns the listener's address, which is the same for every listener
+func (l *Listener) Addr() net.Addr {
+	return addr{}
+}
+
+// DialContext connects to the listener, whatever network and address are asked for, and
+// waits until it accepts the connection. It has the signature of net.Dialer's, so it can
+// stand in for it.
+func (l *Listener) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	client, server := net.Pipe()
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.done:
+		client.Close()
+		server.Close()
+		return nil, errors.New("memnet: listener closed")
+	case <-ctx.Done():
+		client.Close()
+		server.Close()
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,175 @@
+package memnet_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/client/connection"
+	"github.com/yourusername/always-at-morg/internal/gamemap"
+	"github.com/yourusername/always-at-morg/internal/memnet"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+	"github.com/yourusername/always-at-morg/internal/server"
+)
+
+var (
+	serverOnce sync.Once
+	listener   *memnet.Listener
+	runs       atomic.Int64 // Tells apart the players of repeated runs on the one server
+)
+
+// listen starts the server the tests share and returns the listener to dial it on.
+// NewServer sets process-wide options such as the riddle provider, so a process runs
+// only one.
+func listen() *memnet.Listener {
+	serverOnce.Do(func() {
+		cfg := server.DefaultConfig()
+		cfg.DataDir = ""
+		cfg.RecordDir = ""
+		cfg.LLM = server.LLMConfig{Provider: "static"}
+		srv := server.NewServer(cfg)
+
+		listener = memnet.Listen()
+		go http.Serve(listener, srv.Handler())
+	})
+	return listener
+}
+
+// player is a client connected to the test server, keeping the global chat it was sent
+type player struct {
+	name string
+	mgr  *connection.Manager
+
+	mu   sync.Mutex
+	chat []connection.ChatMessage
+}
+
+// join connects a new player over l and puts them in the main room
+func join(t *testing.T, ctx context.Context, l *memnet.Listener, name string) *player {
+	t.Helper()
+	p := &player{name: name, mgr: connection.NewManager("ws://memnet/ws")}
+	p.mgr.SetDialer(l.DialContext)
+	p.mgr.Subscribe(func(event connection.Event) {
+		switch e := event.(type) {
+		case connection.OnboardRequestEvent:
+			p.mgr.SendOnboardResponse(name, []int{0, 0, 0})
+		case connection.GlobalChatMessagesEvent:
+			p.mu.Lock()
+			p.chat = e.Messages
+			p.mu.Unlock()
+		}
+	})
+	if err := p.mgr.Connect(ctx); err != nil {
+		t.Fatalf("%s connecting: %v", name, err)
+	}
+	t.Cleanup(p.mgr.Disconnect)
+	if err := p.mgr.JoinRoom(protocol.DefaultRoomID, name, ""); err != nil {
+		t.Fatalf("%s joining: %v", name, err)
+	}
+	waitFor(t, name+" in the room", func() bool {
+		_, ok := p.position(name)
+		return ok
+	})
+	return p
+}
+
+// position returns where the player's state puts username
+func (p *player) position(username string) (string, bool) {
+	state := p.mgr.GetState()
+	if state == nil {
+		return "", false
+	}
+	player, ok := state.Players[username]
+	return player.Pos, ok
+}
+
+// saw reports whether the player has been sent message in global chat from username
+func (p *player) saw(username, message string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, msg := range p.chat {
+		if msg.Username == username && msg.Message == message {
+			return true
+		}
+	}
+	return false
+}
+
+func waitFor(t *testing.T, what string, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// step finds a tile next to x, y that the avatar fits on and that takes it no closer to
+// other, so the server lets the move through
+func step(t *testing.T, x, y, ox, oy int) (int, int) {
+	t.Helper()
+	tiles, err := gamemap.Tiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dist := func(x, y int) int { return max(abs(x-ox), abs(y-oy)) }
+	for _, d := range [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		nx, ny := x+d[0], y+d[1]
+		if gamemap.AvatarFits(tiles, nx, ny) && dist(nx, ny) >= dist(x, y) {
+			return nx, ny
+		}
+	}
+	t.Fatalf("nowhere to step from %d,%d", x, y)
+	return 0, 0
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func TestJoinMoveChat(t *testing.T) {
+	l := listen()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	run := runs.Add(1)
+	alice := join(t, ctx, l, fmt.Sprintf("alice%d", run))
+	bob := join(t, ctx, l, fmt.Sprintf("bob%d", run))
+
+	// Bob sees Alice's step, which is taken knowing where he is
+	waitFor(t, "alice and bob to see each other", func() bool {
+		_, bobSees := bob.position(alice.name)
+		_, aliceSees := alice.position(bob.name)
+		return bobSees && aliceSees
+	})
+	var x, y, ox, oy int
+	pos, _ := alice.position(alice.name)
+	fmt.Sscanf(pos, "%d:%d", &y, &x)
+	other, _ := alice.position(bob.name)
+	fmt.Sscanf(other, "%d:%d", &oy, &ox)
+	nx, ny := step(t, x, y, ox, oy)
+	if err := alice.mgr.SendPlayerMove(alice.name, nx, ny); err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("%d:%d", ny, nx)
+	waitFor(t, "alice's move to reach bob", func() bool {
+		pos, _ := bob.position(alice.name)
+		return pos == want
+	})
+
+	// And her message
+	if err := alice.mgr.SendGlobalChat(alice.name, "hello from memnet"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "alice's message to reach bob", func() bool {
+		return bob.saw(alice.name, "hello from memnet")
+	})
+}
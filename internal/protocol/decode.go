@@ -0,0 +1,212 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Limits DecodeUntrusted holds messages to
+const (
+	MaxJSONDepth      = 8    // Deepest nesting of objects and arrays in a message
+	DefaultMaxPayload = 512  // Largest payload, in bytes, of a type not in maxPayload
+	maxChatPayload    = 3584 // Room for the longest chat message even if every character is escaped
+	MapWidth          = 400  // Tiles across the map, the bound on x
+	MapHeight         = 250  // Tiles down the map, the bound on y
+	maxRoomCapacity   = 1000 // Most players a room owner can let in
+)
+
+// maxPayload is the largest payload, in bytes, of each type a client sends that can be
+// bigger than DefaultMaxPayload
+var maxPayload = map[MessageType]int{
+	MsgGlobalChat:        maxChatPayload,
+	MsgRoomChat:          maxChatPayload,
+	MsgChatMessage:       maxChatPayload,
+	MsgTreasureHuntGuess: 1024,
+}
+
+// clientPayloads makes an empty payload for each type a client may send, for
+// DecodeUntrusted to check the payload against
+var clientPayloads = map[MessageType]func() any{
+	MsgJoinRoom:                   func() any { return new(JoinRoomPayload) },
+	MsgLeaveRoom:                  func() any { return new(struct{}) },
+	MsgPlayerMove:                 func() any { return new(PlayerMovePayload) },
+	MsgOnboard:                    func() any { return new(OnboardPayload) },
+	MsgChatMessage:                func() any { return new(ChatMessagePayload) },
+	MsgGlobalChat:                 func() any { return new(GlobalChatPayload) },
+	MsgRoomChat:                   func() any { return new(RoomChatPayload) },
+	MsgTreasureHuntGuess:          func() any { return new(TreasureHuntGuessPayload) },
+	MsgInteract:                   func() any { return new(struct{}) },
+	MsgMiniGameChallenge:          func() any { return new(MiniGameChallengePayload) },
+	MsgMiniGameRespond:            func() any { return new(MiniGameRespondPayload) },
+	MsgMiniGameMove:               func() any { return new(MiniGameMovePayload) },
+	MsgPomodoro:                   func() any { return new(PomodoroPayload) },
+	MsgSetStatus:                  func() any { return new(SetStatusPayload) },
+	MsgGameMode:                   func() any { return new(GameModePayload) },
+	MsgScavenger:                  func() any { return new(ScavengerPayload) },
+	MsgLeaderboardRequest:         func() any { return new(struct{}) },
+	MsgShopRequest:                func() any { return new(struct{}) },
+	MsgShopBuy:                    func() any { return new(ShopItemPayload) },
+	MsgShopEquip:                  func() any { return new(ShopItemPayload) },
+	MsgTreasureHuntHistoryRequest: func() any { return new(struct{}) },
+	MsgAnnouncementHistoryRequest: func() any { return new(struct{}) },
+	MsgChatHistoryRequest:         func() any { return new(struct{}) },
+	MsgListRooms:                  func() any { return new(struct{}) },
+	MsgRoomModerate:               func() any { return new(RoomModeratePayload) },
+	MsgPing:                       func() any { return new(PingPayload) },
+}
+
+// DecodeError says why DecodeUntrusted turned a message down
+type DecodeError struct {
+	Type   MessageType // Empty if the message didn't get as far as having one
+	Reason string
+}
+
+func (e *DecodeError) Error() string {
+	if e.Type == "" {
+		return "bad message: " + e.Reason
+	}
+	return fmt.Sprintf("bad %s message: %s", e.Type, e.Reason)
+}
+
+// bounded is a payload with numbers that have to be in range
+type bounded interface {
+	checkBounds() error
+}
+
+// MaxPayload returns the largest payload, in bytes, a client may send with a message type
+func MaxPayload(msgType MessageType) int {
+	if max, ok := maxPayload[msgType]; ok {
+		return max
+	}
+	return DefaultMaxPayload
+}
+
+// DecodeUntrusted decodes a message from a client more carefully than DecodeMessage: it
+// must be nested no deeper than MaxJSONDepth, be of a type clients send, have a payload
+// no bigger than MaxPayload that decodes into that type's payload, and keep the
+// payload's numbers in range. With strict set, fields the message or its payload
+// doesn't have are refused too, which older clients may trip over.
+func DecodeUntrusted(data []byte, strict bool) (*Message, error) {
+	if err := checkDepth(data, MaxJSONDepth); err != nil {
+		return nil, &DecodeError{Reason: err.Error()}
+	}
+
+	var msg Message
+	if err := unmarshal(data, &msg, strict); err != nil {
+		return nil, &DecodeError{Reason: err.Error()}
+	}
+	newPayload, ok := clientPayloads[msg.Type]
+	if !ok {
+		return nil, &DecodeError{Type: msg.Type, Reason: "not a message clients send"}
+	}
+	if max := MaxPayload(msg.Type); len(msg.Payload) > max {
+		return nil, &DecodeError{Type: msg.Type, Reason: fmt.Sprintf("payload is %d bytes, the most is %d", len(msg.Payload), max)}
+	}
+
+	payload := newPayload()
+	if len(msg.Payload) > 0 {
+		if err := unmarshal(msg.Payload, payload, strict); err != nil {
+			return nil, &DecodeError{Type: msg.Type, Reason: err.Error()}
+		}
+	}
+	if b, ok := payload.(bounded); ok {
+		if err := b.checkBounds(); err != nil {
+			return nil, &DecodeError{Type: msg.Type, Reason: err.Error()}
+		}
+	}
+	return &msg, nil
+}
+
+// unmarshal decodes exactly one JSON value from data into v, refusing unknown fields if
+// strict is set
+func unmarshal(data []byte, v any, strict bool) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("data after the message")
+	}
+	return nil
+}
+
+// checkDepth makes sure JSON doesn't nest objects and arrays deeper than max, before
+// anything is decoded. Brackets inside strings don't count.
+func checkDepth(data []byte, max int) error {
+	depth := 0
+	inString, escaped := false, false
+	for _, b := range data {
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			switch b {
+			case '\\':
+				escaped = true
+			case '"':
+				inString = false
+			}
+		case b == '"':
+			inString = true
+		case b == '{' || b == '[':
+			depth++
+			if depth > max {
+				return fmt.Errorf("nested deeper than %d", max)
+			}
+		case b == '}' || b == ']':
+			depth--
+		}
+	}
+	return nil
+}
+
+func (p *PlayerMovePayload) checkBounds() error {
+	if p.NewX < 0 || p.NewX >= MapWidth || p.NewY < 0 || p.NewY >= MapHeight {
+		return fmt.Errorf("position %d,%d is off the map", p.NewX, p.NewY)
+	}
+	return nil
+}
+
+func (p *OnboardPayload) checkBounds() error {
+	if len(p.Avatar) > AvatarParts {
+		return fmt.Errorf("an avatar has %d parts", AvatarParts)
+	}
+	for _, part := range p.Avatar {
+		if part < 0 || part >= AvatarOptions {
+			return fmt.Errorf("avatar parts must be 0-%d", AvatarOptions-1)
+		}
+	}
+	return nil
+}
+
+func (p *RoomModeratePayload) checkBounds() error {
+	if p.Capacity < 0 || p.Capacity > maxRoomCapacity {
+		return fmt.Errorf("capacity must be 0-%d", maxRoomCapacity)
+	}
+	return nil
+}
+
+func (p *ChatMessagePayload) checkBounds() error  { return checkTimestamp(p.Timestamp) }
+func (p *GlobalChatPayload) checkBounds() error   { return checkTimestamp(p.Timestamp) }
+func (p *RoomChatPayload) checkBounds() error     { return checkTimestamp(p.Timestamp) }
+func (p *AnnouncementPayload) checkBounds() error { return checkTimestamp(p.Timestamp) }
+
+func (p *PingPayload) checkBounds() error {
+	if p.SentAt < 0 {
+		return errors.New("sent_at can't be negative")
+	}
+	return nil
+}
+
+func checkTimestamp(t int64) error {
+	if t < 0 {
+		return errors.New("timestamp can't be negative")
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package protocol
+
+import "testing"
+
+// FuzzDecodeUntrusted starts from one valid message of every type a client sends and
+// checks that whatever DecodeUntrusted accepts is still one of them, within its limits
+func FuzzDecodeUntrusted(f *testing.F) {
+	for msgType, newPayload := range clientPayloads {
+		data, err := EncodeMessage(msgType, newPayload())
+		if err != nil {
+			f.Fatalf("encoding %s: %v", msgType, err)
+		}
+		for _, strict := range []bool{false, true} {
+			if _, err := DecodeUntrusted(data, strict); err != nil {
+				f.Fatalf("valid %s message refused: %v", msgType, err)
+			}
+		}
+		f.Add(data, false)
+		f.Add(data, true)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, strict bool) {
+		msg, err := DecodeUntrusted(data, strict)
+		if err != nil {
+			if msg != nil {
+				t.Errorf("returned a message along with %v", err)
+			}
+			return
+		}
+		if _, ok := clientPayloads[msg.Type]; !ok {
+			t.Errorf("accepted %q, which clients don't send", msg.Type)
+		}
+		if len(msg.Payload) > MaxPayload(msg.Type) {
+			t.Errorf("accepted a %d byte %s payload, the most is %d", len(msg.Payload), msg.Type, MaxPayload(msg.Type))
+		}
+		if err := checkDepth(data, MaxJSONDepth); err != nil {
+			t.Errorf("accepted a message %v", err)
+		}
+	})
+}
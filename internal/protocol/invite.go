@@ -0,0 +1,39 @@
+package protocol
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// InvitePrefix starts every invite link
+const InvitePrefix = "morg://join/"
+
+// Invite drops a friend straight into a room: the server to connect to, the room and
+// its password if it's private
+type Invite struct {
+	Server   string `json:"s"`
+	Room     string `json:"r"`
+	Password string `json:"p,omitempty"`
+}
+
+// Link encodes the invite as a morg://join/CODE link
+func (i Invite) Link() string {
+	raw, _ := json.Marshal(i)
+	return InvitePrefix + base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// ParseInvite decodes an invite link, or just its code
+func ParseInvite(link string) (Invite, error) {
+	code := strings.TrimPrefix(strings.TrimSpace(link), InvitePrefix)
+	raw, err := base64.RawURLEncoding.DecodeString(code)
+	if err != nil {
+		return Invite{}, errors.New("not a valid invite code")
+	}
+	var invite Invite
+	if err := json.Unmarshal(raw, &invite); err != nil || invite.Server == "" || invite.Room == "" {
+		return Invite{}, errors.New("not a valid invite code")
+	}
+	return invite, nil
+}
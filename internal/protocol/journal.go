@@ -0,0 +1,55 @@
+package protocol
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+// JournalExt is the extension of session journal files
+const JournalExt = ".morg"
+
+// Which way a journal entry's message went
+const (
+	JournalIn  = "in"  // Sent by a player to the server
+	JournalOut = "out" // Sent by the server
+)
+
+// JournalEntry is one message in a session journal. A journal is a gzipped file of JSON
+// entries, one per line, recorded by the server for a room (see POST /admin/record). Its
+// first entry is a room_joined message with the room as it was when recording started.
+type JournalEntry struct {
+	Time    time.Time       `json:"t"`
+	Dir     string          `json:"d"`           // JournalIn or JournalOut
+	User    string          `json:"u,omitempty"` // Who sent it, or who it went to; empty when it went to the whole room
+	Message json.RawMessage `json:"m"`           // The message exactly as sent
+}
+
+// ReadJournal reads every entry of a session journal
+func ReadJournal(r io.Reader) ([]JournalEntry, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var entries []JournalEntry
+	dec := json.NewDecoder(gz)
+	for {
+		var entry JournalEntry
+		err := dec.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			// A journal cut off by a crash still has everything before the break
+			if len(entries) > 0 && (errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, new(*json.SyntaxError))) {
+				return entries, nil
+			}
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+}
@@ -36,6 +36,96 @@ const (
 	// Treasure Hunt, defined payloads for sending guesses and receiving guesses 
 	MsgTreasureHuntGuess MessageType = "treasure_hunt_guess" //client guess, (Client -> Server)
 	MsgTreasureHuntState MessageType = "treasure_hunt_state"//server update, Server -> Client).
+
+	// Interactive objects (coffee machine, vending machine, ...)
+	MsgInteract       MessageType = "interact"        // Client -> Server: use the object next to me
+	MsgInteractResult MessageType = "interact_result" // Server -> Client: flavor text for the user
+	MsgEmote          MessageType = "emote"           // Server -> Client: broadcast emote ("dhruv made coffee")
+
+	// Mini-games between two players (tic-tac-toe, ...)
+	MsgMiniGameChallenge MessageType = "minigame_challenge" // Client -> Server: challenge a nearby player
+	MsgMiniGameInvite    MessageType = "minigame_invite"    // Server -> Client: someone challenged you
+	MsgMiniGameRespond   MessageType = "minigame_respond"   // Client -> Server: accept or decline an invite
+	MsgMiniGameMove      MessageType = "minigame_move"      // Client -> Server: make a move in a running game
+	MsgMiniGameState     MessageType = "minigame_state"     // Server -> Client: shared board update
+	MsgMiniGameDeclined  MessageType = "minigame_declined"  // Server -> Client: the player you challenged said no
+
+	// Shared pomodoro timer for everyone in a room (state rides on kuluchified_state)
+	MsgPomodoro MessageType = "pomodoro" // Client -> Server: start or stop the timer in my room
+
+	MsgSetStatus MessageType = "set_status" // Client -> Server: set (or clear) my status message
+
+	MsgIdleWarning MessageType = "idle_warning" // Server -> Client: you'll be disconnected for inactivity soon
+
+	// Opt-in room game modes (state rides on kuluchified_state)
+	MsgGameMode MessageType = "game_mode" // Client -> Server: start, join or leave a game mode
+
+	// Location-based scavenger hunt
+	MsgScavenger      MessageType = "scavenger"       // Client -> Server: start a hunt, ask for my clue, or claim it
+	MsgScavengerState MessageType = "scavenger_state" // Server -> Client: my progress and current clue
+
+	// Treasure hunt leaderboard
+	MsgLeaderboardRequest  MessageType = "leaderboard_request"  // Client -> Server: send me the leaderboard
+	MsgLeaderboardResponse MessageType = "leaderboard_response" // Server -> Client: top players
+
+	MsgPoints      MessageType = "points"       // Server -> Client: my points balance changed
+	MsgLoginStreak MessageType = "login_streak" // Server -> Client: my consecutive-day login streak, sent on join
+
+	// Cosmetics shop
+	MsgShopRequest MessageType = "shop_request" // Client -> Server: send me the shop
+	MsgShopBuy     MessageType = "shop_buy"     // Client -> Server: buy an item with points
+	MsgShopEquip   MessageType = "shop_equip"   // Client -> Server: wear (or take off) an owned item
+	MsgShopState   MessageType = "shop_state"   // Server -> Client: catalog, what I own and what I wear
+
+	MsgTreasureHuntWinner MessageType = "treasure_hunt_winner" // Server -> Client: someone solved a riddle, sent to every room
+
+	// Today's past treasure hunt rounds, for players who joined late
+	MsgTreasureHuntHistoryRequest MessageType = "treasure_hunt_history_request" // Client -> Server: send me my room's rounds so far today
+	MsgTreasureHuntHistory        MessageType = "treasure_hunt_history"         // Server -> Client: rounds played today, oldest first
+
+	// Chat posted before the player joined, or that a dropped state never delivered
+	MsgChatHistoryRequest MessageType = "chat_history_request" // Client -> Server: send me global_chat_messages, and room_chat_messages for each building room
+
+	// Server-wide announcements made before the player joined. New ones come as MsgAnnouncement.
+	MsgAnnouncementHistoryRequest MessageType = "announcement_history_request" // Client -> Server: send me the announcements made so far
+	MsgAnnouncementHistory        MessageType = "announcement_history"         // Server -> Client: announcements so far, oldest first
+
+	// Lobby room browser, before joining a room
+	MsgListRooms MessageType = "list_rooms" // Client -> Server: send me the rooms I can join
+	MsgRoomList  MessageType = "room_list"  // Server -> Client: rooms with how many players are in them
+
+	MsgRoomModerate MessageType = "room_moderate" // Client -> Server: an owner or moderator managing my room
+
+	MsgOccupancy MessageType = "occupancy" // Server -> Client: how many players are in each building room, sent when it changes
+
+	// Application-level ping for measuring latency, so players can tell why movement feels slow
+	MsgPing MessageType = "ping" // Client -> Server: echo this back
+	MsgPong MessageType = "pong" // Server -> Client: the ping's payload, unchanged
+)
+
+// Rooms players can join from the lobby
+const (
+	DefaultRoomID         = "default-room" // Always listed, and joined when no room is asked for
+	MaxRoomIDLength       = 24             // Longest room name a player can create
+	MaxRoomPasswordLength = 32             // Longest password a private room can have
+	MaxRoomNameLength     = 32             // Longest name an owner can give their room
+)
+
+// Actions in RoomModeratePayload. Owners can do all of them; moderators can only kick
+// and clear chat.
+const (
+	RoomActionRename    = "rename"     // Name the room Name
+	RoomActionCapacity  = "capacity"   // Take at most Capacity players
+	RoomActionKick      = "kick"       // Disconnect Username and keep them out for a few minutes
+	RoomActionClearChat = "clear_chat" // Empty the chat of every room in the room
+	RoomActionMod       = "mod"        // Make Username a moderator
+	RoomActionUnmod     = "unmod"      // Take away Username's moderator role
+)
+
+// Game modes a room can run
+const (
+	GameModeHideAndSeek = "hideseek"
+	GameModeTag         = "tag"
 )
 
 // Message is the wrapper for all WebSocket messages
@@ -46,15 +136,73 @@ type Message struct {
 
 // JoinRoomPayload is sent when a player wants to join a room
 type JoinRoomPayload struct {
-	Username string `json:"username"` // Always required
-	RoomID   string `json:"room_id"`
+	Username     string `json:"username"` // Always required
+	RoomID       string `json:"room_id"`
+	Password     string `json:"password,omitempty"`      // Needed for private rooms; sets the password when creating one
+	SessionToken string `json:"session_token,omitempty"` // From the last room_joined, when rejoining after a lost connection
+}
+
+// RoomInfo describes a room in the lobby's room browser
+
+type RoomInfo struct {
+	ID          string `json:"id"`              // Sent in JoinRoomPayload to join it
+	Name        string `json:"name"`            // Shown to players
+	Owner       string `json:"owner,omitempty"` // Username of whoever created the room
+	Players     int    `json:"players"`         // Players in the room right now
+	Capacity    int    `json:"capacity"`        // Most players the room takes, 0 if there's no limit
+	HasPassword bool   `json:"has_password"`    // Joining needs the room's password
+	Map         string `json:"map"`
+}
+
+// RoomModeratePayload is sent by a room's owner or a moderator to manage it
+type RoomModeratePayload struct {
+	Action   string `json:"action"`             // One of the RoomAction constants
+	Username string `json:"username,omitempty"` // Player to kick, mod or unmod
+	Name     string `json:"name,omitempty"`     // New name, for rename
+	Capacity int    `json:"capacity,omitempty"` // New capacity, for capacity
+}
+
+// OccupancyPayload says how many players are in each building room
+type OccupancyPayload struct {
+	Rooms map[string]int `json:"rooms"` // Building room number -> players; empty rooms are left out
+}
+
+// RoomListPayload lists the rooms a player can join, busiest first
+type RoomListPayload struct {
+	Rooms []RoomInfo `json:"rooms"`
 }
 
 // RoomJoinedPayload is sent when a player successfully joins a room
 type RoomJoinedPayload struct {
-	RoomID    string     `json:"room_id"`
-	PlayerID  string     `json:"player_id"`
-	GameState *GameState `json:"game_state"`
+	RoomID       string      `json:"room_id"`
+	PlayerID     string      `json:"player_id"`
+	GameState    *GameState  `json:"game_state"`
+	Objects      []MapObject `json:"objects"`       // Interactive objects placed on the map
+	SessionToken string      `json:"session_token"` // Send back in join_room to resume the session after a lost connection
+	Config       RoomConfig  `json:"config"`
+}
+
+// RoomConfig is the room's rules the client follows to move its player before the
+// server has answered
+type RoomConfig struct {
+	PersonalSpace        int `json:"personal_space"`         // Tiles players keep between them inside the building's rooms
+	HallwayPersonalSpace int `json:"hallway_personal_space"` // Tiles players keep between them in hallways
+}
+
+// PersonalSpaceAt returns the personal space radius in a room, or in a hallway
+func (c RoomConfig) PersonalSpaceAt(hallway bool) int {
+	if hallway {
+		return c.HallwayPersonalSpace
+	}
+	return c.PersonalSpace
+}
+
+// MapObject is an interactive object placed on a map tile
+type MapObject struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Pos   string `json:"pos"`   // Format: "Y:X"
+	Glyph string `json:"glyph"` // Single character drawn on the tile
 }
 
 // type Pos struct {
@@ -67,20 +215,47 @@ type GameState struct {
 	Players       map[string]Player `json:"players"`
 	PosToUsername map[string]string `json:"pos_to_username"`
 	Tick          int64             `json:"tick"`
-	Map           [250][400]string     `json:"-"` // Don't send to clients
+	TimeOfDay     string            `json:"time_of_day"` // TimeOfDayDay, TimeOfDayEvening or TimeOfDayNight in Madison
+	NPCs          []NPC             `json:"npcs,omitempty"`
 }
 
+// Phases of the real Madison clock, used by the client to pick the map palette
+const (
+	TimeOfDayDay     = "day"
+	TimeOfDayEvening = "evening"
+	TimeOfDayNight   = "night"
+)
+
 // Player represents a player in the game
 type Player struct {
 	Username string `json:"username"`
 	Pos      string    `json:"pos"`
 	Avatar   []int  `json:"avatar"`
+	Status   string `json:"status,omitempty"`   // Short player-set status ("studying 252", "open to chat")
+	Idle     bool   `json:"idle,omitempty"`     // No input for a while (AFK)
+	MoveSeq  uint64 `json:"move_seq,omitempty"` // Last of the player's moves the server has handled, accepted or not
+	Ghost    bool   `json:"ghost,omitempty"`    // Walks through walls and players; only the ghost sees themselves
+
+	// Cosmetics bought in the shop
+	Accessory string `json:"accessory,omitempty"`  // Glyph drawn above the name
+	NameColor string `json:"name_color,omitempty"` // Hex color of the name
 }
 
-// PlayerMovePayload is sent when a player wants to move
+// NPC is a server-controlled character (janitor, librarian, ...) drawn like a player
+type NPC struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Pos    string `json:"pos"` // "Y:X", same as players
+	Avatar []int  `json:"avatar"`
+}
+
+// PlayerMovePayload is sent when a player wants to move. The client moves its player
+// right away and numbers its moves, so once Player.MoveSeq shows the server has handled
+// one it can tell whether it had to be taken back.
 type PlayerMovePayload struct {
-	NewX int `json:"new_x"`
-	NewY int `json:"new_y"`
+	NewX int    `json:"new_x"`
+	NewY int    `json:"new_y"`
+	Seq  uint64 `json:"seq,omitempty"`
 }
 
 // chat message payload for sending messages between players
@@ -96,6 +271,7 @@ type GlobalChatPayload struct {
 	Username  string `json:"username"`
 	Message   string `json:"message"`
 	Timestamp int64  `json:"timestamp"`
+	Seq       int64  `json:"seq,omitempty"` // Set by the server, counting global chat messages from 1
 }
 
 // room chat message payload for messages sent to room occupants
@@ -104,19 +280,51 @@ type RoomChatPayload struct {
 	Username   string `json:"username"`
 	Message    string `json:"message"`
 	Timestamp  int64  `json:"timestamp"`
+	Seq        int64  `json:"seq,omitempty"` // Set by the server, counting the messages of all the game room's building rooms together from 1
 }
 
 // announcement payload for server-wide messages
 type AnnouncementPayload struct {
 	Message   string `json:"message"`
 	Timestamp int64  `json:"timestamp"`
+	Source    string `json:"source,omitempty"` // AnnouncementAdmin or AnnouncementHunt; empty from older servers
+}
+
+// Where an announcement comes from, so clients can tell them apart
+const (
+	AnnouncementAdmin = "admin" // Made by an admin, to every room
+	AnnouncementHunt  = "hunt"  // The treasure hunt's: new rounds, hints, answers
+)
+
+// AnnouncementHistoryPayload lists the server-wide announcements made so far, oldest first
+type AnnouncementHistoryPayload struct {
+	Announcements []AnnouncementPayload `json:"announcements"`
 }
 
 // ErrorPayload contains error information
 type ErrorPayload struct {
-	Message string `json:"message"`
+	Message    string `json:"message"`
+	Code       string `json:"code,omitempty"`       // One of the ErrCode constants when the client can act on the error
+	Suggestion string `json:"suggestion,omitempty"` // With ErrCodeRoomFull, a room ID to try instead
+	Field      string `json:"field,omitempty"`      // With ErrCodeInvalidMessage, the part of the message that was rejected
 }
 
+// Error codes sent with ErrorPayload
+const (
+	ErrCodeInvalidRoom      = "invalid_room"      // The room name or password isn't allowed
+	ErrCodePasswordRequired = "password_required" // The room is private and no password was given
+	ErrCodeWrongPassword    = "wrong_password"    // The room is private and the password didn't match
+	ErrCodeRoomFull         = "room_full"         // The room is at capacity; Suggestion names another
+	ErrCodeInvalidMessage   = "invalid_message"   // The server rejected the message; Field says which part
+)
+
+// Players and their avatars
+const (
+	MaxUsernameLength = 20 // Longest username, in characters
+	AvatarParts       = 3  // Head, torso and legs
+	AvatarOptions     = 6  // Choices for each part of an avatar
+)
+
 type OnboardPayload struct {
 	Name   string `json:"name"`   // Display name
 	Avatar []int  `json:"avatar"` // Color for now (username already provided in JoinRoom)
@@ -129,9 +337,14 @@ type GlobalChatMessagesPayload struct {
 type RoomChatMessagesPayload struct {
 	RoomNumber string            `json:"room_number"`
 	Messages   []RoomChatPayload `json:"messages"`
+	Latest     int64             `json:"latest"` // Seq of the newest message in any of the game room's building rooms
 }
 
-// KuluchifiedStatePayload is the unified per-tick state update containing everything
+// KuluchifiedStatePayload is the unified per-tick state update containing everything.
+// ChatMessages and RoomChatMessages only have the messages posted since the room's last
+// state; a client that missed some, or joined late, asks for the rest with
+// MsgChatHistoryRequest. Announcements is no longer filled in: each one is sent once, as
+// MsgAnnouncement.
 type KuluchifiedStatePayload struct {
 	GameState         GameState                   `json:"game_state"`
 	ChatMessages      []GlobalChatPayload         `json:"chat_messages"`
@@ -139,6 +352,34 @@ type KuluchifiedStatePayload struct {
 	Announcements     []AnnouncementPayload       `json:"announcements"`
 	Players           map[string]Player           `json:"players"`
 	TreasureHuntState TreasureHuntStatePayload    `json:"treasure_hunt_state"`
+	Pomodoros         map[string]PomodoroState    `json:"pomodoros,omitempty"` // Key: room number
+	GameMode          *GameModeState              `json:"game_mode,omitempty"` // Nil when no mode is running
+}
+
+// TreasureHuntWinnerPayload celebrates a solved riddle with every connected player
+type TreasureHuntWinnerPayload struct {
+	Winner string `json:"winner"`
+	RoomID string `json:"room_id"` // Room whose hunt was solved
+	Answer string `json:"answer"`
+	Points int    `json:"points"`
+}
+
+// TreasureHuntRoundEntry is a round of the treasure hunt that's already been played
+type TreasureHuntRoundEntry struct {
+	Round      int      `json:"round"`
+	Question   string   `json:"question"`
+	Answer     string   `json:"answer"`
+	Category   string   `json:"category"`
+	Difficulty string   `json:"difficulty"`
+	Winner     string   `json:"winner"`            // Empty if time ran out
+	SolveMs    int64    `json:"solve_ms"`          // How long the winner took, 0 if nobody won
+	Correct    []string `json:"correct,omitempty"` // Everyone who answered a trivia round right, fastest first
+	PlayedAt   int64    `json:"played_at"`         // Unix time the round ended
+}
+
+// TreasureHuntHistoryPayload lists the rounds played in the room today, oldest first
+type TreasureHuntHistoryPayload struct {
+	Rounds []TreasureHuntRoundEntry `json:"rounds"`
 }
 
 // TreasureHuntGuessPayload is sent by client to guess an answer
@@ -151,6 +392,180 @@ type TreasureHuntStatePayload struct {
 	CurrentClueIndex int    `json:"current_clue_index"`
 	ClueText         string `json:"clue_text"`
 	Completed        bool   `json:"completed"`
+	Category         string `json:"category,omitempty"`      // Riddle topic, empty between riddles
+	Difficulty       string `json:"difficulty,omitempty"`    // "easy", "medium" or "hard"
+	Points           int    `json:"points,omitempty"`        // What solving the riddle is worth
+	RoundEndsAt      int64  `json:"round_ends_at,omitempty"` // Unix time the riddle's time runs out
+	NextRoundAt      int64  `json:"next_round_at,omitempty"` // Unix time the next riddle starts, during the cooldown
+	Hint             string `json:"hint,omitempty"`          // The riddle's hint once it's given; ClueText has it too
+
+	Options []string `json:"options,omitempty"` // Choices of a multiple-choice trivia round, answered with a guess of "1"-"4"
+}
+
+// InteractResultPayload is sent only to the player who used an object
+type InteractResultPayload struct {
+	Object  string `json:"object"`
+	Message string `json:"message"`
+}
+
+// EmotePayload is broadcast to everyone in the room when a player does something
+type EmotePayload struct {
+	Username  string `json:"username"`
+	Message   string `json:"message"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// MiniGameChallengePayload is sent by a client to challenge another player
+type MiniGameChallengePayload struct {
+	Target string `json:"target"` // Username of the player being challenged
+	Game   string `json:"game"`   // Game kind, e.g. "tictactoe" or "trivia"
+}
+
+// MiniGameInvitePayload tells a client they have been challenged
+type MiniGameInvitePayload struct {
+	From string `json:"from"`
+	Game string `json:"game"`
+}
+
+// MiniGameRespondPayload accepts or declines an invite from a player
+type MiniGameRespondPayload struct {
+	From   string `json:"from"`
+	Accept bool   `json:"accept"`
+}
+
+// MiniGameDeclinedPayload tells a client the player they challenged declined
+type MiniGameDeclinedPayload struct {
+	By   string `json:"by"`
+	Game string `json:"game"`
+}
+
+// MiniGameMovePayload is a move in a running game; its meaning depends on the game
+// (a cell number "1"-"9" for tic-tac-toe)
+type MiniGameMovePayload struct {
+	GameID string `json:"game_id"`
+	Move   string `json:"move"`
+}
+
+// MiniGameStatePayload is the shared state of a game, sent to both players
+type MiniGameStatePayload struct {
+	GameID   string    `json:"game_id"`
+	Game     string    `json:"game"`
+	Players  [2]string `json:"players"`
+	Board    []string  `json:"board"`
+	Turn     string    `json:"turn"`   // Username whose move it is, empty when finished or both move at once (trivia)
+	Winner   string    `json:"winner"` // Empty for a draw or unfinished game
+	Finished bool      `json:"finished"`
+	Message  string    `json:"message"`
+	EndsAt   int64     `json:"ends_at,omitempty"` // Unix time a timed game (trivia) ends
+}
+
+// SetStatusPayload is sent by a client to set its status; an empty status clears it
+type SetStatusPayload struct {
+	Status string `json:"status"`
+}
+
+// IdleWarningPayload warns a client it will be disconnected unless it does something
+type IdleWarningPayload struct {
+	Seconds int `json:"seconds"` // Time left before the disconnect
+}
+
+// GameModePayload is sent by a client to start, join or leave a room game mode
+type GameModePayload struct {
+	Mode   string `json:"mode"`   // GameModeHideAndSeek, GameModeTag
+	Action string `json:"action"` // "start", "join" or "leave"
+}
+
+// GameModeState is the state of the game mode running in a room
+type GameModeState struct {
+	Mode   string            `json:"mode"`
+	Phase  string            `json:"phase"`   // Mode-specific, e.g. "lobby", "hiding", "seeking"
+	EndsAt int64             `json:"ends_at"` // Unix time the current phase ends
+	Roles  map[string]string `json:"roles"`   // Username -> role ("seeker", "hider", "found", "it", "runner", ...)
+}
+
+// ScavengerPayload is sent by a client to act on the scavenger hunt
+type ScavengerPayload struct {
+	Action string `json:"action"` // "start", "clue" or "claim"
+}
+
+// ScavengerStatePayload is a player's progress in the scavenger hunt; Active is false once it's over
+type ScavengerStatePayload struct {
+	Active  bool   `json:"active"`
+	Title   string `json:"title"`
+	Clue    string `json:"clue"`  // Riddle for the next location to claim
+	Found   int    `json:"found"` // Clues claimed so far
+	Total   int    `json:"total"`
+	Message string `json:"message"`
+}
+
+// LeaderboardEntry is one player's treasure hunt record
+type LeaderboardEntry struct {
+	Username       string `json:"username"`
+	Wins           int    `json:"wins"`
+	FastestSolveMs int64  `json:"fastest_solve_ms"`
+	Streak         int    `json:"streak"` // Current run of riddles won in a row
+	BestStreak     int    `json:"best_streak"`
+	Guesses        int    `json:"guesses"` // Guesses made at riddles, right or wrong
+}
+
+// LeaderboardResponsePayload lists the top players, best first
+type LeaderboardResponsePayload struct {
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+// PointsPayload is a player's points balance and the change that led to it
+type PointsPayload struct {
+	Balance int    `json:"balance"`
+	Delta   int    `json:"delta"`  // 0 when just reporting the balance, e.g. on join
+	Reason  string `json:"reason"` // e.g. "Solved the riddle"
+}
+
+// LoginStreakPayload is a player's login streak and what today's login earned
+type LoginStreakPayload struct {
+	Streak int    `json:"streak"` // Consecutive days with a login, including today
+	Bonus  int    `json:"bonus"`  // Points for today's login, 0 if already claimed
+	Reward string `json:"reward"` // Name of a cosmetic the streak just earned, if any
+}
+
+// ShopItem is a cosmetic sold in the shop
+type ShopItem struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Kind   string `json:"kind"` // "accessory" or "name_color"; one of each can be worn
+	Price  int    `json:"price"`
+	Value  string `json:"value"`            // Accessory glyph or name hex color
+	Reward bool   `json:"reward,omitempty"` // Earned (7-day login streak) rather than bought
+}
+
+// ShopItemPayload is sent by a client to buy or equip a shop item
+type ShopItemPayload struct {
+	ItemID string `json:"item_id"`
+}
+
+// ShopStatePayload is the shop catalog along with what the player owns and wears
+type ShopStatePayload struct {
+	Items    []ShopItem        `json:"items"`
+	Owned    []string          `json:"owned"`    // Item IDs
+	Equipped map[string]string `json:"equipped"` // Kind -> item ID
+	Message  string            `json:"message"`
+}
+
+// PomodoroPayload is sent by a client to start or stop the timer in its current room
+type PomodoroPayload struct {
+	Stop bool `json:"stop"`
+}
+
+// PomodoroState is a running pomodoro timer in a room
+type PomodoroState struct {
+	Phase     string `json:"phase"`   // "focus" or "break"
+	EndsAt    int64  `json:"ends_at"` // Unix time the current phase ends
+	Cycle     int    `json:"cycle"`   // Number of the current focus/break cycle, starting at 1
+	StartedBy string `json:"started_by"`
+}
+
+// PingPayload is sent in a ping and echoed back in its pong
+type PingPayload struct {
+	SentAt int64 `json:"sent_at"` // Client's Unix time in nanoseconds when it sent the ping
 }
 
 // EncodeMessage encodes a message with its payload
@@ -168,9 +583,12 @@ func EncodeMessage(msgType MessageType, payload interface{}) ([]byte, error) {
 	return json.Marshal(msg)
 }
 
-// DecodeMessage decodes a message
+// DecodeMessage decodes a message, or returns nil if it doesn't decode. Messages from
+// clients go through DecodeUntrusted instead.
 func DecodeMessage(data []byte) (*Message, error) {
 	var msg Message
-	err := json.Unmarshal(data, &msg)
-	return &msg, err
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
 }
@@ -0,0 +1,363 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HandleAdminHunt lets admins control a room's treasure hunt. It takes
+// POST /admin/hunt?room=<id>&action=start|stop|skip|void with an "Authorization: Bearer <token>"
+// header matching Config.AdminToken, and is disabled when no token is configured.
+func (s *Server) HandleAdminHunt(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	room, ok := s.adminRoom(w, r)
+	if !ok {
+		return
+	}
+
+	var err error
+	action := r.URL.Query().Get("action")
+	switch action {
+	case "start":
+		err = room.hunt.Start()
+	case "stop":
+		err = room.hunt.Stop()
+	case "skip":
+		err = room.hunt.Skip()
+	case "void":
+		err = room.hunt.Void()
+	default:
+		http.Error(w, "action must be start, stop, skip or void", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	log.Printf("Admin %s on the treasure hunt in room %s", action, room.ID)
+	fmt.Fprintf(w, "ok: %s\n", action)
+}
+
+// adminRiddles is what GET /admin/riddle shows, answers included
+type adminRiddles struct {
+	Current *GeminiRiddle `json:"current"`
+	Next    *GeminiRiddle `json:"next"` // Null until the next round's riddle is picked
+}
+
+// HandleAdminRiddle lets admins review riddles before players see them.
+// GET /admin/riddle?room=<id> shows the current and next riddle. POST with a JSON riddle
+// ({"question", "answer", "hint", "category", "difficulty"}) edits the next one, keeping
+// any field left out, or queues it if none is picked yet. Auth is as for HandleAdminHunt.
+func (s *Server) HandleAdminRiddle(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+	room, ok := s.adminRoom(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		var riddles adminRiddles
+		riddles.Current, riddles.Next = room.hunt.Riddles()
+		writeJSON(w, riddles)
+
+	case http.MethodPost:
+		var edit GeminiRiddle
+		if err := json.NewDecoder(io.LimitReader(r.Body, 64<<10)).Decode(&edit); err != nil {
+			http.Error(w, "bad riddle JSON: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		queued, err := room.hunt.QueueRiddle(edit)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, queued)
+
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "use GET or POST", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleAdminRooms lists every room with its player count and settings.
+// GET /admin/rooms lists them; POST /admin/rooms?room=<id>&tick_rate=<duration> changes
+// how often a room ticks while players are active. Auth is as for HandleAdminHunt.
+func (s *Server) HandleAdminRooms(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, s.roomManager.ListRooms())
+
+	case http.MethodPost:
+		room, ok := s.adminRoom(w, r)
+		if !ok {
+			return
+		}
+		rate, err := time.ParseDuration(r.URL.Query().Get("tick_rate"))
+		if err != nil {
+			http.Error(w, "tick_rate must be a duration like 100ms", http.StatusBadRequest)
+			return
+		}
+		if err := room.SetTickRate(rate); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("Admin set the tick rate in room %s to %s", room.ID, rate)
+		fmt.Fprintf(w, "ok: tick rate %s\n", rate)
+
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "use GET or POST", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleAdminBackpressure shows how much slow clients have cost: tick states and other
+// messages dropped, and clients disconnected for lagging. GET /admin/backpressure; auth
+// is as for HandleAdminHunt.
+func (s *Server) HandleAdminBackpressure(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "use GET", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, Backpressure())
+}
+
+// HandleAdminTicks shows whether the rooms keep up with their tick rate: how many ticks
+// ran, how many overran, and the slowest and average tick. GET /admin/ticks; auth is as
+// for HandleAdminHunt.
+func (s *Server) HandleAdminTicks(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "use GET", http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, Ticks())
+}
+
+// HandleAdminGhost turns ghost mode on or off for a player, who then walks through walls
+// and players unseen by anyone else. POST /admin/ghost?room=<id>&user=<name>&on=true|false;
+// auth is as for HandleAdminHunt.
+func (s *Server) HandleAdminGhost(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	room, ok := s.adminRoom(w, r)
+	if !ok {
+		return
+	}
+
+	on, err := strconv.ParseBool(r.URL.Query().Get("on"))
+	if err != nil {
+		http.Error(w, "on must be true or false", http.StatusBadRequest)
+		return
+	}
+	username := r.URL.Query().Get("user")
+	if err := room.SetGhost(username, on); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	fmt.Fprintf(w, "ok: ghost %s for %s\n", onOff(on), username)
+}
+
+// HandleAdminOccupancy shows how many players are in each building room of a room now,
+// and every few minutes over the last day. GET /admin/occupancy?room=<id>; auth is as for
+// HandleAdminHunt.
+func (s *Server) HandleAdminOccupancy(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "use GET", http.StatusMethodNotAllowed)
+		return
+	}
+	room, ok := s.adminRoom(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, room.Occupancy())
+}
+
+// HandleAdminHeatmap exports how often players were seen on each tile of a room, sampled
+// every few seconds, for cmd/heatmap to draw over the map. GET /admin/heatmap?room=<id>;
+// auth is as for HandleAdminHunt.
+func (s *Server) HandleAdminHeatmap(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "use GET", http.StatusMethodNotAllowed)
+		return
+	}
+	room, ok := s.adminRoom(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, room.Heatmap())
+}
+
+// HandleAdminKick disconnects a player and keeps them out of the room for 5 minutes, as
+// a kick by the room's owner does. POST /admin/kick?room=<id>&user=<name>; auth is as for
+// HandleAdminHunt.
+func (s *Server) HandleAdminKick(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	room, ok := s.adminRoom(w, r)
+	if !ok {
+		return
+	}
+
+	username := r.URL.Query().Get("user")
+	if err := room.AdminKick(username); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	fmt.Fprintf(w, "ok: kicked %s\n", username)
+}
+
+// HandleAdminAnnounce makes a server-wide announcement to every room. POST
+// /admin/announce with the announcement as the body; auth is as for HandleAdminHunt.
+func (s *Server) HandleAdminAnnounce(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 64<<10))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	message := strings.TrimSpace(string(body))
+	if err := validChat(message); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.chatManager.HandleAnnouncement(message)
+	s.roomManager.markAllActive()
+	log.Printf("Admin announced: %s", message)
+	fmt.Fprintln(w, "ok: announced")
+}
+
+// adminRoom finds the room named in the request's room parameter
+func (s *Server) adminRoom(w http.ResponseWriter, r *http.Request) (*Room, bool) {
+	roomID := r.URL.Query().Get("room")
+	room := s.roomManager.GetRoom(roomID)
+	if room == nil {
+		http.Error(w, fmt.Sprintf("no room %q", roomID), http.StatusNotFound)
+		return nil, false
+	}
+	return room, true
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Printf("Error writing admin response: %v", err)
+	}
+}
+
+// checkAdmin rejects the request unless it carries the admin token
+func (s *Server) checkAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if s.adminToken == "" {
+		http.NotFound(w, r)
+		return false
+	}
+	if !s.isAdminAuth(r.Header.Get("Authorization")) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
+
+// isAdminAuth reports whether an Authorization value is "Bearer " and the admin token
+func (s *Server) isAdminAuth(auth string) bool {
+	token, ok := strings.CutPrefix(auth, "Bearer ")
+	return ok && s.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) == 1
+}
+
+// HandleAdminRecord starts or stops recording a room's messages to a session journal in
+// the server's -record-dir, to debug desyncs, balance game modes or watch with
+// cmd/client -replay. POST /admin/record?room=<id>&on=true|false; auth is as for
+// HandleAdminHunt.
+func (s *Server) HandleAdminRecord(w http.ResponseWriter, r *http.Request) {
+	if !s.checkAdmin(w, r) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "use POST", http.StatusMethodNotAllowed)
+		return
+	}
+	room, ok := s.adminRoom(w, r)
+	if !ok {
+		return
+	}
+
+	on, err := strconv.ParseBool(r.URL.Query().Get("on"))
+	if err != nil {
+		http.Error(w, "on must be true or false", http.StatusBadRequest)
+		return
+	}
+	if on {
+		path, err := room.StartRecording()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		fmt.Fprintf(w, "ok: recording to %s\n", path)
+		return
+	}
+	path, err := room.StopRecording()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	fmt.Fprintf(w, "ok: recorded to %s\n", path)
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/server/adminpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// defaultMetricsInterval is how often StreamMetrics sends metrics if the caller doesn't say
+const defaultMetricsInterval = 5 * time.Second
+
+// adminService is the gRPC admin service (adminpb/admin.proto), for ops tooling and
+// dashboards that would rather not scrape the /admin API or the logs
+type adminService struct {
+	adminpb.UnimplementedAdminServer
+	s *Server
+}
+
+// GRPCServer returns a gRPC server offering the admin service, which checks every call
+// for the admin token as "authorization: Bearer <token>" metadata. It's nil when no
+// token is configured, as the service is then disabled like the /admin API.
+func (s *Server) GRPCServer() *grpc.Server {
+	if s.adminToken == "" {
+		return nil
+	}
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if err := s.checkAdminMetadata(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := s.checkAdminMetadata(ss.Context()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	)
+	adminpb.RegisterAdminServer(srv, &adminService{s: s})
+	return srv
+}
+
+// checkAdminMetadata rejects a gRPC call unless it carries the admin token
+func (s *Server) checkAdminMetadata(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, auth := range md.Get("authorization") {
+		if s.isAdminAuth(auth) {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "unauthorized")
+}
+
+func (a *adminService) ListRooms(context.Context, *adminpb.ListRoomsRequest) (*adminpb.ListRoomsResponse, error) {
+	var resp adminpb.ListRoomsResponse
+	for _, room := range a.s.roomManager.ListRooms() {
+		resp.Rooms = append(resp.Rooms, &adminpb.Room{
+			Id:          room.ID,
+			Name:        room.Name,
+			Owner:       room.Owner,
+			Players:     int32(room.Players),
+			Capacity:    int32(room.Capacity),
+			HasPassword: room.HasPassword,
+		})
+	}
+	return &resp, nil
+}
+
+func (a *adminService) ListPlayers(_ context.Context, req *adminpb.ListPlayersRequest) (*adminpb.ListPlayersResponse, error) {
+	room, err := a.room(req.GetRoom())
+	if err != nil {
+		return nil, err
+	}
+
+	var resp adminpb.ListPlayersResponse
+	room.mu.RLock()
+	for _, client := range room.Clients {
+		if client.Username == "" {
+			continue // Still onboarding
+		}
+		resp.Players = append(resp.Players, &adminpb.Player{
+			Username:    client.Username,
+			RoomNumber:  client.CurrentRoomNumber,
+			Status:      client.Status,
+			IdleSeconds: int64(client.idleFor().Seconds()),
+		})
+	}
+	room.mu.RUnlock()
+	sort.Slice(resp.Players, func(i, j int) bool { return resp.Players[i].Username < resp.Players[j].Username })
+	return &resp, nil
+}
+
+func (a *adminService) Kick(_ context.Context, req *adminpb.KickRequest) (*adminpb.KickResponse, error) {
+	room, err := a.room(req.GetRoom())
+	if err != nil {
+		return nil, err
+	}
+	if err := room.AdminKick(req.GetUsername()); err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &adminpb.KickResponse{}, nil
+}
+
+func (a *adminService) Announce(_ context.Context, req *adminpb.AnnounceRequest) (*adminpb.AnnounceResponse, error) {
+	if err := validChat(req.GetMessage()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	a.s.chatManager.HandleAnnouncement(req.GetMessage())
+	a.s.roomManager.markAllActive()
+	log.Printf("Admin announced: %s", req.GetMessage())
+	return &adminpb.AnnounceResponse{}, nil
+}
+
+func (a *adminService) StreamMetrics(req *adminpb.StreamMetricsRequest, stream adminpb.Admin_StreamMetricsServer) error {
+	interval := time.Duration(req.GetIntervalSeconds()) * time.Second
+	if interval <= 0 {
+		interval = defaultMetricsInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := stream.Send(a.s.metrics()); err != nil {
+			return err
+		}
+		select {
+		case <-ticker.C:
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// room finds a room by ID for an admin call
+func (a *adminService) room(id string) (*Room, error) {
+	room := a.s.roomManager.GetRoom(id)
+	if room == nil {
+		return nil, status.Error(codes.NotFound, fmt.Sprintf("no room %q", id))
+	}
+	return room, nil
+}
+
+// metrics takes what the debug endpoint shows: rooms and their queues, tick timings and
+// slow-client counters
+func (s *Server) metrics() *adminpb.Metrics {
+	m := &adminpb.Metrics{
+		Time:              time.Now().Unix(),
+		Goroutines:        int32(runtime.NumGoroutine()),
+		Ticks:             ticks.Load(),
+		TickOverruns:      tickOverruns.Load(),
+		SlowestTickMicros: time.Duration(slowestTick.Load()).Microseconds(),
+		StatesDropped:     statesDropped.Load(),
+		MessagesDropped:   messagesDropped.Load(),
+		LagDisconnects:    lagDisconnects.Load(),
+	}
+	if m.Ticks > 0 {
+		m.AverageTickMicros = time.Duration(tickNanos.Load() / m.Ticks).Microseconds()
+	}
+	for id, room := range s.roomManager.debugStats() {
+		m.Rooms = append(m.Rooms, &adminpb.RoomMetrics{
+			Id:             id,
+			Players:        int32(room.Players),
+			BroadcastQueue: int32(room.BroadcastQueue),
+			SendQueueMax:   int32(room.SendQueueMax),
+			SendQueueTotal: int32(room.SendQueueTotal),
+		})
+	}
+	sort.Slice(m.Rooms, func(i, j int) bool { return m.Rooms[i].Id < m.Rooms[j].Id })
+	return m
+}
@@ -0,0 +1,5 @@
+// Package adminpb is the gRPC admin service's generated code; the service itself is
+// described in admin.proto.
+package adminpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative admin.proto
@@ -0,0 +1,289 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	mathrand "math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// How analytics events are batched on their way to the sink
+const (
+	analyticsBuffer     = 1024            // Events waiting to be written; more are dropped
+	analyticsBatch      = 100             // Most events written at once
+	analyticsFlushEvery = 5 * time.Second // Longest an event waits to be written
+)
+
+// Ways of putting usernames in analytics events
+const (
+	AnalyticsUsernamesHash  = "hash"  // A salted hash, the same for a user until the salt changes
+	AnalyticsUsernamesOmit  = "omit"  // No username at all
+	AnalyticsUsernamesPlain = "plain" // The username itself
+)
+
+// AnalyticsConfig picks where analytics events go and how much they say about players.
+// The salt for hashed usernames comes from MORG_ANALYTICS_SALT; without it a random one
+// is picked at startup, so hashes can't be matched up across restarts.
+type AnalyticsConfig struct {
+	Sink       string  // "file:<path>" appends JSON lines, an http(s):// URL gets batches POSTed; empty turns analytics off
+	SampleRate float64 // Fraction of players whose events are recorded, 0-1
+	Usernames  string  // One of the AnalyticsUsernames constants
+}
+
+// Validate reports options analytics can't run with
+func (c AnalyticsConfig) Validate() error {
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		return errors.New("the analytics sample rate must be between 0 and 1")
+	}
+	switch c.Usernames {
+	case AnalyticsUsernamesHash, AnalyticsUsernamesOmit, AnalyticsUsernamesPlain:
+	default:
+		return fmt.Errorf("analytics usernames must be %s, %s or %s",
+			AnalyticsUsernamesHash, AnalyticsUsernamesOmit, AnalyticsUsernamesPlain)
+	}
+	return nil
+}
+
+// AnalyticsEvent is one thing that happened, as written to the sink
+type AnalyticsEvent struct {
+	Time   time.Time      `json:"time"`
+	Type   string         `json:"type"` // "join", "leave", "chat" or "guess"
+	User   string         `json:"user,omitempty"`
+	Room   string         `json:"room,omitempty"`
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// AnalyticsSink stores batches of events
+type AnalyticsSink interface {
+	Write(events []AnalyticsEvent) error
+}
+
+// Analytics records events in the background so tracking never holds up the game
+type Analytics struct {
+	config  AnalyticsConfig
+	sink    AnalyticsSink
+	salt    []byte
+	events  chan AnalyticsEvent
+	done    chan struct{}
+	mu      sync.RWMutex // Held to send on events, so Close can't close it mid-send
+	closed  bool
+	dropped atomic.Int64
+}
+
+// analytics gets the events tracked anywhere in the server; nil until SetAnalytics
+var analytics *Analytics
+
+// SetAnalytics sends the events tracked from now on to a
+func SetAnalytics(a *Analytics) {
+	analytics = a
+}
+
+// NewAnalytics starts recording events to the configured sink, or returns nil if no
+// sink is set
+func NewAnalytics(cfg AnalyticsConfig) (*Analytics, error) {
+	if cfg.Sink == "" {
+		return nil, nil
+	}
+
+	var sink AnalyticsSink
+	switch {
+	case strings.HasPrefix(cfg.Sink, "file:"):
+		sink = &fileSink{path: strings.TrimPrefix(cfg.Sink, "file:")}
+	case strings.HasPrefix(cfg.Sink, "http://"), strings.HasPrefix(cfg.Sink, "https://"):
+		sink = &httpSink{url: cfg.Sink, client: &http.Client{Timeout: 10 * time.Second}}
+	default:
+		return nil, fmt.Errorf("unknown analytics sink %q (want file:<path> or an http(s):// URL)", cfg.Sink)
+	}
+
+	salt := []byte(os.Getenv("MORG_ANALYTICS_SALT"))
+	if len(salt) == 0 {
+		salt = make([]byte, 16)
+		rand.Read(salt)
+	}
+
+	a := &Analytics{
+		config: cfg,
+		sink:   sink,
+		salt:   salt,
+		events: make(chan AnalyticsEvent, analyticsBuffer),
+		done:   make(chan struct{}),
+	}
+	go a.run()
+	log.Printf("Recording analytics to %s (%.0f%% of players, usernames: %s)", cfg.Sink, cfg.SampleRate*100, cfg.Usernames)
+	return a, nil
+}
+
+// Track records an event for a player, if they're sampled. Events without a player are
+// sampled at random.
+func (a *Analytics) Track(eventType, username, room string, fields map[string]any) {
+	if a == nil || !a.sampled(username) {
+		return
+	}
+
+	event := AnalyticsEvent{Time: time.Now(), Type: eventType, Room: room, Fields: fields}
+	switch a.config.Usernames {
+	case AnalyticsUsernamesHash:
+		event.User = a.hash(username)
+	case AnalyticsUsernamesPlain:
+		event.User = username
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return
+	}
+	select {
+	case a.events <- event:
+	default:
+		a.dropped.Add(1)
+	}
+}
+
+// track records an event with the server's analytics, if it has any
+func track(eventType, username, room string, fields map[string]any) {
+	analytics.Track(eventType, username, room, fields)
+}
+
+// Close writes the events still waiting and stops recording
+func (a *Analytics) Close() {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.events)
+	}
+	a.mu.Unlock()
+	<-a.done
+}
+
+// sampled reports whether the player's events are recorded. A player is either always
+// sampled or never, so their sessions can be followed from start to end.
+func (a *Analytics) sampled(username string) bool {
+	if a.config.SampleRate >= 1 {
+		return true
+	}
+	if username == "" {
+		return mathrand.Float64() < a.config.SampleRate
+	}
+	sum := a.digest(username)
+	return float64(binary.BigEndian.Uint64(sum[24:]))/math.MaxUint64 < a.config.SampleRate
+}
+
+// hash stands in for a username without giving it away
+func (a *Analytics) hash(username string) string {
+	if username == "" {
+		return ""
+	}
+	sum := a.digest(username)
+	return hex.EncodeToString(sum[:8])
+}
+
+func (a *Analytics) digest(username string) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write(a.salt)
+	h.Write([]byte(username))
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
+
+// run writes events in batches until Close
+func (a *Analytics) run() {
+	defer close(a.done)
+	ticker := time.NewTicker(analyticsFlushEvery)
+	defer ticker.Stop()
+
+	var batch []AnalyticsEvent
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := a.sink.Write(batch); err != nil {
+			log.Printf("Error writing %d analytics events: %v", len(batch), err)
+		}
+		if dropped := a.dropped.Swap(0); dropped > 0 {
+			log.Printf("Dropped %d analytics events, the sink can't keep up", dropped)
+		}
+		batch = nil
+	}
+
+	for {
+		select {
+		case event, ok := <-a.events:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= analyticsBatch {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// fileSink appends events to a file, one JSON object per line
+type fileSink struct {
+	path string
+}
+
+func (s *fileSink) Write(events []AnalyticsEvent) error {
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// httpSink POSTs each batch of events to a URL as a JSON array
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+func (s *httpSink) Write(events []AnalyticsEvent) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("analytics endpoint answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// maxLag is how long a client can keep falling behind, with every message to it being
+// dropped, before it's disconnected
+const maxLag = 10 * time.Second
+
+// BackpressureStats counts what slow clients have cost since the server started
+type BackpressureStats struct {
+	StatesDropped   int64 `json:"states_dropped"`   // Tick states replaced by a newer one before the client got them
+	MessagesDropped int64 `json:"messages_dropped"` // Other messages thrown away because the client's queue was full
+	LagDisconnects  int64 `json:"lag_disconnects"`  // Clients disconnected for falling behind for maxLag
+}
+
+var statesDropped, messagesDropped, lagDisconnects atomic.Int64
+
+// Backpressure returns the slow client counters
+func Backpressure() BackpressureStats {
+	return BackpressureStats{
+		StatesDropped:   statesDropped.Load(),
+		MessagesDropped: messagesDropped.Load(),
+		LagDisconnects:  lagDisconnects.Load(),
+	}
+}
+
+// deliverState hands the client the latest tick state. Only the newest state matters,
+// so one the client hasn't picked up yet is replaced rather than queued behind.
+func (c *Client) deliverState(msg []byte) {
+	select {
+	case c.state <- msg:
+		c.keepingUp()
+		return
+	default:
+	}
+
+	select {
+	case <-c.state:
+		statesDropped.Add(1)
+		c.fallingBehind()
+	default: // The writer took it in the meantime
+	}
+	select {
+	case c.state <- msg:
+	default:
+	}
+}
+
+// deliver queues a message for the client, dropping it if the client's queue is full
+// rather than holding up the room, or if the client has been hung up on. Every message
+// but the tick state goes through here.
+func (c *Client) deliver(msg []byte) {
+	select {
+	case <-c.done:
+		return
+	default:
+	}
+	select {
+	case c.send <- msg:
+		c.keepingUp()
+	default:
+		messagesDropped.Add(1)
+		c.fallingBehind()
+	}
+}
+
+// keepingUp clears the client's lag once a message gets through
+func (c *Client) keepingUp() {
+	c.lagSince.Store(0)
+}
+
+// fallingBehind notes when the client started dropping messages
+func (c *Client) fallingBehind() {
+	c.lagSince.CompareAndSwap(0, time.Now().UnixNano())
+}
+
+// laggingFor returns how long everything sent to the client has been dropped, 0 if it's
+// keeping up
+func (c *Client) laggingFor() time.Duration {
+	since := c.lagSince.Load()
+	if since == 0 {
+		return 0
+	}
+	return time.Since(time.Unix(0, since))
+}
+
+// laggingClientsLocked returns the clients that have fallen behind for too long; r.mu
+// must be held
+func (r *Room) laggingClientsLocked() []*Client {
+	var lagging []*Client
+	for _, client := range r.Clients {
+		if client.laggingFor() >= maxLag {
+			lagging = append(lagging, client)
+		}
+	}
+	return lagging
+}
+
+// kickLagging disconnects a client that can't keep up, telling it why. It must only be
+// called from the Run loop since it unregisters the client directly.
+func (r *Room) kickLagging(client *Client) {
+	// Nothing queued matters anymore, so make room for the reason
+	for len(client.send) > 0 {
+		select {
+		case <-client.send:
+		default:
+		}
+	}
+	msg, _ := protocol.EncodeMessage(protocol.MsgError, protocol.ErrorPayload{
+		Message: "Your connection is lagging too far behind, so you were disconnected. Reconnect when it's better",
+	})
+	client.deliver(msg)
+
+	lagDisconnects.Add(1)
+	log.Printf("Disconnecting %s from room %s, lagging for %s", client.Username, r.ID, client.laggingFor().Round(time.Second))
+	// The connection closes once the message above is written, and the client's
+	// readPump then finds it already gone from the room
+	r.handleUnregister(client)
+	r.forfeitMiniGames(client.Username)
+	r.leaveGameMode(client.Username)
+}
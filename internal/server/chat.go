@@ -1,31 +1,47 @@
 package server
 
 import (
+	"fmt"
+	"sort"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/always-at-morg/internal/protocol"
 )
 
+// Messages posted by the server itself (game results, timers, ...) use this sender ID
+const (
+	systemSenderID   = "system"
+	systemSenderName = "morg"
+)
+
 // ChatMessage represents a stored chat message
 type ChatMessage struct {
 	ID           string
 	FromPlayerID string
+	FromUsername string // Kept for global chat, whose sender may be in another room
 	ToPlayerID   string // Empty for global chat
 	Message      string
 	Timestamp    int64
 	Type         string // "global", "dm", "announcement"
+	Seq          int64  // Counts global chat, and each game room's room chat, from 1
 }
 
 // ChatManager manages all chat functionality
 type ChatManager struct {
 	// Message storage
-	globalMessages []ChatMessage            // Global chat history
-	dmMessages     map[string][]ChatMessage // key: "playerID1:playerID2" (sorted) -> messages
-	roomMessages   map[string][]ChatMessage // key: room number -> messages
-	announcements  []ChatMessage            // Announcement history
+	globalMessages []ChatMessage                       // Global chat history
+	dmMessages     map[string][]ChatMessage            // key: "playerID1:playerID2" (sorted) -> messages
+	roomMessages   map[string]map[string][]ChatMessage // key: room ID -> room number -> messages
+	announcements  []ChatMessage                       // Announcement history
+	hub            func(msg []byte)                    // Sends to the clients of every room; see SetHub
 	mu             sync.RWMutex
+
+	// Seq of the newest global message, and of the newest room message in each game room
+	globalSeq int64
+	roomSeqs  map[string]int64
 }
 
 // NewChatManager creates a new chat manager
@@ -33,61 +49,45 @@ func NewChatManager() *ChatManager {
 	return &ChatManager{
 		globalMessages: make([]ChatMessage, 0),
 		dmMessages:     make(map[string][]ChatMessage),
-		roomMessages:   make(map[string][]ChatMessage),
+		roomMessages:   make(map[string]map[string][]ChatMessage),
 		announcements:  make([]ChatMessage, 0),
+		roomSeqs:       make(map[string]int64),
 	}
 }
 
-// HandleGlobalChat stores a new global chat message
-func (cm *ChatManager) HandleGlobalChat(client *Client, message string, room *Room) {
+// SetHub gives the chat manager the server-level hub that fans announcements out to
+// the clients of every room, whatever room ID they joined
+func (cm *ChatManager) SetHub(broadcastAll func(msg []byte)) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.hub = broadcastAll
+}
+
+// HandleGlobalChat stores a new global chat message. Every room sends it with its next
+// state.
+func (cm *ChatManager) HandleGlobalChat(client *Client, message string) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
 	// Store the new message
+	cm.globalSeq++
 	chatMsg := ChatMessage{
 		ID:           uuid.New().String(),
 		FromPlayerID: client.ID,
+		FromUsername: client.Username,
 		Message:      message,
 		Timestamp:    time.Now().Unix(),
 		Type:         "global",
+		Seq:          cm.globalSeq,
 	}
 	cm.globalMessages = append(cm.globalMessages, chatMsg)
-
-	// Build payload with all global chat messages
-	messages := make([]protocol.GlobalChatPayload, len(cm.globalMessages))
-	for i, msg := range cm.globalMessages {
-		// Get username from client ID (need to look up from room)
-		username := ""
-		room.mu.RLock()
-		if c, ok := room.Clients[msg.FromPlayerID]; ok {
-			username = c.Username
-		}
-		room.mu.RUnlock()
-
-		messages[i] = protocol.GlobalChatPayload{
-			Username:  username,
-			Message:   msg.Message,
-			Timestamp: msg.Timestamp,
-		}
-	}
-
-	// Broadcast ALL messages to all clients
-	payload := protocol.GlobalChatMessagesPayload{
-		Messages: messages,
-	}
-
-	msg, err := protocol.EncodeMessage(protocol.MsgGlobalChatMessages, payload)
-	if err != nil {
-		return
-	}
-
-	room.broadcast <- msg
+	trackChat(client, client.Room, chatMsg)
 }
 
-// HandleAnnouncement stores a new announcement
-func (cm *ChatManager) HandleAnnouncement(message string, room *Room) {
+// HandleAnnouncement stores a new announcement and sends it to everyone. Only admins make
+// them, through the admin HTTP and gRPC APIs; clients can't send one.
+func (cm *ChatManager) HandleAnnouncement(message string) {
 	cm.mu.Lock()
-	defer cm.mu.Unlock()
 
 	// Store the announcement
 	chatMsg := ChatMessage{
@@ -97,6 +97,18 @@ func (cm *ChatManager) HandleAnnouncement(message string, room *Room) {
 		Type:      "announcement",
 	}
 	cm.announcements = append(cm.announcements, chatMsg)
+	hub := cm.hub
+	cm.mu.Unlock()
+
+	msg, err := protocol.EncodeMessage(protocol.MsgAnnouncement, protocol.AnnouncementPayload{
+		Message:   chatMsg.Message,
+		Timestamp: chatMsg.Timestamp,
+		Source:    protocol.AnnouncementAdmin,
+	})
+	if err != nil || hub == nil {
+		return
+	}
+	hub(msg)
 }
 
 // HandleDirectMessage sends a 1:1 message between two players
@@ -115,6 +127,7 @@ func (cm *ChatManager) HandleDirectMessage(fromClient *Client, toUsername string
 
 	if targetClient == nil {
 		// Target player not found in room
+		sendError(fromClient, fmt.Sprintf("%s isn't here", toUsername))
 		return
 	}
 
@@ -133,6 +146,7 @@ func (cm *ChatManager) HandleDirectMessage(fromClient *Client, toUsername string
 	dmKey := getDMKey(fromClient.ID, targetClient.ID)
 	cm.dmMessages[dmKey] = append(cm.dmMessages[dmKey], chatMsg)
 	cm.mu.Unlock()
+	trackChat(fromClient, room, chatMsg)
 
 	// Send usernames in the payload (not IDs) so client can display them
 	payload := protocol.ChatMessagePayload{
@@ -148,36 +162,43 @@ func (cm *ChatManager) HandleDirectMessage(fromClient *Client, toUsername string
 	}
 
 	// Send message to both sender and receiver
-	targetClient.send <- msg
-	fromClient.send <- msg
+	targetClient.deliver(msg)
+	fromClient.deliver(msg)
 }
 
 // GetGlobalMessages returns all global chat messages as GlobalChatPayload format
-func (cm *ChatManager) GetGlobalMessages(room *Room) protocol.GlobalChatMessagesPayload {
+func (cm *ChatManager) GetGlobalMessages() protocol.GlobalChatMessagesPayload {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	return protocol.GlobalChatMessagesPayload{
+		Messages: globalPayloads(cm.globalMessages),
+	}
+}
+
+// GlobalMessagesSince returns the global chat messages after seq after, and the seq of
+// the newest one to ask from next time
+func (cm *ChatManager) GlobalMessagesSince(after int64) ([]protocol.GlobalChatPayload, int64) {
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
 
-	// Build payload with all global chat messages
-	messages := make([]protocol.GlobalChatPayload, len(cm.globalMessages))
-	for i, msg := range cm.globalMessages {
-		// Get username from client ID (need to look up from room)
-		username := ""
-		room.mu.RLock()
-		if c, ok := room.Clients[msg.FromPlayerID]; ok {
-			username = c.Username
-		}
-		room.mu.RUnlock()
+	start := sort.Search(len(cm.globalMessages), func(i int) bool { return cm.globalMessages[i].Seq > after })
+	if start == len(cm.globalMessages) {
+		return nil, max(after, cm.globalSeq)
+	}
+	return globalPayloads(cm.globalMessages[start:]), cm.globalSeq
+}
 
-		messages[i] = protocol.GlobalChatPayload{
-			Username:  username,
+func globalPayloads(messages []ChatMessage) []protocol.GlobalChatPayload {
+	payloads := make([]protocol.GlobalChatPayload, len(messages))
+	for i, msg := range messages {
+		payloads[i] = protocol.GlobalChatPayload{
+			Username:  msg.FromUsername,
 			Message:   msg.Message,
 			Timestamp: msg.Timestamp,
+			Seq:       msg.Seq,
 		}
 	}
-
-	return protocol.GlobalChatMessagesPayload{
-		Messages: messages,
-	}
+	return payloads
 }
 
 // GetDMMessages returns all DM messages between two players
@@ -205,12 +226,43 @@ func (cm *ChatManager) GetAnnouncements() []ChatMessage {
 	return messages
 }
 
-// HandleRoomChat stores and broadcasts a room chat message
+// sendAnnouncementHistory sends a client the announcements made so far, for players who
+// joined after them
+func (cm *ChatManager) sendAnnouncementHistory(c *Client) {
+	announcements := cm.GetAnnouncements()
+	payload := protocol.AnnouncementHistoryPayload{Announcements: make([]protocol.AnnouncementPayload, len(announcements))}
+	for i, announcement := range announcements {
+		payload.Announcements[i] = protocol.AnnouncementPayload{
+			Message:   announcement.Message,
+			Timestamp: announcement.Timestamp,
+			Source:    protocol.AnnouncementAdmin,
+		}
+	}
+	msg, _ := protocol.EncodeMessage(protocol.MsgAnnouncementHistory, payload)
+	c.deliver(msg)
+}
+
+// sendChatHistory sends a client all of global chat and, if it's in a room, the chat of
+// each of the room's building rooms, for players who joined late or missed a state
+func (s *Server) sendChatHistory(c *Client) {
+	msg, _ := protocol.EncodeMessage(protocol.MsgGlobalChatMessages, s.chatManager.GetGlobalMessages())
+	c.deliver(msg)
+	if c.Room == nil {
+		return
+	}
+	for _, payload := range s.chatManager.RoomHistory(c.Room) {
+		msg, _ := protocol.EncodeMessage(protocol.MsgRoomChatMessages, payload)
+		c.deliver(msg)
+	}
+}
+
+// HandleRoomChat stores a room chat message. The game room sends it with its next state.
 func (cm *ChatManager) HandleRoomChat(client *Client, roomNumber string, message string, room *Room) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
 
 	// Store the new message
+	cm.roomSeqs[room.ID]++
 	chatMsg := ChatMessage{
 		ID:           uuid.New().String(),
 		FromPlayerID: client.ID,
@@ -218,118 +270,161 @@ func (cm *ChatManager) HandleRoomChat(client *Client, roomNumber string, message
 		Message:      message,
 		Timestamp:    time.Now().Unix(),
 		Type:         "room",
+		Seq:          cm.roomSeqs[room.ID],
 	}
 
-	// Initialize room message array if it doesn't exist
-	if cm.roomMessages[roomNumber] == nil {
-		cm.roomMessages[roomNumber] = make([]ChatMessage, 0)
+	// Initialize the game room's message map if it doesn't exist
+	if cm.roomMessages[room.ID] == nil {
+		cm.roomMessages[room.ID] = make(map[string][]ChatMessage)
 	}
-	cm.roomMessages[roomNumber] = append(cm.roomMessages[roomNumber], chatMsg)
-
-	// Build payload with all room chat messages for this room
-	messages := make([]protocol.RoomChatPayload, len(cm.roomMessages[roomNumber]))
-	for i, msg := range cm.roomMessages[roomNumber] {
-		// Get username from client ID
-		username := ""
-		room.mu.RLock()
-		if c, ok := room.Clients[msg.FromPlayerID]; ok {
-			username = c.Name
-		}
-		room.mu.RUnlock()
+	cm.roomMessages[room.ID][roomNumber] = append(cm.roomMessages[room.ID][roomNumber], chatMsg)
+	trackChat(client, room, chatMsg)
+}
 
-		messages[i] = protocol.RoomChatPayload{
-			RoomNumber: roomNumber,
-			Username:   username,
-			Message:    msg.Message,
-			Timestamp:  msg.Timestamp,
-		}
-	}
+// GetRoomMessages returns all chat messages for a specific room
+func (cm *ChatManager) GetRoomMessages(roomNumber string, room *Room) protocol.RoomChatMessagesPayload {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
 
-	payload := protocol.RoomChatMessagesPayload{
+	return protocol.RoomChatMessagesPayload{
 		RoomNumber: roomNumber,
-		Messages:   messages,
+		Messages:   roomPayloads(room, roomNumber, cm.roomMessages[room.ID][roomNumber]),
+		Latest:     cm.roomSeqs[room.ID],
 	}
+}
 
-	// Broadcast to all clients in the same room
-	room.mu.RLock()
-	for _, c := range room.Clients {
-		// Only send to clients in the same room
-		if c.CurrentRoomNumber == roomNumber {
-			msg, _ := protocol.EncodeMessage(protocol.MsgRoomChatMessages, payload)
-			c.send <- msg
-		}
+// RoomHistory returns the chat of every building room in a game room, including
+// the ones that were cleared, for players who joined late
+func (cm *ChatManager) RoomHistory(room *Room) []protocol.RoomChatMessagesPayload {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	var history []protocol.RoomChatMessagesPayload
+	for roomNumber, roomMsgs := range cm.roomMessages[room.ID] {
+		history = append(history, protocol.RoomChatMessagesPayload{
+			RoomNumber: roomNumber,
+			Messages:   roomPayloads(room, roomNumber, roomMsgs),
+			Latest:     cm.roomSeqs[room.ID],
+		})
 	}
-	room.mu.RUnlock()
+	return history
 }
 
-// GetRoomMessages returns all chat messages for a specific room
-func (cm *ChatManager) GetRoomMessages(roomNumber string, room *Room) protocol.RoomChatMessagesPayload {
+// RoomMessagesSince returns the messages posted in a game room's building rooms after
+// seq after, by room number, and the seq of the newest one to ask from next time
+func (cm *ChatManager) RoomMessagesSince(room *Room, after int64) (map[string][]protocol.RoomChatPayload, int64) {
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
 
-	// Get messages for this room
-	roomMsgs := cm.roomMessages[roomNumber]
-	if roomMsgs == nil {
-		return protocol.RoomChatMessagesPayload{
-			RoomNumber: roomNumber,
-			Messages:   []protocol.RoomChatPayload{},
+	latest := cm.roomSeqs[room.ID]
+	if latest <= after {
+		return nil, max(after, latest)
+	}
+	result := make(map[string][]protocol.RoomChatPayload)
+	for roomNumber, roomMsgs := range cm.roomMessages[room.ID] {
+		start := sort.Search(len(roomMsgs), func(i int) bool { return roomMsgs[i].Seq > after })
+		if start < len(roomMsgs) {
+			result[roomNumber] = roomPayloads(room, roomNumber, roomMsgs[start:])
 		}
 	}
+	return result, latest
+}
 
-	// Convert to protocol format
+// roomPayloads converts room chat messages for sending, naming their senders
+func roomPayloads(room *Room, roomNumber string, roomMsgs []ChatMessage) []protocol.RoomChatPayload {
 	messages := make([]protocol.RoomChatPayload, len(roomMsgs))
+	room.mu.RLock()
+	defer room.mu.RUnlock()
 	for i, msg := range roomMsgs {
 		// Get username from client ID
 		username := ""
-		room.mu.RLock()
 		if c, ok := room.Clients[msg.FromPlayerID]; ok {
 			username = c.Name
+		} else if msg.FromPlayerID == systemSenderID {
+			username = systemSenderName
 		}
-		room.mu.RUnlock()
 
 		messages[i] = protocol.RoomChatPayload{
 			RoomNumber: roomNumber,
 			Username:   username,
 			Message:    msg.Message,
 			Timestamp:  msg.Timestamp,
+			Seq:        msg.Seq,
 		}
 	}
-
-	return protocol.RoomChatMessagesPayload{
-		RoomNumber: roomNumber,
-		Messages:   messages,
-	}
+	return messages
 }
 
-// GetAllRoomMessages returns all room chat messages for all rooms
-func (cm *ChatManager) GetAllRoomMessages(room *Room) map[string][]protocol.RoomChatPayload {
+// latestSeqs returns the seqs of the newest global message and the newest room message
+// in a game room, for a new room to send what comes after them
+func (cm *ChatManager) latestSeqs(roomID string) (global, room int64) {
 	cm.mu.RLock()
 	defer cm.mu.RUnlock()
+	return cm.globalSeq, cm.roomSeqs[roomID]
+}
 
-	result := make(map[string][]protocol.RoomChatPayload)
+// PostSystemMessage stores a server-authored message in the chat of a room in the game
+// room roomID, or in global chat when roomNumber is empty. Global chat is shared by every
+// game room, so news of just one of them goes out with Room.postNotice instead. Clients
+// pick it up with the next state tick.
+func (cm *ChatManager) PostSystemMessage(roomID, roomNumber string, message string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
 
-	for roomNumber, roomMsgs := range cm.roomMessages {
-		messages := make([]protocol.RoomChatPayload, len(roomMsgs))
-		for i, msg := range roomMsgs {
-			// Get username from client ID
-			username := ""
-			room.mu.RLock()
-			if c, ok := room.Clients[msg.FromPlayerID]; ok {
-				username = c.Name
-			}
-			room.mu.RUnlock()
-
-			messages[i] = protocol.RoomChatPayload{
-				RoomNumber: roomNumber,
-				Username:   username,
-				Message:    msg.Message,
-				Timestamp:  msg.Timestamp,
-			}
-		}
-		result[roomNumber] = messages
+	chatMsg := ChatMessage{
+		ID:           uuid.New().String(),
+		FromPlayerID: systemSenderID,
+		FromUsername: systemSenderName,
+		ToPlayerID:   roomNumber,
+		Message:      message,
+		Timestamp:    time.Now().Unix(),
+		Type:         "room",
 	}
 
-	return result
+	if roomNumber == "" {
+		cm.globalSeq++
+		chatMsg.Type = "global"
+		chatMsg.Seq = cm.globalSeq
+		cm.globalMessages = append(cm.globalMessages, chatMsg)
+		return
+	}
+	cm.roomSeqs[roomID]++
+	chatMsg.Seq = cm.roomSeqs[roomID]
+	if cm.roomMessages[roomID] == nil {
+		cm.roomMessages[roomID] = make(map[string][]ChatMessage)
+	}
+	cm.roomMessages[roomID][roomNumber] = append(cm.roomMessages[roomID][roomNumber], chatMsg)
+}
+
+// notice encodes a message from the server to everyone in the room. It's shown the way
+// emotes are, since global chat would carry it to every other room too.
+func (r *Room) notice(message string) []byte {
+	msg, _ := protocol.EncodeMessage(protocol.MsgEmote, protocol.EmotePayload{
+		Username:  systemSenderName,
+		Message:   message,
+		Timestamp: r.clock.Now().Unix(),
+	})
+	return msg
+}
+
+// postNotice sends a notice to everyone in the room, unless it has closed. The Run loop
+// sends its own with handleBroadcast instead, since it's the one reading r.broadcast.
+func (r *Room) postNotice(message string) {
+	select {
+	case r.broadcast <- r.notice(message):
+	case <-r.quit:
+	}
+}
+
+// ClearRoomChat empties the chat of every room in a game room. The room numbers are
+// kept with no messages so RoomHistory tells clients to empty them too.
+func (cm *ChatManager) ClearRoomChat(roomID string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	for roomNumber := range cm.roomMessages[roomID] {
+		cm.roomMessages[roomID][roomNumber] = nil
+	}
 }
 
 // Helper function to generate consistent DM keys
@@ -339,3 +434,16 @@ func getDMKey(playerID1, playerID2 string) string {
 	}
 	return playerID2 + ":" + playerID1
 }
+
+// trackChat records that a player sent a message. Only its kind and length are kept,
+// never what it says or who it went to.
+func trackChat(client *Client, room *Room, msg ChatMessage) {
+	roomID := ""
+	if room != nil {
+		roomID = room.ID
+	}
+	track("chat", client.Username, roomID, map[string]any{
+		"kind":   msg.Type,
+		"length": utf8.RuneCountInString(msg.Message),
+	})
+}
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// Config holds the tunable server options, set from command-line flags in cmd/server
+type Config struct {
+	// IdleKick disconnects clients that send nothing for this long, freeing their spot
+	// on the map. Zero disables it.
+	IdleKick time.Duration
+
+	// DataDir is where the server keeps data that survives restarts, such as the
+	// leaderboard. Empty keeps everything in memory.
+	DataDir string
+
+	// RoomCapacity is the most players a room takes. Zero means no limit.
+	RoomCapacity int
+
+	// MaxRooms is the most rooms the server runs at once, each with its own treasure
+	// hunt. Rooms close once they've been empty for a while. Zero means no limit.
+	MaxRooms int
+
+	// PersonalSpace is how many tiles players keep between them inside the building's
+	// rooms, and HallwayPersonalSpace in the hallways, where a crowd blocks the way more
+	// easily. Avatars never overlap, whatever they're set to.
+	PersonalSpace        int
+	HallwayPersonalSpace int
+
+	// TickRate is how often a room runs its game loop and sends its state to players
+	// while they're playing. IdleTickRate is the least often it sends its state: a room
+	// slows down towards it once nobody has moved or chatted for a second. An empty room
+	// ticks at it too.
+	TickRate     time.Duration
+	IdleTickRate time.Duration
+
+	// Hunt times the treasure hunt rounds in every room
+	Hunt HuntSchedule
+
+	// LLM picks the model that writes riddles, trivia and scavenger hunts
+	LLM LLMConfig
+
+	// RecordDir is where session journals go when an admin records a room. Empty
+	// turns recording off.
+	RecordDir string
+
+	// Analytics records joins, chat and guesses for looking at how the game is played
+	Analytics AnalyticsConfig
+
+	// StrictProtocol refuses client messages with fields the protocol doesn't have.
+	// Sizes, nesting and numbers are checked either way.
+	StrictProtocol bool
+
+	// AdminToken must be sent as a bearer token to use the admin API. Empty disables it.
+	AdminToken string
+
+	// Seed drives spawn spots, riddle picks from the local bank and other random
+	// choices, so a run can be replayed. Zero picks one at random, which is logged.
+	Seed int64
+
+	// Clock times the rooms and treasure hunts; nil uses the wall clock. A simulation
+	// sets a ManualClock so round timing is the same on every run.
+	Clock Clock
+}
+
+// roomConfig returns the rules clients need to know about
+func (c Config) roomConfig() protocol.RoomConfig {
+	return protocol.RoomConfig{
+		PersonalSpace:        c.PersonalSpace,
+		HallwayPersonalSpace: c.HallwayPersonalSpace,
+	}
+}
+
+// HuntSchedule sets how long treasure hunt riddles run and how many are played a day
+type HuntSchedule struct {
+	Round       time.Duration // How long players have to solve a riddle
+	Hint        time.Duration // How far into the round the hint is shown
+	Cooldown    time.Duration // Break between rounds while the next riddle is prepared
+	DailyRounds int           // Riddles per Madison day
+	TriviaEvery int           // Every this many rounds is multiple-choice trivia; 0 plays only riddles
+}
+
+// LLMConfig selects a RiddleProvider. API keys come from GEMINI_API_KEY and OPENAI_API_KEY
+// rather than flags so they don't show up in the process list.
+type LLMConfig struct {
+	Provider string // "gemini", "openai", "ollama" or "static"; empty picks one from the keys set
+	Model    string // Empty uses the provider's default
+	URL      string // Server for ollama, or an OpenAI-compatible server for openai
+}
+
+// DefaultConfig returns the options the server runs with when no flags are given
+func DefaultConfig() Config {
+	return Config{
+		IdleKick:             0,
+		DataDir:              "data",
+		RecordDir:            "recordings",
+		RoomCapacity:         50,
+		MaxRooms:             100,
+		PersonalSpace:        4,
+		HallwayPersonalSpace: 2,
+		TickRate:             50 * time.Millisecond, // 20 ticks per second
+		IdleTickRate:         time.Second,
+		Hunt: HuntSchedule{
+			Round:       time.Minute,
+			Hint:        30 * time.Second,
+			Cooldown:    2 * time.Minute,
+			DailyRounds: 3,
+			TriviaEvery: 3,
+		},
+		Analytics: AnalyticsConfig{
+			SampleRate: 1,
+			Usernames:  AnalyticsUsernamesHash,
+		},
+	}
+}
+
+// minTickRate is the fastest a room may tick
+const minTickRate = 10 * time.Millisecond
+
+// Validate reports options the server can't run with
+func (c Config) Validate() error {
+	if c.TickRate < minTickRate {
+		return fmt.Errorf("the tick rate must be at least %s", minTickRate)
+	}
+	if c.IdleTickRate < c.TickRate {
+		return errors.New("the idle tick rate can't be faster than the tick rate")
+	}
+	if c.MaxRooms < 0 {
+		return errors.New("the room limit can't be negative")
+	}
+	if c.PersonalSpace < 0 || c.HallwayPersonalSpace < 0 {
+		return errors.New("personal space can't be negative")
+	}
+	if err := c.Analytics.Validate(); err != nil {
+		return err
+	}
+	return c.Hunt.Validate()
+}
+
+// Validate reports a schedule the game loop can't run
+func (h HuntSchedule) Validate() error {
+	if h.Round <= 0 || h.Hint <= 0 || h.Cooldown < 0 {
+		return errors.New("hunt round and hint times must be positive and the cooldown can't be negative")
+	}
+	if h.DailyRounds < 1 {
+		return errors.New("at least one hunt round must be played a day")
+	}
+	if h.TriviaEvery < 0 {
+		return errors.New("trivia rounds can't be every negative number of rounds")
+	}
+	return nil
+}
+
+// triviaRound reports whether a day's round (counting from 1) is multiple-choice trivia
+func (h HuntSchedule) triviaRound(round int) bool {
+	return h.TriviaEvery > 0 && round%h.TriviaEvery == 0
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"log"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// madisonLocation is the time zone the day/night cycle follows
+var madisonLocation = loadMadisonLocation()
+
+func loadMadisonLocation() *time.Location {
+	loc, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		log.Printf("Warning: failed to load Madison time zone, using CST: %v", err)
+		return time.FixedZone("CST", -6*60*60)
+	}
+	return loc
+}
+
+// timeOfDay returns the phase of the day in Madison at the given time
+func timeOfDay(now time.Time) string {
+	hour := now.In(madisonLocation).Hour()
+	switch {
+	case hour >= 7 && hour < 17:
+		return protocol.TimeOfDayDay
+	case hour >= 17 && hour < 20:
+		return protocol.TimeOfDayEvening
+	default:
+		return protocol.TimeOfDayNight
+	}
+}
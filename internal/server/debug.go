@@ -0,0 +1,70 @@
+package server
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	debugVarsOnce sync.Once
+	debugServer   atomic.Pointer[Server] // Server the expvar variables describe
+)
+
+// RoomDebugStats is how busy a room is, for the debug endpoint
+type RoomDebugStats struct {
+	Players        int `json:"players"`
+	BroadcastQueue int `json:"broadcast_queue"`  // Messages waiting for the room's loop to send them
+	SendQueueMax   int `json:"send_queue_max"`   // Longest queue of messages waiting for one of its clients
+	SendQueueTotal int `json:"send_queue_total"` // Messages waiting for all its clients together
+}
+
+// DebugHandler serves net/http/pprof under /debug/pprof/ and expvar at /debug/vars,
+// with the goroutine count, the rooms and their queues, tick timings and slow-client
+// counters alongside Go's memory stats. It's meant for a port only operators can
+// reach, as profiles give away a lot about the server.
+func (s *Server) DebugHandler() http.Handler {
+	debugServer.Store(s)
+	debugVarsOnce.Do(func() {
+		expvar.Publish("goroutines", expvar.Func(func() any { return runtime.NumGoroutine() }))
+		expvar.Publish("rooms", expvar.Func(func() any { return debugServer.Load().roomManager.debugStats() }))
+		expvar.Publish("ticks", expvar.Func(func() any { return Ticks() }))
+		expvar.Publish("backpressure", expvar.Func(func() any { return Backpressure() }))
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+// debugStats describes every room by ID
+func (rm *RoomManager) debugStats() map[string]RoomDebugStats {
+	rm.mu.RLock()
+	rooms := make([]*Room, 0, len(rm.rooms))
+	for _, room := range rm.rooms {
+		rooms = append(rooms, room)
+	}
+	rm.mu.RUnlock()
+
+	stats := make(map[string]RoomDebugStats, len(rooms))
+	for _, room := range rooms {
+		room.mu.RLock()
+		st := RoomDebugStats{Players: len(room.Clients), BroadcastQueue: len(room.broadcast)}
+		for _, client := range room.Clients {
+			queued := len(client.send)
+			st.SendQueueTotal += queued
+			st.SendQueueMax = max(st.SendQueueMax, queued)
+		}
+		room.mu.RUnlock()
+		stats[room.ID] = st
+	}
+	return stats
+}
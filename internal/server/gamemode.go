@@ -0,0 +1,180 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// GameMode is an opt-in game played by the players in a room who join it
+// (hide-and-seek, tag, ...). A room runs at most one mode at a time, and every
+// method is called with the room's lock held.
+type GameMode interface {
+	// Join adds a player, returning an error if they can't join right now
+	Join(username string) error
+	// Leave removes a player (they quit the mode or disconnected)
+	Leave(username string)
+	// Tick advances timers and proximity checks. It returns announcements for
+	// everyone and whether the mode has ended.
+	Tick(now time.Time, positions map[string][2]int) (events []string, over bool)
+	// HiddenFrom returns the players that must be left out of viewer's state
+	HiddenFrom(viewer string, positions map[string][2]int) []string
+	// CanMove reports whether the player is allowed to move at now
+	CanMove(username string, now time.Time) bool
+	// State returns the mode's state for clients
+	State() protocol.GameModeState
+}
+
+// gameModeFactories maps a mode name to its constructor, which makes its random picks
+// with rng; the starter joins automatically
+var gameModeFactories = map[string]func(now time.Time, rng *seededRand) GameMode{
+	protocol.GameModeHideAndSeek: newHideAndSeek,
+	protocol.GameModeTag:         newTag,
+}
+
+// gameModeNames are the human-readable names used in announcements
+var gameModeNames = map[string]string{
+	protocol.GameModeHideAndSeek: "hide-and-seek",
+	protocol.GameModeTag:         "tag",
+}
+
+// HandleGameMode starts, joins or leaves the room's game mode
+func (r *Room) HandleGameMode(client *Client, mode, action string) {
+	name := gameModeNames[mode]
+	factory, ok := gameModeFactories[mode]
+	if !ok {
+		sendError(client, fmt.Sprintf("Unknown game mode %q", mode))
+		return
+	}
+
+	r.mu.Lock()
+	var announcement string
+	var err error
+	switch action {
+	case "start":
+		if r.mode != nil {
+			err = fmt.Errorf("A game of %s is already running", gameModeNames[r.mode.State().Mode])
+			break
+		}
+		r.mode = factory(r.clock.Now(), r.rng)
+		err = r.mode.Join(client.Username)
+		announcement = fmt.Sprintf("🎲 %s started %s! Type /%s join to play.", client.Username, name, mode)
+
+	case "join":
+		if r.mode == nil || r.mode.State().Mode != mode {
+			err = fmt.Errorf("There's no %s game to join - start one with /%s start", name, mode)
+			break
+		}
+		err = r.mode.Join(client.Username)
+
+	case "leave":
+		if r.mode == nil || r.mode.State().Mode != mode {
+			err = fmt.Errorf("You're not playing %s", name)
+			break
+		}
+		r.mode.Leave(client.Username)
+
+	default:
+		err = fmt.Errorf("Unknown action %q", action)
+	}
+	r.mu.Unlock()
+
+	if err != nil {
+		sendError(client, err.Error())
+		return
+	}
+	if announcement != "" {
+		r.postNotice(announcement)
+	}
+}
+
+// leaveGameMode removes a disconnected player from the running mode
+func (r *Room) leaveGameMode(username string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mode != nil {
+		r.mode.Leave(username)
+	}
+}
+
+// tickGameModeLocked advances the running mode and returns its announcements, its
+// client state and the players hidden from each viewer; r.mu must be held
+func (r *Room) tickGameModeLocked(now time.Time) ([]string, *protocol.GameModeState, map[string][]string) {
+	if r.mode == nil {
+		return nil, nil, nil
+	}
+
+	positions := make(map[string][2]int, len(r.Clients))
+	for _, client := range r.Clients {
+		x, y := parsePos(client.Pos)
+		positions[client.Username] = [2]int{x, y}
+	}
+
+	events, over := r.mode.Tick(now, positions)
+	if over {
+		r.mode = nil
+		return events, nil, nil
+	}
+
+	hidden := make(map[string][]string)
+	for username := range positions {
+		if names := r.mode.HiddenFrom(username, positions); len(names) > 0 {
+			hidden[username] = names
+		}
+	}
+	state := r.mode.State()
+	return events, &state, hidden
+}
+
+// canMoveLocked reports whether the running mode lets the player move at now; r.mu must be held
+func (r *Room) canMoveLocked(username string, now time.Time) bool {
+	return r.mode == nil || r.mode.CanMove(username, now)
+}
+
+// sendStateFiltered sends the tick state to every client, leaving the hidden players
+// out for the viewers they're hidden from. It must only be called from the Run loop.
+func (r *Room) sendStateFiltered(state protocol.KuluchifiedStatePayload, hidden map[string][]string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// PosToUsername is the room's own map, which moves write to, so it's encoded from a copy
+	posToUsername := make(map[string]string, len(state.GameState.PosToUsername))
+	for pos, username := range state.GameState.PosToUsername {
+		posToUsername[pos] = username
+	}
+	state.GameState.PosToUsername = posToUsername
+
+	common, _ := protocol.EncodeMessage(protocol.MsgKuluchifiedState, state)
+	r.record(protocol.JournalOut, "", common)
+	for _, client := range r.Clients {
+		msg := common
+		if names := hidden[client.Username]; len(names) > 0 {
+			msg = encodeStateWithout(state, names)
+			r.record(protocol.JournalOut, client.Username, msg)
+		}
+		client.deliverState(msg)
+	}
+}
+
+// encodeStateWithout encodes the tick state with the given players removed
+func encodeStateWithout(state protocol.KuluchifiedStatePayload, names []string) []byte {
+	players := make(map[string]protocol.Player, len(state.Players))
+	for username, player := range state.Players {
+		players[username] = player
+	}
+	posToUsername := make(map[string]string, len(state.GameState.PosToUsername))
+	for pos, username := range state.GameState.PosToUsername {
+		posToUsername[pos] = username
+	}
+	for _, name := range names {
+		delete(posToUsername, players[name].Pos)
+		delete(players, name)
+	}
+
+	state.Players = players
+	state.GameState.Players = players
+	state.GameState.PosToUsername = posToUsername
+	msg, _ := protocol.EncodeMessage(protocol.MsgKuluchifiedState, state)
+	return msg
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// SetGhost turns ghost mode on or off for a player in the room. A ghost walks through
+// walls and players, doesn't block anyone, and is left out of everyone else's state, so
+// admins can look around the map without being seen.
+func (r *Room) SetGhost(username string, on bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	client := r.clientByUsernameLocked(username)
+	if client == nil {
+		return fmt.Errorf("%s isn't in room %s", username, r.ID)
+	}
+	if client.ghost == on {
+		return nil
+	}
+	client.ghost = on
+
+	x, y := parsePos(client.Pos)
+	if on {
+		if r.GameState.PosToUsername[client.Pos] == client.Username {
+			delete(r.GameState.PosToUsername, client.Pos)
+		}
+		r.spatial.remove(client)
+	} else {
+		// Back where they are, even if that's inside a wall, so they can walk out of it
+		r.GameState.PosToUsername[client.Pos] = client.Username
+		r.spatial.move(client, x, y)
+	}
+	log.Printf("Ghost mode %s for %s in room %s", onOff(on), username, r.ID)
+	return nil
+}
+
+// ghostsLocked returns the usernames of the ghosts in the room; r.mu must be held
+func (r *Room) ghostsLocked() []string {
+	var ghosts []string
+	for _, client := range r.Clients {
+		if client.ghost {
+			ghosts = append(ghosts, client.Username)
+		}
+	}
+	return ghosts
+}
+
+// hideGhosts adds the ghosts to what every other player has hidden from them
+func hideGhosts(hidden map[string][]string, players map[string]protocol.Player, ghosts []string) map[string][]string {
+	if len(ghosts) == 0 {
+		return hidden
+	}
+	if hidden == nil {
+		hidden = make(map[string][]string)
+	}
+	for viewer := range players {
+		for _, ghost := range ghosts {
+			if ghost != viewer {
+				hidden[viewer] = append(hidden[viewer], ghost)
+			}
+		}
+	}
+	return hidden
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// heatmapSampleEvery is how often a room records where its players are standing
+const heatmapSampleEvery = 10 * time.Second
+
+// Heatmap counts how often players were seen on each tile of a room, to show which parts
+// of the map get used. It only has counts, never who was standing there.
+type Heatmap struct {
+	Since       time.Time     `json:"since"`
+	SampleEvery string        `json:"sample_every"`
+	Samples     int           `json:"samples"` // Times positions were recorded
+	Tiles       []HeatmapTile `json:"tiles"`   // Tiles anyone was seen on, most visited first
+}
+
+// HeatmapTile is how many samples found a player on a tile
+type HeatmapTile struct {
+	X     int `json:"x"`
+	Y     int `json:"y"`
+	Count int `json:"count"`
+}
+
+// heatmap is a room's running tile counts
+type heatmap struct {
+	counts  map[[2]int]int // {x, y} -> samples with a player there
+	samples int
+	since   time.Time
+	last    time.Time
+}
+
+// sampleHeatLocked records where the players are, if a sample is due; r.mu must be held
+func (r *Room) sampleHeatLocked(now time.Time) {
+	h := &r.heat
+	if now.Sub(h.last) < heatmapSampleEvery {
+		return
+	}
+	if h.counts == nil {
+		h.counts = make(map[[2]int]int)
+		h.since = now
+	}
+	h.last = now
+	h.samples++
+	for _, client := range r.Clients {
+		if client.ghost {
+			continue
+		}
+		x, y := parsePos(client.Pos)
+		h.counts[[2]int{x, y}]++
+	}
+}
+
+// Heatmap returns the room's tile visit counts since the server started
+func (r *Room) Heatmap() Heatmap {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	h := Heatmap{
+		Since:       r.heat.since,
+		SampleEvery: heatmapSampleEvery.String(),
+		Samples:     r.heat.samples,
+		Tiles:       make([]HeatmapTile, 0, len(r.heat.counts)),
+	}
+	for tile, count := range r.heat.counts {
+		h.Tiles = append(h.Tiles, HeatmapTile{X: tile[0], Y: tile[1], Count: count})
+	}
+	slices.SortFunc(h.Tiles, func(a, b HeatmapTile) int {
+		return cmp.Or(b.Count-a.Count, a.Y-b.Y, a.X-b.X)
+	})
+	return h
+}
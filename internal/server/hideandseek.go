@@ -0,0 +1,201 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+const (
+	hideSeekJoinWindow   = 30 * time.Second // Lobby time for players to join
+	hideSeekHideTime     = 30 * time.Second // Seeker is frozen while everyone hides
+	hideSeekSeekTime     = 3 * time.Minute  // Time the seeker has to find everyone
+	hideSeekRevealRadius = 8                // Hiders this close show up on the seeker's screen
+	hideSeekTagRadius    = 3                // Hiders this close are found
+)
+
+// hideAndSeek is a GameMode where one seeker has to find everyone else. Hiders are
+// left out of the seeker's state until they're within hideSeekRevealRadius.
+type hideAndSeek struct {
+	phase   string // "lobby", "hiding" or "seeking"
+	endsAt  time.Time
+	seeker  string
+	players []string        // Everyone who joined, in join order
+	found   map[string]bool // Hider -> found by the seeker
+	rng     *seededRand     // Picks the seeker
+}
+
+func newHideAndSeek(now time.Time, rng *seededRand) GameMode {
+	return &hideAndSeek{
+		phase:  "lobby",
+		endsAt: now.Add(hideSeekJoinWindow),
+		found:  make(map[string]bool),
+		rng:    rng,
+	}
+}
+
+func (h *hideAndSeek) Join(username string) error {
+	if h.phase != "lobby" {
+		return errors.New("This round has already started - wait for the next one")
+	}
+	for _, player := range h.players {
+		if player == username {
+			return errors.New("You've already joined")
+		}
+	}
+	h.players = append(h.players, username)
+	return nil
+}
+
+func (h *hideAndSeek) Leave(username string) {
+	for i, player := range h.players {
+		if player == username {
+			h.players = append(h.players[:i], h.players[i+1:]...)
+			break
+		}
+	}
+	delete(h.found, username)
+}
+
+func (h *hideAndSeek) Tick(now time.Time, positions map[string][2]int) ([]string, bool) {
+	// Players who disconnected without leaving are dropped
+	for _, player := range append([]string(nil), h.players...) {
+		if _, online := positions[player]; !online {
+			h.Leave(player)
+		}
+	}
+
+	switch h.phase {
+	case "lobby":
+		if now.Before(h.endsAt) {
+			return nil, false
+		}
+		if len(h.players) < 2 {
+			return []string{"🙈 Not enough players joined hide-and-seek. Maybe next time!"}, true
+		}
+		h.seeker = h.players[h.rng.Intn(len(h.players))]
+		h.phase = "hiding"
+		h.endsAt = now.Add(hideSeekHideTime)
+		return []string{fmt.Sprintf("🙈 %s is the seeker! Everyone else has %d seconds to hide.",
+			h.seeker, int(hideSeekHideTime.Seconds()))}, false
+
+	case "hiding":
+		if !h.seekerPresent() {
+			return []string{"🙈 The seeker left - hide-and-seek is cancelled."}, true
+		}
+		if now.Before(h.endsAt) {
+			return nil, false
+		}
+		h.phase = "seeking"
+		h.endsAt = now.Add(hideSeekSeekTime)
+		return []string{fmt.Sprintf("👀 Ready or not, here %s comes!", h.seeker)}, false
+
+	case "seeking":
+		if !h.seekerPresent() {
+			return []string{"🙈 The seeker left - the hiders win!"}, true
+		}
+
+		var events []string
+		seekerPos := positions[h.seeker]
+		for _, hider := range h.hiders() {
+			pos := positions[hider]
+			if !h.found[hider] && chebyshev(seekerPos, pos) <= hideSeekTagRadius {
+				h.found[hider] = true
+				events = append(events, fmt.Sprintf("👀 %s found %s!", h.seeker, hider))
+			}
+		}
+
+		remaining := h.remaining()
+		if len(remaining) == 0 {
+			return append(events, fmt.Sprintf("🏆 %s found everyone and wins hide-and-seek!", h.seeker)), true
+		}
+		if !now.Before(h.endsAt) {
+			return append(events, fmt.Sprintf("🏆 Time's up - the hiders win! %s never found %s.",
+				h.seeker, strings.Join(remaining, ", "))), true
+		}
+		return events, false
+	}
+	return nil, false
+}
+
+func (h *hideAndSeek) HiddenFrom(viewer string, positions map[string][2]int) []string {
+	if viewer != h.seeker || h.phase == "lobby" {
+		return nil
+	}
+
+	var hidden []string
+	for _, hider := range h.remaining() {
+		if chebyshev(positions[viewer], positions[hider]) > hideSeekRevealRadius {
+			hidden = append(hidden, hider)
+		}
+	}
+	return hidden
+}
+
+func (h *hideAndSeek) CanMove(username string, now time.Time) bool {
+	return !(h.phase == "hiding" && username == h.seeker)
+}
+
+func (h *hideAndSeek) State() protocol.GameModeState {
+	roles := make(map[string]string, len(h.players))
+	for _, player := range h.players {
+		switch {
+		case h.phase == "lobby":
+			roles[player] = "joined"
+		case player == h.seeker:
+			roles[player] = "seeker"
+		case h.found[player]:
+			roles[player] = "found"
+		default:
+			roles[player] = "hider"
+		}
+	}
+
+	return protocol.GameModeState{
+		Mode:   protocol.GameModeHideAndSeek,
+		Phase:  h.phase,
+		EndsAt: h.endsAt.Unix(),
+		Roles:  roles,
+	}
+}
+
+// hiders returns everyone except the seeker
+func (h *hideAndSeek) hiders() []string {
+	var hiders []string
+	for _, player := range h.players {
+		if player != h.seeker {
+			hiders = append(hiders, player)
+		}
+	}
+	return hiders
+}
+
+// remaining returns the hiders that haven't been found yet, sorted
+func (h *hideAndSeek) remaining() []string {
+	var remaining []string
+	for _, hider := range h.hiders() {
+		if !h.found[hider] {
+			remaining = append(remaining, hider)
+		}
+	}
+	sort.Strings(remaining)
+	return remaining
+}
+
+func (h *hideAndSeek) seekerPresent() bool {
+	for _, player := range h.players {
+		if player == h.seeker {
+			return true
+		}
+	}
+	return false
+}
+
+// chebyshev returns the Chebyshev distance between two {x, y} positions
+func chebyshev(a, b [2]int) int {
+	return max(abs(a[0]-b[0]), abs(a[1]-b[1]))
+}
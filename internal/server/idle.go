@@ -0,0 +1,61 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// idleWarningLead is how long before an idle kick the client gets a warning
+const idleWarningLead = time.Minute
+
+// idleClientsLocked returns the clients to warn about an upcoming idle kick and the
+// clients to kick now; r.mu must be held
+func (r *Room) idleClientsLocked() (warn, kick []*Client) {
+	if r.config.IdleKick <= 0 {
+		return nil, nil
+	}
+
+	for _, client := range r.Clients {
+		idle := client.idleFor()
+		switch {
+		case idle >= r.config.IdleKick:
+			kick = append(kick, client)
+		case idle >= r.config.IdleKick-idleWarningLead:
+			if !client.idleWarned {
+				client.idleWarned = true
+				warn = append(warn, client)
+			}
+		default:
+			client.idleWarned = false
+		}
+	}
+	return warn, kick
+}
+
+// warnIdle tells a client it's about to be disconnected for inactivity
+func (r *Room) warnIdle(client *Client) {
+	remaining := r.config.IdleKick - client.idleFor()
+	msg, _ := protocol.EncodeMessage(protocol.MsgIdleWarning, protocol.IdleWarningPayload{
+		Seconds: int(remaining.Seconds()),
+	})
+	client.deliver(msg)
+}
+
+// kickIdle disconnects a client that has been idle too long. It must only be called
+// from the Run loop since it unregisters the client directly.
+func (r *Room) kickIdle(client *Client) {
+	msg, _ := protocol.EncodeMessage(protocol.MsgError, protocol.ErrorPayload{
+		Message: fmt.Sprintf("Disconnected after %s of inactivity", r.config.IdleKick),
+	})
+	client.deliver(msg)
+
+	log.Printf("Kicking idle player %s from room %s", client.Username, r.ID)
+	// The connection closes once the message above is written, and the client's
+	// readPump then finds it already gone from the room
+	r.handleUnregister(client)
+	r.forfeitMiniGames(client.Username)
+	r.leaveGameMode(client.Username)
+}
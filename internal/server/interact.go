@@ -0,0 +1,154 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// interactRange is how close (Chebyshev distance from the avatar center) a player must be to use an object
+const interactRange = 3
+
+// Interactable is a map tile that responds to MsgInteract
+type Interactable struct {
+	ID       string
+	Name     string
+	X        int
+	Y        int
+	Glyph    string
+	Flavor   []string      // One is picked at random and sent to the user
+	Emote    string        // Broadcast to the room, %s is replaced with the username
+	Cooldown time.Duration // Per-user cooldown before the object can be used again
+}
+
+// interactables are placed on wall tiles that face a hallway so they never block movement
+var interactables = []Interactable{
+	{
+		ID:    "coffee",
+		Name:  "Coffee Machine",
+		X:     30,
+		Y:     42,
+		Glyph: "C",
+		Flavor: []string{
+			"The machine gurgles and hands you a suspiciously strong espresso.",
+			"You got a latte. The foam art looks like a binary tree.",
+			"Out of oat milk again. You settle for black coffee.",
+		},
+		Emote:    "%s made coffee ☕",
+		Cooldown: 30 * time.Second,
+	},
+	{
+		ID:    "vending",
+		Name:  "Vending Machine",
+		X:     90,
+		Y:     37,
+		Glyph: "V",
+		Flavor: []string{
+			"A bag of chips drops... and gets stuck. Classic.",
+			"You grab a granola bar. Fuel for the next PR.",
+			"The machine eats your dollar and gives you nothing. Rude.",
+		},
+		Emote:    "%s raided the vending machine 🍫",
+		Cooldown: 30 * time.Second,
+	},
+	{
+		ID:    "fountain",
+		Name:  "Water Fountain",
+		X:     60,
+		Y:     42,
+		Glyph: "F",
+		Flavor: []string{
+			"Refreshingly cold. Hydration is important.",
+			"The water pressure nearly knocks you over.",
+		},
+		Emote:    "%s stopped for a sip of water 💧",
+		Cooldown: 15 * time.Second,
+	},
+}
+
+// mapObjects returns the interactables in protocol format for the client
+func mapObjects() []protocol.MapObject {
+	objects := make([]protocol.MapObject, len(interactables))
+	for i, obj := range interactables {
+		objects[i] = protocol.MapObject{
+			ID:    obj.ID,
+			Name:  obj.Name,
+			Pos:   fmt.Sprintf("%d:%d", obj.Y, obj.X),
+			Glyph: obj.Glyph,
+		}
+	}
+	return objects
+}
+
+// nearestInteractable returns the closest object within interactRange of (x, y), or nil
+func nearestInteractable(x, y int) *Interactable {
+	var nearest *Interactable
+	bestDistance := interactRange + 1
+	for i := range interactables {
+		obj := &interactables[i]
+		distance := max(abs(obj.X-x), abs(obj.Y-y))
+		if distance < bestDistance {
+			nearest = obj
+			bestDistance = distance
+		}
+	}
+	return nearest
+}
+
+// HandleInteract uses the object next to the client, replying with flavor text and
+// broadcasting an emote to the room
+func (r *Room) HandleInteract(client *Client) {
+	r.mu.Lock()
+	x, y := parsePos(client.Pos)
+
+	// NPCs take priority over objects so you can always talk to someone next to the coffee machine
+	if n := r.nearestNPCLocked(x, y); n != nil {
+		r.mu.Unlock()
+		r.talkToNPC(client, n)
+		return
+	}
+
+	obj := nearestInteractable(x, y)
+	if obj == nil {
+		r.mu.Unlock()
+		sendError(client, "There's nothing here to interact with")
+		return
+	}
+
+	// Enforce a short per-user cooldown so the emote can't be spammed
+	cooldownKey := client.Username + ":" + obj.ID
+	now := r.clock.Now()
+	if until, ok := r.interactCooldowns[cooldownKey]; ok && now.Before(until) {
+		r.mu.Unlock()
+		msg, _ := protocol.EncodeMessage(protocol.MsgInteractResult, protocol.InteractResultPayload{
+			Object:  obj.Name,
+			Message: fmt.Sprintf("The %s needs a moment. Try again in %ds.", obj.Name, int(until.Sub(now).Seconds())+1),
+		})
+		client.deliver(msg)
+		return
+	}
+	r.interactCooldowns[cooldownKey] = now.Add(obj.Cooldown)
+	r.mu.Unlock()
+
+	result, _ := protocol.EncodeMessage(protocol.MsgInteractResult, protocol.InteractResultPayload{
+		Object:  obj.Name,
+		Message: obj.Flavor[r.rng.Intn(len(obj.Flavor))],
+	})
+	client.deliver(result)
+
+	emote, _ := protocol.EncodeMessage(protocol.MsgEmote, protocol.EmotePayload{
+		Username:  client.Username,
+		Message:   fmt.Sprintf(obj.Emote, client.Username),
+		Timestamp: now.Unix(),
+	})
+	r.broadcast <- emote
+}
+
+// abs returns the absolute value of an integer
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
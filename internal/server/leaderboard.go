@@ -0,0 +1,174 @@
+package server
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+const leaderboardSize = 10 // Entries sent to clients
+
+// LeaderboardStats are a player's treasure hunt results
+type LeaderboardStats struct {
+	Wins           int   `json:"wins"`
+	FastestSolveMs int64 `json:"fastest_solve_ms"` // 0 until the first win
+	Streak         int   `json:"streak"`           // Riddles won in a row, reset when someone else solves one or time runs out
+	BestStreak     int   `json:"best_streak"`
+	Guesses        int   `json:"guesses"` // Guesses made at riddles, right or wrong
+}
+
+// recordTreasureHuntWin credits a riddle solved in took to the player, returning their
+// win streak including it
+func recordTreasureHuntWin(store *Store, username string, took time.Duration) int {
+	var streak int
+	err := store.Update(func(d *StoreData) {
+		if d.LastHuntWinner != username {
+			breakStreakLocked(d)
+		}
+		d.LastHuntWinner = username
+		creditWinLocked(d, username, took, true)
+		streak = d.Leaderboard[username].Streak
+	})
+	if err != nil {
+		log.Printf("Error saving treasure hunt win: %v", err)
+	}
+	return streak
+}
+
+// treasureHuntStreak returns the player's running win streak, 0 unless they won the
+// last riddle
+func treasureHuntStreak(store *Store, username string) int {
+	var streak int
+	store.View(func(d *StoreData) {
+		if stats, ok := d.Leaderboard[username]; ok && d.LastHuntWinner == username {
+			streak = stats.Streak
+		}
+	})
+	return streak
+}
+
+// recordTreasureHuntTrivia credits a multiple-choice round to everyone who answered it
+// right. The fastest counts as its winner, so only their streak carries on.
+func recordTreasureHuntTrivia(store *Store, correct []huntChoice) {
+	err := store.Update(func(d *StoreData) {
+		if d.LastHuntWinner != correct[0].username {
+			breakStreakLocked(d)
+		}
+		d.LastHuntWinner = correct[0].username
+		for i, answered := range correct {
+			creditWinLocked(d, answered.username, answered.took, i == 0)
+		}
+	})
+	if err != nil {
+		log.Printf("Error saving treasure hunt trivia: %v", err)
+	}
+}
+
+// creditWinLocked adds a win in took to the player's stats, extending their streak if
+// streak is set; the store's lock must be held
+func creditWinLocked(d *StoreData, username string, took time.Duration, streak bool) {
+	stats, ok := d.Leaderboard[username]
+	if !ok {
+		stats = &LeaderboardStats{}
+		d.Leaderboard[username] = stats
+	}
+	stats.Wins++
+	if streak {
+		stats.Streak++
+		stats.BestStreak = max(stats.BestStreak, stats.Streak)
+	}
+	if ms := took.Milliseconds(); stats.FastestSolveMs == 0 || ms < stats.FastestSolveMs {
+		stats.FastestSolveMs = ms
+	}
+}
+
+// recordTreasureHuntGuess counts a guess the player made at a riddle
+func recordTreasureHuntGuess(store *Store, username string) {
+	err := store.Update(func(d *StoreData) {
+		stats, ok := d.Leaderboard[username]
+		if !ok {
+			stats = &LeaderboardStats{}
+			d.Leaderboard[username] = stats
+		}
+		stats.Guesses++
+	})
+	if err != nil {
+		log.Printf("Error saving treasure hunt guess: %v", err)
+	}
+}
+
+// refundTreasureHuntGuesses takes back guesses made at a riddle that was voided
+func refundTreasureHuntGuesses(store *Store, attempts map[string]int) {
+	if len(attempts) == 0 {
+		return
+	}
+	err := store.Update(func(d *StoreData) {
+		for username, n := range attempts {
+			if stats, ok := d.Leaderboard[username]; ok {
+				stats.Guesses = max(stats.Guesses-n, 0)
+			}
+		}
+	})
+	if err != nil {
+		log.Printf("Error refunding treasure hunt guesses: %v", err)
+	}
+}
+
+// recordTreasureHuntMiss breaks the running streak when nobody solves a riddle in time
+func recordTreasureHuntMiss(store *Store) {
+	if err := store.Update(breakStreakLocked); err != nil {
+		log.Printf("Error saving treasure hunt miss: %v", err)
+	}
+}
+
+// breakStreakLocked ends the last winner's streak; the store's lock must be held
+func breakStreakLocked(d *StoreData) {
+	if stats, ok := d.Leaderboard[d.LastHuntWinner]; ok {
+		stats.Streak = 0
+	}
+	d.LastHuntWinner = ""
+}
+
+// leaderboardEntries returns the top players by wins, then fastest solve
+func leaderboardEntries(store *Store) []protocol.LeaderboardEntry {
+	var entries []protocol.LeaderboardEntry
+	store.View(func(d *StoreData) {
+		for username, stats := range d.Leaderboard {
+			if stats.Wins == 0 {
+				continue // Only guessed so far
+			}
+			entries = append(entries, protocol.LeaderboardEntry{
+				Username:       username,
+				Wins:           stats.Wins,
+				FastestSolveMs: stats.FastestSolveMs,
+				Streak:         stats.Streak,
+				BestStreak:     stats.BestStreak,
+				Guesses:        stats.Guesses,
+			})
+		}
+	})
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Wins != entries[j].Wins {
+			return entries[i].Wins > entries[j].Wins
+		}
+		if entries[i].FastestSolveMs != entries[j].FastestSolveMs {
+			return entries[i].FastestSolveMs < entries[j].FastestSolveMs
+		}
+		return entries[i].Username < entries[j].Username
+	})
+	if len(entries) > leaderboardSize {
+		entries = entries[:leaderboardSize]
+	}
+	return entries
+}
+
+// handleLeaderboardRequest sends the leaderboard to the client that asked for it
+func (s *Server) handleLeaderboardRequest(c *Client) {
+	msg, _ := protocol.EncodeMessage(protocol.MsgLeaderboardResponse, protocol.LeaderboardResponsePayload{
+		Entries: leaderboardEntries(s.store),
+	})
+	c.deliver(msg)
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// RiddleProvider generates the riddles, trivia and scavenger hunts played on the server
+type RiddleProvider interface {
+	Name() string
+	// GenerateRiddle writes a riddle about topic at the given difficulty
+	GenerateRiddle(topic, difficulty string) (*GeminiRiddle, error)
+	// GenerateTreasureMap writes a themed scavenger hunt. Every clue's location is one of
+	// the given location names, so the server can check where players stand.
+	GenerateTreasureMap(theme string, locations []string, clueCount int) (*TreasureMap, error)
+	// GenerateTriviaQuestion writes a multiple-choice CS question with choiceCount choices
+	GenerateTriviaQuestion(choiceCount int) (*TriviaQuestion, error)
+}
+
+// errNoLLM is returned by the static provider, which leaves every game on its built-in content
+var errNoLLM = errors.New("no language model configured")
+
+// llmHTTPClient is shared by the providers that call a model over HTTP
+var llmHTTPClient = &http.Client{Timeout: 60 * time.Second}
+
+// provider is what the package-level Generate functions use; NewServer sets it from Config
+var provider RiddleProvider = StaticProvider{}
+
+// GeminiRiddle is a treasure hunt riddle. The name predates other providers; it's kept
+// because saved hunts use it.
+type GeminiRiddle struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+	Hint     string `json:"hint"`
+
+	Category   string `json:"category"`   // riddleCategory ID
+	Difficulty string `json:"difficulty"` // riddleEasy, riddleMedium or riddleHard
+
+	Choices []string `json:"choices,omitempty"` // Options of a multiple-choice trivia round, one of them the Answer
+}
+
+type MapClue struct {
+	Location string `json:"location"`
+	Riddle   string `json:"riddle"`
+}
+
+type TreasureMap struct {
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Clues       []MapClue `json:"clues"`
+}
+
+type TriviaQuestion struct {
+	Question string   `json:"question"`
+	Choices  []string `json:"choices"`
+	Answer   int      `json:"answer"` // Index into Choices
+}
+
+// NewRiddleProvider builds the provider named in cfg. An empty name picks Gemini or
+// OpenAI if their API key is set in the environment, and the static provider otherwise.
+func NewRiddleProvider(cfg LLMConfig) (RiddleProvider, error) {
+	geminiKey, openAIKey := os.Getenv("GEMINI_API_KEY"), os.Getenv("OPENAI_API_KEY")
+
+	name := cfg.Provider
+	if name == "" {
+		switch {
+		case geminiKey != "":
+			name = "gemini"
+		case openAIKey != "":
+			name = "openai"
+		default:
+			name = "static"
+		}
+	}
+
+	switch name {
+	case "gemini":
+		if geminiKey == "" {
+			return nil, errors.New("gemini needs GEMINI_API_KEY set")
+		}
+		return newGeminiProvider(geminiKey, cfg.Model), nil
+	case "openai":
+		if openAIKey == "" && cfg.URL == "" {
+			return nil, errors.New("openai needs OPENAI_API_KEY set, or -llm-url for a compatible local server")
+		}
+		return newOpenAIProvider(openAIKey, cfg.Model, cfg.URL), nil
+	case "ollama":
+		return newOllamaProvider(cfg.Model, cfg.URL), nil
+	case "static":
+		return StaticProvider{}, nil
+	}
+	return nil, fmt.Errorf("unknown LLM provider %q (want gemini, openai, ollama or static)", name)
+}
+
+// SetRiddleProvider picks the provider for everything generated from now on
+func SetRiddleProvider(p RiddleProvider) {
+	provider = p
+	log.Printf("Generating riddles with %s", p.Name())
+}
+
+func GenerateRiddle(topic, difficulty string) (*GeminiRiddle, error) {
+	return provider.GenerateRiddle(topic, difficulty)
+}
+
+func GenerateTreasureMap(theme string, locations []string, clueCount int) (*TreasureMap, error) {
+	return provider.GenerateTreasureMap(theme, locations, clueCount)
+}
+
+func GenerateTriviaQuestion(choiceCount int) (*TriviaQuestion, error) {
+	return provider.GenerateTriviaQuestion(choiceCount)
+}
+
+// StaticProvider generates nothing, so the games use their built-in riddles and questions.
+// It's for deployments without a model.
+type StaticProvider struct{}
+
+func (StaticProvider) Name() string { return "static riddles" }
+
+func (StaticProvider) GenerateRiddle(string, string) (*GeminiRiddle, error) { return nil, errNoLLM }
+
+func (StaticProvider) GenerateTreasureMap(string, []string, int) (*TreasureMap, error) {
+	return nil, errNoLLM
+}
+
+func (StaticProvider) GenerateTriviaQuestion(int) (*TriviaQuestion, error) { return nil, errNoLLM }
+
+// promptProvider implements RiddleProvider for any model that answers a text prompt;
+// the Gemini, OpenAI and Ollama providers differ only in how they send it
+type promptProvider struct {
+	name     string
+	complete func(prompt string) (string, error)
+}
+
+func (p *promptProvider) Name() string { return p.name }
+
+func (p *promptProvider) GenerateRiddle(topic, difficulty string) (*GeminiRiddle, error) {
+	prompt := fmt.Sprintf(`Generate a short, fun %s riddle about %s.
+	The answer should be one or two words.
+	Return ONLY a JSON object with three fields: "question", "answer", and "hint". 
+	Do not wrap in markdown code blocks.`, difficulty, topic)
+
+	text, err := p.complete(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var riddle GeminiRiddle
+	if err := decodeModelJSON(text, &riddle); err != nil {
+		return nil, fmt.Errorf("failed to parse riddle JSON: %w", err)
+	}
+
+	return &riddle, nil
+}
+
+func (p *promptProvider) GenerateTreasureMap(theme string, locations []string, clueCount int) (*TreasureMap, error) {
+	systemPrompt := `You are a creative treasure hunt generator. 
+	Generate a fun and engaging treasure map with clues based on the theme provided.
+	Return the response strictly as a JSON object.`
+
+	userPrompt := fmt.Sprintf("%s. Theme: %s. Write exactly %d clues. Each clue's \"location\" must be copied exactly from this list, with no location used twice: %s. The riddle must hint at the location without naming it. Structure: { \"title\": \"...\", \"description\": \"...\", \"clues\": [ {\"location\": \"...\", \"riddle\": \"...\"} ] }",
+		systemPrompt, theme, clueCount, strings.Join(locations, ", "))
+
+	text, err := p.complete(userPrompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var tMap TreasureMap
+	if err := decodeModelJSON(text, &tMap); err != nil {
+		return nil, fmt.Errorf("failed to parse map JSON: %w", err)
+	}
+
+	return &tMap, nil
+}
+
+func (p *promptProvider) GenerateTriviaQuestion(choiceCount int) (*TriviaQuestion, error) {
+	prompt := fmt.Sprintf(`Generate a short multiple-choice trivia question about Computer Science, Programming, or Technology.
+	Give exactly %d choices, each under 30 characters, with exactly one correct.
+	Return ONLY a JSON object with three fields: "question", "choices" (an array of strings), and "answer" (the index of the correct choice).
+	Do not wrap in markdown code blocks.`, choiceCount)
+
+	text, err := p.complete(prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var question TriviaQuestion
+	if err := decodeModelJSON(text, &question); err != nil {
+		return nil, fmt.Errorf("failed to parse trivia JSON: %w", err)
+	}
+	if len(question.Choices) != choiceCount || question.Answer < 0 || question.Answer >= choiceCount {
+		return nil, fmt.Errorf("trivia question has %d choices and answer %d", len(question.Choices), question.Answer)
+	}
+
+	return &question, nil
+}
+
+// decodeModelJSON decodes a model's reply into v, dropping the markdown code fence
+// models like to wrap JSON in
+func decodeModelJSON(text string, v any) error {
+	text = strings.TrimSpace(text)
+	text = strings.TrimPrefix(text, "```json")
+	text = strings.TrimPrefix(text, "```")
+	text = strings.TrimSuffix(text, "```")
+	return json.Unmarshal([]byte(text), v)
+}
@@ -1,15 +1,37 @@
 package server
 
 import (
-	_ "embed"
 	"strings"
+	"sync"
+
+	"github.com/yourusername/always-at-morg/internal/gamemap"
+)
+
+var (
+	tileMap     [gamemap.Height][gamemap.Width]string
+	tileMapErr  error
+	tileMapOnce sync.Once
 )
 
-//go:embed game_assets/map.txt
-var embeddedMap string
+// getRoomNumberMap returns the flood-filled map used to tell which room a tile is in.
+// The tile map keeps the raw characters because movement checks depend on them.
+func getRoomNumberMap() *[gamemap.Height][gamemap.Width]string {
+	roomNumberMap, _ := gamemap.Tiles()
+	return roomNumberMap
+}
+
+// getTileMap returns the map's raw characters, built the first time it's asked for.
+// Every room shares it, so it must never be written to. If it couldn't be built, it's
+// empty and the error says why.
+func getTileMap() (*[gamemap.Height][gamemap.Width]string, error) {
+	tileMapOnce.Do(func() {
+		tileMap, tileMapErr = fillRoomMap()
+	})
+	return &tileMap, tileMapErr
+}
 
 func fillRoomMap() ([250][400]string, error) {
-	lines := strings.Split(embeddedMap, "\n")
+	lines := strings.Split(gamemap.Text(), "\n")
 	var result [250][400]string
 	var mapChars [250][400]rune
 
@@ -0,0 +1,424 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+const (
+	challengeRange  = 10               // Max Chebyshev distance between players to send a challenge
+	challengeExpiry = 60 * time.Second // How long an unanswered invite stays valid
+)
+
+// MiniGame is a game played between two players inside a room
+type MiniGame interface {
+	// Move applies a move by the given player, returning an error if it isn't allowed
+	Move(player, move string) error
+	// Board returns the shared board and whose turn it is ("" if nobody's)
+	Board() (board []string, turn string)
+	// Result reports whether the game is over and who won ("" for a draw)
+	Result() (finished bool, winner string)
+}
+
+// timedMiniGame is a MiniGame with a time limit; Result reports it finished once the deadline passes
+type timedMiniGame interface {
+	Deadline() time.Time
+}
+
+// miniGameFactories maps a game kind to its constructor, which times the game with the
+// room's clock and makes its random picks with rng. Constructors may be slow (trivia asks
+// Gemini for a question), so they're called without holding any lock.
+var miniGameFactories = map[string]func(players [2]string, clock Clock, rng *seededRand) MiniGame{
+	"tictactoe": newTicTacToe,
+	"trivia":    newTriviaBattle,
+}
+
+// miniGameNames are the human-readable names used in announcements
+var miniGameNames = map[string]string{
+	"tictactoe": "tic-tac-toe",
+	"trivia":    "trivia",
+}
+
+type miniGameInvite struct {
+	From    string
+	Kind    string
+	Expires time.Time
+}
+
+type miniGameSession struct {
+	ID      string
+	Kind    string
+	Players [2]string
+	Game    MiniGame
+	Forfeit string // Winner by forfeit when the other player left
+}
+
+// result returns the outcome, taking a forfeit into account
+func (s *miniGameSession) result() (bool, string) {
+	if s.Forfeit != "" {
+		return true, s.Forfeit
+	}
+	return s.Game.Result()
+}
+
+// MiniGameManager tracks invites and running mini-games for a room
+type MiniGameManager struct {
+	mu       sync.Mutex
+	invites  map[string]miniGameInvite   // target username -> pending invite
+	sessions map[string]*miniGameSession // game ID -> session
+	byPlayer map[string]string           // username -> game ID
+}
+
+// NewMiniGameManager creates an empty mini-game manager
+func NewMiniGameManager() *MiniGameManager {
+	return &MiniGameManager{
+		invites:  make(map[string]miniGameInvite),
+		sessions: make(map[string]*miniGameSession),
+		byPlayer: make(map[string]string),
+	}
+}
+
+// HandleMiniGameChallenge invites a nearby player to a game
+func (r *Room) HandleMiniGameChallenge(client *Client, target, kind string) {
+	if kind == "" {
+		kind = "tictactoe"
+	}
+	if _, ok := miniGameFactories[kind]; !ok {
+		sendError(client, fmt.Sprintf("Unknown game %q", kind))
+		return
+	}
+	if target == client.Username {
+		sendError(client, "You can't challenge yourself")
+		return
+	}
+
+	targetClient := r.clientByUsername(target)
+	if targetClient == nil {
+		sendError(client, fmt.Sprintf("%s isn't here", target))
+		return
+	}
+
+	r.mu.RLock()
+	fromX, fromY := parsePos(client.Pos)
+	toX, toY := parsePos(targetClient.Pos)
+	r.mu.RUnlock()
+	if max(abs(fromX-toX), abs(fromY-toY)) > challengeRange {
+		sendError(client, fmt.Sprintf("%s is too far away to challenge", target))
+		return
+	}
+
+	mg := r.miniGames
+	mg.mu.Lock()
+	if _, busy := mg.byPlayer[client.Username]; busy {
+		mg.mu.Unlock()
+		sendError(client, "Finish your current game first")
+		return
+	}
+	if _, busy := mg.byPlayer[target]; busy {
+		mg.mu.Unlock()
+		sendError(client, fmt.Sprintf("%s is already in a game", target))
+		return
+	}
+	mg.invites[target] = miniGameInvite{
+		From:    client.Username,
+		Kind:    kind,
+		Expires: r.clock.Now().Add(challengeExpiry),
+	}
+	mg.mu.Unlock()
+
+	msg, _ := protocol.EncodeMessage(protocol.MsgMiniGameInvite, protocol.MiniGameInvitePayload{
+		From: client.Username,
+		Game: kind,
+	})
+	targetClient.deliver(msg)
+}
+
+// HandleMiniGameRespond accepts or declines the pending invite from a player
+func (r *Room) HandleMiniGameRespond(client *Client, from string, accept bool) {
+	mg := r.miniGames
+	mg.mu.Lock()
+	invite, ok := mg.invites[client.Username]
+	if !ok || (from != "" && invite.From != from) || r.clock.Now().After(invite.Expires) {
+		mg.mu.Unlock()
+		sendError(client, "You don't have a pending challenge")
+		return
+	}
+	delete(mg.invites, client.Username)
+	mg.mu.Unlock()
+
+	// mg.mu is never held while taking r.mu
+	challenger := r.clientByUsername(invite.From)
+	if challenger == nil {
+		return
+	}
+	if !accept {
+		msg, _ := protocol.EncodeMessage(protocol.MsgMiniGameDeclined, protocol.MiniGameDeclinedPayload{
+			By:   client.Username,
+			Game: invite.Kind,
+		})
+		challenger.deliver(msg)
+		return
+	}
+
+	mg.mu.Lock()
+	if _, busy := mg.byPlayer[invite.From]; busy {
+		mg.mu.Unlock()
+		sendError(client, fmt.Sprintf("%s is already in a game", invite.From))
+		return
+	}
+	mg.mu.Unlock()
+
+	players := [2]string{invite.From, client.Username}
+	session := &miniGameSession{
+		ID:      uuid.New().String(),
+		Kind:    invite.Kind,
+		Players: players,
+		Game:    miniGameFactories[invite.Kind](players, r.clock, r.rng),
+	}
+
+	// Either player may have started another game while this one was being set up
+	mg.mu.Lock()
+	for _, player := range players {
+		if _, busy := mg.byPlayer[player]; busy {
+			mg.mu.Unlock()
+			sendError(client, fmt.Sprintf("%s is already in a game", player))
+			return
+		}
+	}
+	mg.sessions[session.ID] = session
+	mg.byPlayer[players[0]] = session.ID
+	mg.byPlayer[players[1]] = session.ID
+	mg.mu.Unlock()
+
+	log.Printf("Mini-game %s started: %s vs %s", invite.Kind, players[0], players[1])
+	r.sendMiniGameState(session, "Game on!")
+}
+
+// HandleMiniGameMove applies a move and ends the game if it's over
+func (r *Room) HandleMiniGameMove(client *Client, gameID, move string) {
+	mg := r.miniGames
+	mg.mu.Lock()
+	session, ok := mg.sessions[gameID]
+	if !ok || mg.byPlayer[client.Username] != gameID {
+		mg.mu.Unlock()
+		sendError(client, "You're not in that game")
+		return
+	}
+	if err := session.Game.Move(client.Username, move); err != nil {
+		mg.mu.Unlock()
+		sendError(client, err.Error())
+		return
+	}
+	finished, _ := session.Game.Result()
+	if finished {
+		mg.endSessionLocked(session)
+	}
+	mg.mu.Unlock()
+
+	r.sendMiniGameState(session, "")
+	if finished {
+		r.announceMiniGameResult(session)
+	}
+}
+
+// finishExpiredMiniGames ends games that finished without a move, such as a trivia battle
+// that ran out of time
+func (r *Room) finishExpiredMiniGames() {
+	mg := r.miniGames
+	mg.mu.Lock()
+	var expired []*miniGameSession
+	for _, session := range mg.sessions {
+		if finished, _ := session.Game.Result(); finished {
+			expired = append(expired, session)
+		}
+	}
+	for _, session := range expired {
+		mg.endSessionLocked(session)
+	}
+	mg.mu.Unlock()
+
+	for _, session := range expired {
+		r.sendMiniGameState(session, "Time's up!")
+		r.announceMiniGameResult(session)
+	}
+}
+
+// forfeitMiniGames ends any game the user is in, awarding it to their opponent
+func (r *Room) forfeitMiniGames(username string) {
+	mg := r.miniGames
+	mg.mu.Lock()
+	delete(mg.invites, username)
+	gameID, ok := mg.byPlayer[username]
+	if !ok {
+		mg.mu.Unlock()
+		return
+	}
+	session := mg.sessions[gameID]
+	session.Forfeit = session.Players[0]
+	if session.Forfeit == username {
+		session.Forfeit = session.Players[1]
+	}
+	mg.endSessionLocked(session)
+	mg.mu.Unlock()
+
+	r.sendMiniGameState(session, fmt.Sprintf("%s left the game", username))
+	r.announceMiniGameResult(session)
+}
+
+// endSessionLocked removes a session; mg.mu must be held
+func (mg *MiniGameManager) endSessionLocked(session *miniGameSession) {
+	delete(mg.sessions, session.ID)
+	for _, player := range session.Players {
+		delete(mg.byPlayer, player)
+	}
+}
+
+// sendMiniGameState sends the shared board to both players
+func (r *Room) sendMiniGameState(session *miniGameSession, message string) {
+	r.miniGames.mu.Lock()
+	board, turn := session.Game.Board()
+	finished, winner := session.result()
+	var endsAt int64
+	if timed, ok := session.Game.(timedMiniGame); ok {
+		endsAt = timed.Deadline().Unix()
+	}
+	r.miniGames.mu.Unlock()
+	if finished {
+		turn = ""
+	}
+
+	msg, _ := protocol.EncodeMessage(protocol.MsgMiniGameState, protocol.MiniGameStatePayload{
+		GameID:   session.ID,
+		Game:     session.Kind,
+		Players:  session.Players,
+		Board:    board,
+		Turn:     turn,
+		Winner:   winner,
+		Finished: finished,
+		Message:  message,
+		EndsAt:   endsAt,
+	})
+	for _, player := range session.Players {
+		if c := r.clientByUsername(player); c != nil {
+			c.deliver(msg)
+		}
+	}
+}
+
+// announceMiniGameResult posts the outcome to the room chat where the game was played
+// and pays out points for playing and winning
+func (r *Room) announceMiniGameResult(session *miniGameSession) {
+	r.miniGames.mu.Lock()
+	_, winner := session.result()
+	r.miniGames.mu.Unlock()
+
+	name := miniGameNames[session.Kind]
+	for _, player := range session.Players {
+		r.awardPoints(player, pointsMiniGamePlayed, "Played "+name)
+	}
+	if winner != "" {
+		r.awardPoints(winner, pointsMiniGameWin, "Won at "+name)
+	}
+
+	var text string
+	switch winner {
+	case "":
+		text = fmt.Sprintf("🤝 %s and %s drew at %s", session.Players[0], session.Players[1], name)
+	case session.Players[0]:
+		text = fmt.Sprintf("🏆 %s beat %s at %s", session.Players[0], session.Players[1], name)
+	default:
+		text = fmt.Sprintf("🏆 %s beat %s at %s", session.Players[1], session.Players[0], name)
+	}
+
+	roomNumber := ""
+	if c := r.clientByUsername(session.Players[0]); c != nil {
+		r.mu.RLock()
+		roomNumber = c.CurrentRoomNumber
+		r.mu.RUnlock()
+	}
+	if roomNumber == "" {
+		// Played in a hallway. Both the Run loop and players' moves end games, so this
+		// broadcasts directly rather than through r.broadcast.
+		r.handleBroadcast(r.notice(text))
+		return
+	}
+	r.chatManager.PostSystemMessage(r.ID, roomNumber, text)
+}
+
+// ---------------------------------------------------------
+// TIC-TAC-TOE
+// ---------------------------------------------------------
+
+// ticTacToeLines are the index triples that win the game
+var ticTacToeLines = [8][3]int{
+	{0, 1, 2}, {3, 4, 5}, {6, 7, 8}, // rows
+	{0, 3, 6}, {1, 4, 7}, {2, 5, 8}, // columns
+	{0, 4, 8}, {2, 4, 6}, // diagonals
+}
+
+type ticTacToe struct {
+	players [2]string
+	board   [9]string // "", "X" or "O"
+	turn    int       // Index into players
+	moves   int
+	winner  string
+}
+
+func newTicTacToe(players [2]string, clock Clock, rng *seededRand) MiniGame {
+	return &ticTacToe{players: players}
+}
+
+// Move places the player's mark on a cell numbered "1"-"9"
+func (t *ticTacToe) Move(player, move string) error {
+	if finished, _ := t.Result(); finished {
+		return errors.New("The game is already over")
+	}
+	if t.players[t.turn] != player {
+		return errors.New("It's not your turn")
+	}
+
+	var cell int
+	if _, err := fmt.Sscanf(move, "%d", &cell); err != nil || cell < 1 || cell > 9 {
+		return errors.New("Pick a cell from 1 to 9")
+	}
+	if t.board[cell-1] != "" {
+		return errors.New("That cell is taken")
+	}
+
+	mark := "X"
+	if t.turn == 1 {
+		mark = "O"
+	}
+	t.board[cell-1] = mark
+	t.moves++
+
+	for _, line := range ticTacToeLines {
+		if t.board[line[0]] == mark && t.board[line[1]] == mark && t.board[line[2]] == mark {
+			t.winner = player
+			return nil
+		}
+	}
+
+	t.turn = 1 - t.turn
+	return nil
+}
+
+func (t *ticTacToe) Board() ([]string, string) {
+	board := make([]string, len(t.board))
+	copy(board, t.board[:])
+
+	if finished, _ := t.Result(); finished {
+		return board, ""
+	}
+	return board, t.players[t.turn]
+}
+
+func (t *ticTacToe) Result() (bool, string) {
+	return t.winner != "" || t.moves == len(t.board), t.winner
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/gamemap"
+)
+
+// Movement limits, so a modified client can't teleport or race across the map
+const (
+	maxMoveStep       = 1  // Tiles a player can move at once, in each direction
+	maxMovesPerSecond = 30 // A little above a held key's repeat rate
+)
+
+// crowdsLocked reports whether moving the client from where it is to x, y would overlap
+// another player's avatar or step further into their personal space. Idle players are
+// left out so an AFK avatar can't wall off a corridor, and moving away from someone is
+// always allowed so nobody gets stuck. r.mu must be held.
+func (r *Room) crowdsLocked(client *Client, x, y int) bool {
+	hallway := r.getRoomNumberFromPosition(x, y) == ""
+	radius := max(gamemap.AvatarReach, r.config.roomConfig().PersonalSpaceAt(hallway))
+	fromX, fromY := parsePos(client.Pos)
+	for _, other := range r.spatial.near(x, y, radius) {
+		if other == client || other.idleFor() >= idleAfter {
+			continue
+		}
+		ox, oy := parsePos(other.Pos)
+		if max(abs(x-ox), abs(y-oy)) < max(abs(fromX-ox), abs(fromY-oy)) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowMoveLocked reports whether the client may step to x, y now: only to a tile next
+// to where it is, and only maxMovesPerSecond times a second. r.mu must be held.
+func (c *Client) allowMoveLocked(x, y int, now time.Time) bool {
+	fromX, fromY := parsePos(c.Pos)
+	if abs(x-fromX) > maxMoveStep || abs(y-fromY) > maxMoveStep {
+		return false
+	}
+
+	if now.Sub(c.moveWindow) >= time.Second {
+		c.moveWindow = now
+		c.movesInWindow = 0
+	}
+	if c.movesInWindow >= maxMovesPerSecond {
+		return false
+	}
+	c.movesInWindow++
+	return true
+}
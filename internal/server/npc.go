@@ -0,0 +1,153 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+const (
+	npcMoveEvery   = 6  // Ticks between NPC steps (~3 tiles per second at 20Hz)
+	npcPauseTicks  = 60 // Ticks an NPC waits at each waypoint
+	npcPersonalGap = 3  // NPCs won't step within this Chebyshev distance of a player
+)
+
+// npc is a server-controlled character that walks a fixed patrol route
+type npc struct {
+	ID        string
+	Name      string // Shown above the avatar (kept short, the client shows 5 characters)
+	Title     string // Used in dialogue, e.g. "Gus the janitor"
+	Avatar    []int
+	X, Y      int
+	Waypoints [][2]int // Patrol route as {x, y}; an NPC with none stays put
+	Lines     []string // Flavor dialogue, one picked at random
+	GivesHint bool     // Shares the current riddle's hint when talked to
+
+	nextWaypoint int
+	pauseTicks   int
+}
+
+// newNPCs creates the NPCs for a room at their starting spots
+func newNPCs() []*npc {
+	return []*npc{
+		{
+			ID:        "janitor",
+			Name:      "Gus",
+			Title:     "Gus the janitor",
+			Avatar:    []int{2, 3, 1},
+			X:         20,
+			Y:         40,
+			Waypoints: [][2]int{{230, 40}, {20, 40}},
+			Lines: []string{
+				"Mind the wet floor. I just mopped that.",
+				"You wouldn't believe what people leave in the 1240 lecture hall.",
+				"Been here since the building opened. Still can't find room 23 half the time.",
+				"If the coffee machine's broken again, it wasn't me.",
+			},
+		},
+		{
+			ID:     "librarian",
+			Name:   "Ruth",
+			Title:  "Ruth the librarian",
+			Avatar: []int{3, 4, 0},
+			X:      58,
+			Y:      51,
+			Lines: []string{
+				"Shh! People are studying.",
+				"The answer is always in a book. Or on Stack Overflow.",
+				"Come back when there's a riddle going - I might know a thing or two.",
+			},
+			GivesHint: true,
+		},
+	}
+}
+
+// moveNPCsLocked advances every NPC one step along its patrol; r.mu must be held
+func (r *Room) moveNPCsLocked() {
+	if r.GameState.Tick%npcMoveEvery != 0 {
+		return
+	}
+
+	for _, n := range r.npcs {
+		if len(n.Waypoints) == 0 {
+			continue
+		}
+		if n.pauseTicks > 0 {
+			n.pauseTicks -= npcMoveEvery
+			continue
+		}
+
+		target := n.Waypoints[n.nextWaypoint]
+		if n.X == target[0] && n.Y == target[1] {
+			n.nextWaypoint = (n.nextWaypoint + 1) % len(n.Waypoints)
+			n.pauseTicks = npcPauseTicks
+			continue
+		}
+
+		nx, ny := n.X+sign(target[0]-n.X), n.Y+sign(target[1]-n.Y)
+		if !r.canAvatarFitAt(nx, ny) || r.playerNearLocked(nx, ny, npcPersonalGap) {
+			continue // Blocked - wait for the way to clear
+		}
+		n.X, n.Y = nx, ny
+	}
+}
+
+// playerNearLocked reports whether any player is within distance of (x, y); r.mu must be held
+func (r *Room) playerNearLocked(x, y, distance int) bool {
+	return len(r.spatial.near(x, y, distance-1)) > 0
+}
+
+// npcStatesLocked returns the NPCs in protocol format; r.mu must be held
+func (r *Room) npcStatesLocked() []protocol.NPC {
+	states := make([]protocol.NPC, len(r.npcs))
+	for i, n := range r.npcs {
+		states[i] = protocol.NPC{
+			ID:     n.ID,
+			Name:   n.Name,
+			Pos:    fmt.Sprintf("%d:%d", n.Y, n.X),
+			Avatar: n.Avatar,
+		}
+	}
+	return states
+}
+
+// nearestNPCLocked returns the closest NPC the player at (x, y) can talk to, or nil; r.mu must be held
+func (r *Room) nearestNPCLocked(x, y int) *npc {
+	var nearest *npc
+	bestDistance := interactRange + 2 // NPCs are 3 wide, so allow one more tile than objects
+	for _, n := range r.npcs {
+		distance := max(abs(n.X-x), abs(n.Y-y))
+		if distance < bestDistance {
+			nearest = n
+			bestDistance = distance
+		}
+	}
+	return nearest
+}
+
+// talkToNPC sends the NPC's reply to the client
+func (r *Room) talkToNPC(client *Client, n *npc) {
+	line := n.Lines[r.rng.Intn(len(n.Lines))]
+	if n.GivesHint {
+		if hint, ok := r.hunt.CurrentHint(); ok {
+			line = fmt.Sprintf("Stuck on the riddle? Between you and me: %s", hint)
+		}
+	}
+
+	msg, _ := protocol.EncodeMessage(protocol.MsgInteractResult, protocol.InteractResultPayload{
+		Object:  n.Title,
+		Message: fmt.Sprintf("%s: \"%s\"", n.Title, line),
+	})
+	client.deliver(msg)
+}
+
+// sign returns -1, 0 or 1 matching the sign of x
+func sign(x int) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"maps"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// How often a room records how many players are in each building room, and how far back
+// it keeps those records
+const (
+	occupancySampleEvery = 5 * time.Minute
+	occupancyKeep        = 24 * time.Hour
+)
+
+// OccupancySample is how many players were in each building room at one time
+type OccupancySample struct {
+	Time   time.Time      `json:"time"`
+	Counts map[string]int `json:"counts"` // Building room number -> players; empty rooms are left out
+}
+
+// OccupancyStats is what GET /admin/occupancy shows for a room
+type OccupancyStats struct {
+	Current map[string]int    `json:"current"`
+	History []OccupancySample `json:"history"` // Oldest first
+}
+
+// occupancyLocked counts the players in each building room, leaving out the hallways;
+// r.mu must be held
+func (r *Room) occupancyLocked() map[string]int {
+	counts := make(map[string]int)
+	for _, client := range r.Clients {
+		if client.CurrentRoomNumber != "" && !client.ghost {
+			counts[client.CurrentRoomNumber]++
+		}
+	}
+	return counts
+}
+
+// trackOccupancyLocked records a sample when one is due and returns the counts if they
+// changed since they were last sent, nil otherwise; r.mu must be held
+func (r *Room) trackOccupancyLocked(now time.Time) map[string]int {
+	counts := r.occupancyLocked()
+
+	if len(r.occupancyHistory) == 0 || now.Sub(r.occupancyHistory[len(r.occupancyHistory)-1].Time) >= occupancySampleEvery {
+		r.occupancyHistory = append(r.occupancyHistory, OccupancySample{Time: now, Counts: counts})
+		cutoff := now.Add(-occupancyKeep)
+		for len(r.occupancyHistory) > 0 && r.occupancyHistory[0].Time.Before(cutoff) {
+			r.occupancyHistory = r.occupancyHistory[1:]
+		}
+	}
+
+	if maps.Equal(counts, r.occupancySent) {
+		return nil
+	}
+	r.occupancySent = counts
+	return counts
+}
+
+// Occupancy returns how many players are in each building room now and over the last day
+func (r *Room) Occupancy() OccupancyStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return OccupancyStats{
+		Current: r.occupancyLocked(),
+		History: append([]OccupancySample(nil), r.occupancyHistory...),
+	}
+}
+
+// encodeOccupancy builds the message telling players how busy each building room is
+func encodeOccupancy(counts map[string]int) []byte {
+	msg, _ := protocol.EncodeMessage(protocol.MsgOccupancy, protocol.OccupancyPayload{Rooms: counts})
+	return msg
+}
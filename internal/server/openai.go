@@ -0,0 +1,145 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	defaultOpenAIModel = "gpt-4o-mini"
+	defaultOpenAIURL   = "https://api.openai.com/v1"
+	defaultOllamaModel = "llama3.2"
+	defaultOllamaURL   = "http://localhost:11434"
+)
+
+// newOpenAIProvider calls the OpenAI chat completions API. baseURL can point it at any
+// OpenAI-compatible server instead, such as a local one that needs no key.
+func newOpenAIProvider(apiKey, model, baseURL string) RiddleProvider {
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+	if baseURL == "" {
+		baseURL = defaultOpenAIURL
+	}
+	endpoint := strings.TrimSuffix(baseURL, "/") + "/chat/completions"
+
+	return &promptProvider{
+		name: "OpenAI (" + model + ")",
+		complete: func(prompt string) (string, error) {
+			return openAICall(endpoint, apiKey, model, prompt)
+		},
+	}
+}
+
+// newOllamaProvider calls a local Ollama server
+func newOllamaProvider(model, baseURL string) RiddleProvider {
+	if model == "" {
+		model = defaultOllamaModel
+	}
+	if baseURL == "" {
+		baseURL = defaultOllamaURL
+	}
+	endpoint := strings.TrimSuffix(baseURL, "/") + "/api/chat"
+
+	return &promptProvider{
+		name: "Ollama (" + model + ")",
+		complete: func(prompt string) (string, error) {
+			return ollamaCall(endpoint, model, prompt)
+		},
+	}
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+type ollamaRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+	Format   string        `json:"format"`
+}
+
+type ollamaResponse struct {
+	Message chatMessage `json:"message"`
+}
+
+func openAICall(endpoint, apiKey, model, prompt string) (string, error) {
+	var resp openAIResponse
+	err := postJSON(endpoint, apiKey, openAIRequest{
+		Model:    model,
+		Messages: []chatMessage{{Role: "user", Content: prompt}},
+	}, &resp)
+	if err != nil {
+		return "", fmt.Errorf("OpenAI: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("empty response from model")
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+func ollamaCall(endpoint, model, prompt string) (string, error) {
+	var resp ollamaResponse
+	err := postJSON(endpoint, "", ollamaRequest{
+		Model:    model,
+		Messages: []chatMessage{{Role: "user", Content: prompt}},
+		Format:   "json", // Every prompt asks for a JSON object
+	}, &resp)
+	if err != nil {
+		return "", fmt.Errorf("Ollama: %w", err)
+	}
+	if resp.Message.Content == "" {
+		return "", fmt.Errorf("empty response from model")
+	}
+	return resp.Message.Content, nil
+}
+
+// postJSON sends req to endpoint, with apiKey as a bearer token if there is one, and
+// decodes the reply into resp
+func postJSON(endpoint, apiKey string, req, resp any) error {
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+
+	httpResp, err := llmHTTPClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	body, _ := io.ReadAll(httpResp.Body)
+	if httpResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API Error %d: %s", httpResp.StatusCode, string(body))
+	}
+	if err := json.Unmarshal(body, resp); err != nil {
+		return fmt.Errorf("bad API response format: %w", err)
+	}
+	return nil
+}
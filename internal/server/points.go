@@ -0,0 +1,107 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// Points awarded for each activity
+const (
+	pointsRiddleEasy     = 30
+	pointsRiddleMedium   = 50
+	pointsRiddleHard     = 80
+	pointsMiniGamePlayed = 5
+	pointsMiniGameWin    = 20
+	pointsScavengerClue  = 5
+	pointsScavengerWin   = 50
+	pointsDailyLogin     = 10
+	pointsStreakDay      = 5  // Extra per day of login streak, on top of the daily login
+	pointsStreakWin      = 10 // Extra per riddle of a win streak, on top of the riddle
+)
+
+const (
+	maxStreakBonusDays    = 6         // Streak days that add to the bonus, so it tops out on day 7
+	loginStreakRewardDays = 7         // Streak length that earns streakRewardItem
+	streakRewardItem      = "acc_sun" // Shop item that can only be earned, not bought
+	maxWinStreakBonus     = 4         // Streak riddles that add to the win bonus, so it tops out at 5 in a row
+)
+
+// riddlePoints is what solving a treasure hunt riddle of the given difficulty is worth
+func riddlePoints(difficulty string) int {
+	switch difficulty {
+	case riddleEasy:
+		return pointsRiddleEasy
+	case riddleHard:
+		return pointsRiddleHard
+	}
+	return pointsRiddleMedium
+}
+
+// winStreakBonus is the extra a riddle win is worth as part of a streak of wins in a row
+func winStreakBonus(streak int) int {
+	return pointsStreakWin * min(max(streak-1, 0), maxWinStreakBonus)
+}
+
+// awardPoints credits the user and tells their client about the new balance
+func awardPoints(users *UserManager, c *Client, amount int, reason string) {
+	balance, err := users.AwardPoints(c.Username, amount, reason)
+	if err != nil {
+		log.Printf("Error awarding points: %v", err)
+		return
+	}
+	sendPoints(c, balance, amount, reason)
+}
+
+// awardPoints credits a player in the room, if they're still here
+func (r *Room) awardPoints(username string, amount int, reason string) {
+	if c := r.clientByUsername(username); c != nil {
+		awardPoints(r.users, c, amount, reason)
+	}
+}
+
+// sendLoginRewards gives the daily login bonus, which grows with the login streak, once
+// per Madison day, and tells the client its streak. A long enough streak also earns a cosmetic.
+func sendLoginRewards(users *UserManager, c *Client) {
+	streak, firstToday := users.RecordLogin(c.Username, time.Now())
+	if !firstToday {
+		sendPoints(c, users.Points(c.Username), 0, "")
+		sendLoginStreak(c, streak, 0, "")
+		return
+	}
+
+	bonus := pointsDailyLogin + pointsStreakDay*min(streak-1, maxStreakBonusDays)
+	reason := "Daily login"
+	if streak > 1 {
+		reason = fmt.Sprintf("Day %d login streak", streak)
+	}
+	awardPoints(users, c, bonus, reason)
+
+	var reward string
+	if streak == loginStreakRewardDays {
+		if item, ok := shopItemByID(streakRewardItem); ok && users.GrantItem(c.Username, item) {
+			reward = item.Name
+		}
+	}
+	sendLoginStreak(c, streak, bonus, reward)
+}
+
+func sendLoginStreak(c *Client, streak, bonus int, reward string) {
+	msg, _ := protocol.EncodeMessage(protocol.MsgLoginStreak, protocol.LoginStreakPayload{
+		Streak: streak,
+		Bonus:  bonus,
+		Reward: reward,
+	})
+	c.deliver(msg)
+}
+
+func sendPoints(c *Client, balance, delta int, reason string) {
+	msg, _ := protocol.EncodeMessage(protocol.MsgPoints, protocol.PointsPayload{
+		Balance: balance,
+		Delta:   delta,
+		Reason:  reason,
+	})
+	c.deliver(msg)
+}
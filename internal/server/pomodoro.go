@@ -0,0 +1,127 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+const (
+	pomodoroFocus = 25 * time.Minute
+	pomodoroBreak = 5 * time.Minute
+)
+
+// pomodoro is a focus/break timer shared by everyone in one building room
+type pomodoro struct {
+	Phase     string    `json:"phase"` // "focus" or "break"
+	EndsAt    time.Time `json:"ends_at"`
+	Cycle     int       `json:"cycle"`
+	StartedBy string    `json:"started_by"`
+}
+
+// HandlePomodoro starts (or stops) the timer for the room the client is standing in
+func (r *Room) HandlePomodoro(client *Client, stop bool) {
+	r.mu.Lock()
+	roomNumber := client.CurrentRoomNumber
+	if roomNumber == "" {
+		r.mu.Unlock()
+		sendError(client, "Step into a room to start a pomodoro")
+		return
+	}
+
+	var ping string
+	if stop {
+		if _, ok := r.pomodoros[roomNumber]; !ok {
+			r.mu.Unlock()
+			sendError(client, "There's no pomodoro running in this room")
+			return
+		}
+		delete(r.pomodoros, roomNumber)
+		r.unsaved = true
+		ping = fmt.Sprintf("🍅 %s stopped the pomodoro", client.Username)
+	} else {
+		if _, ok := r.pomodoros[roomNumber]; ok {
+			r.mu.Unlock()
+			sendError(client, "A pomodoro is already running in this room")
+			return
+		}
+		r.pomodoros[roomNumber] = &pomodoro{
+			Phase:     "focus",
+			EndsAt:    r.clock.Now().Add(pomodoroFocus),
+			Cycle:     1,
+			StartedBy: client.Username,
+		}
+		r.unsaved = true
+		ping = fmt.Sprintf("🍅 %s started a pomodoro - 25 minutes of focus, go!", client.Username)
+	}
+	r.mu.Unlock()
+
+	log.Printf("Pomodoro in room %s: %s", roomNumber, ping)
+	r.chatManager.PostSystemMessage(r.ID, roomNumber, ping)
+}
+
+// advancePomodorosLocked moves timers whose phase has ended on to the next phase and
+// returns the chat ping for each room that changed; r.mu must be held
+func (r *Room) advancePomodorosLocked(now time.Time) map[string]string {
+	var pings map[string]string
+	for roomNumber, p := range r.pomodoros {
+		if now.Before(p.EndsAt) {
+			continue
+		}
+		if pings == nil {
+			pings = make(map[string]string)
+		}
+		r.unsaved = true
+
+		if p.Phase == "focus" {
+			p.Phase = "break"
+			p.EndsAt = now.Add(pomodoroBreak)
+			pings[roomNumber] = "☕ Focus session done! Take a 5 minute break."
+		} else {
+			p.Phase = "focus"
+			p.EndsAt = now.Add(pomodoroFocus)
+			p.Cycle++
+			pings[roomNumber] = fmt.Sprintf("🍅 Break's over - focus session #%d starts now.", p.Cycle)
+		}
+	}
+	return pings
+}
+
+// dropAbandonedPomodorosLocked stops the timers of building rooms that nobody in the
+// room is standing in anymore, once their players have left; r.mu must be held
+func (r *Room) dropAbandonedPomodorosLocked() {
+	if len(r.pomodoros) == 0 {
+		return
+	}
+	occupied := make(map[string]bool, len(r.Clients))
+	for _, client := range r.Clients {
+		occupied[client.CurrentRoomNumber] = true
+	}
+	for roomNumber := range r.pomodoros {
+		if !occupied[roomNumber] {
+			delete(r.pomodoros, roomNumber)
+			r.unsaved = true
+			log.Printf("Pomodoro in room %s stopped, everyone left", roomNumber)
+		}
+	}
+}
+
+// pomodoroStatesLocked returns the running timers in protocol format; r.mu must be held
+func (r *Room) pomodoroStatesLocked() map[string]protocol.PomodoroState {
+	if len(r.pomodoros) == 0 {
+		return nil
+	}
+
+	states := make(map[string]protocol.PomodoroState, len(r.pomodoros))
+	for roomNumber, p := range r.pomodoros {
+		states[roomNumber] = protocol.PomodoroState{
+			Phase:     p.Phase,
+			EndsAt:    p.EndsAt.Unix(),
+			Cycle:     p.Cycle,
+			StartedBy: p.StartedBy,
+		}
+	}
+	return states
+}
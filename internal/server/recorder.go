@@ -0,0 +1,171 @@
+package server
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// recorderFlushEvery is how often a journal is flushed to disk, so a crash loses at most
+// this much of it
+const recorderFlushEvery = time.Second
+
+// recorder writes everything a room's players send and everything sent to them to a
+// session journal (see protocol.JournalEntry), for debugging desyncs and balancing game
+// modes after the fact
+type recorder struct {
+	mu        sync.Mutex
+	path      string
+	file      *os.File
+	gz        *gzip.Writer
+	enc       *json.Encoder // nil once stopped
+	entries   int
+	lastFlush time.Time
+}
+
+// startRecorder creates a journal for the room in dir
+func startRecorder(dir, roomID string) (*recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%s-%s%s", journalName(roomID), time.Now().Format("20060102-150405"), protocol.JournalExt)
+	path := filepath.Join(dir, name)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(file)
+	return &recorder{
+		path:      path,
+		file:      file,
+		gz:        gz,
+		enc:       json.NewEncoder(gz),
+		lastFlush: time.Now(),
+	}, nil
+}
+
+// journalName makes a room ID safe to use in a file name
+func journalName(roomID string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, roomID)
+}
+
+// write adds a message to the journal. It's safe to call on a nil recorder, which
+// records nothing.
+func (rec *recorder) write(dir, username string, msg []byte) {
+	if rec == nil {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.enc == nil {
+		return
+	}
+
+	now := time.Now()
+	entry := protocol.JournalEntry{Time: now, Dir: dir, User: username, Message: msg}
+	if err := rec.enc.Encode(entry); err != nil {
+		log.Printf("Error recording to %s: %v", rec.path, err)
+		return
+	}
+	rec.entries++
+	if now.Sub(rec.lastFlush) >= recorderFlushEvery {
+		rec.gz.Flush()
+		rec.lastFlush = now
+	}
+}
+
+// stop finishes the journal and returns how many messages it has
+func (rec *recorder) stop() (int, error) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.enc == nil {
+		return rec.entries, nil
+	}
+	rec.enc = nil
+	return rec.entries, errors.Join(rec.gz.Close(), rec.file.Close())
+}
+
+// record adds a message to the room's journal if it's being recorded. username is who
+// sent it or who it went to, empty when it went to everyone.
+func (r *Room) record(dir, username string, msg []byte) {
+	r.recording.Load().write(dir, username, msg)
+}
+
+// StartRecording starts writing the room's messages to a new journal in the configured
+// recordings directory and returns its path. The journal starts with the room as it is
+// now, so it can be replayed without anything recorded before.
+func (r *Room) StartRecording() (string, error) {
+	if r.config.RecordDir == "" {
+		return "", errors.New("recording is turned off on this server")
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if rec := r.recording.Load(); rec != nil {
+		return "", fmt.Errorf("room %s is already being recorded to %s", r.ID, rec.path)
+	}
+
+	rec, err := startRecorder(r.config.RecordDir, r.ID)
+	if err != nil {
+		return "", err
+	}
+	snapshot, err := protocol.EncodeMessage(protocol.MsgRoomJoined, protocol.RoomJoinedPayload{
+		RoomID:    r.ID,
+		GameState: r.GameState,
+		Objects:   mapObjects(),
+		Config:    r.config.roomConfig(),
+	})
+	if err != nil {
+		rec.stop()
+		return "", err
+	}
+	rec.write(protocol.JournalOut, "", snapshot)
+	if !r.recording.CompareAndSwap(nil, rec) {
+		rec.stop()
+		os.Remove(rec.path)
+		return "", fmt.Errorf("room %s is already being recorded", r.ID)
+	}
+
+	log.Printf("Recording room %s to %s", r.ID, rec.path)
+	return rec.path, nil
+}
+
+// StopRecording finishes the room's journal and returns its path
+func (r *Room) StopRecording() (string, error) {
+	rec := r.recording.Swap(nil)
+	if rec == nil {
+		return "", fmt.Errorf("room %s isn't being recorded", r.ID)
+	}
+	entries, err := rec.stop()
+	if err != nil {
+		return rec.path, fmt.Errorf("finishing %s: %w", rec.path, err)
+	}
+	log.Printf("Stopped recording room %s, %d messages in %s", r.ID, entries, rec.path)
+	return rec.path, nil
+}
+
+// StopRecordings finishes the journals of every room being recorded; call it when
+// shutting down
+func (rm *RoomManager) StopRecordings() {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	for _, room := range rm.rooms {
+		if room.recording.Load() != nil {
+			room.StopRecording()
+		}
+	}
+}
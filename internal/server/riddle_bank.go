@@ -0,0 +1,189 @@
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed game_assets/riddles.json
+var embeddedRiddles []byte
+
+const (
+	riddleBankRecent = 20  // Recently drawn riddles that won't be drawn again
+	riddleBankMax    = 500 // Riddles kept before top-ups from the model stop being added
+)
+
+// Riddle difficulty tiers; harder riddles are worth more points
+const (
+	riddleEasy   = "easy"
+	riddleMedium = "medium"
+	riddleHard   = "hard"
+)
+
+// riddleDifficulties is the order difficulties rotate through in a day's rounds
+var riddleDifficulties = []string{riddleEasy, riddleMedium, riddleHard}
+
+// riddleCategory is a topic riddles are written about
+type riddleCategory struct {
+	ID    string // Stored with the riddle
+	Name  string // Shown to players
+	Topic string // Described to the model
+}
+
+// riddleCategories rotate across rounds, starting at a different one each day
+var riddleCategories = []riddleCategory{
+	{ID: "algorithms", Name: "Algorithms", Topic: "algorithms and data structures"},
+	{ID: "networking", Name: "Networking", Topic: "computer networking and the internet"},
+	{ID: "uw-madison", Name: "UW-Madison Trivia", Topic: "UW-Madison, its campus, traditions and history"},
+	{ID: "programming", Name: "Programming", Topic: "programming, computer systems and technology"},
+}
+
+// riddleCategoryByID looks up a category, falling back to the general programming one
+func riddleCategoryByID(id string) riddleCategory {
+	for _, category := range riddleCategories {
+		if category.ID == id {
+			return category
+		}
+	}
+	return riddleCategories[len(riddleCategories)-1]
+}
+
+// riddleKindForRound picks the category and difficulty of a day's round (counting from 1)
+func riddleKindForRound(day string, round int) (riddleCategory, string) {
+	offset := 0
+	if t, err := time.Parse("2006-01-02", day); err == nil {
+		offset = t.YearDay()
+	}
+	round = max(round, 1) - 1
+	category := riddleCategories[(offset+round)%len(riddleCategories)]
+	return category, riddleDifficulties[round%len(riddleDifficulties)]
+}
+
+// riddles is the bank every room's treasure hunt draws from
+var riddles = newRiddleBank(embeddedRiddles)
+
+// riddleBank is the pool of treasure hunt riddles. It starts with the riddles shipped
+// in game_assets/riddles.json, so the hunt works without a model, and the model tops it up.
+type riddleBank struct {
+	mu      sync.Mutex
+	riddles []GeminiRiddle
+	recent  []string // Answers of the most recently drawn riddles, oldest first
+}
+
+func newRiddleBank(raw []byte) *riddleBank {
+	b := &riddleBank{}
+	if err := json.Unmarshal(raw, &b.riddles); err != nil {
+		log.Printf("Error loading riddle bank: %v", err)
+	}
+	if len(b.riddles) == 0 {
+		b.riddles = []GeminiRiddle{{
+			Question: "I have keys but no locks. I have a space but no room. You can enter, but never leave. What am I?",
+			Answer:   "keyboard",
+			Hint:     "I am an input device.",
+		}}
+	}
+	for i := range b.riddles {
+		b.riddles[i].normalizeKind()
+	}
+	return b
+}
+
+// Draw picks a random riddle with rng whose answer hasn't come up recently, preferring
+// one of the asked-for category and difficulty, then one of the category
+func (b *riddleBank) Draw(rng *seededRand, category riddleCategory, difficulty string) *GeminiRiddle {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var exact, sameCategory, fresh []int
+	for i, riddle := range b.riddles {
+		if slices.Contains(b.recent, normalizeRiddleText(riddle.Answer)) {
+			continue
+		}
+		fresh = append(fresh, i)
+		if riddle.Category == category.ID {
+			sameCategory = append(sameCategory, i)
+			if riddle.Difficulty == difficulty {
+				exact = append(exact, i)
+			}
+		}
+	}
+
+	i := rng.Intn(len(b.riddles))
+	for _, candidates := range [][]int{exact, sameCategory, fresh} {
+		if len(candidates) > 0 {
+			i = candidates[rng.Intn(len(candidates))]
+			break
+		}
+	}
+
+	riddle := b.riddles[i]
+	b.markUsedLocked(riddle.Answer)
+	return &riddle
+}
+
+// MarkUsed keeps a riddle that's being played, such as one restored after a restart,
+// from being drawn again soon
+func (b *riddleBank) MarkUsed(riddle *GeminiRiddle) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.markUsedLocked(riddle.Answer)
+}
+
+// TopUp asks the model for a new riddle of the given kind and adds it to the bank unless
+// the bank already has one like it. Failures are only logged, the bank has plenty to draw from.
+func (b *riddleBank) TopUp(category riddleCategory, difficulty string) {
+	riddle, err := GenerateRiddle(category.Topic, difficulty)
+	if errors.Is(err, errNoLLM) {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to generate riddle, drawing from the bank: %v", err)
+		return
+	}
+	if riddle.Question == "" || riddle.Answer == "" {
+		return
+	}
+	riddle.Category, riddle.Difficulty = category.ID, difficulty
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.riddles) >= riddleBankMax {
+		return
+	}
+	question, answer := normalizeRiddleText(riddle.Question), normalizeRiddleText(riddle.Answer)
+	for _, have := range b.riddles {
+		if normalizeRiddleText(have.Question) == question || normalizeRiddleText(have.Answer) == answer {
+			log.Printf("Generated riddle for %q is already in the bank", riddle.Answer)
+			return
+		}
+	}
+	b.riddles = append(b.riddles, *riddle)
+	log.Printf("Added generated riddle to the bank (%d riddles): %s", len(b.riddles), riddle.Question)
+}
+
+// markUsedLocked records a drawn answer, forgetting the oldest; b.mu must be held
+func (b *riddleBank) markUsedLocked(answer string) {
+	b.recent = append(b.recent, normalizeRiddleText(answer))
+	if len(b.recent) > riddleBankRecent {
+		b.recent = b.recent[len(b.recent)-riddleBankRecent:]
+	}
+}
+
+// normalizeRiddleText compares riddle text ignoring case and extra whitespace
+func normalizeRiddleText(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// normalizeKind fills in the category and difficulty of riddles saved before they had one
+func (r *GeminiRiddle) normalizeKind() {
+	r.Category = riddleCategoryByID(r.Category).ID
+	if !slices.Contains(riddleDifficulties, r.Difficulty) {
+		r.Difficulty = riddleMedium
+	}
+}
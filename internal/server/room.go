@@ -1,146 +1,258 @@
 package server
 
 import (
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"log" //logs messages
-	"math/rand"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/yourusername/always-at-morg/internal/gamemap"
 	"github.com/yourusername/always-at-morg/internal/protocol"
 )
 
-var startingPositions = []string{
-	"10:100",
-	"18:150",
-	"18:200",
-	"23:100",
-}
-
 // Room represents a game room/session
 type Room struct {
 	ID          string
 	Clients     map[string]*Client
 	GameState   *protocol.GameState
 	chatManager *ChatManager
+	users       *UserManager // Profiles and points
+	hunt        *TreasureHuntManager
+	tiles       *[gamemap.Height][gamemap.Width]string // The map's raw characters, shared by every room
 
 	mu        sync.RWMutex
 	broadcast chan []byte  //this is private to room only, used to send messages to all clients in the room
 	register  chan *Client //clients register to room, used when a new client joins
 
 	unregister chan *Client
-	tickRate   time.Duration
+	wake       chan struct{} // Nudged when the tick rate changes so an empty room's slow tick doesn't hold it up
+	tickRate   time.Duration // Tick while anyone is in the room; it slows to config.IdleTickRate while empty
+
+	interactCooldowns map[string]time.Time // "username:objectID" -> time the object can be used again
+	miniGames         *MiniGameManager
+	scavenger         *ScavengerManager
+	pomodoros         map[string]*pomodoro // Building room number -> running timer
+	npcs              []*npc
+	mode              GameMode // Opt-in game mode running in the room, nil if none
+	config            Config
+	password          string               // Set by whoever created the room, empty for a public room
+	owner             string               // Username of whoever created the room, who can moderate it; empty for the main room
+	moderators        map[string]bool      // Usernames the owner lets kick players and clear chat
+	customName        string               // Set by the owner, empty to go by roomName
+	customCapacity    int                  // Set by the owner, 0 to use config.RoomCapacity
+	kicked            map[string]time.Time // Username -> when they may come back
+	store             *Store
+	lastPositions     map[string]string // Username -> "Y:X" where they last left the room
+	unsaved           bool              // The room changed since it was last saved
+	spatial           *spatialIndex     // Where the players are, for finding who is near a spot
+	occupancyHistory  []OccupancySample // Players per building room over the last day, oldest first
+	occupancySent     map[string]int    // Players per building room as last sent to clients
+	heat              heatmap           // How often players were seen on each tile
+
+	// The manager closes the room once nobody has been in it for roomEmptyGrace, closing
+	// quit as Run returns. emptySince is when it last had nobody, guarded by mu.
+	manager    *RoomManager
+	emptySince time.Time
+	quit       chan struct{}
+
+	// Session journal being written, nil when the room isn't recorded. Atomic so
+	// messages can be recorded without the room lock.
+	recording atomic.Pointer[recorder]
+
+	// Time and random choices, from the config's clock and seed
+	clock Clock
+	rng   *seededRand
+	// When a player last moved or chatted, in UnixNano on the room's clock. Atomic so
+	// input doesn't wait on the room lock to record it.
+	lastActivity atomic.Int64
+	// When the last state went out; a quiet room sends them less often. Only the Run
+	// loop uses it.
+	stateSentAt time.Time
+
+	// Seqs of the newest global and room chat messages sent with a state, and the
+	// state's sections as last encoded. Only the Run loop uses them.
+	globalChatSent int64
+	roomChatSent   int64
+	states         stateCache
 }
 
 // NewRoom creates a new game room
-func NewRoom(id string, chatManager *ChatManager) *Room {
-	roomMap, err := fillRoomMap()
-	if err != nil {
-		log.Printf("Warning: failed to load room map: %v", err)
-		roomMap = [250][400]string{} // Use empty map as fallback
-	}
+func NewRoom(id string, chatManager *ChatManager, users *UserManager, hunt *TreasureHuntManager, cfg Config) *Room {
+	tiles, _ := getTileMap() // NewServer has already warned if it's empty
 
-	return &Room{
+	r := &Room{
 		ID:      id,
 		Clients: make(map[string]*Client),
 		GameState: &protocol.GameState{
 			Tick:          0,
 			Players:       make(map[string]protocol.Player),
 			PosToUsername: make(map[string]string),
-			Map:           roomMap,
 		},
 		chatManager: chatManager,
+		users:       users,
+		hunt:        hunt,
+		tiles:       tiles,
 
 		broadcast:  make(chan []byte, 256),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
-		tickRate:   time.Millisecond * 50, // 20 ticks per second
+		wake:       make(chan struct{}, 1),
+		quit:       make(chan struct{}),
+		tickRate:   cfg.TickRate,
+
+		interactCooldowns: make(map[string]time.Time),
+		miniGames:         NewMiniGameManager(),
+		scavenger:         &ScavengerManager{},
+		pomodoros:         make(map[string]*pomodoro),
+		lastPositions:     make(map[string]string),
+		moderators:        make(map[string]bool),
+		kicked:            make(map[string]time.Time),
+		npcs:              newNPCs(),
+		config:            cfg,
+		spatial:           newSpatialIndex(),
+		clock:             clockOrWall(cfg.Clock),
+		rng:               newSeededRand(cfg.Seed, "room:"+id),
 	}
+
+	r.emptySince = r.clock.Now()
+
+	// Chat from before the room was made is for clients to ask for, not for its first state
+	r.globalChatSent, r.roomChatSent = chatManager.latestSeqs(id)
+
+	// Push hunt changes (new riddle, winner, hint) right away rather than on the next tick
+	hunt.SetUpdateCallback(func(payload protocol.TreasureHuntStatePayload) {
+		msg, _ := protocol.EncodeMessage(protocol.MsgTreasureHuntState, payload)
+		select {
+		case r.broadcast <- msg:
+		case <-r.quit:
+		}
+	})
+	// Everyone who answers a trivia round right scores, not just the first
+	hunt.SetTriviaCallback(func(correct []string, points int) {
+		for _, username := range correct {
+			r.awardPoints(username, points, "Answered the trivia question")
+		}
+	})
+	return r
 }
 
 // Run starts the room's main loop
 func (r *Room) Run() {
-	ticker := time.NewTicker(r.tickRate)
+	rate := r.currentTickRate()
+	ticker := r.clock.NewTicker(rate)
 	defer ticker.Stop()
+	retune := func() {
+		if next := r.currentTickRate(); next != rate {
+			rate = next
+			ticker.Reset(rate)
+		}
+	}
+	saveTicker := r.clock.NewTicker(roomSaveInterval)
+	defer saveTicker.Stop()
 
 	for {
 		select {
 		case client := <-r.register:
 			r.handleRegister(client)
+			r.markActive()
+			retune()
+
+		case <-r.wake:
+			retune()
 
 		case client := <-r.unregister:
-			r.handleUnregister(client)
+			// A connection replaced by a resumed session has already gone, and its
+			// player is still here on the new one
+			if r.handleUnregister(client) {
+				r.forfeitMiniGames(client.Username)
+				r.leaveGameMode(client.Username)
+			}
+			r.markActive()
 
 		case message := <-r.broadcast:
 			r.handleBroadcast(message)
 
-		case <-ticker.C:
+		case <-ticker.Chan():
+			start := time.Now()
 			r.update(r.chatManager)
+			countTick(time.Since(start), rate)
+			retune()
+
+		case <-saveTicker.Chan():
+			r.save(false)
+			if r.manager != nil && r.manager.reap(r) {
+				r.close()
+				return
+			}
 		}
 	}
 }
 
-// findRandomSpawnPosition finds a random valid spawn position in the room
-// A valid position must have all 9 tiles in the 3x3 area as walkable (' ' or '@')
+// findRandomSpawnPosition finds a free spot in one of the map's spawn zones, trying
+// random ones first and then every tile in case the zones are crowded
 func (r *Room) findRandomSpawnPosition() (string, error) {
+	zones := gamemap.SpawnZones
 	maxAttempts := 1000
 	for i := 0; i < maxAttempts; i++ {
-		x := rand.Intn(400)
-		y := rand.Intn(250)
-		posStr := fmt.Sprintf("%d:%d", y, x) // Format: "Y:X" to match client expectation
-
-		// Check if all 9 tiles in the 3x3 area are walkable (' ' or '@')
-		valid := true
-		for dy := -1; dy <= 1; dy++ {
-			for dx := -1; dx <= 1; dx++ {
-				ny := y + dy
-				nx := x + dx
-
-				// Check bounds
-				if ny < 0 || ny >= 250 || nx < 0 || nx >= 400 {
-					valid = false
-					break
-				}
+		zone := zones[r.rng.Intn(len(zones))]
+		x := zone.X + r.rng.Intn(zone.W)
+		y := zone.Y + r.rng.Intn(zone.H)
+		if r.canSpawnAt(x, y) {
+			return fmt.Sprintf("%d:%d", y, x), nil // Format: "Y:X" to match client expectation
+		}
+	}
 
-				// Get value - must be walkable (' ' space or '@' dark brown floor)
-				cellValue := r.GameState.Map[ny][nx]
-				if cellValue != " " && cellValue != "@" {
-					valid = false
-					break
+	for _, zone := range zones {
+		for y := zone.Y; y < zone.Y+zone.H; y++ {
+			for x := zone.X; x < zone.X+zone.W; x++ {
+				if r.canSpawnAt(x, y) {
+					return fmt.Sprintf("%d:%d", y, x), nil
 				}
 			}
-			if !valid {
-				break
-			}
-		}
-
-		if !valid {
-			continue
-		}
-
-		// Check if position is not occupied
-		if _, occupied := r.GameState.PosToUsername[posStr]; occupied {
-			continue
 		}
-
-		return posStr, nil
 	}
+	return "", errors.New("every spawn zone is full")
+}
 
-	return "", fmt.Errorf("failed to find valid spawn position after %d attempts", maxAttempts)
+// canSpawnAt reports whether a player can be placed at x, y: the avatar must fit and
+// nobody's avatar may be in the way
+func (r *Room) canSpawnAt(x, y int) bool {
+	return gamemap.AvatarFits(r.tiles, x, y) && len(r.spatial.near(x, y, gamemap.AvatarReach)) == 0
 }
 
 func (r *Room) handleRegister(client *Client) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// Find a random valid spawn position
-	posStr, err := r.findRandomSpawnPosition()
-	if err != nil {
-		log.Printf("Error finding spawn position for %s: %v", client.Name, err)
-		// Fallback to a default position if we can't find a valid one
-		posStr = "52:120"
+	if client.resumed {
+		r.dropStaleLocked(client)
+	}
+
+	// Put a returning player back where they left the room if it's still free,
+	// otherwise find a random valid spawn position. Their profile knows where they left
+	// off, unless they're taking over a connection that was still in the room.
+	posStr, returning := r.lastPositions[client.Username]
+	if lastRoom, lastPos := r.users.LastPosition(client.Username); lastRoom == r.ID && !client.resumed {
+		posStr, returning = lastPos, true
+	}
+	if x, y := parsePos(posStr); !returning || !r.canSpawnAt(x, y) {
+		var err error
+		posStr, err = r.findRandomSpawnPosition()
+		if err != nil {
+			log.Printf("Error finding spawn position for %s: %v", client.Name, err)
+			// Fallback to the middle of the first spawn zone if we can't find a valid one
+			x, y := gamemap.SpawnZones[0].Center()
+			posStr = fmt.Sprintf("%d:%d", y, x)
+		}
 	}
 	client.Pos = posStr
 
@@ -150,6 +262,7 @@ func (r *Room) handleRegister(client *Client) {
 	client.CurrentRoomNumber = r.getRoomNumberFromPosition(x, y)
 
 	r.Clients[client.ID] = client
+	r.spatial.move(client, x, y)
 
 	// Update GameState.Players map
 	r.GameState.Players[client.Username] = protocol.Player{
@@ -162,42 +275,101 @@ func (r *Room) handleRegister(client *Client) {
 	r.GameState.PosToUsername[posStr] = client.Username
 
 	log.Printf("Player %s joined room %s at position %s", client.Name, r.ID, client.Pos)
+	client.joinedAt = r.clock.Now()
+	track("join", client.Username, r.ID, map[string]any{"resumed": client.resumed})
 
 	// Send room joined message to the new client
 	msg, _ := protocol.EncodeMessage(protocol.MsgRoomJoined, protocol.RoomJoinedPayload{
-		RoomID:    r.ID,
-		PlayerID:  client.ID,
-		GameState: r.GameState,
+		RoomID:       r.ID,
+		PlayerID:     client.ID,
+		GameState:    r.GameState,
+		Objects:      mapObjects(),
+		SessionToken: client.sessionToken,
+		Config:       r.config.roomConfig(),
 	})
-	client.send <- msg
+	client.deliver(msg)
+	r.record(protocol.JournalOut, client.Username, msg)
+	client.deliver(encodeOccupancy(r.occupancyLocked()))
 
 	// Broadcast player joined to others
 }
 
-func (r *Room) handleUnregister(client *Client) {
+// handleUnregister takes the client out of the room, reporting whether it was still in it
+func (r *Room) handleUnregister(client *Client) bool {
 	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	if _, ok := r.Clients[client.ID]; ok {
+	_, ok := r.Clients[client.ID]
+	if ok {
 		delete(r.Clients, client.ID)
-		close(client.send)
+		client.disconnect()
+
+		// Free the player's spot on the map, remembering it for when they come back
+		r.lastPositions[client.Username] = client.Pos
+		r.unsaved = true
+		delete(r.GameState.Players, client.Username)
+		if r.GameState.PosToUsername[client.Pos] == client.Username {
+			delete(r.GameState.PosToUsername, client.Pos)
+		}
+		r.spatial.remove(client)
+		r.dropAbandonedPomodorosLocked()
+		if len(r.Clients) == 0 {
+			r.emptySince = r.clock.Now()
+		}
 
 		log.Printf("Player %s left room %s", client.Name, r.ID)
+		track("leave", client.Username, r.ID, map[string]any{
+			"session_seconds": int(r.clock.Now().Sub(client.joinedAt).Seconds()),
+		})
+	}
+	r.mu.Unlock()
 
+	// Saved outside the lock since it writes the store
+	if ok {
+		r.users.SetLastPositions(r.ID, map[string]string{client.Username: client.Pos})
 	}
+	return ok
 }
 
+// dropStaleLocked removes the connection a resuming player left behind, if the room
+// still has it, keeping its spot for them, and hangs up on it. Its readPump then finds
+// it already gone from the room. r.mu must be held.
+func (r *Room) dropStaleLocked(client *Client) {
+	for id, old := range r.Clients {
+		if old == client || old.Username != client.Username {
+			continue
+		}
+		delete(r.Clients, id)
+		old.disconnect()
+
+		r.lastPositions[old.Username] = old.Pos
+		delete(r.GameState.Players, old.Username)
+		if r.GameState.PosToUsername[old.Pos] == old.Username {
+			delete(r.GameState.PosToUsername, old.Pos)
+		}
+		r.spatial.remove(old)
+		log.Printf("Player %s resumed their session in room %s", client.Name, r.ID)
+	}
+}
+
+// handleBroadcast sends a message to everyone in the room. A client whose queue is full
+// misses it; one that keeps missing everything is disconnected by update.
 func (r *Room) handleBroadcast(message []byte) {
+	r.record(protocol.JournalOut, "", message)
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	for _, client := range r.Clients {
-		select {
-		case client.send <- message:
-		default:
-			close(client.send)
-			delete(r.Clients, client.ID)
-		}
+		client.deliver(message)
+	}
+}
+
+// broadcastState sends the tick state to everyone in the room
+func (r *Room) broadcastState(message []byte) {
+	r.record(protocol.JournalOut, "", message)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, client := range r.Clients {
+		client.deliverState(message)
 	}
 }
 
@@ -205,35 +377,87 @@ func (r *Room) handleBroadcast(message []byte) {
 func (r *Room) update(chatManager *ChatManager) {
 	r.mu.Lock()
 	r.GameState.Tick++
+	now := r.clock.Now()
 
 	// Add game logic here (e.g., entity movement, collision detection)
+	r.moveNPCsLocked()
+	npcs := r.npcStatesLocked()
+	pings := r.advancePomodorosLocked(now)
+	pomodoros := r.pomodoroStatesLocked()
+	idleWarn, idleKick := r.idleClientsLocked()
+	lagging := r.laggingClientsLocked()
+	modeEvents, modeState, hidden := r.tickGameModeLocked(now)
+	ghosts := r.ghostsLocked()
+	occupancy := r.trackOccupancyLocked(now)
+	r.sampleHeatLocked(now)
 
 	r.mu.Unlock()
 
-	// Build unified Kuluchified state containing everything
-	announcements := chatManager.GetAnnouncements()
-	announcementPayloads := make([]protocol.AnnouncementPayload, len(announcements))
-	for i, announcement := range announcements {
-		announcementPayloads[i] = protocol.AnnouncementPayload{
-			Message:   announcement.Message,
-			Timestamp: announcement.Timestamp,
-		}
+	for _, event := range modeEvents {
+		r.handleBroadcast(r.notice(event))
+	}
+
+	for _, client := range idleWarn {
+		r.warnIdle(client)
+	}
+	for _, client := range idleKick {
+		r.kickIdle(client)
+	}
+	for _, client := range lagging {
+		r.kickLagging(client)
+	}
+
+	for roomNumber, ping := range pings {
+		chatManager.PostSystemMessage(r.ID, roomNumber, ping)
+	}
+
+	// Only when someone has moved between rooms
+	if occupancy != nil {
+		r.handleBroadcast(encodeOccupancy(occupancy))
+	}
+
+	r.expireScavengerHunt()
+	r.finishExpiredMiniGames()
+
+	// Announcements go out once, when they're made, rather than with every state.
+	// Server-wide ones are sent as they're made (see HandleAnnouncement); the hunt's
+	// are picked up here.
+	for _, announcement := range r.hunt.PopAnnouncements() {
+		msg, _ := protocol.EncodeMessage(protocol.MsgAnnouncement, announcement)
+		r.handleBroadcast(msg)
+	}
+
+	// A quiet room skips states in between; the chat waits for the next one
+	if !r.stateDue(now) {
+		return
 	}
 
-	chatMessages := chatManager.GetGlobalMessages(r)
-	roomChatMessages := chatManager.GetAllRoomMessages(r)
+	// Only the chat posted since the last state; clients fetch the rest themselves
+	var chatMessages []protocol.GlobalChatPayload
+	var roomChatMessages map[string][]protocol.RoomChatPayload
+	chatMessages, r.globalChatSent = chatManager.GlobalMessagesSince(r.globalChatSent)
+	roomChatMessages, r.roomChatSent = chatManager.RoomMessagesSince(r, r.roomChatSent)
 
+	// Build unified Kuluchified state containing everything
 	// Build players map (keyed by username for easy client lookup)
 	r.mu.RLock()
 	players := make(map[string]protocol.Player)
 	for _, client := range r.Clients {
 		players[client.Username] = protocol.Player{
-			Pos:      client.Pos,
-			Avatar:   client.Avatar,
-			Username: client.Username,
+			Pos:       client.Pos,
+			Avatar:    client.Avatar,
+			Username:  client.Username,
+			Status:    client.Status,
+			Accessory: client.Accessory,
+			NameColor: client.NameColor,
+			Idle:      client.idleFor() >= idleAfter,
+			MoveSeq:   client.moveSeq,
+			Ghost:     client.ghost,
 		}
 	}
+	positions := r.states.encodePositionsLocked(r.GameState.PosToUsername)
 	r.mu.RUnlock()
+	hidden = hideGhosts(hidden, players, ghosts)
 
 	// Create unified state payload with current players
 	kuluchifiedState := protocol.KuluchifiedStatePayload{
@@ -241,17 +465,25 @@ func (r *Room) update(chatManager *ChatManager) {
 			Tick:          r.GameState.Tick,
 			Players:       players, // Use the players map we just built!
 			PosToUsername: r.GameState.PosToUsername,
+			TimeOfDay:     timeOfDay(r.clock.Now()),
+			NPCs:          npcs,
 		},
-		ChatMessages:      chatMessages.Messages,
+		ChatMessages:      chatMessages,
 		RoomChatMessages:  roomChatMessages,
-		Announcements:     announcementPayloads,
 		Players:           players,
-		TreasureHuntState: Manager.GetState(), // Broadcast treasure hunt state to all clients
+		TreasureHuntState: r.hunt.GetState(), // Broadcast treasure hunt state to all clients
+		Pomodoros:         pomodoros,
+		GameMode:          modeState,
+	}
+
+	// Some players can't see others during a game mode (hide-and-seek), so they get their own copy
+	if len(hidden) > 0 {
+		r.sendStateFiltered(kuluchifiedState, hidden)
+		return
 	}
 
-	// Send ONE broadcast with everything
-	msg, _ := protocol.EncodeMessage(protocol.MsgKuluchifiedState, kuluchifiedState)
-	r.broadcast <- msg
+	// Send ONE broadcast with everything, re-encoding only what changed since the last one
+	r.broadcastState(r.states.encode(&kuluchifiedState, positions))
 }
 
 // isWalkable checks if a position is walkable according to the room map
@@ -262,7 +494,7 @@ func (r *Room) isWalkable(x, y int) bool {
 	}
 
 	// Get room map value
-	value := r.GameState.Map[y][x]
+	value := r.tiles[y][x]
 
 	// Wall characters ("r", "o", "i") are not walkable
 	// "e" (entrances), "-1" (hallways), "@" (dark brown), and room numbers ("1", "2", "3", ...) are walkable
@@ -273,41 +505,50 @@ func (r *Room) isWalkable(x, y int) bool {
 // canAvatarFitAt checks if a 3x3 avatar can fit at the given position
 // The avatar occupies a 3x3 grid centered on (x, y)
 func (r *Room) canAvatarFitAt(x, y int) bool {
-	// Check all 9 tiles in the 3x3 footprint - must be walkable
-	for dy := -1; dy <= 1; dy++ {
-		for dx := -1; dx <= 1; dx++ {
-			checkX := x + dx
-			checkY := y + dy
-
-			// Check bounds
-			if checkY < 0 || checkY >= 250 || checkX < 0 || checkX >= 400 {
-				return false // Out of bounds
-			}
-
-			// Check if tile is walkable: ' ' (hallway), 'e' (entrance), "-1" (outside), '@' (dark brown floor), or room numbers ("1", "2", etc.)
-			value := r.GameState.Map[checkY][checkX]
-			if value == " " || value == "e" || value == "-1" || value == "@" {
-				// Explicitly walkable
-				continue
-			}
-			// Check if it's a room number (numeric string)
-			if _, err := strconv.Atoi(value); err == nil {
-				// It's a room number - walkable
-				continue
-			}
-			// Not walkable (walls, inaccessible areas, furniture T/t/W, etc.)
-			return false
-		}
-	}
-
-	return true // All tiles in 3x3 grid are walkable
+	return gamemap.AvatarFits(r.tiles, x, y)
 }
 
-// UpdatePlayerPosition updates a player's position
-func (r *Room) UpdatePlayerPosition(username string, x, y int) {
+// UpdatePlayerPosition updates a player's position. seq is the client's number for the
+// move; it's sent back in the player's state whether or not the move was allowed, so the
+// client can correct its prediction.
+func (r *Room) UpdatePlayerPosition(username string, x, y int, seq uint64) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	client := r.clientByUsernameLocked(username)
+	if client == nil {
+		return
+	}
+	client.moveSeq = max(client.moveSeq, seq)
+
+	// The running game mode can freeze players (e.g. the seeker while others hide)
+	now := r.clock.Now()
+	if !r.canMoveLocked(username, now) {
+		return
+	}
+
+	// One tile at a time, at a limited rate
+	if !client.allowMoveLocked(x, y, now) {
+		return
+	}
+
+	// Ghosts go through walls and players, and don't take up any room themselves
+	newPos := fmt.Sprintf("%d:%d", y, x) // Format: "Y:X"
+	if client.ghost {
+		client.Pos = newPos
+		client.CurrentRoomNumber = r.getRoomNumberFromPosition(x, y)
+		if player, exists := r.GameState.Players[username]; exists {
+			player.Pos = newPos
+			r.GameState.Players[username] = player
+		}
+		return
+	}
+
+	// Not into someone else's avatar or personal space
+	if r.crowdsLocked(client, x, y) {
+		return
+	}
+
 	// Validate that the 3x3 avatar footprint fits at the new position
 	if !r.canAvatarFitAt(x, y) {
 		// Avatar would collide with wall or go out of bounds, reject movement
@@ -315,7 +556,6 @@ func (r *Room) UpdatePlayerPosition(username string, x, y int) {
 	}
 
 	// Check if position is already occupied by another player
-	newPos := fmt.Sprintf("%d:%d", y, x) // Format: "Y:X"
 	if existingUser, occupied := r.GameState.PosToUsername[newPos]; occupied && existingUser != username {
 		// Position is occupied by another player, reject movement
 		return
@@ -341,6 +581,7 @@ func (r *Room) UpdatePlayerPosition(username string, x, y int) {
 
 			// Update new position in PosToUsername map
 			r.GameState.PosToUsername[newPos] = username
+			r.spatial.move(client, x, y)
 
 			// Update GameState.Players directly so client sees the change on next state update
 			if player, exists := r.GameState.Players[username]; exists {
@@ -353,61 +594,334 @@ func (r *Room) UpdatePlayerPosition(username string, x, y int) {
 	}
 }
 
-// getRoomNumberFromPosition determines which room a position is in
-// Returns room number as string ("1", "2", etc.) or "" if in hallway
-func (r *Room) getRoomNumberFromPosition(x, y int) string {
-	// Check bounds
-	if y < 0 || y >= 250 || x < 0 || x >= 400 {
-		return ""
-	}
+// clientByUsername finds a connected client by username, or nil
+func (r *Room) clientByUsername(username string) *Client {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.clientByUsernameLocked(username)
+}
 
-	// Get the map value at this position
-	value := r.GameState.Map[y][x]
+// idleAfter is how long a player can go without sending input before they're shown as idle
+const idleAfter = 5 * time.Minute
 
-	// Check if it's a room number (numeric string)
-	if _, err := strconv.Atoi(value); err == nil {
-		return value
+// maxStatusLength is the longest status message a player can set, in runes
+const maxStatusLength = 40
+
+// HandleSetStatus sets the client's status message, shown in the player list and profile
+func (r *Room) HandleSetStatus(client *Client, status string) {
+	status = strings.TrimSpace(status)
+	if utf8.RuneCountInString(status) > maxStatusLength {
+		sendError(client, fmt.Sprintf("Status is too long (max %d characters)", maxStatusLength))
+		return
+	}
+
+	r.mu.Lock()
+	client.Status = status
+	if player, exists := r.GameState.Players[client.Username]; exists {
+		player.Status = status
+		r.GameState.Players[client.Username] = player
 	}
+	r.mu.Unlock()
+}
 
-	return ""
+// parsePos parses a position string "Y:X" into integer coordinates
+func parsePos(pos string) (x, y int) {
+	fmt.Sscanf(pos, "%d:%d", &y, &x)
+	return x, y
+}
+
+// getRoomNumberFromPosition determines which room a position is in
+// Returns room number as string ("1", "2", etc.) or "" if in hallway
+func (r *Room) getRoomNumberFromPosition(x, y int) string {
+	return gamemap.RoomNumberAt(getRoomNumberMap(), x, y)
 }
 
 // RoomManager manages all game rooms
 type RoomManager struct {
 	rooms       map[string]*Room
 	chatManager *ChatManager
+	users       *UserManager
+	store       *Store
+	config      Config
 	mu          sync.RWMutex
 }
 
 // NewRoomManager creates a new room manager
-func NewRoomManager(chatManager *ChatManager) *RoomManager {
+func NewRoomManager(chatManager *ChatManager, users *UserManager, store *Store, cfg Config) *RoomManager {
 	return &RoomManager{
 		rooms:       make(map[string]*Room),
 		chatManager: chatManager,
+		users:       users,
+		store:       store,
+		config:      cfg,
+	}
+}
+
+// roomMapName is the map every room is played on
+const roomMapName = "Morgridge Hall"
+
+// ListRooms describes every room for the lobby, bots and the admin API, busiest first.
+// The default room is listed even before anyone has joined it, so a fresh server has
+// somewhere to go.
+func (rm *RoomManager) ListRooms() []protocol.RoomInfo {
+	rm.mu.RLock()
+	rooms := make([]protocol.RoomInfo, 0, len(rm.rooms)+1)
+	for _, room := range rm.rooms {
+		rooms = append(rooms, room.info())
+	}
+	_, hasDefault := rm.rooms[protocol.DefaultRoomID]
+	rm.mu.RUnlock()
+
+	if !hasDefault {
+		rooms = append(rooms, protocol.RoomInfo{
+			ID:       protocol.DefaultRoomID,
+			Name:     roomName(protocol.DefaultRoomID),
+			Capacity: rm.config.RoomCapacity,
+			Map:      roomMapName,
+		})
+	}
+	sort.Slice(rooms, func(i, j int) bool {
+		if rooms[i].Players != rooms[j].Players {
+			return rooms[i].Players > rooms[j].Players
+		}
+		return rooms[i].Name < rooms[j].Name
+	})
+	return rooms
+}
+
+// roomName is what the lobby calls a room; rooms players create are named by their ID
+func roomName(id string) string {
+	if id == protocol.DefaultRoomID {
+		return "Main Room"
+	}
+	return id
+}
+
+// validRoomID checks a room name a player wants to create or join
+func validRoomID(id string) error {
+	if id == "" || utf8.RuneCountInString(id) > protocol.MaxRoomIDLength {
+		return fmt.Errorf("Room names must be 1 to %d characters", protocol.MaxRoomIDLength)
+	}
+	for _, r := range id {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return errors.New("Room names can only use letters, numbers, - and _")
+		}
+	}
+	return nil
+}
+
+// info describes the room for ListRooms
+func (r *Room) info() protocol.RoomInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return protocol.RoomInfo{
+		ID:          r.ID,
+		Name:        r.nameLocked(),
+		Owner:       r.owner,
+		Players:     len(r.Clients),
+		Capacity:    r.capacityLocked(),
+		HasPassword: r.password != "",
+		Map:         roomMapName,
+	}
+}
+
+// Reasons GetOrCreateRoom turns a player away from a private room
+var (
+	errRoomPasswordRequired = errors.New("This room is private. Enter its password to join")
+	errRoomWrongPassword    = errors.New("Wrong password for this room")
+)
+
+// roomFullError turns a player away from a room at capacity, pointing them at another
+type roomFullError struct {
+	room           string // Name of the full room
+	suggestion     string // ID of a room with space, which may not exist yet
+	suggestionName string
+}
+
+func (e *roomFullError) Error() string {
+	return fmt.Sprintf("%s is full. Try %s instead", e.room, e.suggestionName)
+}
+
+// validRoomPassword checks the password a player gives a room they're creating
+func validRoomPassword(roomID, password string) error {
+	if password == "" {
+		return nil
+	}
+	if roomID == protocol.DefaultRoomID {
+		return errors.New("The main room is open to everyone and can't have a password")
+	}
+	if utf8.RuneCountInString(password) > protocol.MaxRoomPasswordLength {
+		return fmt.Errorf("Room passwords can be at most %d characters", protocol.MaxRoomPasswordLength)
 	}
+	return nil
 }
 
-// GetOrCreateRoom gets an existing room or creates a new one
-func (rm *RoomManager) GetOrCreateRoom(roomID string) *Room {
+// checkRoomPassword lets a player into a room if it's public (want is empty) or they
+// know its password
+func checkRoomPassword(want, given string) error {
+	switch {
+	case want == "":
+		return nil
+	case given == "":
+		return errRoomPasswordRequired
+	case subtle.ConstantTimeCompare([]byte(given), []byte(want)) != 1:
+		return errRoomWrongPassword
+	}
+	return nil
+}
+
+// full reports whether the room has as many players as it takes
+func (r *Room) full() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	capacity := r.capacityLocked()
+	return capacity > 0 && len(r.Clients) >= capacity
+}
+
+// CheckSpace returns a roomFullError if the room is at capacity. Players are only
+// counted once they're in, so a few joining at the same moment can still squeeze past.
+func (rm *RoomManager) CheckSpace(room *Room) error {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+	return rm.checkSpaceLocked(room)
+}
+
+// checkSpaceLocked is CheckSpace with rm.mu held
+func (rm *RoomManager) checkSpaceLocked(room *Room) error {
+	if !room.full() {
+		return nil
+	}
+	err := &roomFullError{room: room.name(), suggestion: rm.suggestRoomLocked(room.ID)}
+	err.suggestionName = roomName(err.suggestion)
+	if suggested, ok := rm.rooms[err.suggestion]; ok {
+		err.suggestionName = suggested.name()
+	}
+	return err
+}
+
+// suggestRoomLocked picks where to send a player who can't get into the full room: the
+// busiest public room with space, or else a new room named after the full one. rm.mu
+// must be held.
+func (rm *RoomManager) suggestRoomLocked(full string) string {
+	var best *Room
+	bestPlayers := -1
+	for id, room := range rm.rooms {
+		if id == full || room.password != "" || room.full() {
+			continue
+		}
+		if players := room.playerCount(); players > bestPlayers || (players == bestPlayers && id < best.ID) {
+			best, bestPlayers = room, players
+		}
+	}
+	if best != nil {
+		return best.ID
+	}
+	if _, ok := rm.rooms[protocol.DefaultRoomID]; !ok {
+		return protocol.DefaultRoomID
+	}
+
+	for n := 2; ; n++ {
+		suffix := "-" + strconv.Itoa(n)
+		base := []rune(full)
+		if len(base)+len(suffix) > protocol.MaxRoomIDLength {
+			base = base[:protocol.MaxRoomIDLength-len(suffix)]
+		}
+		if id := string(base) + suffix; rm.rooms[id] == nil {
+			return id
+		}
+	}
+}
+
+// playerCount is how many players are in the room
+func (r *Room) playerCount() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.Clients)
+}
+
+// GetOrCreateRoom gets an existing room for username, checking its password if it's
+// private, that it has space and that they weren't kicked, or creates a new one owned by
+// them. A password given for a new room makes it private.
+func (rm *RoomManager) GetOrCreateRoom(roomID, username, password string) (*Room, error) {
 	rm.mu.Lock()
 	defer rm.mu.Unlock()
 
 	if room, ok := rm.rooms[roomID]; ok {
-		return room
+		if err := checkRoomPassword(room.password, password); err != nil {
+			return nil, err
+		}
+		if err := room.checkKicked(username); err != nil {
+			return nil, err
+		}
+		if err := rm.checkSpaceLocked(room); err != nil {
+			return nil, err
+		}
+		room.expectJoin()
+		return room, nil
+	}
+	if err := rm.checkRoomLimitLocked(roomID, username); err != nil {
+		return nil, err
 	}
 
-	// Create new room
+	// Create new room, or bring back one saved before a restart with its old password
 	if roomID == "" {
 		roomID = uuid.New().String()
 	}
+	saved := savedRoom(rm.store, roomID)
+	if saved != nil && saved.Password != "" {
+		if err := checkRoomPassword(saved.Password, password); err != nil {
+			return nil, err
+		}
+		password = saved.Password
+	} else if err := validRoomPassword(roomID, password); err != nil {
+		return nil, err
+	}
 
-	room := NewRoom(roomID, rm.chatManager)
+	room := NewRoom(roomID, rm.chatManager, rm.users, NewTreasureHuntManager(roomID, rm.store, rm.config.Hunt, rm.config.Clock, rm.config.Seed), rm.config)
+	room.password = password
+	room.store = rm.store
+	room.manager = rm
+	room.restore(saved)
+	if room.owner == "" && roomID != protocol.DefaultRoomID {
+		room.owner = username
+	}
 	rm.rooms[roomID] = room
 
+	// Every room celebrates a win, not just the one whose riddle was solved
+	room.hunt.SetWinCallback(func(winner, answer string, points int) {
+		msg, _ := protocol.EncodeMessage(protocol.MsgTreasureHuntWinner, protocol.TreasureHuntWinnerPayload{
+			Winner: winner,
+			RoomID: roomID,
+			Answer: answer,
+			Points: points,
+		})
+		rm.BroadcastAll(msg)
+	})
+
 	go room.Run()
+	go room.hunt.StartGameLoop()
+
+	if password != "" {
+		log.Printf("Created new private room: %s", roomID)
+	} else {
+		log.Printf("Created new room: %s", roomID)
+	}
+	return room, nil
+}
+
+// BroadcastAll sends a message to the clients of every room right away, rather than
+// with the rooms' next tick
+func (rm *RoomManager) BroadcastAll(msg []byte) {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
 
-	log.Printf("Created new room: %s", roomID)
-	return room
+	for _, room := range rm.rooms {
+		select {
+		case room.broadcast <- msg:
+		default:
+			log.Printf("Broadcast queue for room %s is full, dropping message", room.ID)
+		}
+	}
 }
 
 // GetRoom gets an existing room
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// roomEmptyGrace is how long a room can go without players before it's closed, its hunt
+// stopped and its goroutines ended. Its owner, password and positions stay in the store
+// and come back if anyone joins it again.
+const roomEmptyGrace = 10 * time.Minute
+
+// maxOwnedRooms is how many running rooms one player can have created
+const maxOwnedRooms = 3
+
+var errRoomClosed = errors.New("That room closed while nobody was in it. Pick a room from the lobby")
+
+// checkRoomLimitLocked refuses to create another room once the server runs as many as
+// it takes, or username already owns maxOwnedRooms of them. The main room is always let
+// through. rm.mu must be held.
+func (rm *RoomManager) checkRoomLimitLocked(roomID, username string) error {
+	if roomID == protocol.DefaultRoomID {
+		return nil
+	}
+	if rm.config.MaxRooms > 0 && len(rm.rooms) >= rm.config.MaxRooms {
+		return errors.New("The server can't open any more rooms right now. Join one from the lobby")
+	}
+	owned := 0
+	for _, room := range rm.rooms {
+		if room.owner == username {
+			owned++
+		}
+	}
+	if owned >= maxOwnedRooms {
+		return fmt.Errorf("You already have %d rooms open. Use one of them, or wait for an empty one to close", owned)
+	}
+	return nil
+}
+
+// expectJoin puts off closing an empty room, since someone is about to join it
+func (r *Room) expectJoin() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.Clients) == 0 {
+		r.emptySince = r.clock.Now()
+	}
+}
+
+// abandoned reports whether the room has had nobody in it for roomEmptyGrace
+func (r *Room) abandoned() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.Clients) == 0 && r.clock.Now().Sub(r.emptySince) >= roomEmptyGrace
+}
+
+// reap removes the room if it has been abandoned, reporting whether it did; Run then
+// closes it. The main room always stays.
+func (rm *RoomManager) reap(r *Room) bool {
+	if r.ID == protocol.DefaultRoomID {
+		return false
+	}
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	if rm.rooms[r.ID] != r || !r.abandoned() {
+		return false
+	}
+	delete(rm.rooms, r.ID)
+	return true
+}
+
+// close stops the room's hunt and recording once the manager has removed it. It must
+// only be called from the Run loop, which returns right after.
+func (r *Room) close() {
+	close(r.quit)
+	r.hunt.Close()
+	if r.recording.Load() != nil {
+		r.StopRecording()
+	}
+	r.save(true)
+	log.Printf("Closed room %s, empty for %s", r.ID, roomEmptyGrace)
+}
+
+// join registers the client with the room, unless the room closed since it was picked
+func (r *Room) join(client *Client) error {
+	select {
+	case r.register <- client:
+		return nil
+	case <-r.quit:
+		return errRoomClosed
+	}
+}
+
+// leave unregisters the client from the room, if it's still running
+func (r *Room) leave(client *Client) {
+	select {
+	case r.unregister <- client:
+	case <-r.quit:
+	}
+}
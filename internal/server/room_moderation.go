@@ -0,0 +1,223 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// kickBan is how long a kicked player is kept out of the room
+const kickBan = 5 * time.Minute
+
+// ownerOnlyActions are the room actions moderators can't take
+var ownerOnlyActions = map[string]bool{
+	protocol.RoomActionRename:   true,
+	protocol.RoomActionCapacity: true,
+	protocol.RoomActionMod:      true,
+	protocol.RoomActionUnmod:    true,
+}
+
+// name is what players see the room called
+func (r *Room) name() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.nameLocked()
+}
+
+// nameLocked is name with r.mu held
+func (r *Room) nameLocked() string {
+	if r.customName != "" {
+		return r.customName
+	}
+	return roomName(r.ID)
+}
+
+// capacityLocked is the most players the room takes, 0 for no limit; r.mu must be held
+func (r *Room) capacityLocked() int {
+	if r.customCapacity > 0 {
+		return r.customCapacity
+	}
+	return r.config.RoomCapacity
+}
+
+// canModerateLocked reports whether the player may kick and clear chat; r.mu must be held
+func (r *Room) canModerateLocked(username string) bool {
+	return username != "" && (username == r.owner || r.moderators[username])
+}
+
+// checkKicked keeps a kicked player out until their ban is up
+func (r *Room) checkKicked(username string) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := r.clock.Now()
+	until, ok := r.kicked[username]
+	if !ok || now.After(until) {
+		return nil
+	}
+	return fmt.Errorf("You were kicked from %s. You can come back in %s",
+		r.nameLocked(), until.Sub(now).Round(time.Second))
+}
+
+// HandleModerate runs an owner or moderator action on the room, telling everyone in it
+// what changed
+func (r *Room) HandleModerate(client *Client, payload protocol.RoomModeratePayload) {
+	r.mu.Lock()
+	isOwner := client.Username == r.owner && r.owner != ""
+	if !r.canModerateLocked(client.Username) {
+		r.mu.Unlock()
+		sendError(client, "Only the room's owner and moderators can do that")
+		return
+	}
+	if ownerOnlyActions[payload.Action] && !isOwner {
+		r.mu.Unlock()
+		sendError(client, "Only the room's owner can do that")
+		return
+	}
+
+	var notice string
+	var kick *Client
+	switch payload.Action {
+	case protocol.RoomActionRename:
+		name := strings.TrimSpace(payload.Name)
+		if err := validRoomName(name); err != nil {
+			r.mu.Unlock()
+			sendError(client, err.Error())
+			return
+		}
+		r.customName = name
+		notice = fmt.Sprintf("%s renamed the room to %s", client.Username, name)
+
+	case protocol.RoomActionCapacity:
+		if limit := r.config.RoomCapacity; payload.Capacity < 1 || (limit > 0 && payload.Capacity > limit) {
+			r.mu.Unlock()
+			if limit == 0 {
+				sendError(client, "Capacity must be at least 1")
+			} else {
+				sendError(client, fmt.Sprintf("Capacity must be between 1 and %d", limit))
+			}
+			return
+		}
+		r.customCapacity = payload.Capacity
+		notice = fmt.Sprintf("%s set the room's capacity to %d", client.Username, payload.Capacity)
+
+	case protocol.RoomActionKick:
+		target := r.clientByUsernameLocked(payload.Username)
+		switch {
+		case target == nil:
+			r.mu.Unlock()
+			sendError(client, fmt.Sprintf("%s isn't in this room", payload.Username))
+			return
+		case target == client:
+			r.mu.Unlock()
+			sendError(client, "You can't kick yourself")
+			return
+		case target.Username == r.owner || (r.moderators[target.Username] && !isOwner):
+			r.mu.Unlock()
+			sendError(client, fmt.Sprintf("You can't kick %s", target.Username))
+			return
+		}
+		r.kicked[target.Username] = r.clock.Now().Add(kickBan)
+		kick = target
+		notice = fmt.Sprintf("%s kicked %s from the room", client.Username, target.Username)
+
+	case protocol.RoomActionClearChat:
+		notice = fmt.Sprintf("%s cleared the room chat", client.Username)
+
+	case protocol.RoomActionMod, protocol.RoomActionUnmod:
+		if payload.Username == "" || payload.Username == r.owner {
+			r.mu.Unlock()
+			sendError(client, "Name another player to make them a moderator")
+			return
+		}
+		if payload.Action == protocol.RoomActionMod {
+			r.moderators[payload.Username] = true
+			notice = fmt.Sprintf("%s made %s a moderator", client.Username, payload.Username)
+		} else {
+			delete(r.moderators, payload.Username)
+			notice = fmt.Sprintf("%s is no longer a moderator", payload.Username)
+		}
+
+	default:
+		r.mu.Unlock()
+		sendError(client, fmt.Sprintf("Unknown room action %q", payload.Action))
+		return
+	}
+	r.unsaved = true
+	r.mu.Unlock()
+
+	if payload.Action == protocol.RoomActionClearChat {
+		r.chatManager.ClearRoomChat(r.ID)
+		// States only carry new messages, so tell everyone to empty theirs
+		for _, history := range r.chatManager.RoomHistory(r) {
+			msg, _ := protocol.EncodeMessage(protocol.MsgRoomChatMessages, history)
+			r.broadcast <- msg
+		}
+	}
+	log.Printf("Room %s: %s", r.ID, notice)
+	emote, _ := protocol.EncodeMessage(protocol.MsgEmote, protocol.EmotePayload{
+		Username:  client.Username,
+		Message:   notice,
+		Timestamp: r.clock.Now().Unix(),
+	})
+	r.broadcast <- emote
+
+	if kick != nil {
+		r.disconnectKicked(kick, fmt.Sprintf("%s kicked you from the room. You can come back in %s", client.Username, kickBan))
+	}
+}
+
+// AdminKick disconnects a player for an admin and keeps them out of the room for
+// kickBan, as a kick by the room's owner does
+func (r *Room) AdminKick(username string) error {
+	r.mu.Lock()
+	target := r.clientByUsernameLocked(username)
+	if target == nil {
+		r.mu.Unlock()
+		return fmt.Errorf("%s isn't in room %s", username, r.ID)
+	}
+	r.kicked[username] = r.clock.Now().Add(kickBan)
+	r.unsaved = true
+	r.mu.Unlock()
+
+	log.Printf("Room %s: an admin kicked %s", r.ID, username)
+	r.disconnectKicked(target, fmt.Sprintf("An admin kicked you from the room. You can come back in %s", kickBan))
+	return nil
+}
+
+// disconnectKicked tells a kicked player why, then hangs up on them. Their readPump
+// takes them out of the room once the connection is closed.
+func (r *Room) disconnectKicked(client *Client, message string) {
+	msg, _ := protocol.EncodeMessage(protocol.MsgError, protocol.ErrorPayload{Message: message})
+	client.deliver(msg)
+	client.disconnect()
+}
+
+// clientByUsernameLocked finds a player in the room; r.mu must be held
+func (r *Room) clientByUsernameLocked(username string) *Client {
+	for _, client := range r.Clients {
+		if client.Username == username {
+			return client
+		}
+	}
+	return nil
+}
+
+// validRoomName checks a name an owner wants to give their room
+func validRoomName(name string) error {
+	if name == "" || utf8.RuneCountInString(name) > protocol.MaxRoomNameLength {
+		return fmt.Errorf("Room names must be 1 to %d characters", protocol.MaxRoomNameLength)
+	}
+	for _, r := range name {
+		if !unicode.IsPrint(r) {
+			return errors.New("Room names can't have control characters")
+		}
+	}
+	return nil
+}
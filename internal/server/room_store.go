@@ -0,0 +1,105 @@
+package server
+
+import (
+	"log"
+	"maps"
+	"slices"
+	"time"
+)
+
+// roomSaveInterval is how often a room that changed is written to the store
+const roomSaveInterval = time.Minute
+
+// RoomSnapshot is a room's world as saved in the store, so a deploy doesn't reset it.
+// It's brought back when someone next joins the room.
+type RoomSnapshot struct {
+	Password   string               `json:"password,omitempty"`
+	Owner      string               `json:"owner,omitempty"`
+	Moderators []string             `json:"moderators,omitempty"`
+	Name       string               `json:"name,omitempty"`     // Set by the owner
+	Capacity   int                  `json:"capacity,omitempty"` // Set by the owner
+	Positions  map[string]string    `json:"positions"`          // Username -> "Y:X" where they last left the room
+	Pomodoros  map[string]*pomodoro `json:"pomodoros"`          // Building room number -> running timer
+	SavedAt    time.Time            `json:"saved_at"`
+}
+
+// savedRoom returns the room's snapshot from the store, or nil if it was never saved
+func savedRoom(store *Store, roomID string) *RoomSnapshot {
+	var snapshot *RoomSnapshot
+	store.View(func(d *StoreData) {
+		if saved, ok := d.Rooms[roomID]; ok {
+			copied := *saved
+			snapshot = &copied
+		}
+	})
+	return snapshot
+}
+
+// restore loads a saved snapshot into a room that isn't running yet
+func (r *Room) restore(snapshot *RoomSnapshot) {
+	if snapshot == nil {
+		return
+	}
+	r.owner = snapshot.Owner
+	for _, username := range snapshot.Moderators {
+		r.moderators[username] = true
+	}
+	r.customName = snapshot.Name
+	r.customCapacity = snapshot.Capacity
+	maps.Copy(r.lastPositions, snapshot.Positions)
+	for roomNumber, p := range snapshot.Pomodoros {
+		timer := *p
+		r.pomodoros[roomNumber] = &timer // Catches up on its next tick if it ended while we were down
+	}
+	log.Printf("Restored room %s saved at %s (%d positions, %d pomodoros)",
+		r.ID, snapshot.SavedAt.Format(time.RFC3339), len(r.lastPositions), len(r.pomodoros))
+}
+
+// save writes the room to the store; unless force is set, only if it changed since the
+// last save. Players still in the room are saved where they're standing.
+func (r *Room) save(force bool) {
+	r.mu.Lock()
+	if r.store == nil || (!r.unsaved && !force) {
+		r.mu.Unlock()
+		return
+	}
+	snapshot := &RoomSnapshot{
+		Password:   r.password,
+		Owner:      r.owner,
+		Moderators: slices.Sorted(maps.Keys(r.moderators)),
+		Name:       r.customName,
+		Capacity:   r.customCapacity,
+		Positions:  maps.Clone(r.lastPositions),
+		Pomodoros:  make(map[string]*pomodoro, len(r.pomodoros)),
+		SavedAt:    time.Now(),
+	}
+	current := make(map[string]string, len(r.Clients))
+	for _, c := range r.Clients {
+		snapshot.Positions[c.Username] = c.Pos
+		current[c.Username] = c.Pos
+	}
+	for roomNumber, p := range r.pomodoros {
+		timer := *p
+		snapshot.Pomodoros[roomNumber] = &timer
+	}
+	r.unsaved = false
+	r.mu.Unlock()
+
+	err := r.store.Update(func(d *StoreData) {
+		d.Rooms[r.ID] = snapshot
+	})
+	if err != nil {
+		log.Printf("Error saving room %s: %v", r.ID, err)
+	}
+	r.users.SetLastPositions(r.ID, current)
+}
+
+// SaveRooms writes every room to the store, for when the server shuts down
+func (rm *RoomManager) SaveRooms() {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	for _, room := range rm.rooms {
+		room.save(true)
+	}
+}
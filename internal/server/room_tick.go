@@ -0,0 +1,148 @@
+package server
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// activeWindow is how long after a player moves or chats a room keeps sending a state every
+// tick. After that the time between states doubles every second until it reaches the idle
+// rate.
+const activeWindow = time.Second
+
+// requests are the messages that only ask the server for something, so they don't change
+// what anyone else sees and leave a quiet room's state rate alone
+var requests = map[protocol.MessageType]bool{
+	protocol.MsgLeaderboardRequest:         true,
+	protocol.MsgShopRequest:                true,
+	protocol.MsgTreasureHuntHistoryRequest: true,
+	protocol.MsgAnnouncementHistoryRequest: true,
+	protocol.MsgChatHistoryRequest:         true,
+	protocol.MsgListRooms:                  true,
+	protocol.MsgPing:                       true,
+}
+
+// TickStats counts how the rooms' ticks have kept up since the server started
+type TickStats struct {
+	Ticks       int64  `json:"ticks"`
+	Overruns    int64  `json:"overruns"`     // Ticks that took longer than the room's tick rate
+	SlowestTick string `json:"slowest_tick"` // Longest a tick has taken
+	AverageTick string `json:"average_tick"`
+}
+
+var ticks, tickOverruns, tickNanos, slowestTick atomic.Int64
+
+// Ticks returns the tick counters
+func Ticks() TickStats {
+	n := ticks.Load()
+	average := time.Duration(0)
+	if n > 0 {
+		average = time.Duration(tickNanos.Load() / n)
+	}
+	return TickStats{
+		Ticks:       n,
+		Overruns:    tickOverruns.Load(),
+		SlowestTick: time.Duration(slowestTick.Load()).String(),
+		AverageTick: average.String(),
+	}
+}
+
+// countTick records how long a tick took against the rate it had to keep up with
+func countTick(took, rate time.Duration) {
+	ticks.Add(1)
+	tickNanos.Add(int64(took))
+	if took > rate {
+		tickOverruns.Add(1)
+	}
+	for {
+		slowest := slowestTick.Load()
+		if int64(took) <= slowest || slowestTick.CompareAndSwap(slowest, int64(took)) {
+			return
+		}
+	}
+}
+
+// currentTickRate is how often the room should tick right now: its tick rate while anyone
+// is in it, so NPCs, timers and idle checks keep their pace, or the idle rate while it's
+// empty
+func (r *Room) currentTickRate() time.Duration {
+	r.mu.RLock()
+	players, rate := len(r.Clients), r.tickRate
+	r.mu.RUnlock()
+
+	if players == 0 {
+		return max(r.config.IdleTickRate, rate)
+	}
+	return rate
+}
+
+// stateInterval is how long the room should leave between the states it sends: none
+// while players are moving or chatting, then longer the longer the room stays quiet,
+// until it reaches the idle rate. A running game mode keeps every tick's state going out
+// since players chase and hide by each other's positions.
+func (r *Room) stateInterval(now time.Time) time.Duration {
+	r.mu.RLock()
+	mode, rate := r.mode, r.tickRate
+	r.mu.RUnlock()
+
+	quiet := now.Sub(time.Unix(0, r.lastActivity.Load()))
+	if mode != nil || quiet < activeWindow {
+		return 0
+	}
+	idle := max(r.config.IdleTickRate, rate)
+	slowed := rate
+	for waited := activeWindow; waited <= quiet && slowed < idle; waited += time.Second {
+		slowed *= 2
+	}
+	return min(slowed, idle)
+}
+
+// stateDue reports whether this tick should send a state, and if so counts it as sent.
+// It must only be called from the Run loop.
+func (r *Room) stateDue(now time.Time) bool {
+	if now.Sub(r.stateSentAt) < r.stateInterval(now) {
+		return false
+	}
+	r.stateSentAt = now
+	return true
+}
+
+// markActive records that a player moved, chatted or otherwise changed what the others
+// see, so the room goes straight back to sending a state every tick
+func (r *Room) markActive() {
+	r.lastActivity.Store(r.clock.Now().UnixNano())
+}
+
+// markAllActive brings every room back to sending a state every tick, for chat everyone sees
+func (rm *RoomManager) markAllActive() {
+	rm.mu.RLock()
+	defer rm.mu.RUnlock()
+
+	for _, room := range rm.rooms {
+		room.markActive()
+	}
+}
+
+// SetTickRate changes how often the room ticks while players are active
+func (r *Room) SetTickRate(rate time.Duration) error {
+	if rate < minTickRate {
+		return fmt.Errorf("the tick rate must be at least %s", minTickRate)
+	}
+	r.mu.Lock()
+	r.tickRate = rate
+	r.mu.Unlock()
+	r.nudge()
+	return nil
+}
+
+// nudge tells the room's loop to work out its tick rate again now instead of on its
+// next tick, which could be an empty room's slow one
+func (r *Room) nudge() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,283 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/gamemap"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+const (
+	scavengerClueCount = 5
+	scavengerTimeout   = 20 * time.Minute // Hunt ends if nobody finishes by then
+	scavengerRadius    = 4                // How close to a spot a player must stand to claim it
+	scavengerTheme     = "a computer science building on the UW Madison campus"
+)
+
+// scavengerLocation is a named place on the map a clue can point to. A player is
+// there if they're inside Room, or within scavengerRadius of (X, Y) when Room is "".
+type scavengerLocation struct {
+	Name string
+	Room string
+	X, Y int
+}
+
+// scavengerSpots are the named non-room locations; rooms are added by scavengerLocations
+var scavengerSpots = []scavengerLocation{
+	{Name: "Main Corridor", X: 120, Y: 40},
+	{Name: "Study Hall", Room: "13"},
+}
+
+// scavengerLocations returns every place a clue can point to
+func scavengerLocations() []scavengerLocation {
+	locations := make([]scavengerLocation, 0, len(interactables)+len(scavengerSpots)+len(gamemap.RoomCoordinates))
+	for _, obj := range interactables {
+		locations = append(locations, scavengerLocation{Name: obj.Name, X: obj.X, Y: obj.Y})
+	}
+	locations = append(locations, scavengerSpots...)
+	for _, room := range gamemap.RoomCoordinates {
+		if room.Name == "13" {
+			continue // Named "Study Hall" above
+		}
+		locations = append(locations, scavengerLocation{Name: "Room " + room.Name, Room: room.Name})
+	}
+	return locations
+}
+
+// contains reports whether a player at (x, y) in roomNumber is at the location
+func (l scavengerLocation) contains(x, y int, roomNumber string) bool {
+	if l.Room != "" {
+		return roomNumber == l.Room
+	}
+	return max(abs(l.X-x), abs(l.Y-y)) <= scavengerRadius
+}
+
+type scavengerClue struct {
+	Location scavengerLocation
+	Riddle   string
+}
+
+type scavengerHunt struct {
+	Title       string
+	Description string
+	Clues       []scavengerClue
+	Progress    map[string]int // Username -> clues claimed
+	Started     time.Time
+}
+
+// defaultScavengerHunt is used when Gemini is unavailable
+func defaultScavengerHunt() *scavengerHunt {
+	byName := make(map[string]scavengerLocation)
+	for _, loc := range scavengerLocations() {
+		byName[loc.Name] = loc
+	}
+
+	return &scavengerHunt{
+		Title:       "The Caffeinated Trail",
+		Description: "Follow the fuel of every late-night coder.",
+		Clues: []scavengerClue{
+			{Location: byName["Coffee Machine"], Riddle: "I keep the night owls compiling, one cup at a time."},
+			{Location: byName["Water Fountain"], Riddle: "Press my button and I'll cool you down for free."},
+			{Location: byName["Vending Machine"], Riddle: "Insert a dollar, receive a snack... usually."},
+			{Location: byName["Study Hall"], Riddle: "Long tables, quiet voices, and a librarian watching."},
+			{Location: byName["Main Corridor"], Riddle: "Everyone passes through me, but nobody stays."},
+		},
+	}
+}
+
+// newScavengerHunt generates a hunt with Gemini, keeping only clues that point at
+// known locations, and falls back to the default hunt if that fails
+func newScavengerHunt() *scavengerHunt {
+	locations := scavengerLocations()
+	byName := make(map[string]scavengerLocation, len(locations))
+	names := make([]string, len(locations))
+	for i, loc := range locations {
+		byName[strings.ToLower(loc.Name)] = loc
+		names[i] = loc.Name
+	}
+
+	hunt := defaultScavengerHunt()
+	treasureMap, err := GenerateTreasureMap(scavengerTheme, names, scavengerClueCount)
+	if err != nil {
+		log.Printf("Error generating scavenger hunt, using the default one: %v", err)
+	} else {
+		var clues []scavengerClue
+		used := make(map[string]bool)
+		for _, clue := range treasureMap.Clues {
+			key := strings.ToLower(strings.TrimSpace(clue.Location))
+			if loc, ok := byName[key]; ok && !used[key] && clue.Riddle != "" {
+				used[key] = true
+				clues = append(clues, scavengerClue{Location: loc, Riddle: clue.Riddle})
+			}
+		}
+		if len(clues) >= 3 {
+			hunt = &scavengerHunt{
+				Title:       treasureMap.Title,
+				Description: treasureMap.Description,
+				Clues:       clues,
+			}
+		} else {
+			log.Printf("Generated scavenger hunt only had %d usable clues, using the default one", len(clues))
+		}
+	}
+
+	hunt.Progress = make(map[string]int)
+	return hunt
+}
+
+// ScavengerManager runs a room's location-based scavenger hunt
+type ScavengerManager struct {
+	mu         sync.Mutex
+	hunt       *scavengerHunt
+	generating bool
+}
+
+// HandleScavenger starts a hunt, sends the client's current clue, or claims it
+func (r *Room) HandleScavenger(client *Client, action string) {
+	sm := r.scavenger
+	sm.mu.Lock()
+
+	switch action {
+	case "start":
+		if sm.hunt != nil || sm.generating {
+			sm.mu.Unlock()
+			sendError(client, "A scavenger hunt is already running - type /scavenger for your clue")
+			return
+		}
+		sm.generating = true
+		sm.mu.Unlock()
+
+		r.postNotice(fmt.Sprintf("🗺️ %s is drawing up a scavenger hunt...", client.Username))
+		go r.startScavengerHunt()
+		return
+
+	case "clue", "":
+		hunt := sm.hunt
+		if hunt == nil {
+			sm.mu.Unlock()
+			sendError(client, "No scavenger hunt is running - start one with /scavenger start")
+			return
+		}
+		state := scavengerStateFor(hunt, client.Username, "")
+		sm.mu.Unlock()
+		sendScavengerState(client, state)
+		return
+
+	case "claim":
+		hunt := sm.hunt
+		if hunt == nil {
+			sm.mu.Unlock()
+			sendError(client, "No scavenger hunt is running")
+			return
+		}
+
+		r.mu.RLock()
+		x, y := parsePos(client.Pos)
+		roomNumber := client.CurrentRoomNumber
+		r.mu.RUnlock()
+
+		clue := hunt.Clues[hunt.Progress[client.Username]]
+		if !clue.Location.contains(x, y, roomNumber) {
+			sm.mu.Unlock()
+			sendError(client, "Nothing here... read the clue again!")
+			return
+		}
+
+		hunt.Progress[client.Username]++
+		if hunt.Progress[client.Username] < len(hunt.Clues) {
+			state := scavengerStateFor(hunt, client.Username, fmt.Sprintf("✅ Found the %s!", clue.Location.Name))
+			sm.mu.Unlock()
+			sendScavengerState(client, state)
+			awardPoints(r.users, client, pointsScavengerClue, "Found a scavenger hunt clue")
+			return
+		}
+
+		// First one to claim every clue wins and ends the hunt
+		players := r.endScavengerHuntLocked()
+		sm.mu.Unlock()
+
+		log.Printf("Scavenger hunt %q won by %s", hunt.Title, client.Username)
+		r.postNotice(fmt.Sprintf("🏆 %s finished the scavenger hunt \"%s\" first!", client.Username, hunt.Title))
+		r.sendScavengerEnded(players, fmt.Sprintf("%s won the scavenger hunt", client.Username))
+		awardPoints(r.users, client, pointsScavengerWin, "Won the scavenger hunt")
+		return
+	}
+
+	sm.mu.Unlock()
+	sendError(client, fmt.Sprintf("Unknown scavenger action %q", action))
+}
+
+// startScavengerHunt generates a hunt (slow - it calls Gemini) and announces it
+func (r *Room) startScavengerHunt() {
+	hunt := newScavengerHunt()
+	hunt.Started = r.clock.Now()
+
+	r.scavenger.mu.Lock()
+	r.scavenger.hunt = hunt
+	r.scavenger.generating = false
+	r.scavenger.mu.Unlock()
+
+	log.Printf("Scavenger hunt %q started with %d clues", hunt.Title, len(hunt.Clues))
+	r.postNotice(fmt.Sprintf("🗺️ Scavenger hunt \"%s\" has begun! %s Type /scavenger for your first clue.",
+		hunt.Title, hunt.Description))
+}
+
+// expireScavengerHunt ends a hunt nobody finished in time. It must only be called from
+// the Run loop since it broadcasts directly.
+func (r *Room) expireScavengerHunt() {
+	sm := r.scavenger
+	sm.mu.Lock()
+	if sm.hunt == nil || r.clock.Now().Sub(sm.hunt.Started) < scavengerTimeout {
+		sm.mu.Unlock()
+		return
+	}
+	title := sm.hunt.Title
+	players := r.endScavengerHuntLocked()
+	sm.mu.Unlock()
+
+	r.handleBroadcast(r.notice(fmt.Sprintf("⌛ Nobody finished the scavenger hunt \"%s\" in time.", title)))
+	r.sendScavengerEnded(players, "The scavenger hunt ran out of time")
+}
+
+// endScavengerHuntLocked clears the hunt and returns who took part; r.scavenger.mu must be held
+func (r *Room) endScavengerHuntLocked() []string {
+	var players []string
+	for username := range r.scavenger.hunt.Progress {
+		players = append(players, username)
+	}
+	r.scavenger.hunt = nil
+	return players
+}
+
+// sendScavengerEnded tells everyone who took part that the hunt is over
+func (r *Room) sendScavengerEnded(players []string, message string) {
+	for _, username := range players {
+		if c := r.clientByUsername(username); c != nil {
+			sendScavengerState(c, protocol.ScavengerStatePayload{Message: message})
+		}
+	}
+}
+
+// scavengerStateFor returns a player's progress and current clue; the hunt's lock must be held
+func scavengerStateFor(hunt *scavengerHunt, username, message string) protocol.ScavengerStatePayload {
+	found := hunt.Progress[username] // Also registers the player as taking part
+	hunt.Progress[username] = found
+
+	return protocol.ScavengerStatePayload{
+		Active:  true,
+		Title:   hunt.Title,
+		Clue:    hunt.Clues[found].Riddle,
+		Found:   found,
+		Total:   len(hunt.Clues),
+		Message: message,
+	}
+}
+
+func sendScavengerState(client *Client, state protocol.ScavengerStatePayload) {
+	msg, _ := protocol.EncodeMessage(protocol.MsgScavengerState, state)
+	client.deliver(msg)
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"crypto/subtle"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// SessionManager hands each player a token when they join a room. A client that lost
+// its connection sends the token back when it rejoins, which lets it take over the
+// connection it left behind before the server has noticed that one is gone.
+type SessionManager struct {
+	mu     sync.Mutex
+	tokens map[string]string // Username -> token of their latest session
+}
+
+// NewSessionManager creates an empty session manager
+func NewSessionManager() *SessionManager {
+	return &SessionManager{tokens: make(map[string]string)}
+}
+
+// Resume returns the user's session token and whether the one they sent is it. A wrong
+// or missing token starts a new session.
+func (sm *SessionManager) Resume(username, token string) (string, bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if current, ok := sm.tokens[username]; ok && token != "" &&
+		subtle.ConstantTimeCompare([]byte(current), []byte(token)) == 1 {
+		return current, true
+	}
+	current := uuid.New().String()
+	sm.tokens[username] = current
+	return current, false
+}
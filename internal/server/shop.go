@@ -0,0 +1,112 @@
+package server
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// Kinds of cosmetic; a player can wear one of each
+const (
+	cosmeticAccessory = "accessory"
+	cosmeticNameColor = "name_color"
+)
+
+// shopItems is everything the shop sells. Value is the glyph drawn above the avatar
+// for accessories and the hex color of the username for name colors.
+var shopItems = []protocol.ShopItem{
+	{ID: "acc_star", Name: "Gold Star", Kind: cosmeticAccessory, Price: 100, Value: "★"},
+	{ID: "acc_music", Name: "Headphones", Kind: cosmeticAccessory, Price: 120, Value: "♫"},
+	{ID: "acc_heart", Name: "Heart", Kind: cosmeticAccessory, Price: 120, Value: "♥"},
+	{ID: "acc_crown", Name: "Crown", Kind: cosmeticAccessory, Price: 300, Value: "♛"},
+	{ID: "acc_sun", Name: "Streak Sun", Kind: cosmeticAccessory, Value: "☼", Reward: true}, // 7-day login streak
+	{ID: "name_forest", Name: "Forest Name", Kind: cosmeticNameColor, Price: 80, Value: "#2E8B57"},
+	{ID: "name_ocean", Name: "Ocean Name", Kind: cosmeticNameColor, Price: 80, Value: "#1F6FB2"},
+	{ID: "name_crimson", Name: "Badger Red Name", Kind: cosmeticNameColor, Price: 150, Value: "#C5050C"},
+	{ID: "name_gold", Name: "Gold Name", Kind: cosmeticNameColor, Price: 250, Value: "#B8860B"},
+}
+
+// shopItemByID looks up an item in the catalog
+func shopItemByID(id string) (protocol.ShopItem, bool) {
+	for _, item := range shopItems {
+		if item.ID == id {
+			return item, true
+		}
+	}
+	return protocol.ShopItem{}, false
+}
+
+// handleShopBuy buys an item with the client's points
+func (s *Server) handleShopBuy(c *Client, itemID string) {
+	item, ok := shopItemByID(itemID)
+	if !ok {
+		sendError(c, fmt.Sprintf("The shop doesn't sell %q", itemID))
+		return
+	}
+
+	balance, err := s.userManager.BuyItem(c.Username, item)
+	if err != nil {
+		sendError(c, err.Error())
+		return
+	}
+
+	log.Printf("%s bought %s for %d points", c.Username, item.ID, item.Price)
+	sendPoints(c, balance, -item.Price, "Bought "+item.Name)
+	s.sendShopState(c, fmt.Sprintf("Bought %s! Press ENTER again to wear it.", item.Name))
+}
+
+// handleShopEquip puts on an owned item, or takes it off if it's already worn
+func (s *Server) handleShopEquip(c *Client, itemID string) {
+	item, ok := shopItemByID(itemID)
+	if !ok {
+		sendError(c, fmt.Sprintf("The shop doesn't sell %q", itemID))
+		return
+	}
+
+	worn, err := s.userManager.ToggleEquipped(c.Username, item)
+	if err != nil {
+		sendError(c, err.Error())
+		return
+	}
+
+	applyCosmetics(s.userManager, c)
+	if worn {
+		s.sendShopState(c, "Now wearing "+item.Name)
+	} else {
+		s.sendShopState(c, "Took off "+item.Name)
+	}
+}
+
+// sendShopState sends the catalog with what the client owns and wears
+func (s *Server) sendShopState(c *Client, message string) {
+	owned, equipped := s.userManager.Cosmetics(c.Username)
+	msg, _ := protocol.EncodeMessage(protocol.MsgShopState, protocol.ShopStatePayload{
+		Items:    shopItems,
+		Owned:    owned,
+		Equipped: equipped,
+		Message:  message,
+	})
+	c.deliver(msg)
+}
+
+// applyCosmetics copies the user's equipped items onto the client so other players see them
+func applyCosmetics(users *UserManager, c *Client) {
+	_, equipped := users.Cosmetics(c.Username)
+
+	var accessory, nameColor string
+	if item, ok := shopItemByID(equipped[cosmeticAccessory]); ok {
+		accessory = item.Value
+	}
+	if item, ok := shopItemByID(equipped[cosmeticNameColor]); ok {
+		nameColor = item.Value
+	}
+
+	// The room's update loop reads these while building player state
+	if c.Room != nil {
+		c.Room.mu.Lock()
+		defer c.Room.mu.Unlock()
+	}
+	c.Accessory = accessory
+	c.NameColor = nameColor
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is where rooms and treasure hunts get the time and their tickers, so a
+// simulation can run them on a clock it moves itself (see ManualClock) instead of the
+// wall clock
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+	After(d time.Duration) <-chan time.Time
+	AfterFunc(d time.Duration, f func())
+}
+
+// Ticker is the part of time.Ticker the server uses
+type Ticker interface {
+	Chan() <-chan time.Time
+	Reset(d time.Duration)
+	Stop()
+}
+
+// clockOrWall returns c, or the wall clock if it's nil
+func clockOrWall(c Clock) Clock {
+	if c == nil {
+		return wallClock{}
+	}
+	return c
+}
+
+// wallClock is the real time
+type wallClock struct{}
+
+func (wallClock) Now() time.Time                         { return time.Now() }
+func (wallClock) NewTicker(d time.Duration) Ticker       { return wallTicker{time.NewTicker(d)} }
+func (wallClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (wallClock) AfterFunc(d time.Duration, f func())    { time.AfterFunc(d, f) }
+
+type wallTicker struct {
+	*time.Ticker
+}
+
+func (t wallTicker) Chan() <-chan time.Time { return t.C }
+
+// ManualClock only moves when Advance is called, firing the tickers and timers that
+// come due on the way in order, so a simulation's timing is the same on every run
+type ManualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*manualTimer
+}
+
+// manualTimer is a ticker (every > 0) or a one-off timer waiting on a ManualClock
+type manualTimer struct {
+	clock *ManualClock
+	next  time.Time
+	every time.Duration
+	c     chan time.Time
+	f     func() // Run instead of sending on c, for AfterFunc
+}
+
+// NewManualClock returns a clock stopped at start
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now returns the clock's time
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTicker ticks every d of the clock's time. Like time.Ticker it drops ticks nobody
+// picked up.
+func (c *ManualClock) NewTicker(d time.Duration) Ticker {
+	t := &manualTimer{clock: c, every: d, c: make(chan time.Time, 1)}
+	c.add(t, d)
+	return t
+}
+
+// After sends the clock's time once d has passed on it
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	t := &manualTimer{clock: c, c: make(chan time.Time, 1)}
+	c.add(t, d)
+	return t.c
+}
+
+// AfterFunc runs f on its own goroutine once d has passed on the clock
+func (c *ManualClock) AfterFunc(d time.Duration, f func()) {
+	c.add(&manualTimer{clock: c, f: f}, d)
+}
+
+// Advance moves the clock on by d, firing everything that comes due
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	for {
+		sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].next.Before(c.timers[j].next) })
+		if len(c.timers) == 0 || c.timers[0].next.After(end) {
+			break
+		}
+		t := c.timers[0]
+		c.now = t.next
+		if t.every > 0 {
+			t.next = t.next.Add(t.every)
+		} else {
+			c.timers = c.timers[1:]
+		}
+		t.fire(c.now)
+	}
+	c.now = end
+	c.mu.Unlock()
+}
+
+func (c *ManualClock) add(t *manualTimer, d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t.next = c.now.Add(d)
+	c.timers = append(c.timers, t)
+}
+
+func (c *ManualClock) remove(t *manualTimer) {
+	for i, other := range c.timers {
+		if other == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (t *manualTimer) fire(now time.Time) {
+	if t.f != nil {
+		go t.f()
+		return
+	}
+	select {
+	case t.c <- now:
+	default:
+	}
+}
+
+func (t *manualTimer) Chan() <-chan time.Time {
+	return t.c
+}
+
+func (t *manualTimer) Reset(d time.Duration) {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.clock.remove(t)
+	t.every = d
+	t.next = t.clock.now.Add(d)
+	t.clock.timers = append(t.clock.timers, t)
+}
+
+func (t *manualTimer) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.clock.remove(t)
+}
+
+// seededRand is a random source safe to share between goroutines
+type seededRand struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// newSeededRand returns a random source for one part of the server, such as a room,
+// that gives the same numbers for the same seed and name on every run
+func newSeededRand(seed int64, name string) *seededRand {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return &seededRand{rng: rand.New(rand.NewSource(seed ^ int64(h.Sum64())))}
+}
+
+// Intn returns a number in [0, n)
+func (r *seededRand) Intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Intn(n)
+}
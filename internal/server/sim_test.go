@@ -0,0 +1,96 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// simRun is what a simulated tag round decided
+type simRun struct {
+	spawns map[string]string
+	it     string
+}
+
+// runTagSim plays the start of a round of tag in a room on a ManualClock, calling the
+// room's update directly rather than through Run so every tick happens in order
+func runTagSim(t *testing.T, seed int64) simRun {
+	t.Helper()
+
+	clock := NewManualClock(time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC))
+	cfg := DefaultConfig()
+	cfg.DataDir = ""
+	cfg.Clock = clock
+	cfg.Seed = seed
+
+	store, err := NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hunt := NewTreasureHuntManager("sim", store, cfg.Hunt, cfg.Clock, cfg.Seed)
+	r := NewRoom("sim", NewChatManager(), NewUserManager(store), hunt, cfg)
+
+	players := []string{"ada", "grace", "linus"}
+	clients := make(map[string]*Client, len(players))
+	run := simRun{spawns: make(map[string]string)}
+	for _, name := range players {
+		c := &Client{
+			ID:       "id-" + name,
+			Name:     name,
+			Username: name,
+			send:     make(chan []byte, 1024),
+			state:    make(chan []byte, 1),
+			done:     make(chan struct{}),
+		}
+		r.handleRegister(c)
+		clients[name] = c
+		run.spawns[name] = c.Pos
+	}
+
+	r.HandleGameMode(clients["ada"], protocol.GameModeTag, "start")
+	r.HandleGameMode(clients["grace"], protocol.GameModeTag, "join")
+	r.HandleGameMode(clients["linus"], protocol.GameModeTag, "join")
+
+	// The lobby only closes once the room's clock passes the join window
+	clock.Advance(tagJoinWindow - time.Second)
+	r.update(r.chatManager)
+	if state := r.mode.State(); state.Phase != "lobby" {
+		t.Fatalf("phase %q before the join window ended, want lobby", state.Phase)
+	}
+	clock.Advance(time.Second)
+	r.update(r.chatManager)
+
+	tg := r.mode.(*tag)
+	if tg.phase != "chase" {
+		t.Fatalf("phase %q after the join window, want chase", tg.phase)
+	}
+	run.it = tg.it
+
+	// Whoever is it stays frozen until tagFreeze has passed on the room's clock
+	r.mu.RLock()
+	frozen := !r.canMoveLocked(run.it, clock.Now())
+	r.mu.RUnlock()
+	if !frozen {
+		t.Errorf("%s can move right after becoming it", run.it)
+	}
+	clock.Advance(tagFreeze)
+	r.mu.RLock()
+	frozen = !r.canMoveLocked(run.it, clock.Now())
+	r.mu.RUnlock()
+	if frozen {
+		t.Errorf("%s still frozen %s after becoming it", run.it, tagFreeze)
+	}
+	return run
+}
+
+func TestSimulationReplaysWithSameSeed(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		first := runTagSim(t, seed)
+		second := runTagSim(t, seed)
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("seed %d gave different runs:\n%+v\n%+v", seed, first, second)
+		}
+	}
+}
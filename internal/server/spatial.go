@@ -0,0 +1,98 @@
+package server
+
+import "sort"
+
+// spatialCellSize is the width and height, in tiles, of one bucket of the spatial index
+const spatialCellSize = 16
+
+type spatialCell struct{ x, y int }
+
+// spatialIndex buckets players by the area of the map they're in, so finding who is near
+// a spot only looks at the buckets around it instead of every player in the room
+type spatialIndex struct {
+	cells     map[spatialCell]map[*Client]struct{} // Bucket -> players in it
+	positions map[*Client][2]int                   // Player -> {x, y}
+}
+
+func newSpatialIndex() *spatialIndex {
+	return &spatialIndex{
+		cells:     make(map[spatialCell]map[*Client]struct{}),
+		positions: make(map[*Client][2]int),
+	}
+}
+
+func cellAt(x, y int) spatialCell {
+	return spatialCell{floorDiv(x, spatialCellSize), floorDiv(y, spatialCellSize)}
+}
+
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return (a - b + 1) / b
+	}
+	return a / b
+}
+
+// move puts the player at (x, y), adding them if they aren't indexed yet
+func (s *spatialIndex) move(client *Client, x, y int) {
+	if old, ok := s.positions[client]; ok {
+		if old == [2]int{x, y} {
+			return
+		}
+		s.removeFromCell(client, cellAt(old[0], old[1]))
+	}
+	s.positions[client] = [2]int{x, y}
+
+	cell := cellAt(x, y)
+	if s.cells[cell] == nil {
+		s.cells[cell] = make(map[*Client]struct{})
+	}
+	s.cells[cell][client] = struct{}{}
+}
+
+// remove takes the player out of the index
+func (s *spatialIndex) remove(client *Client) {
+	pos, ok := s.positions[client]
+	if !ok {
+		return
+	}
+	delete(s.positions, client)
+	s.removeFromCell(client, cellAt(pos[0], pos[1]))
+}
+
+func (s *spatialIndex) removeFromCell(client *Client, cell spatialCell) {
+	delete(s.cells[cell], client)
+	if len(s.cells[cell]) == 0 {
+		delete(s.cells, cell)
+	}
+}
+
+// near returns the players within radius of (x, y) by Chebyshev distance
+func (s *spatialIndex) near(x, y, radius int) []*Client {
+	var clients []*Client
+	from, to := cellAt(x-radius, y-radius), cellAt(x+radius, y+radius)
+	for cy := from.y; cy <= to.y; cy++ {
+		for cx := from.x; cx <= to.x; cx++ {
+			for client := range s.cells[spatialCell{cx, cy}] {
+				pos := s.positions[client]
+				if max(abs(pos[0]-x), abs(pos[1]-y)) <= radius {
+					clients = append(clients, client)
+				}
+			}
+		}
+	}
+	return clients
+}
+
+// NearbyPlayers returns the usernames of the players within radius tiles of (x, y),
+// sorted
+func (r *Room) NearbyPlayers(x, y, radius int) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var usernames []string
+	for _, client := range r.spatial.near(x, y, radius) {
+		usernames = append(usernames, client.Username)
+	}
+	sort.Strings(usernames)
+	return usernames
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"maps"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// statePool holds the buffers tick states are put together in
+var statePool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// stateSection is one part of the tick state, kept encoded until its value changes
+type stateSection struct {
+	value   any
+	encoded []byte
+}
+
+// encode returns v encoded, reusing the last encoding if v is the same as last time.
+// v is kept to compare the next one against, so it mustn't be changed afterwards.
+func (s *stateSection) encode(v any) []byte {
+	if s.encoded != nil && reflect.DeepEqual(s.value, v) {
+		return s.encoded
+	}
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("Error encoding tick state: %v", err)
+		return []byte("null")
+	}
+	s.value, s.encoded = v, encoded
+	return encoded
+}
+
+// stateCache keeps the sections of a room's tick state encoded, so a tick only encodes
+// what changed since the last one: often the players, now and then the hunt or the
+// pomodoros, and the rest hardly ever. The players go in the state twice but are only
+// encoded once. Only the Run loop uses it.
+type stateCache struct {
+	players, positions, npcs, hunt, pomodoros, gameMode stateSection
+}
+
+// encodePositionsLocked encodes the room's tile -> username map. The room changes it in
+// place, so it's copied when it's kept; r.mu must be held.
+func (c *stateCache) encodePositionsLocked(positions map[string]string) []byte {
+	if c.positions.encoded != nil && maps.Equal(c.positions.value.(map[string]string), positions) {
+		return c.positions.encoded
+	}
+	return c.positions.encode(maps.Clone(positions))
+}
+
+// encode returns the kuluchified_state message for state, with the positions from
+// encodePositionsLocked. It's written out by hand to reuse the cached sections, so the
+// keys and omitempty rules have to match protocol.KuluchifiedStatePayload's.
+func (c *stateCache) encode(state *protocol.KuluchifiedStatePayload, positions []byte) []byte {
+	players := c.players.encode(state.Players)
+
+	buf := statePool.Get().(*bytes.Buffer)
+	defer statePool.Put(buf)
+	buf.Reset()
+
+	buf.WriteString(`{"type":"` + string(protocol.MsgKuluchifiedState) + `","payload":{"game_state":{"players":`)
+	buf.Write(players)
+	buf.WriteString(`,"pos_to_username":`)
+	buf.Write(positions)
+	buf.WriteString(`,"tick":`)
+	buf.WriteString(strconv.FormatInt(state.GameState.Tick, 10))
+	buf.WriteString(`,"time_of_day":`)
+	appendJSON(buf, state.GameState.TimeOfDay)
+	if len(state.GameState.NPCs) > 0 {
+		buf.WriteString(`,"npcs":`)
+		buf.Write(c.npcs.encode(state.GameState.NPCs))
+	}
+	buf.WriteString(`},"chat_messages":`)
+	appendJSON(buf, state.ChatMessages)
+	buf.WriteString(`,"room_chat_messages":`)
+	appendJSON(buf, state.RoomChatMessages)
+	buf.WriteString(`,"announcements":null`)
+	buf.WriteString(`,"players":`)
+	buf.Write(players)
+	buf.WriteString(`,"treasure_hunt_state":`)
+	buf.Write(c.hunt.encode(state.TreasureHuntState))
+	if len(state.Pomodoros) > 0 {
+		buf.WriteString(`,"pomodoros":`)
+		buf.Write(c.pomodoros.encode(state.Pomodoros))
+	}
+	if state.GameMode != nil {
+		buf.WriteString(`,"game_mode":`)
+		buf.Write(c.gameMode.encode(state.GameMode))
+	}
+	buf.WriteString(`}}`)
+
+	// The clients' writers hold on to the message, so it can't share the pooled buffer
+	return bytes.Clone(buf.Bytes())
+}
+
+// appendJSON encodes v into buf, for the parts of the state that change every time
+func appendJSON(buf *bytes.Buffer, v any) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("Error encoding tick state: %v", err)
+		encoded = []byte("null")
+	}
+	buf.Write(encoded)
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// StoreData is everything the server keeps across restarts
+type StoreData struct {
+	Users          map[string]*User                 `json:"users"`       // Username -> profile and points
+	Leaderboard    map[string]*LeaderboardStats     `json:"leaderboard"` // Username -> treasure hunt stats
+	LastHuntWinner string                           `json:"last_hunt_winner"`
+	TreasureHunts  map[string]*TreasureHuntSnapshot `json:"treasure_hunts"` // Room ID -> today's hunt
+	Rooms          map[string]*RoomSnapshot         `json:"rooms"`          // Room ID -> its world
+}
+
+// Store persists StoreData as a JSON file. A store with no path keeps everything in memory.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	data StoreData
+}
+
+// NewStore loads the store from dir/store.json, starting empty if the file doesn't exist yet
+func NewStore(dir string) (*Store, error) {
+	s := &Store{}
+	s.data.init()
+	if dir == "" {
+		return s, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create data dir: %w", err)
+	}
+	s.path = filepath.Join(dir, "store.json")
+
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read store: %w", err)
+	}
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		return nil, fmt.Errorf("failed to parse store: %w", err)
+	}
+	s.data.init()
+	return s, nil
+}
+
+// init makes the maps that a fresh or older store file might not have
+func (d *StoreData) init() {
+	if d.Users == nil {
+		d.Users = make(map[string]*User)
+	}
+	if d.Leaderboard == nil {
+		d.Leaderboard = make(map[string]*LeaderboardStats)
+	}
+	if d.TreasureHunts == nil {
+		d.TreasureHunts = make(map[string]*TreasureHuntSnapshot)
+	}
+	if d.Rooms == nil {
+		d.Rooms = make(map[string]*RoomSnapshot)
+	}
+}
+
+// View calls fn with the data; fn must not keep references to it
+func (s *Store) View(fn func(*StoreData)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.data)
+}
+
+// Update calls fn to change the data, then writes it to disk
+func (s *Store) Update(fn func(*StoreData)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.data)
+	return s.saveLocked()
+}
+
+// saveLocked writes the data to a temp file and renames it over the store so a crash
+// never leaves a half-written file; s.mu must be held
+func (s *Store) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+
+	raw, err := json.MarshalIndent(&s.data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode store: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		return fmt.Errorf("failed to write store: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+const (
+	tagJoinWindow = 30 * time.Second // Lobby time for players to join
+	tagChaseTime  = 3 * time.Minute  // Length of the chase
+	tagRadius     = 3                // "It" tags anyone whose avatar is touching theirs
+	tagFreeze     = 3 * time.Second  // The newly tagged player waits this long before chasing
+)
+
+// tag is a GameMode where whoever is "it" chases everyone else. Moving next to a
+// runner tags them and makes them "it"; whoever is it when time runs out loses.
+type tag struct {
+	phase       string // "lobby" or "chase"
+	endsAt      time.Time
+	it          string
+	frozenUntil time.Time // "It" can't move until then, so there are no instant tag-backs
+	players     []string  // Everyone who joined, in join order
+	tags        map[string]int
+	rng         *seededRand // Picks who's it
+}
+
+func newTag(now time.Time, rng *seededRand) GameMode {
+	return &tag{
+		phase:  "lobby",
+		endsAt: now.Add(tagJoinWindow),
+		tags:   make(map[string]int),
+		rng:    rng,
+	}
+}
+
+func (t *tag) Join(username string) error {
+	if t.phase != "lobby" {
+		return errors.New("This round has already started - wait for the next one")
+	}
+	for _, player := range t.players {
+		if player == username {
+			return errors.New("You've already joined")
+		}
+	}
+	t.players = append(t.players, username)
+	return nil
+}
+
+func (t *tag) Leave(username string) {
+	for i, player := range t.players {
+		if player == username {
+			t.players = append(t.players[:i], t.players[i+1:]...)
+			break
+		}
+	}
+}
+
+func (t *tag) Tick(now time.Time, positions map[string][2]int) ([]string, bool) {
+	// Players who disconnected without leaving are dropped
+	for _, player := range append([]string(nil), t.players...) {
+		if _, online := positions[player]; !online {
+			t.Leave(player)
+		}
+	}
+
+	switch t.phase {
+	case "lobby":
+		if now.Before(t.endsAt) {
+			return nil, false
+		}
+		if len(t.players) < 2 {
+			return []string{"🏃 Not enough players joined tag. Maybe next time!"}, true
+		}
+		t.it = t.players[t.rng.Intn(len(t.players))]
+		t.phase = "chase"
+		t.endsAt = now.Add(tagChaseTime)
+		t.frozenUntil = now.Add(tagFreeze)
+		return []string{fmt.Sprintf("🏃 %s is it! Run!", t.it)}, false
+
+	case "chase":
+		if len(t.players) < 2 {
+			return []string{"🏃 Everyone else left - tag is over."}, true
+		}
+
+		var events []string
+		if !t.isPlaying(t.it) {
+			// "It" left, so hand it to someone else
+			t.it = t.players[t.rng.Intn(len(t.players))]
+			t.frozenUntil = now.Add(tagFreeze)
+			events = append(events, fmt.Sprintf("🏃 %s is it now!", t.it))
+		} else if !now.Before(t.frozenUntil) {
+			for _, player := range t.players {
+				if player != t.it && chebyshev(positions[t.it], positions[player]) <= tagRadius {
+					t.tags[t.it]++
+					events = append(events, fmt.Sprintf("🏃 %s tagged %s!", t.it, player))
+					t.it = player
+					t.frozenUntil = now.Add(tagFreeze)
+					break
+				}
+			}
+		}
+
+		if !now.Before(t.endsAt) {
+			return append(events, t.result()), true
+		}
+		return events, false
+	}
+	return nil, false
+}
+
+// result announces who lost and who tagged the most
+func (t *tag) result() string {
+	best, bestTags := "", 0
+	for _, player := range t.players {
+		if t.tags[player] > bestTags {
+			best, bestTags = player, t.tags[player]
+		}
+	}
+
+	text := fmt.Sprintf("🏁 Time's up! %s was it at the end.", t.it)
+	if best != "" {
+		text += fmt.Sprintf(" Most tags: %s (%d).", best, bestTags)
+	}
+	return text
+}
+
+func (t *tag) HiddenFrom(viewer string, positions map[string][2]int) []string {
+	return nil
+}
+
+func (t *tag) CanMove(username string, now time.Time) bool {
+	return username != t.it || !now.Before(t.frozenUntil)
+}
+
+func (t *tag) State() protocol.GameModeState {
+	roles := make(map[string]string, len(t.players))
+	for _, player := range t.players {
+		switch {
+		case t.phase == "lobby":
+			roles[player] = "joined"
+		case player == t.it:
+			roles[player] = "it"
+		default:
+			roles[player] = "runner"
+		}
+	}
+
+	return protocol.GameModeState{
+		Mode:   protocol.GameModeTag,
+		Phase:  t.phase,
+		EndsAt: t.endsAt.Unix(),
+		Roles:  roles,
+	}
+}
+
+func (t *tag) isPlaying(username string) bool {
+	for _, player := range t.players {
+		if player == username {
+			return true
+		}
+	}
+	return false
+}
@@ -1,8 +1,10 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -10,6 +12,15 @@ import (
 	"github.com/yourusername/always-at-morg/internal/protocol"
 )
 
+const guessCooldown = 2 * time.Second // Minimum time between a player's guesses
+
+// The player on a win streak waits this long per riddle of it before their first guess at
+// a new riddle, so one fast typist doesn't win every round
+const (
+	winnerGracePerWin = 3 * time.Second
+	maxWinnerGrace    = 10 * time.Second
+)
+
 // Dummy variable to satisfy legacy references if any remain
 var TreasureClues = []Clue{}
 
@@ -18,31 +29,80 @@ type Clue struct {
 	Answer   string
 }
 
-// TreasureHuntManager handles the game state
+// TreasureHuntManager runs the riddle hunt for one room
 type TreasureHuntManager struct {
+	id             string // Room the hunt belongs to, used as its key in the store
 	mu             sync.RWMutex
 	currentRiddle  *GeminiRiddle
 	nextRiddle     *GeminiRiddle // Pre-fetched next riddle during cooldown
+	upcomingRiddle *GeminiRiddle // Riddle for the next round while the cooldown runs, or queued by an admin
 	currentRound   int
 	isSolved       bool
 	winner         string
 	showHint       bool
-	inCooldown     bool // True during 2-minute cooldown period
+	inCooldown     bool // True during the cooldown between rounds
 	waitingForNext bool // Prevents ticker from skipping the "Solved" screen
 	gameOver       bool // Tracks if the daily limit is reached
+	paused         bool // An admin stopped the hunt
+	schedule       HuntSchedule
 	announcements  []protocol.AnnouncementPayload
 	updateCallback func(protocol.TreasureHuntStatePayload)
+	winCallback    func(winner, answer string, points int)
 	startNextCh    chan struct{} // Channel to signal next round is ready
+	skipCh         chan struct{} // Cuts the cooldown short once the next riddle is fetched
+	stop           chan struct{} // Closed by Close to end the game loop
+	stopOnce       sync.Once
+	roundStarted   time.Time // When the current riddle went live, for solve times
+	nextRoundAt    time.Time // When the cooldown ends and the next riddle goes live
+	store          *Store    // Leaderboard and saved hunt, nil if nothing is recorded
+	day            string    // Madison date of the daily hunt being played
+	history        []TreasureHuntRound
+	lastGuess      map[string]time.Time // Username -> when they last guessed, for rate limiting
+	attempts       map[string]int       // Username -> guesses at the current riddle
+	choices        []huntChoice         // Answers to a multiple-choice round, in the order they came in
+	triviaCallback func(correct []string, points int)
+
+	// Time and riddle picks, from the server's clock and seed
+	clock Clock
+	rng   *seededRand
 }
 
-// Initialize with a default riddle so clients never see "Loading..."
-var Manager = &TreasureHuntManager{
-	currentRound: 1,
-	currentRiddle: &GeminiRiddle{
-		Question: "I have keys but no locks. I have a space but no room. You can enter, but never leave. What am I?",
-		Answer:   "keyboard",
-		Hint:     "I am an input device.",
-	},
+// huntChoice is a player's answer to a multiple-choice round
+type huntChoice struct {
+	username string
+	choice   int           // Index into the riddle's Choices
+	took     time.Duration // How far into the round they answered
+}
+
+// choiceUsernames lists who gave each of the answers, in order
+func choiceUsernames(choices []huntChoice) []string {
+	var usernames []string
+	for _, answered := range choices {
+		usernames = append(usernames, answered.username)
+	}
+	return usernames
+}
+
+// NewTreasureHuntManager creates the hunt for a room, restoring today's progress if the
+// server was restarted partway through it. A nil store records nothing. The hunt is timed
+// by clock (nil for the wall clock) and draws riddles as seed decides.
+func NewTreasureHuntManager(id string, store *Store, schedule HuntSchedule, clock Clock, seed int64) *TreasureHuntManager {
+	// Initialize with a riddle from the bank so clients never see "Loading..."
+	tm := &TreasureHuntManager{
+		id:           id,
+		store:        store,
+		schedule:     schedule,
+		skipCh:       make(chan struct{}, 1),
+		stop:         make(chan struct{}),
+		currentRound: 1,
+		clock:        clockOrWall(clock),
+		rng:          newSeededRand(seed, "hunt:"+id),
+	}
+	tm.currentRiddle = tm.drawRound(huntDay(tm.clock.Now()), 1)
+	if store != nil {
+		tm.restoreLocked() // Nobody else has tm yet
+	}
+	return tm
 }
 
 // SetUpdateCallback sets the function to call when state changes
@@ -50,70 +110,117 @@ func (tm *TreasureHuntManager) SetUpdateCallback(callback func(protocol.Treasure
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 	tm.updateCallback = callback
-	
+
 	// IMMEDIATELY trigger the callback with the current state so the client gets data right away
 	if tm.currentRiddle != nil || tm.gameOver {
 		go callback(tm.getStateLocked())
 	}
 }
 
-// StartGameLoop begins the game cycle: 1 min round + 2 min cooldown
+// SetWinCallback sets the function to call when someone solves a riddle
+func (tm *TreasureHuntManager) SetWinCallback(callback func(winner, answer string, points int)) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.winCallback = callback
+}
+
+// SetTriviaCallback sets the function to call with everyone who answered a multiple-choice
+// round right, fastest first, and the points each of them earned
+func (tm *TreasureHuntManager) SetTriviaCallback(callback func(correct []string, points int)) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.triviaCallback = callback
+}
+
+// StartGameLoop begins the game cycle: a round, then a cooldown, as set by the schedule
 func (tm *TreasureHuntManager) StartGameLoop() {
 	// Prevent multiple loops if called twice
+	tm.mu.Lock()
 	if tm.startNextCh != nil {
+		tm.mu.Unlock()
 		return
 	}
 
-	// Buffered channel to signal when next round is ready
+	// Buffered channel to signal when next round is ready (guarded by tm.mu, Start reads it)
 	tm.startNextCh = make(chan struct{}, 1)
+	tm.mu.Unlock()
 
 	// Ensure we have a riddle to start with
 	if tm.currentRiddle == nil && !tm.gameOver {
 		tm.loadNextRiddle()
 	}
+	tm.mu.Lock()
+	tm.roundStarted = tm.clock.Now()
+	if tm.day == "" {
+		tm.day = huntDay(tm.clock.Now())
+	}
+	// A round restored after a restart that had already ended moves straight on
+	roundOver := tm.isSolved && !tm.gameOver
+	tm.saveLocked()
+	tm.mu.Unlock()
+
+	if roundOver {
+		go tm.startCooldown()
+	}
 
-	roundTimer := time.NewTicker(1 * time.Minute)   // 1 minute active round
-	hintTimer := time.NewTicker(30 * time.Second)   // Hint at 30 seconds (halfway)
+	roundTimer := tm.clock.NewTicker(tm.schedule.Round)
+	hintTimer := tm.clock.NewTicker(tm.schedule.Hint)
 
 	go func() {
 		for {
 			select {
-			case <-roundTimer.C:
+			case <-roundTimer.Chan():
 				tm.mu.RLock()
 				waiting := tm.waitingForNext
 				isOver := tm.gameOver
 				inCooldown := tm.inCooldown
+				paused := tm.paused
 				tm.mu.RUnlock()
 
 				if isOver {
+					// The daily limit resets at midnight in Madison
+					if tm.isNewDay() {
+						tm.startNewDay()
+					}
 					continue
 				}
 
 				// If we're in cooldown, don't do anything (waiting for next riddle)
-				if inCooldown {
+				if inCooldown || paused {
 					continue
 				}
 
-				// Only start cooldown if we aren't waiting for the 5s post-win timer
+				// Only end the round if we aren't waiting for the 5s post-win timer
 				if !waiting {
-					tm.startCooldown()
+					tm.endRound()
 					hintTimer.Stop() // Stop hint timer during cooldown
 				}
 
 			case <-tm.startNextCh:
 				// Next riddle is ready! Start the new round
-				tm.activateNextRound()
-				roundTimer.Reset(1 * time.Minute)
-				hintTimer.Reset(30 * time.Second)
+				if tm.activateNextRound() {
+					roundTimer.Reset(tm.schedule.Round)
+					hintTimer.Reset(tm.schedule.Hint)
+				}
 
-			case <-hintTimer.C:
+			case <-hintTimer.Chan():
 				tm.revealHint()
+
+			case <-tm.stop:
+				roundTimer.Stop()
+				hintTimer.Stop()
+				return
 			}
 		}
 	}()
 }
 
-// startCooldown begins the 2-minute cooldown and fetches next riddle
+// Close ends the game loop for good, once its room has closed
+func (tm *TreasureHuntManager) Close() {
+	tm.stopOnce.Do(func() { close(tm.stop) })
+}
+
+// startCooldown begins the cooldown and fetches next riddle
 func (tm *TreasureHuntManager) startCooldown() {
 	tm.mu.Lock()
 
@@ -131,19 +238,31 @@ func (tm *TreasureHuntManager) startCooldown() {
 		return
 	}
 
-	// Check if we've reached the daily limit (3 questions)
-	if tm.currentRound >= 3 {
+	// A round nobody solved goes in the history as unsolved and breaks the leaderboard streak
+	missed := !tm.isSolved && tm.currentRiddle != nil
+	if missed {
+		tm.recordRoundLocked()
+	}
+	store := tm.store
+
+	// Check if we've reached the daily limit
+	if tm.currentRound >= tm.schedule.DailyRounds {
 		tm.gameOver = true
 		tm.currentRiddle = nil
 		tm.isSolved = true
 		tm.winner = ""
 		tm.announcements = nil
 		tm.inCooldown = false
+		tm.saveLocked()
 
 		state := tm.getStateLocked()
 		callback := tm.updateCallback
 		tm.mu.Unlock()
 
+		if missed && store != nil {
+			recordTreasureHuntMiss(store)
+		}
+
 		if callback != nil {
 			log.Println("Daily limit reached, ending game loop.")
 			callback(state)
@@ -152,85 +271,160 @@ func (tm *TreasureHuntManager) startCooldown() {
 	}
 
 	// If previous wasn't solved, announce the answer
-	if !tm.isSolved && tm.currentRiddle != nil {
+	if missed {
 		tm.addAnnouncement(fmt.Sprintf("Time's up! The answer was: %s", tm.currentRiddle.Answer))
 	}
 
 	tm.inCooldown = true
 	tm.waitingForNext = false
+	tm.nextRoundAt = tm.clock.Now().Add(tm.schedule.Cooldown)
+	tm.saveLocked()
 
-	log.Println("Starting cooldown - will fetch ONE riddle from Gemini in 2 minutes")
+	log.Printf("Starting cooldown - next riddle in %s", tm.schedule.Cooldown)
 
 	// Show cooldown message to clients
 	state := tm.getStateLocked()
 	callback := tm.updateCallback
 	tm.mu.Unlock()
 
+	if missed && store != nil {
+		recordTreasureHuntMiss(store)
+	}
+
 	if callback != nil {
 		log.Println("Broadcasting cooldown state...")
 		callback(state)
 	}
 
-	// Start fetching next riddle in background (2 minute cooldown)
-	go func() {
-		log.Println("Starting 2-minute cooldown, fetching next riddle from Gemini...")
-
-		// Generate riddle (this may take a few seconds)
-		riddle, err := GenerateRiddle()
-		if err != nil {
-			log.Printf("Failed to generate riddle: %v", err)
-			// Fallback to a simple CS one if API fails
-			riddle = &GeminiRiddle{
-				Question: "I have keys but no locks. I have a space but no room. You can enter, but never leave. What am I?",
-				Answer:   "keyboard",
-				Hint:     "Input device...",
-			}
-		}
-
-		log.Printf("Gemini API call complete. Riddle generated: %s", riddle.Question)
+	// Start fetching next riddle in background
+	go tm.fetchNextRiddle(tm.schedule.Cooldown)
+}
 
-		// Wait for the remainder of 2 minutes after fetching
-		// (If fetch took 5 seconds, wait 115 more seconds)
-		time.Sleep(2 * time.Minute)
+// fetchNextRiddle draws the next riddle from the bank, waits out the cooldown and signals
+// the game loop to start the round. Gemini tops the bank up in the meantime.
+func (tm *TreasureHuntManager) fetchNextRiddle(cooldown time.Duration) {
+	// A riddle an admin queued is played instead of one from the bank
+	tm.mu.RLock()
+	queued := tm.upcomingRiddle != nil
+	day, round := tm.day, tm.currentRound+1
+	tm.mu.RUnlock()
 
+	if !queued {
+		// Drawn without the lock since the model can take a few seconds to write trivia
+		riddle := tm.drawRound(day, round)
 		tm.mu.Lock()
-		// Double-check we don't already have a next riddle (race condition protection)
-		if tm.nextRiddle != nil {
-			log.Println("WARNING: nextRiddle already set! Discarding newly fetched riddle to avoid duplication.")
-			tm.mu.Unlock()
-			return
+		if tm.upcomingRiddle == nil {
+			tm.upcomingRiddle = riddle
+			log.Printf("Next riddle drawn (%s, %s): %s", riddle.Category, riddle.Difficulty, riddle.Question)
 		}
-		tm.nextRiddle = riddle
 		tm.mu.Unlock()
+	}
 
-		log.Printf("Riddle ready: %s (Ans: %s)", riddle.Question, riddle.Answer)
-		log.Println("Cooldown complete, signaling next round...")
+	// Wait for the remainder of the cooldown after fetching, unless an admin skips it.
+	// Admins can review and edit the upcoming riddle in the meantime.
+	select {
+	case <-tm.clock.After(cooldown):
+	case <-tm.skipCh:
+	case <-tm.stop:
+		return
+	}
 
-		// Signal that next round is ready
-		select {
-		case tm.startNextCh <- struct{}{}:
-		default:
-		}
-	}()
+	tm.mu.Lock()
+	// Double-check we don't already have a next riddle (race condition protection)
+	if tm.nextRiddle != nil {
+		log.Println("WARNING: nextRiddle already set! Discarding newly fetched riddle to avoid duplication.")
+		tm.mu.Unlock()
+		return
+	}
+	riddle := tm.upcomingRiddle
+	tm.nextRiddle = riddle
+	tm.upcomingRiddle = nil
+	tm.mu.Unlock()
+
+	log.Printf("Riddle ready: %s (Ans: %s)", riddle.Question, riddle.Answer)
+	log.Println("Cooldown complete, signaling next round...")
+
+	// Signal that next round is ready
+	signal(tm.startNextCh)
 }
 
-// activateNextRound switches to the pre-fetched next riddle
-func (tm *TreasureHuntManager) activateNextRound() {
+// drawRound picks what a day's round plays: a trivia question, or a riddle from the bank
+func (tm *TreasureHuntManager) drawRound(day string, round int) *GeminiRiddle {
+	if tm.schedule.triviaRound(round) {
+		return drawTrivia(tm.rng)
+	}
+	return drawRiddle(tm.rng, day, round)
+}
+
+// drawRiddle takes the riddle for a round of the day's hunt from the bank, and has the
+// model top the bank up with another of its kind
+func drawRiddle(rng *seededRand, day string, round int) *GeminiRiddle {
+	category, difficulty := riddleKindForRound(day, round)
+	go riddles.TopUp(category, difficulty)
+	return riddles.Draw(rng, category, difficulty)
+}
+
+// isNewDay reports whether the daily hunt being played is from an earlier day
+func (tm *TreasureHuntManager) isNewDay() bool {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return huntDay(tm.clock.Now()) != tm.day
+}
+
+// startNewDay resets the daily limit and fetches the first riddle of the day
+func (tm *TreasureHuntManager) startNewDay() {
+	tm.mu.Lock()
+	tm.day = huntDay(tm.clock.Now())
+	tm.currentRound = 0 // activateNextRound moves it to round 1
+	tm.currentRiddle = nil
+	tm.gameOver = false
+	tm.inCooldown = true // Shows "preparing next riddle" until it's ready
+	tm.nextRoundAt = tm.clock.Now()
+	tm.history = nil
+	tm.saveLocked()
+	tm.mu.Unlock()
+
+	log.Printf("New day %s, starting a fresh treasure hunt", tm.day)
+	go tm.fetchNextRiddle(0)
+}
+
+// activateNextRound switches to the pre-fetched next riddle, reporting whether a round
+// started. A stopped hunt keeps the riddle until an admin starts it again.
+func (tm *TreasureHuntManager) activateNextRound() bool {
 	tm.mu.Lock()
 
-	if tm.nextRiddle == nil {
+	switch {
+	case tm.paused:
+		tm.mu.Unlock()
+		return false
+	case tm.nextRiddle != nil:
+		tm.currentRiddle = tm.nextRiddle
+		tm.nextRiddle = nil
+		tm.currentRound++
+		tm.isSolved = false
+		tm.winner = ""
+		tm.inCooldown = false
+		tm.waitingForNext = false // Still set if the daily limit was reached right after a win
+		tm.gameOver = false       // An admin can start extra rounds after the daily limit
+	case tm.roundLiveLocked():
+		// The hunt was stopped mid-round, so the riddle is played again from the start
+	default:
 		log.Println("WARNING: activateNextRound called but nextRiddle is nil!")
 		tm.mu.Unlock()
-		return
+		return false
 	}
-
-	tm.currentRiddle = tm.nextRiddle
-	tm.nextRiddle = nil
-	tm.currentRound++
-	tm.isSolved = false
-	tm.winner = ""
 	tm.showHint = false
-	tm.inCooldown = false
+	tm.roundStarted = tm.clock.Now()
+	tm.lastGuess = nil
+	tm.attempts = nil
+	tm.choices = nil
+	tm.saveLocked()
+
+	// Drop a skip that arrived after the cooldown had already ended
+	select {
+	case <-tm.skipCh:
+	default:
+	}
 
 	log.Printf("New Round %d: %s (Ans: %s)", tm.currentRound, tm.currentRiddle.Question, tm.currentRiddle.Answer)
 
@@ -242,19 +436,15 @@ func (tm *TreasureHuntManager) activateNextRound() {
 		log.Printf("Broadcasting new riddle state (Round %d)", state.CurrentClueIndex)
 		callback(state)
 	}
+	return true
 }
 
 // loadNextRiddle is used for initial setup only
 func (tm *TreasureHuntManager) loadNextRiddle() {
-	riddle, err := GenerateRiddle()
-	if err != nil {
-		log.Printf("Failed to generate initial riddle: %v", err)
-		riddle = &GeminiRiddle{
-			Question: "I have keys but no locks. I have a space but no room. You can enter, but never leave. What am I?",
-			Answer:   "keyboard",
-			Hint:     "Input device...",
-		}
-	}
+	tm.mu.RLock()
+	day := tm.day
+	tm.mu.RUnlock()
+	riddle := tm.drawRound(day, 1)
 
 	tm.mu.Lock()
 	tm.currentRiddle = riddle
@@ -266,7 +456,8 @@ func (tm *TreasureHuntManager) loadNextRiddle() {
 
 func (tm *TreasureHuntManager) revealHint() {
 	tm.mu.Lock()
-	if !tm.isSolved && !tm.waitingForNext && !tm.gameOver && !tm.inCooldown {
+	// Trivia questions have no hint
+	if tm.roundLiveLocked() && !tm.paused && tm.currentRiddle.Hint != "" {
 		tm.showHint = true
 		state := tm.getStateLocked()
 		callback := tm.updateCallback
@@ -280,14 +471,64 @@ func (tm *TreasureHuntManager) revealHint() {
 	tm.mu.Unlock()
 }
 
-// CheckGuess validates a guess and updates state if correct
-func (tm *TreasureHuntManager) CheckGuess(username, guess string) bool {
+// AllowGuess rate limits guesses, saying how long the player must wait if they guessed
+// too recently or are on a win streak and the riddle only just started. Allowed guesses
+// at a riddle being played count toward the player's attempts.
+func (tm *TreasureHuntManager) AllowGuess(username string) error {
+	tm.mu.Lock()
+	now := tm.clock.Now()
+	if wait := guessCooldown - now.Sub(tm.lastGuess[username]); wait > 0 {
+		tm.mu.Unlock()
+		return fmt.Errorf("Slow down! You can guess again in %.1fs", wait.Seconds())
+	}
+	if tm.roundLiveLocked() && !tm.paused && tm.store != nil {
+		if streak := treasureHuntStreak(tm.store, username); streak > 0 {
+			if wait := tm.winnerGrace(streak) - now.Sub(tm.roundStarted); wait > 0 {
+				tm.mu.Unlock()
+				won := "You won the last riddle"
+				if streak > 1 {
+					won = fmt.Sprintf("You've won %d riddles in a row", streak)
+				}
+				return fmt.Errorf("%s, so everyone else gets a head start. You can guess in %.1fs", won, wait.Seconds())
+			}
+		}
+	}
+	if tm.lastGuess == nil {
+		tm.lastGuess = make(map[string]time.Time)
+	}
+	tm.lastGuess[username] = now
+
+	counted := tm.roundLiveLocked() && !tm.paused
+	if counted {
+		if tm.attempts == nil {
+			tm.attempts = make(map[string]int)
+		}
+		tm.attempts[username]++
+	}
+	store := tm.store
+	tm.mu.Unlock()
+
+	if counted && store != nil {
+		recordTreasureHuntGuess(store, username)
+	}
+	return nil
+}
+
+// winnerGrace is how long into a riddle the player on a win streak of the given length
+// has to wait to guess. It's kept to a quarter of the round so they still get a chance.
+func (tm *TreasureHuntManager) winnerGrace(streak int) time.Duration {
+	return min(time.Duration(streak)*winnerGracePerWin, maxWinnerGrace, tm.schedule.Round/4)
+}
+
+// CheckGuess validates a guess and updates state if correct, returning the points the
+// riddle was worth and the winner's streak of wins in a row, counting this one
+func (tm *TreasureHuntManager) CheckGuess(username, guess string) (points, streak int, ok bool) {
 	tm.mu.Lock()
 	// We do NOT defer unlock here because we want to unlock before calling the callback
 
-	if tm.isSolved || tm.currentRiddle == nil || tm.waitingForNext || tm.gameOver || tm.inCooldown {
+	if !tm.roundLiveLocked() || tm.paused || tm.currentRiddle.multipleChoice() {
 		tm.mu.Unlock()
-		return false
+		return 0, 0, false
 	}
 
 	cleanGuess := strings.TrimSpace(guess)
@@ -297,36 +538,382 @@ func (tm *TreasureHuntManager) CheckGuess(username, guess string) bool {
 		tm.isSolved = true
 		tm.winner = username
 		tm.waitingForNext = true // Block the main ticker from skipping the win screen
-		tm.addAnnouncement(fmt.Sprintf("🏆 WINNER: %s guessed '%s' correctly!", username, cleanAnswer))
+		tm.addAnnouncement(fmt.Sprintf("🏆 WINNER: %s guessed '%s' correctly%s!", username, cleanAnswer, triesSuffix(tm.attempts[username])))
+		tm.recordRoundLocked()
+		tm.saveLocked()
 
 		// Capture state and callback while locked
 		state := tm.getStateLocked()
 		callback := tm.updateCallback
+		onWin := tm.winCallback
+		store := tm.store
+		took := tm.clock.Now().Sub(tm.roundStarted)
+		points = riddlePoints(tm.currentRiddle.Difficulty)
 		tm.mu.Unlock() // Unlock BEFORE callback to ensure ordering
 
+		if store != nil {
+			streak = recordTreasureHuntWin(store, username, took)
+		}
+
 		// Notify clients of the win immediately and SYNCHRONOUSLY
 		if callback != nil {
 			log.Printf("Broadcasting WINNER state for %s", username)
 			callback(state)
 		}
+		if onWin != nil {
+			onWin(username, cleanAnswer, points+winStreakBonus(streak))
+		}
 
 		// Wait 5 seconds to show win screen, then start cooldown
-		time.AfterFunc(5*time.Second, func() {
+		tm.clock.AfterFunc(5*time.Second, func() {
 			log.Println("Win screen timeout, starting cooldown...")
 			tm.startCooldown()
 		})
 
-		return true
+		return points, streak, true
+	}
+
+	tm.mu.Unlock()
+	return 0, 0, false
+}
+
+// MultipleChoice reports whether the round being played is answered by picking one of
+// its options with AnswerChoice rather than guessing
+func (tm *TreasureHuntManager) MultipleChoice() bool {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return tm.currentRiddle != nil && tm.currentRiddle.multipleChoice()
+}
+
+// AnswerChoice locks in a player's answer to a multiple-choice round, an option numbered
+// "1"-"4". Answers can't be changed; every right one scores when the round ends.
+func (tm *TreasureHuntManager) AnswerChoice(username, answer string) error {
+	tm.mu.Lock()
+	if !tm.roundLiveLocked() || tm.paused || !tm.currentRiddle.multipleChoice() {
+		tm.mu.Unlock()
+		return errors.New("There's no trivia question to answer right now")
+	}
+
+	options := len(tm.currentRiddle.Choices)
+	var choice int
+	if _, err := fmt.Sscanf(strings.TrimSpace(answer), "%d", &choice); err != nil || choice < 1 || choice > options {
+		tm.mu.Unlock()
+		return fmt.Errorf("Pick an answer from 1 to %d", options)
+	}
+	for _, answered := range tm.choices {
+		if answered.username == username {
+			tm.mu.Unlock()
+			return errors.New("You've already answered")
+		}
+	}
+
+	tm.choices = append(tm.choices, huntChoice{
+		username: username,
+		choice:   choice - 1,
+		took:     tm.clock.Now().Sub(tm.roundStarted),
+	})
+	if tm.attempts == nil {
+		tm.attempts = make(map[string]int)
+	}
+	tm.attempts[username]++
+	store := tm.store
+	tm.mu.Unlock()
+
+	if store != nil {
+		recordTreasureHuntGuess(store, username)
+	}
+	return nil
+}
+
+// endRound ends the round being played when its time runs out. A multiple-choice round
+// is scored first, crediting everyone who answered it right, and its results stay up
+// for a few seconds before the cooldown.
+func (tm *TreasureHuntManager) endRound() {
+	tm.mu.Lock()
+	if !tm.roundLiveLocked() || !tm.currentRiddle.multipleChoice() {
+		tm.mu.Unlock()
+		tm.startCooldown()
+		return
 	}
 
+	correct := tm.correctChoicesLocked()
+	tm.waitingForNext = true // Blocks the main ticker from skipping the results
+	if len(correct) > 0 {
+		tm.isSolved = true
+		tm.winner = correct[0].username
+		tm.recordRoundLocked()
+	}
+	// With no right answers startCooldown records the round as missed
+	tm.saveLocked()
+
+	state := tm.getStateLocked()
+	callback := tm.updateCallback
+	onScore := tm.triviaCallback
+	store := tm.store
+	points := riddlePoints(tm.currentRiddle.Difficulty)
 	tm.mu.Unlock()
-	return false
+
+	log.Printf("Trivia round %d in %s over, %d answered right", state.CurrentClueIndex, tm.id, len(correct))
+	if store != nil && len(correct) > 0 {
+		recordTreasureHuntTrivia(store, correct)
+	}
+	if callback != nil {
+		callback(state)
+	}
+	if onScore != nil && len(correct) > 0 {
+		onScore(choiceUsernames(correct), points)
+	}
+
+	tm.clock.AfterFunc(5*time.Second, tm.startCooldown)
+}
+
+// correctChoicesLocked returns the right answers to a multiple-choice round, fastest
+// first; tm.mu must be held
+func (tm *TreasureHuntManager) correctChoicesLocked() []huntChoice {
+	if tm.currentRiddle == nil || !tm.currentRiddle.multipleChoice() {
+		return nil
+	}
+	var correct []huntChoice
+	for _, answered := range tm.choices {
+		if strings.EqualFold(tm.currentRiddle.Choices[answered.choice], tm.currentRiddle.Answer) {
+			correct = append(correct, answered)
+		}
+	}
+	return correct
+}
+
+// Start resumes a stopped hunt, or begins the next round now instead of waiting out the
+// cooldown. After the daily limit it starts an extra round.
+func (tm *TreasureHuntManager) Start() error {
+	tm.mu.Lock()
+	if !tm.paused && tm.roundLiveLocked() {
+		tm.mu.Unlock()
+		return errors.New("a round is already being played")
+	}
+	tm.paused = false
+
+	var next, skip chan struct{}
+	switch {
+	case tm.nextRiddle != nil || tm.roundLiveLocked():
+		next = tm.startNextCh
+	case tm.inCooldown:
+		skip = tm.skipCh
+		tm.nextRoundAt = tm.clock.Now()
+	case tm.gameOver:
+		tm.gameOver = false
+		tm.inCooldown = true // Shows "preparing next riddle" until it's ready
+		tm.nextRoundAt = tm.clock.Now()
+		go tm.fetchNextRiddle(0)
+	}
+	// Otherwise the round was just solved and its cooldown starts on its own
+
+	state := tm.getStateLocked()
+	callback := tm.updateCallback
+	tm.mu.Unlock()
+
+	log.Printf("Treasure hunt in %s started by an admin", tm.id)
+	signal(next)
+	signal(skip)
+	if callback != nil {
+		callback(state)
+	}
+	return nil
+}
+
+// Stop pauses the hunt until Start is called. A riddle being played is shown again
+// from the start when the hunt resumes.
+func (tm *TreasureHuntManager) Stop() error {
+	tm.mu.Lock()
+	if tm.paused {
+		tm.mu.Unlock()
+		return errors.New("the hunt is already stopped")
+	}
+	tm.paused = true
+	state := tm.getStateLocked()
+	callback := tm.updateCallback
+	tm.mu.Unlock()
+
+	log.Printf("Treasure hunt in %s stopped by an admin", tm.id)
+	if callback != nil {
+		callback(state)
+	}
+	return nil
+}
+
+// Skip ends the riddle being played as if time ran out, or cuts the cooldown short
+func (tm *TreasureHuntManager) Skip() error {
+	tm.mu.Lock()
+	switch {
+	case tm.paused:
+		tm.mu.Unlock()
+		return errors.New("the hunt is stopped")
+	case tm.gameOver:
+		tm.mu.Unlock()
+		return errors.New("today's riddles are done")
+	case tm.inCooldown:
+		tm.nextRoundAt = tm.clock.Now()
+		tm.mu.Unlock()
+		signal(tm.skipCh)
+		return nil
+	case !tm.roundLiveLocked():
+		tm.mu.Unlock()
+		return errors.New("the riddle was just solved")
+	}
+	tm.mu.Unlock()
+
+	log.Printf("Treasure hunt round in %s skipped by an admin", tm.id)
+	tm.endRound()
+	return nil
+}
+
+// Void throws out the riddle being played, for when it's wrong or ambiguous. The round
+// doesn't count toward the daily limit and guesses at it are given back.
+func (tm *TreasureHuntManager) Void() error {
+	tm.mu.Lock()
+	if !tm.roundLiveLocked() {
+		tm.mu.Unlock()
+		return errors.New("no riddle is being played")
+	}
+
+	tm.history = append(tm.history, TreasureHuntRound{
+		Round:    tm.currentRound,
+		Riddle:   *tm.currentRiddle,
+		Voided:   true,
+		PlayedAt: tm.clock.Now(),
+	})
+	attempts := tm.attempts
+	tm.attempts = nil
+	tm.lastGuess = nil
+	tm.choices = nil
+	tm.currentRound--  // The next riddle replays this round
+	tm.isSolved = true // Ends the round without a winner, so startCooldown doesn't count a miss
+	store := tm.store
+	tm.mu.Unlock()
+
+	log.Printf("Treasure hunt round in %s voided by an admin", tm.id)
+	if store != nil {
+		refundTreasureHuntGuesses(store, attempts)
+	}
+	tm.startCooldown()
+	return nil
+}
+
+// Riddles returns the riddle being played and the one lined up for the next round, if
+// there is one yet, for admins to review
+func (tm *TreasureHuntManager) Riddles() (current, next *GeminiRiddle) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	if tm.currentRiddle != nil {
+		riddle := *tm.currentRiddle
+		current = &riddle
+	}
+	if upcoming := tm.queuedRiddleLocked(); upcoming != nil {
+		riddle := *upcoming
+		next = &riddle
+	}
+	return current, next
+}
+
+// QueueRiddle edits the riddle lined up for the next round before it goes live. Fields
+// left empty in edit keep their value; with nothing lined up yet, edit becomes the next
+// riddle and needs a question and answer. Giving choices makes it a trivia question.
+func (tm *TreasureHuntManager) QueueRiddle(edit GeminiRiddle) (*GeminiRiddle, error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	var riddle GeminiRiddle
+	if queued := tm.queuedRiddleLocked(); queued != nil {
+		riddle = *queued
+	}
+	set := func(field *string, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			*field = value
+		}
+	}
+	set(&riddle.Question, edit.Question)
+	set(&riddle.Answer, edit.Answer)
+	set(&riddle.Hint, edit.Hint)
+	set(&riddle.Category, edit.Category)
+	set(&riddle.Difficulty, edit.Difficulty)
+	if len(edit.Choices) > 0 {
+		riddle.Choices = append([]string(nil), edit.Choices...)
+	}
+	if riddle.Question == "" || riddle.Answer == "" {
+		return nil, errors.New("the riddle needs a question and an answer")
+	}
+	if riddle.multipleChoice() && !slices.ContainsFunc(riddle.Choices, func(choice string) bool {
+		return strings.EqualFold(choice, riddle.Answer)
+	}) {
+		return nil, errors.New("the answer must be one of the choices")
+	}
+	if len(riddle.Choices) > triviaChoices {
+		return nil, fmt.Errorf("a trivia question can have at most %d choices", triviaChoices)
+	}
+	riddle.normalizeKind()
+
+	// Once the cooldown is over the riddle waits in nextRiddle (e.g. while the hunt is stopped)
+	if tm.nextRiddle != nil {
+		tm.nextRiddle = &riddle
+	} else {
+		tm.upcomingRiddle = &riddle
+	}
+	log.Printf("Admin queued riddle in %s: %s (Ans: %s)", tm.id, riddle.Question, riddle.Answer)
+
+	queued := riddle
+	return &queued, nil
+}
+
+// queuedRiddleLocked returns the riddle the next round will play, nil if it isn't picked
+// yet; tm.mu must be held
+func (tm *TreasureHuntManager) queuedRiddleLocked() *GeminiRiddle {
+	if tm.nextRiddle != nil {
+		return tm.nextRiddle
+	}
+	return tm.upcomingRiddle
+}
+
+// roundLiveLocked reports whether a riddle is open for guesses (ignoring a pause); tm.mu must be held
+func (tm *TreasureHuntManager) roundLiveLocked() bool {
+	return tm.currentRiddle != nil && !tm.isSolved && !tm.waitingForNext && !tm.inCooldown && !tm.gameOver
+}
+
+// signal wakes whoever is waiting on ch without blocking; a nil ch does nothing
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// triesSuffix describes how many guesses a win took, e.g. " in 3 tries"
+func triesSuffix(tries int) string {
+	switch tries {
+	case 0:
+		return ""
+	case 1:
+		return " on the first try"
+	}
+	return fmt.Sprintf(" in %d tries", tries)
+}
+
+// formatCooldown describes a cooldown for players, e.g. "2 minutes" or "45 seconds"
+func formatCooldown(d time.Duration) string {
+	switch {
+	case d >= 2*time.Minute:
+		return fmt.Sprintf("%d minutes", int(d.Round(time.Minute)/time.Minute))
+	case d >= time.Minute:
+		return "1 minute"
+	default:
+		return fmt.Sprintf("%d seconds", int(d.Round(time.Second)/time.Second))
+	}
 }
 
 func (tm *TreasureHuntManager) addAnnouncement(msg string) {
 	tm.announcements = append(tm.announcements, protocol.AnnouncementPayload{
 		Message:   msg,
-		Timestamp: time.Now().Unix(),
+		Timestamp: tm.clock.Now().Unix(),
+		Source:    protocol.AnnouncementHunt,
 	})
 }
 
@@ -339,6 +926,13 @@ func (tm *TreasureHuntManager) GetState() protocol.TreasureHuntStatePayload {
 
 // Helper to get state while holding lock
 func (tm *TreasureHuntManager) getStateLocked() protocol.TreasureHuntStatePayload {
+	if tm.paused {
+		return protocol.TreasureHuntStatePayload{
+			CurrentClueIndex: tm.currentRound,
+			ClueText:         "⏸ Treasure Hunt Paused ⏸\n\nThe hunt has been stopped for now.\nCheck back later!",
+		}
+	}
+
 	// Check for Game Over state
 	if tm.gameOver {
 		return protocol.TreasureHuntStatePayload{
@@ -352,8 +946,9 @@ func (tm *TreasureHuntManager) getStateLocked() protocol.TreasureHuntStatePayloa
 	if tm.inCooldown {
 		return protocol.TreasureHuntStatePayload{
 			CurrentClueIndex: tm.currentRound,
-			ClueText:         "⏳ Cooldown Period ⏳\n\nPreparing next riddle...\nTake a break, next question coming in ~2 minutes!",
+			ClueText:         fmt.Sprintf("⏳ Cooldown Period ⏳\n\nPreparing next riddle...\nTake a break, next question coming in ~%s!", formatCooldown(tm.schedule.Cooldown)),
 			Completed:        false,
+			NextRoundAt:      tm.nextRoundAt.Unix(),
 		}
 	}
 
@@ -361,41 +956,66 @@ func (tm *TreasureHuntManager) getStateLocked() protocol.TreasureHuntStatePayloa
 		return protocol.TreasureHuntStatePayload{ClueText: "Loading..."}
 	}
 
-	text := tm.currentRiddle.Question
+	text, hint := tm.currentRiddle.Question, ""
 	if tm.showHint && !tm.isSolved {
-		text += fmt.Sprintf("\n\n💡 HINT: %s", tm.currentRiddle.Hint)
+		hint = tm.currentRiddle.Hint
+		text += fmt.Sprintf("\n\n💡 HINT: %s", hint)
 	}
-	if tm.isSolved {
+	switch {
+	case tm.currentRiddle.multipleChoice() && tm.waitingForNext:
+		text = fmt.Sprintf("⏰ Time's up!\nAnswer: %s\n\n%s\n\nNext question coming soon...", tm.currentRiddle.Answer, tm.triviaResultsLocked())
+	case tm.isSolved:
 		text = fmt.Sprintf("✅ SOLVED by %s!\nAnswer: %s\n\nNext question coming soon...", tm.winner, tm.currentRiddle.Answer)
 	}
 
+	var roundEndsAt int64
+	if tm.roundLiveLocked() && !tm.roundStarted.IsZero() {
+		roundEndsAt = tm.roundStarted.Add(tm.schedule.Round).Unix()
+	}
+
 	return protocol.TreasureHuntStatePayload{
 		CurrentClueIndex: tm.currentRound,
 		ClueText:         text,
 		Completed:        tm.isSolved,
+		RoundEndsAt:      roundEndsAt,
+		Category:         riddleCategoryByID(tm.currentRiddle.Category).Name,
+		Difficulty:       tm.currentRiddle.Difficulty,
+		Points:           riddlePoints(tm.currentRiddle.Difficulty),
+		Hint:             hint,
+		Options:          tm.currentRiddle.Choices,
+	}
+}
+
+// triviaResultsLocked says who answered a finished multiple-choice round right; tm.mu must be held
+func (tm *TreasureHuntManager) triviaResultsLocked() string {
+	usernames := choiceUsernames(tm.correctChoicesLocked())
+	if len(usernames) == 0 {
+		return "Nobody got it right"
+	}
+	return fmt.Sprintf("✅ Right: %s (+%d pts each)", strings.Join(usernames, ", "), riddlePoints(tm.currentRiddle.Difficulty))
+}
+
+// CurrentHint returns the hint for the riddle being played, if there's one to give
+func (tm *TreasureHuntManager) CurrentHint() (string, bool) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	if tm.currentRiddle == nil || tm.isSolved || tm.inCooldown || tm.gameOver || tm.paused || tm.currentRiddle.Hint == "" {
+		return "", false
 	}
+	return tm.currentRiddle.Hint, true
 }
 
 // PopAnnouncements returns new announcements and clears the queue
 func (tm *TreasureHuntManager) PopAnnouncements() []protocol.AnnouncementPayload {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
-	
+
 	if len(tm.announcements) == 0 {
 		return nil
 	}
-	
+
 	msgs := tm.announcements
 	tm.announcements = nil // Clear queue
 	return msgs
 }
-
-// CheckTreasureHuntAnswer checks the answer.
-func CheckTreasureHuntAnswer(username, guess string) bool {
-	return Manager.CheckGuess(username, guess)
-}
-
-// GetClueText returns the text for the current step.
-func GetClueText(step int) string {
-	return Manager.GetState().ClueText
-}
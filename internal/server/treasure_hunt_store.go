@@ -0,0 +1,176 @@
+package server
+
+import (
+	"log"
+	"time"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// TreasureHuntRound is a riddle that was played, kept for the day's history
+type TreasureHuntRound struct {
+	Round    int          `json:"round"`
+	Riddle   GeminiRiddle `json:"riddle"`
+	Winner   string       `json:"winner"`             // Empty if time ran out
+	Guesses  int          `json:"guesses"`            // Guesses made by everyone
+	Attempts int          `json:"attempts"`           // Guesses the winner needed
+	Voided   bool         `json:"voided,omitempty"`   // Thrown out by an admin
+	Correct  []string     `json:"correct,omitempty"`  // Everyone who answered a trivia round right, fastest first
+	SolveMs  int64        `json:"solve_ms,omitempty"` // How long the winner took
+	PlayedAt time.Time    `json:"played_at"`
+}
+
+// TreasureHuntSnapshot is the day's treasure hunt as saved in the store, so a restart
+// picks up the same riddle and round instead of starting the daily hunt over
+type TreasureHuntSnapshot struct {
+	Day      string              `json:"day"` // Madison date ("2006-01-02") the hunt belongs to
+	Round    int                 `json:"round"`
+	Riddle   *GeminiRiddle       `json:"riddle"`
+	Solved   bool                `json:"solved"`
+	Winner   string              `json:"winner"`
+	GameOver bool                `json:"game_over"`
+	Cooldown bool                `json:"cooldown"` // Between rounds, the current riddle is finished
+	History  []TreasureHuntRound `json:"history"`
+}
+
+// huntDay returns the Madison date the daily hunt is counted in
+func huntDay(now time.Time) string {
+	return now.In(madisonLocation).Format("2006-01-02")
+}
+
+// restoreLocked loads today's hunt from the store, if it has one; tm.mu must be held
+func (tm *TreasureHuntManager) restoreLocked() {
+	tm.day = huntDay(tm.clock.Now())
+
+	var snapshot *TreasureHuntSnapshot
+	tm.store.View(func(d *StoreData) {
+		if saved, ok := d.TreasureHunts[tm.id]; ok && saved.Day == tm.day {
+			copied := *saved
+			snapshot = &copied
+		}
+	})
+	if snapshot == nil {
+		return
+	}
+
+	tm.currentRound = snapshot.Round
+	tm.currentRiddle = nil
+	if snapshot.Riddle != nil {
+		riddle := *snapshot.Riddle
+		riddle.normalizeKind() // Saved before riddles had a category and difficulty
+		tm.currentRiddle = &riddle
+	}
+	tm.isSolved = snapshot.Solved
+	tm.winner = snapshot.Winner
+	tm.gameOver = snapshot.GameOver
+	tm.history = append([]TreasureHuntRound(nil), snapshot.History...)
+	for _, round := range tm.history {
+		riddles.MarkUsed(&round.Riddle)
+	}
+	if tm.currentRiddle != nil {
+		riddles.MarkUsed(tm.currentRiddle)
+	}
+
+	// The riddle's round ended before the restart (its answer may have been announced),
+	// so treat it as over and let the game loop move on to the next one
+	if snapshot.Cooldown && tm.currentRiddle != nil {
+		tm.isSolved = true
+	}
+	log.Printf("Restored treasure hunt in %s for %s at round %d (solved: %v, game over: %v)",
+		tm.id, tm.day, tm.currentRound, tm.isSolved, tm.gameOver)
+}
+
+// saveLocked writes the day's hunt to the store; tm.mu must be held
+func (tm *TreasureHuntManager) saveLocked() {
+	if tm.store == nil {
+		return
+	}
+
+	snapshot := &TreasureHuntSnapshot{
+		Day:      tm.day,
+		Round:    tm.currentRound,
+		Solved:   tm.isSolved,
+		Winner:   tm.winner,
+		GameOver: tm.gameOver,
+		Cooldown: tm.inCooldown,
+		History:  append([]TreasureHuntRound(nil), tm.history...),
+	}
+	if tm.currentRiddle != nil {
+		riddle := *tm.currentRiddle
+		snapshot.Riddle = &riddle
+	}
+
+	err := tm.store.Update(func(d *StoreData) {
+		d.TreasureHunts[tm.id] = snapshot
+	})
+	if err != nil {
+		log.Printf("Error saving treasure hunt: %v", err)
+	}
+}
+
+// History returns the rounds played today, oldest first, leaving out any an admin voided
+func (tm *TreasureHuntManager) History() []TreasureHuntRound {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	var rounds []TreasureHuntRound
+	for _, round := range tm.history {
+		if !round.Voided {
+			rounds = append(rounds, round)
+		}
+	}
+	return rounds
+}
+
+// recordRoundLocked adds the riddle being played to the day's history; tm.mu must be held
+func (tm *TreasureHuntManager) recordRoundLocked() {
+	if tm.currentRiddle == nil {
+		return
+	}
+	guesses := 0
+	for _, n := range tm.attempts {
+		guesses += n
+	}
+	correct := tm.correctChoicesLocked()
+	var took time.Duration
+	switch {
+	case len(correct) > 0:
+		took = correct[0].took // The round ran its full time, but the winner answered before that
+	case tm.winner != "":
+		took = tm.clock.Now().Sub(tm.roundStarted)
+	}
+	tm.history = append(tm.history, TreasureHuntRound{
+		Round:    tm.currentRound,
+		Riddle:   *tm.currentRiddle,
+		Winner:   tm.winner,
+		Guesses:  guesses,
+		Attempts: tm.attempts[tm.winner],
+		Correct:  choiceUsernames(correct),
+		SolveMs:  took.Milliseconds(),
+		PlayedAt: tm.clock.Now(),
+	})
+}
+
+// sendTreasureHuntHistory sends the client the rounds its room has played today, so
+// players who join late can see what they missed
+func sendTreasureHuntHistory(c *Client, hunt *TreasureHuntManager) {
+	var rounds []protocol.TreasureHuntRoundEntry
+	for _, round := range hunt.History() {
+		rounds = append(rounds, protocol.TreasureHuntRoundEntry{
+			Round:      round.Round,
+			Question:   round.Riddle.Question,
+			Answer:     round.Riddle.Answer,
+			Category:   riddleCategoryByID(round.Riddle.Category).Name,
+			Difficulty: round.Riddle.Difficulty,
+			Winner:     round.Winner,
+			SolveMs:    round.SolveMs,
+			Correct:    round.Correct,
+			PlayedAt:   round.PlayedAt.Unix(),
+		})
+	}
+
+	msg, _ := protocol.EncodeMessage(protocol.MsgTreasureHuntHistory, protocol.TreasureHuntHistoryPayload{
+		Rounds: rounds,
+	})
+	c.deliver(msg)
+}
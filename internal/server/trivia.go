@@ -0,0 +1,156 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	triviaChoices   = 4
+	triviaTimeLimit = 20 * time.Second
+	triviaMaxPoints = 1000 // A correct answer is worth half of this, plus up to half again for speed
+)
+
+// triviaFallback is used when Gemini can't come up with a question
+var triviaFallback = []TriviaQuestion{
+	{Question: "What does CPU stand for?", Choices: []string{"Central Processing Unit", "Core Power Unit", "Computer Personal Unit", "Central Program Utility"}, Answer: 0},
+	{Question: "Which data structure is LIFO?", Choices: []string{"Queue", "Stack", "Heap", "Linked list"}, Answer: 1},
+	{Question: "Binary search on a sorted array runs in...", Choices: []string{"O(1)", "O(n)", "O(log n)", "O(n log n)"}, Answer: 2},
+	{Question: "Which language did Go's creators work on at Bell Labs?", Choices: []string{"Java", "Python", "Rust", "C"}, Answer: 3},
+	{Question: "What is 0b1010 in decimal?", Choices: []string{"8", "10", "12", "5"}, Answer: 1},
+	{Question: "Which HTTP status means Not Found?", Choices: []string{"404", "500", "301", "403"}, Answer: 0},
+}
+
+// drawTrivia asks the model for a question for a treasure hunt trivia round, falling back
+// to a built-in one picked with rng. The question is played as a riddle whose answer is
+// the right choice.
+func drawTrivia(rng *seededRand) *GeminiRiddle {
+	question, err := GenerateTriviaQuestion(triviaChoices)
+	if err != nil {
+		if !errors.Is(err, errNoLLM) {
+			log.Printf("Error generating trivia question, using a fallback: %v", err)
+		}
+		question = &triviaFallback[rng.Intn(len(triviaFallback))]
+	}
+
+	return &GeminiRiddle{
+		Question:   question.Question,
+		Answer:     question.Choices[question.Answer],
+		Category:   "programming",
+		Difficulty: riddleEasy,
+		Choices:    append([]string(nil), question.Choices...),
+	}
+}
+
+// multipleChoice reports whether the riddle is a trivia question answered by picking an option
+func (r *GeminiRiddle) multipleChoice() bool {
+	return len(r.Choices) > 0
+}
+
+// triviaBattle is a mini-game where both players answer the same question privately
+// and score on correctness and speed
+type triviaBattle struct {
+	players  [2]string
+	question TriviaQuestion
+	started  time.Time
+	deadline time.Time
+	answers  [2]int           // Index into question.Choices, -1 until the player answers
+	took     [2]time.Duration // How long each player took to answer
+	clock    Clock
+}
+
+// newTriviaBattle asks Gemini for a question, so it can take a second or two
+func newTriviaBattle(players [2]string, clock Clock, rng *seededRand) MiniGame {
+	question, err := GenerateTriviaQuestion(triviaChoices)
+	if err != nil {
+		log.Printf("Error generating trivia question, using a fallback: %v", err)
+		question = &triviaFallback[rng.Intn(len(triviaFallback))]
+	}
+
+	now := clock.Now()
+	return &triviaBattle{
+		players:  players,
+		question: *question,
+		started:  now,
+		deadline: now.Add(triviaTimeLimit),
+		answers:  [2]int{-1, -1},
+		clock:    clock,
+	}
+}
+
+// Move answers the question with a choice numbered "1"-"4"
+func (t *triviaBattle) Move(player, move string) error {
+	if finished, _ := t.Result(); finished {
+		return errors.New("The battle is already over")
+	}
+
+	i := 0
+	if t.players[1] == player {
+		i = 1
+	}
+	if t.answers[i] != -1 {
+		return errors.New("You've already answered")
+	}
+
+	var choice int
+	if _, err := fmt.Sscanf(move, "%d", &choice); err != nil || choice < 1 || choice > len(t.question.Choices) {
+		return fmt.Errorf("Pick an answer from 1 to %d", len(t.question.Choices))
+	}
+
+	t.answers[i] = choice - 1
+	t.took[i] = t.clock.Now().Sub(t.started)
+	return nil
+}
+
+// Board returns the question followed by its choices. Answers stay private until the
+// battle is over, then the correct answer and each player's result are appended.
+// Both players answer at once, so it's never anyone's turn.
+func (t *triviaBattle) Board() ([]string, string) {
+	board := append([]string{t.question.Question}, t.question.Choices...)
+
+	if finished, _ := t.Result(); finished {
+		board = append(board, "Answer: "+t.question.Choices[t.question.Answer])
+		for i, player := range t.players {
+			switch {
+			case t.answers[i] == -1:
+				board = append(board, player+": no answer")
+			case t.answers[i] == t.question.Answer:
+				board = append(board, fmt.Sprintf("%s: ✓ %.1fs - %d pts", player, t.took[i].Seconds(), t.score(i)))
+			default:
+				board = append(board, player+": ✗ "+t.question.Choices[t.answers[i]])
+			}
+		}
+	}
+	return board, ""
+}
+
+// Result ends the battle once both players answered or time ran out
+func (t *triviaBattle) Result() (bool, string) {
+	if (t.answers[0] == -1 || t.answers[1] == -1) && t.clock.Now().Before(t.deadline) {
+		return false, ""
+	}
+
+	switch scores := [2]int{t.score(0), t.score(1)}; {
+	case scores[0] > scores[1]:
+		return true, t.players[0]
+	case scores[1] > scores[0]:
+		return true, t.players[1]
+	}
+	return true, ""
+}
+
+// Deadline is when the battle ends even if nobody answered
+func (t *triviaBattle) Deadline() time.Time {
+	return t.deadline
+}
+
+// score is worth more the faster a player answered correctly, and nothing if they didn't
+func (t *triviaBattle) score(i int) int {
+	if t.answers[i] != t.question.Answer {
+		return 0
+	}
+	remaining := max(triviaTimeLimit-t.took[i], 0)
+	return triviaMaxPoints/2 + int(int64(triviaMaxPoints/2)*int64(remaining)/int64(triviaTimeLimit))
+}
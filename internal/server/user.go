@@ -1,31 +1,68 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/yourusername/always-at-morg/internal/protocol"
+	"log"
+	"maps"
+	"slices"
 	"sync"
+	"time"
 )
 
+const ledgerHistory = 20 // Most recent point changes kept per user
+
 // User represents a persistent user profile
 type User struct {
-	ID       string
-	Username string
-	Avatar   []int
+	ID       string        `json:"id"`
+	Username string        `json:"username"`
+	Avatar   []int         `json:"avatar"`
+	Points   int           `json:"points"`
+	Ledger   []LedgerEntry `json:"ledger"` // Most recent first
+
+	LastLogin   string `json:"last_login"`   // Madison date ("2006-01-02") of the last login
+	LoginStreak int    `json:"login_streak"` // Consecutive days with a login, counting LastLogin
+
+	Owned    []string          `json:"owned"`    // Shop item IDs the user has bought
+	Equipped map[string]string `json:"equipped"` // Cosmetic kind -> item ID being worn
+
+	LastRoom string `json:"last_room,omitempty"` // ID of the room the user was last in
+	LastPos  string `json:"last_pos,omitempty"`  // "Y:X" where they were standing in it
+}
+
+// LedgerEntry is one change to a user's points
+type LedgerEntry struct {
+	Amount int       `json:"amount"` // Negative when points were spent
+	Reason string    `json:"reason"`
+	Time   time.Time `json:"time"`
 }
 
-// UserManager manages persistent user profiles
+// UserManager manages persistent user profiles, saving them to the store on every change
 type UserManager struct {
 	users     map[string]*User // UserID -> User
 	usernames map[string]*User // Username -> User (for uniqueness check)
+	store     *Store
 	mu        sync.RWMutex
 }
 
-// NewUserManager creates a new user manager
-func NewUserManager() *UserManager {
-	return &UserManager{
+// NewUserManager creates a user manager with the profiles saved in the store
+func NewUserManager(store *Store) *UserManager {
+	um := &UserManager{
 		users:     make(map[string]*User),
 		usernames: make(map[string]*User),
+		store:     store,
 	}
+
+	store.View(func(d *StoreData) {
+		for _, saved := range d.Users {
+			user := *saved
+			um.users[user.ID] = &user
+			um.usernames[user.Username] = &user
+		}
+	})
+	return um
 }
 
 // GetOrCreateUserByUsername gets existing user by username or creates new one
@@ -48,6 +85,7 @@ func (um *UserManager) GetOrCreateUserByUsername(username string, avatar []int)
 
 	um.users[user.ID] = user
 	um.usernames[username] = user
+	um.saveLocked(user)
 	return user, false // new user
 }
 
@@ -59,3 +97,203 @@ func (um *UserManager) DoesUserExist(username string) bool {
 	_, exists := um.usernames[username]
 	return exists
 }
+
+// Points returns the user's balance
+func (um *UserManager) Points(username string) int {
+	um.mu.RLock()
+	defer um.mu.RUnlock()
+
+	if user, exists := um.usernames[username]; exists {
+		return user.Points
+	}
+	return 0
+}
+
+// AwardPoints adds points to the user's balance and returns the new balance
+func (um *UserManager) AwardPoints(username string, amount int, reason string) (int, error) {
+	um.mu.Lock()
+	defer um.mu.Unlock()
+
+	user, exists := um.usernames[username]
+	if !exists {
+		return 0, fmt.Errorf("unknown user %q", username)
+	}
+	um.addLedgerEntryLocked(user, amount, reason)
+	return user.Points, nil
+}
+
+// SpendPoints takes points from the user's balance, failing if they can't afford it
+func (um *UserManager) SpendPoints(username string, amount int, reason string) (int, error) {
+	um.mu.Lock()
+	defer um.mu.Unlock()
+
+	user, exists := um.usernames[username]
+	if !exists {
+		return 0, fmt.Errorf("unknown user %q", username)
+	}
+	if user.Points < amount {
+		return user.Points, errors.New("not enough points")
+	}
+	um.addLedgerEntryLocked(user, -amount, reason)
+	return user.Points, nil
+}
+
+// Cosmetics returns the shop items the user owns and wears
+func (um *UserManager) Cosmetics(username string) ([]string, map[string]string) {
+	um.mu.RLock()
+	defer um.mu.RUnlock()
+
+	user, exists := um.usernames[username]
+	if !exists {
+		return nil, nil
+	}
+	equipped := make(map[string]string, len(user.Equipped))
+	for kind, id := range user.Equipped {
+		equipped[kind] = id
+	}
+	return append([]string(nil), user.Owned...), equipped
+}
+
+// BuyItem spends the item's price and adds it to what the user owns
+func (um *UserManager) BuyItem(username string, item protocol.ShopItem) (int, error) {
+	um.mu.Lock()
+	defer um.mu.Unlock()
+
+	user, exists := um.usernames[username]
+	if !exists {
+		return 0, fmt.Errorf("unknown user %q", username)
+	}
+	if slices.Contains(user.Owned, item.ID) {
+		return user.Points, fmt.Errorf("You already own %s", item.Name)
+	}
+	if item.Reward {
+		return user.Points, fmt.Errorf("%s can't be bought - it's earned", item.Name)
+	}
+	if user.Points < item.Price {
+		return user.Points, fmt.Errorf("%s costs %d points, you have %d", item.Name, item.Price, user.Points)
+	}
+
+	user.Owned = append(user.Owned, item.ID)
+	um.addLedgerEntryLocked(user, -item.Price, "Bought "+item.Name)
+	return user.Points, nil
+}
+
+// GrantItem gives the user an item for free, reporting false if they already had it
+func (um *UserManager) GrantItem(username string, item protocol.ShopItem) bool {
+	um.mu.Lock()
+	defer um.mu.Unlock()
+
+	user, exists := um.usernames[username]
+	if !exists || slices.Contains(user.Owned, item.ID) {
+		return false
+	}
+	user.Owned = append(user.Owned, item.ID)
+	um.saveLocked(user)
+	return true
+}
+
+// ToggleEquipped wears an owned item in place of any other of its kind, or takes it
+// off if it's already worn. Reports whether the item is now worn.
+func (um *UserManager) ToggleEquipped(username string, item protocol.ShopItem) (bool, error) {
+	um.mu.Lock()
+	defer um.mu.Unlock()
+
+	user, exists := um.usernames[username]
+	if !exists {
+		return false, fmt.Errorf("unknown user %q", username)
+	}
+	if !slices.Contains(user.Owned, item.ID) {
+		return false, fmt.Errorf("You don't own %s", item.Name)
+	}
+
+	if user.Equipped == nil {
+		user.Equipped = make(map[string]string)
+	}
+	worn := user.Equipped[item.Kind] != item.ID
+	if worn {
+		user.Equipped[item.Kind] = item.ID
+	} else {
+		delete(user.Equipped, item.Kind)
+	}
+	um.saveLocked(user)
+	return worn, nil
+}
+
+// RecordLogin notes that the user logged in, returning their login streak and whether
+// this is their first login of the day in Madison
+func (um *UserManager) RecordLogin(username string, now time.Time) (int, bool) {
+	um.mu.Lock()
+	defer um.mu.Unlock()
+
+	user, exists := um.usernames[username]
+	if !exists {
+		return 0, false
+	}
+	local := now.In(madisonLocation)
+	today := local.Format("2006-01-02")
+	if user.LastLogin == today {
+		return user.LoginStreak, false
+	}
+
+	if user.LastLogin == local.AddDate(0, 0, -1).Format("2006-01-02") {
+		user.LoginStreak++
+	} else {
+		user.LoginStreak = 1
+	}
+	user.LastLogin = today
+	um.saveLocked(user)
+	return user.LoginStreak, true
+}
+
+// LastPosition returns the room the user was last in and where they were standing
+func (um *UserManager) LastPosition(username string) (roomID, pos string) {
+	um.mu.RLock()
+	defer um.mu.RUnlock()
+
+	if user, exists := um.usernames[username]; exists {
+		return user.LastRoom, user.LastPos
+	}
+	return "", ""
+}
+
+// SetLastPositions records where users are standing in a room, so they come back there
+func (um *UserManager) SetLastPositions(roomID string, positions map[string]string) {
+	um.mu.Lock()
+	defer um.mu.Unlock()
+
+	for username, pos := range positions {
+		user, exists := um.usernames[username]
+		if !exists || (user.LastRoom == roomID && user.LastPos == pos) {
+			continue
+		}
+		user.LastRoom = roomID
+		user.LastPos = pos
+		um.saveLocked(user)
+	}
+}
+
+// addLedgerEntryLocked changes the balance, records why and saves the user; um.mu must be held
+func (um *UserManager) addLedgerEntryLocked(user *User, amount int, reason string) {
+	user.Points += amount
+	user.Ledger = append([]LedgerEntry{{Amount: amount, Reason: reason, Time: time.Now()}}, user.Ledger...)
+	if len(user.Ledger) > ledgerHistory {
+		user.Ledger = user.Ledger[:ledgerHistory]
+	}
+	um.saveLocked(user)
+}
+
+// saveLocked writes a copy of the user to the store; um.mu must be held
+func (um *UserManager) saveLocked(user *User) {
+	saved := *user
+	saved.Avatar = append([]int(nil), user.Avatar...)
+	saved.Ledger = append([]LedgerEntry(nil), user.Ledger...)
+	saved.Owned = append([]string(nil), user.Owned...)
+	saved.Equipped = maps.Clone(user.Equipped)
+
+	err := um.store.Update(func(d *StoreData) {
+		d.Users[user.Username] = &saved
+	})
+	if err != nil {
+		log.Printf("Error saving user %s: %v", user.Username, err)
+	}
+}
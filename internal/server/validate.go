@@ -0,0 +1,189 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yourusername/always-at-morg/internal/protocol"
+)
+
+// Limits on what a client can send
+const (
+	maxChatLength  = 500 // Longest chat message, in runes
+	maxGuessLength = 100 // Longest treasure hunt guess, in runes
+)
+
+// invalidMessageError says which field of a client's message was rejected and why
+type invalidMessageError struct {
+	field  string
+	reason string
+}
+
+func (e *invalidMessageError) Error() string {
+	return fmt.Sprintf("Invalid %s: %s", e.field, e.reason)
+}
+
+func invalid(field, reason string) error {
+	return &invalidMessageError{field: field, reason: reason}
+}
+
+// needsRoom lists the messages that only make sense from a player in a room
+var needsRoom = map[protocol.MessageType]bool{
+	protocol.MsgGlobalChat:        true,
+	protocol.MsgRoomChat:          true,
+	protocol.MsgChatMessage:       true,
+	protocol.MsgTreasureHuntGuess: true,
+	protocol.MsgInteract:          true,
+	protocol.MsgMiniGameChallenge: true,
+	protocol.MsgMiniGameRespond:   true,
+	protocol.MsgMiniGameMove:      true,
+	protocol.MsgSetStatus:         true,
+	protocol.MsgRoomModerate:      true,
+	protocol.MsgGameMode:          true,
+	protocol.MsgScavenger:         true,
+	protocol.MsgPomodoro:          true,
+	protocol.MsgPlayerMove:        true,
+}
+
+// validate checks a client's message before it's handled: that the client is in a room
+// if the message needs one, that usernames in it are the client's own, and that sizes
+// and coordinates are in range. DecodeUntrusted has already checked that the payload
+// decodes.
+func (c *Client) validate(msg *protocol.Message) error {
+	if needsRoom[msg.Type] && c.Room == nil {
+		return invalid("message", "join a room first")
+	}
+
+	switch msg.Type {
+	case protocol.MsgJoinRoom:
+		var payload protocol.JoinRoomPayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		if err := validUsername(payload.Username); err != nil {
+			return err
+		}
+		if c.Username != "" && payload.Username != c.Username {
+			return invalid("username", "can't change usernames on the same connection")
+		}
+
+	case protocol.MsgOnboard:
+		var payload protocol.OnboardPayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		if len(payload.Avatar) != protocol.AvatarParts {
+			return invalid("avatar", fmt.Sprintf("must have %d parts", protocol.AvatarParts))
+		}
+		for _, part := range payload.Avatar {
+			if part < 0 || part >= protocol.AvatarOptions {
+				return invalid("avatar", fmt.Sprintf("parts must be 0-%d", protocol.AvatarOptions-1))
+			}
+		}
+
+	case protocol.MsgGlobalChat:
+		var payload protocol.GlobalChatPayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		if err := c.ownUsername(payload.Username); err != nil {
+			return err
+		}
+		return validChat(payload.Message)
+
+	case protocol.MsgRoomChat:
+		var payload protocol.RoomChatPayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		if err := c.ownUsername(payload.Username); err != nil {
+			return err
+		}
+		c.Room.mu.RLock()
+		current := c.CurrentRoomNumber
+		c.Room.mu.RUnlock()
+		if payload.RoomNumber == "" || payload.RoomNumber != current {
+			return invalid("room_number", "you can only chat in the room you're standing in")
+		}
+		return validChat(payload.Message)
+
+	case protocol.MsgChatMessage:
+		var payload protocol.ChatMessagePayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		if err := c.ownUsername(payload.FromPlayerID); err != nil {
+			return err
+		}
+		if payload.ToPlayerID == "" {
+			return invalid("to_player_id", "pick who to message")
+		}
+		return validChat(payload.Message)
+
+	case protocol.MsgTreasureHuntGuess:
+		var payload protocol.TreasureHuntGuessPayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		if utf8.RuneCountInString(payload.Guess) > maxGuessLength {
+			return invalid("guess", fmt.Sprintf("too long (max %d characters)", maxGuessLength))
+		}
+
+	case protocol.MsgPlayerMove:
+		var payload protocol.PlayerMovePayload
+		if json.Unmarshal(msg.Payload, &payload) != nil {
+			return nil
+		}
+		// How far and how often a player moves is up to the room (see allowMoveLocked)
+		if payload.NewX < 0 || payload.NewX >= protocol.MapWidth || payload.NewY < 0 || payload.NewY >= protocol.MapHeight {
+			return invalid("position", "off the map")
+		}
+	}
+	return nil
+}
+
+// ownUsername rejects a message sent on behalf of someone else. Clients may leave the
+// username out; the server fills in their own.
+func (c *Client) ownUsername(username string) error {
+	if username != "" && username != c.Username {
+		return invalid("username", "can't send messages as someone else")
+	}
+	return nil
+}
+
+// validUsername checks a username picked when joining
+func validUsername(username string) error {
+	switch {
+	case strings.TrimSpace(username) == "":
+		return invalid("username", "can't be empty")
+	case utf8.RuneCountInString(username) > protocol.MaxUsernameLength:
+		return invalid("username", fmt.Sprintf("too long (max %d characters)", protocol.MaxUsernameLength))
+	case strings.IndexFunc(username, func(r rune) bool { return !unicode.IsPrint(r) }) >= 0:
+		return invalid("username", "can only have printable characters")
+	}
+	return nil
+}
+
+// validChat checks the text of a chat message
+func validChat(message string) error {
+	switch {
+	case strings.TrimSpace(message) == "":
+		return invalid("message", "can't be empty")
+	case utf8.RuneCountInString(message) > maxChatLength:
+		return invalid("message", fmt.Sprintf("too long (max %d characters)", maxChatLength))
+	}
+	return nil
+}
+
+// sendInvalidMessage tells the client why its message was rejected
+func sendInvalidMessage(c *Client, err error) {
+	payload := protocol.ErrorPayload{Message: err.Error(), Code: protocol.ErrCodeInvalidMessage}
+	if invalid, ok := err.(*invalidMessageError); ok {
+		payload.Field = invalid.field
+	}
+	msg, _ := protocol.EncodeMessage(protocol.MsgError, payload)
+	c.deliver(msg)
+}
@@ -2,12 +2,17 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"github.com/yourusername/always-at-morg/internal/gamemap"
 	"github.com/yourusername/always-at-morg/internal/protocol"
 )
 
@@ -15,7 +20,7 @@ const (
 	writeWait      = 10 * time.Second
 	pongWait       = 60 * time.Second    //time allowed to read the next pong message from client
 	pingPeriod     = (pongWait * 9) / 10 //send pings to client with this period. must be less than pongWait
-	maxMessageSize = 512
+	maxMessageSize = 4096                //largest message read from a client, in bytes
 )
 
 var upgrader = websocket.Upgrader{ //upgrade HTTP connections to WebSocket connections
@@ -28,19 +33,48 @@ var upgrader = websocket.Upgrader{ //upgrade HTTP connections to WebSocket conne
 
 // Client represents a WebSocket client
 type Client struct {
-	ID               string
-	Name             string
-	Room             *Room
-	conn             *websocket.Conn
-	send             chan []byte
-	Username         string
-	Avatar           []int
-	inGame           bool
-	Pos              string
+	ID                string
+	Name              string
+	Room              *Room
+	conn              *websocket.Conn
+	send              chan []byte
+	state             chan []byte // Latest tick state, replaced by the next one if it hasn't gone out yet
+	Username          string
+	Avatar            []int
+	inGame            bool
+	Pos               string
 	CurrentRoomNumber string // Current room the player is in ("1", "2", etc.) or "" if in hallway
+	Status            string // Player-set status message shown to others
+	lobbyRoom         *Room  // Room picked in the lobby, joined once a new user finishes onboarding
+	Accessory         string // Equipped shop accessory glyph (guarded by Room.mu)
+	NameColor         string // Equipped shop name color (guarded by Room.mu)
+	sessionToken      string // Sent in room_joined so the client can resume after a lost connection
+	resumed           bool   // Rejoined with a valid session token, replacing any connection left behind
 
 	// Treasure Hunt Progress
 	TreasureHuntStep int
+
+	// When the client entered its room, for session length analytics (guarded by Room.mu)
+	joinedAt time.Time
+
+	// Idle detection, Unix nanoseconds of the last message from the client. Only touch
+	// and idleFor use it, so it can be read without the client's room lock.
+	lastInput  atomic.Int64
+	idleWarned bool // Idle kick warning already sent (guarded by Room.mu)
+
+	// Movement rate limit, and the last move handled (guarded by Room.mu)
+	moveWindow    time.Time
+	movesInWindow int
+	moveSeq       uint64
+	ghost         bool // Admin looking around unseen, through walls (see Room.SetGhost)
+
+	// Backpressure, Unix nanoseconds since everything sent to the client started being
+	// dropped, 0 while it keeps up
+	lagSince atomic.Int64
+
+	// Closed by disconnect to hang up; send never is, so queueing on it can't panic
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 // Server represents the WebSocket server
@@ -48,33 +82,90 @@ type Server struct {
 	roomManager *RoomManager
 	userManager *UserManager
 	chatManager *ChatManager
+	store       *Store
+	sessions    *SessionManager
+	adminToken  string // Bearer token for the admin API, empty disables it
+	strict      bool   // Refuse client messages with fields the protocol doesn't have
 }
 
 // NewServer creates a new WebSocket server
-func NewServer() *Server {
+func NewServer(cfg Config) *Server {
+	// Every room plays on the same map, so it's read once, here
+	tiles, err := getTileMap()
+	if err != nil {
+		log.Printf("Warning: failed to load room map: %v", err)
+	} else if err := gamemap.ValidateSpawnZones(tiles); err != nil {
+		log.Printf("Warning: %v; players may spawn in odd places", err)
+	}
+
 	chatManager := NewChatManager()
-	s := &Server{
-		roomManager: NewRoomManager(chatManager),
-		userManager: NewUserManager(),
-		chatManager: chatManager,
+	store, err := NewStore(cfg.DataDir)
+	if err != nil {
+		log.Printf("Warning: failed to open data store, nothing will be saved: %v", err)
+		store, _ = NewStore("")
 	}
 
-	// Setup treasure hunt broadcast
-	Manager.SetUpdateCallback(func(payload protocol.TreasureHuntStatePayload) {
-		// Broadcast to all rooms/clients
-		// Since we don't have a direct "BroadcastAll" on RoomManager, we can iterate or
-		// rely on the fact that the next tick will pick it up.
-		// Ideally, RoomManager should have a Broadcast method.
-		// For now, we rely on the game loop tick in room.go to pick up the state via Manager.GetState()
-		// But to be safe, we can try to broadcast if possible.
-	})
+	llm, err := NewRiddleProvider(cfg.LLM)
+	if err != nil {
+		log.Printf("Warning: %v, using the built-in riddles", err)
+		llm = StaticProvider{}
+	}
+	SetRiddleProvider(llm)
+
+	tracker, err := NewAnalytics(cfg.Analytics)
+	if err != nil {
+		log.Printf("Warning: %v, analytics are off", err)
+	}
+	SetAnalytics(tracker)
 
-	// Start the treasure hunt game loop
-	go Manager.StartGameLoop()
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	log.Printf("Random seed %d (pass -seed %d to replay this run)", cfg.Seed, cfg.Seed)
 
+	users := NewUserManager(store)
+	s := &Server{
+		roomManager: NewRoomManager(chatManager, users, store, cfg),
+		userManager: users,
+		chatManager: chatManager,
+		store:       store,
+		sessions:    NewSessionManager(),
+		adminToken:  cfg.AdminToken,
+		strict:      cfg.StrictProtocol,
+	}
+	// Announcements reach players in every room, not just the sender's
+	chatManager.SetHub(s.roomManager.BroadcastAll)
 	return s
 }
 
+// SaveState writes the rooms to the store so they come back after a restart, finishes
+// any session journals, and writes the analytics events still waiting to their sink;
+// call it when shutting down
+func (s *Server) SaveState() {
+	s.roomManager.SaveRooms()
+	s.roomManager.StopRecordings()
+	analytics.Close()
+}
+
+// Handler routes the game's WebSocket at /ws and the admin API under /admin/, to serve
+// from an http.Server, or over a memnet.Listener to run the server in-process
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", s.HandleWebSocket)
+	mux.HandleFunc("/admin/hunt", s.HandleAdminHunt)
+	mux.HandleFunc("/admin/riddle", s.HandleAdminRiddle)
+	mux.HandleFunc("/admin/rooms", s.HandleAdminRooms)
+	mux.HandleFunc("/admin/backpressure", s.HandleAdminBackpressure)
+	mux.HandleFunc("/admin/ticks", s.HandleAdminTicks)
+	mux.HandleFunc("/admin/ghost", s.HandleAdminGhost)
+	mux.HandleFunc("/admin/occupancy", s.HandleAdminOccupancy)
+	mux.HandleFunc("/admin/heatmap", s.HandleAdminHeatmap)
+	mux.HandleFunc("/admin/record", s.HandleAdminRecord)
+	mux.HandleFunc("/admin/kick", s.HandleAdminKick)
+	mux.HandleFunc("/admin/announce", s.HandleAdminAnnounce)
+	return mux
+}
+
 // HandleWebSocket handles WebSocket connections
 func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	conn, err := upgrader.Upgrade(w, r, nil)
@@ -84,10 +175,13 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	}
 
 	client := &Client{
-		ID:   uuid.New().String(),
-		conn: conn,
-		send: make(chan []byte, 256),
+		ID:    uuid.New().String(),
+		conn:  conn,
+		send:  make(chan []byte, 256),
+		state: make(chan []byte, 1),
+		done:  make(chan struct{}),
 	}
+	client.touch()
 
 	go client.writePump()
 	go client.readPump(s)
@@ -97,11 +191,13 @@ func (s *Server) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 func (c *Client) readPump(s *Server) {
 	defer func() {
 		if c.Room != nil {
-			c.Room.unregister <- c
+			c.Room.leave(c)
 		}
+		c.disconnect()
 		c.conn.Close()
 	}()
 
+	c.conn.SetReadLimit(maxMessageSize)
 	c.conn.SetReadDeadline(time.Now().Add(pongWait))
 	c.conn.SetPongHandler(func(string) error {
 		c.conn.SetReadDeadline(time.Now().Add(pongWait))
@@ -121,6 +217,22 @@ func (c *Client) readPump(s *Server) {
 	}
 }
 
+// touch records client activity for idle detection
+func (c *Client) touch() {
+	c.lastInput.Store(time.Now().UnixNano())
+}
+
+// idleFor returns how long it has been since the client last sent anything
+func (c *Client) idleFor() time.Duration {
+	return time.Since(time.Unix(0, c.lastInput.Load()))
+}
+
+// disconnect hangs up on the client once what's already queued for it has been written.
+// Its readPump then fails and unregisters it, if the room hasn't already.
+func (c *Client) disconnect() {
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
 // writePump pumps messages from the room to the WebSocket connection
 func (c *Client) writePump() {
 	ticker := time.NewTicker(pingPeriod)
@@ -131,13 +243,8 @@ func (c *Client) writePump() {
 
 	for {
 		select {
-		case message, ok := <-c.send:
+		case message := <-c.send:
 			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
-			if !ok {
-				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
-				return
-			}
-
 			w, err := c.conn.NextWriter(websocket.TextMessage)
 			if err != nil {
 				return
@@ -155,20 +262,57 @@ func (c *Client) writePump() {
 				return
 			}
 
+		case message := <-c.state:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return
+			}
+
 		case <-ticker.C:
 			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
 			}
+
+		case <-c.done:
+			// Flush what's queued, such as why the client was kicked, then hang up
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			for n := len(c.send); n > 0; n-- {
+				if err := c.conn.WriteMessage(websocket.TextMessage, <-c.send); err != nil {
+					return
+				}
+			}
+			c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+			return
 		}
 	}
 }
 
 // handleMessage handles incoming messages from the client
 func (c *Client) handleMessage(s *Server, data []byte) {
-	msg, err := protocol.DecodeMessage(data)
+	msg, err := protocol.DecodeUntrusted(data, s.strict)
 	if err != nil {
-		log.Printf("Error decoding message: %v", err)
+		log.Printf("Error decoding message from %s: %v", c.Username, err)
+		sendInvalidMessage(c, invalid("message", err.Error()))
+		return
+	}
+
+	// Pings are sent on their own every few seconds, so they don't count as activity
+	if msg.Type == protocol.MsgPing {
+		pong, _ := protocol.EncodeMessage(protocol.MsgPong, msg.Payload)
+		c.deliver(pong)
+		return
+	}
+	c.touch()
+	if c.Room != nil {
+		c.Room.record(protocol.JournalIn, c.Username, data)
+		if !requests[msg.Type] {
+			c.Room.markActive()
+		}
+	}
+
+	if err := c.validate(msg); err != nil {
+		sendInvalidMessage(c, err)
 		return
 	}
 
@@ -185,7 +329,7 @@ func (c *Client) handleMessage(s *Server, data []byte) {
 			errMsg, _ := protocol.EncodeMessage(protocol.MsgError, protocol.ErrorPayload{
 				Message: "Invalid onboarding flow - username not set",
 			})
-			c.send <- errMsg
+			c.deliver(errMsg)
 			return
 		}
 
@@ -195,21 +339,38 @@ func (c *Client) handleMessage(s *Server, data []byte) {
 		// Set client fields
 		c.Avatar = user.Avatar
 		c.Name = payload.Name
+		applyCosmetics(s.userManager, c)
 
 		log.Printf("New user %s onboarded with avatar %v", c.Username, c.Avatar)
 
-		// Auto-join default room
-		room := s.roomManager.GetOrCreateRoom("default-room")
+		// Join the room picked in the lobby
+		room := c.lobbyRoom
+		if room == nil {
+			sendError(c, "Invalid onboarding flow - no room picked")
+			return
+		}
+		// The room may have filled up while they picked an avatar
+		if err := s.roomManager.CheckSpace(room); err != nil {
+			sendRoomError(c, err)
+			return
+		}
+		c.sessionToken, _ = s.sessions.Resume(c.Username, "")
 		c.Room = room
 		c.inGame = true
-		room.register <- c
+		if err := room.join(c); err != nil {
+			c.Room, c.inGame = nil, false
+			sendRoomError(c, err)
+			return
+		}
 
 		// --- ADDED: Send initial treasure hunt state for new users ---
 		// Use global state instead of per-user step
-		thMsg, _ := protocol.EncodeMessage(protocol.MsgTreasureHuntState, Manager.GetState())
-		c.send <- thMsg
+		thMsg, _ := protocol.EncodeMessage(protocol.MsgTreasureHuntState, room.hunt.GetState())
+		c.deliver(thMsg)
 		// ------------------------------------------------------------
 
+		sendLoginRewards(s.userManager, c)
+
 	case protocol.MsgJoinRoom:
 		var payload protocol.JoinRoomPayload
 		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
@@ -219,7 +380,16 @@ func (c *Client) handleMessage(s *Server, data []byte) {
 
 		// Set default room ID if not specified
 		if payload.RoomID == "" {
-			payload.RoomID = "default-room"
+			payload.RoomID = protocol.DefaultRoomID
+		}
+		if err := validRoomID(payload.RoomID); err != nil {
+			sendRoomError(c, err)
+			return
+		}
+		room, err := s.roomManager.GetOrCreateRoom(payload.RoomID, payload.Username, payload.Password)
+		if err != nil {
+			sendRoomError(c, err)
+			return
 		}
 
 		// Check if username exists in UserManager
@@ -231,29 +401,36 @@ func (c *Client) handleMessage(s *Server, data []byte) {
 			c.Username = user.Username
 			c.Avatar = user.Avatar
 			c.Name = user.Username
+			applyCosmetics(s.userManager, c)
 
 			// Join room
-			room := s.roomManager.GetOrCreateRoom(payload.RoomID)
+			c.sessionToken, c.resumed = s.sessions.Resume(user.Username, payload.SessionToken)
 			c.Room = room
 			c.inGame = true
-			room.register <- c
+			if err := room.join(c); err != nil {
+				c.Room, c.inGame = nil, false
+				sendRoomError(c, err)
+				return
+			}
 			log.Printf("Returning user %s joined", user.Username)
 
 			// Send initial treasure hunt state
-			thMsg, _ := protocol.EncodeMessage(protocol.MsgTreasureHuntState, Manager.GetState())
-			c.send <- thMsg
+			thMsg, _ := protocol.EncodeMessage(protocol.MsgTreasureHuntState, room.hunt.GetState())
+			c.deliver(thMsg)
 
+			sendLoginRewards(s.userManager, c)
 			return
 		}
 
 		// New user - store username and request onboarding for avatar selection
 		c.Username = payload.Username
+		c.lobbyRoom = room
 		onboardRequest, _ := protocol.EncodeMessage(protocol.MsgOnboardRequest, nil)
-		c.send <- onboardRequest
+		c.deliver(onboardRequest)
 
 	case protocol.MsgLeaveRoom:
 		if c.Room != nil {
-			c.Room.unregister <- c
+			c.Room.leave(c)
 			c.Room = nil
 			// TODO: mark user not in game so they're not rendered
 		}
@@ -266,7 +443,9 @@ func (c *Client) handleMessage(s *Server, data []byte) {
 		}
 
 		// Handle global chat through ChatManager
-		s.chatManager.HandleGlobalChat(c, payload.Message, c.Room)
+		s.chatManager.HandleGlobalChat(c, payload.Message)
+		// Everyone sees global chat, so every room picks up its pace to show it
+		s.roomManager.markAllActive()
 
 	case protocol.MsgRoomChat:
 		var payload protocol.RoomChatPayload
@@ -275,22 +454,9 @@ func (c *Client) handleMessage(s *Server, data []byte) {
 			return
 		}
 
-		// No validation - trust the client about which room they're in
-		// (Server doesn't have flood-filled room map, only client does)
-
 		// Handle room chat through ChatManager
 		s.chatManager.HandleRoomChat(c, payload.RoomNumber, payload.Message, c.Room)
 
-	case protocol.MsgAnnouncement:
-		var payload protocol.AnnouncementPayload
-		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
-			log.Printf("Error unmarshaling announcement payload: %v", err)
-			return
-		}
-
-		// Handle global chat through ChatManager
-		s.chatManager.HandleAnnouncement(payload.Message, c.Room)
-
 	case protocol.MsgChatMessage:
 		var payload protocol.ChatMessagePayload
 		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
@@ -301,33 +467,170 @@ func (c *Client) handleMessage(s *Server, data []byte) {
 		// payload.ToPlayerID is actually a username from the client
 		s.chatManager.HandleDirectMessage(c, payload.ToPlayerID, payload.Message, c.Room)
 
-	case protocol.MsgGlobalChatMessages:
-		// Client requesting global chat history
+	case protocol.MsgChatHistoryRequest:
+		s.sendChatHistory(c)
+
+	case protocol.MsgTreasureHuntGuess:
+		var payload protocol.TreasureHuntGuessPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			return
+		}
 		if c.Room == nil {
 			return
 		}
 
-		payload := s.chatManager.GetGlobalMessages(c.Room)
+		// Each room runs its own hunt. Trivia rounds take one answer from each player,
+		// so they aren't rate limited.
+		if c.Room.hunt.MultipleChoice() {
+			if err := c.Room.hunt.AnswerChoice(c.Username, payload.Guess); err != nil {
+				sendError(c, err.Error())
+				return
+			}
+			// Whether it's right is only known when the round ends
+			track("guess", c.Username, c.Room.ID, map[string]any{"trivia": true})
+		} else if err := c.Room.hunt.AllowGuess(c.Username); err != nil {
+			sendError(c, err.Error())
+			return
+		} else {
+			points, streak, ok := c.Room.hunt.CheckGuess(c.Username, payload.Guess)
+			track("guess", c.Username, c.Room.ID, map[string]any{"trivia": false, "correct": ok})
+			if ok {
+				awardPoints(s.userManager, c, points, "Solved the riddle")
+				if bonus := winStreakBonus(streak); bonus > 0 {
+					awardPoints(s.userManager, c, bonus, fmt.Sprintf("%d riddles in a row", streak))
+				}
+			}
+		}
 
-		msg, err := protocol.EncodeMessage(protocol.MsgGlobalChatMessages, payload)
-		if err != nil {
+		// Send updated state
+		resp, _ := protocol.EncodeMessage(protocol.MsgTreasureHuntState, c.Room.hunt.GetState())
+		c.deliver(resp)
+
+	case protocol.MsgInteract:
+		if c.Room != nil {
+			c.Room.HandleInteract(c)
+		}
+
+	case protocol.MsgMiniGameChallenge:
+		var payload protocol.MiniGameChallengePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling mini-game challenge payload: %v", err)
 			return
 		}
 
-		c.send <- msg
+		if c.Room != nil {
+			c.Room.HandleMiniGameChallenge(c, payload.Target, payload.Game)
+		}
 
-	case protocol.MsgTreasureHuntGuess:
-		var payload protocol.TreasureHuntGuessPayload
+	case protocol.MsgMiniGameRespond:
+		var payload protocol.MiniGameRespondPayload
 		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling mini-game respond payload: %v", err)
 			return
 		}
 
-		// Check answer using Username (Global Game)
-		CheckTreasureHuntAnswer(c.Username, payload.Guess)
+		if c.Room != nil {
+			c.Room.HandleMiniGameRespond(c, payload.From, payload.Accept)
+		}
 
-		// Send updated state
-		resp, _ := protocol.EncodeMessage(protocol.MsgTreasureHuntState, Manager.GetState())
-		c.send <- resp
+	case protocol.MsgMiniGameMove:
+		var payload protocol.MiniGameMovePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling mini-game move payload: %v", err)
+			return
+		}
+
+		if c.Room != nil {
+			c.Room.HandleMiniGameMove(c, payload.GameID, payload.Move)
+		}
+
+	case protocol.MsgSetStatus:
+		var payload protocol.SetStatusPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling set status payload: %v", err)
+			return
+		}
+
+		if c.Room != nil {
+			c.Room.HandleSetStatus(c, payload.Status)
+		}
+
+	case protocol.MsgRoomModerate:
+		var payload protocol.RoomModeratePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling room moderate payload: %v", err)
+			return
+		}
+
+		if c.Room != nil {
+			c.Room.HandleModerate(c, payload)
+		}
+
+	case protocol.MsgGameMode:
+		var payload protocol.GameModePayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling game mode payload: %v", err)
+			return
+		}
+
+		if c.Room != nil {
+			c.Room.HandleGameMode(c, payload.Mode, payload.Action)
+		}
+
+	case protocol.MsgScavenger:
+		var payload protocol.ScavengerPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling scavenger payload: %v", err)
+			return
+		}
+
+		if c.Room != nil {
+			c.Room.HandleScavenger(c, payload.Action)
+		}
+
+	case protocol.MsgListRooms:
+		msg, _ := protocol.EncodeMessage(protocol.MsgRoomList, protocol.RoomListPayload{
+			Rooms: s.roomManager.ListRooms(),
+		})
+		c.deliver(msg)
+
+	case protocol.MsgLeaderboardRequest:
+		s.handleLeaderboardRequest(c)
+
+	case protocol.MsgTreasureHuntHistoryRequest:
+		if c.Room != nil {
+			sendTreasureHuntHistory(c, c.Room.hunt)
+		}
+
+	case protocol.MsgAnnouncementHistoryRequest:
+		s.chatManager.sendAnnouncementHistory(c)
+
+	case protocol.MsgShopRequest:
+		s.sendShopState(c, "")
+
+	case protocol.MsgShopBuy, protocol.MsgShopEquip:
+		var payload protocol.ShopItemPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling shop payload: %v", err)
+			return
+		}
+
+		if msg.Type == protocol.MsgShopBuy {
+			s.handleShopBuy(c, payload.ItemID)
+		} else {
+			s.handleShopEquip(c, payload.ItemID)
+		}
+
+	case protocol.MsgPomodoro:
+		var payload protocol.PomodoroPayload
+		if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+			log.Printf("Error unmarshaling pomodoro payload: %v", err)
+			return
+		}
+
+		if c.Room != nil {
+			c.Room.HandlePomodoro(c, payload.Stop)
+		}
 
 	case protocol.MsgPlayerMove:
 		var payload protocol.PlayerMovePayload
@@ -338,7 +641,38 @@ func (c *Client) handleMessage(s *Server, data []byte) {
 
 		// Update player position in room
 		if c.Room != nil {
-			c.Room.UpdatePlayerPosition(c.Username, payload.NewX, payload.NewY)
+			c.Room.UpdatePlayerPosition(c.Username, payload.NewX, payload.NewY, payload.Seq)
 		}
 	}
 }
+
+// sendError sends an error message to the client
+func sendError(c *Client, message string) {
+	errMsg, _ := protocol.EncodeMessage(protocol.MsgError, protocol.ErrorPayload{
+		Message: message,
+	})
+	c.deliver(errMsg)
+}
+
+// sendRoomError tells the client why it couldn't join a room, with a code the lobby
+// uses to ask for a password or offer another room
+func sendRoomError(c *Client, err error) {
+	code := protocol.ErrCodeInvalidRoom
+	var suggestion string
+	var full *roomFullError
+	switch {
+	case errors.Is(err, errRoomPasswordRequired):
+		code = protocol.ErrCodePasswordRequired
+	case errors.Is(err, errRoomWrongPassword):
+		code = protocol.ErrCodeWrongPassword
+	case errors.As(err, &full):
+		code = protocol.ErrCodeRoomFull
+		suggestion = full.suggestion
+	}
+	errMsg, _ := protocol.EncodeMessage(protocol.MsgError, protocol.ErrorPayload{
+		Message:    err.Error(),
+		Code:       code,
+		Suggestion: suggestion,
+	})
+	c.deliver(errMsg)
+}
@@ -0,0 +1,207 @@
+// Package webterm plays the game from a browser. The page it serves is an xterm.js
+// terminal whose keys and screen go over a WebSocket to a client UI running in the
+// server, one per browser, connected to the game in-process through memnet.
+//
+//	mux.Handle("/play/", http.StripPrefix("/play", webterm.Handler(srv.Handler())))
+//
+// The terminal sends keys as binary frames and its size, when it changes, as a text
+// frame like {"cols":120,"rows":40}; the screen comes back as binary frames.
+package webterm
+
+import (
+	_ "embed"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gorilla/websocket"
+	"github.com/muesli/termenv"
+	"github.com/yourusername/always-at-morg/internal/client/config"
+	"github.com/yourusername/always-at-morg/internal/client/ui"
+	"github.com/yourusername/always-at-morg/internal/memnet"
+)
+
+// MaxSessions is how many browsers can play at once; each runs a whole client
+const MaxSessions = 32
+
+//go:embed index.html
+var page []byte
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 4096,
+	// The default origin check keeps other sites' pages from opening sessions
+}
+
+// resize is the text frame the page sends with the terminal's size
+type resize struct {
+	Cols int `json:"cols"`
+	Rows int `json:"rows"`
+}
+
+// handler serves the page at / and its sessions at /ws
+type handler struct {
+	game *memnet.Listener
+
+	mu       sync.Mutex
+	sessions int
+}
+
+// Handler serves the browser client, playing on the game served by game
+func Handler(game http.Handler) http.Handler {
+	h := &handler{game: memnet.Listen()}
+	go http.Serve(h.game, game)
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/", "":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+	case "/ws":
+		h.serveSession(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveSession runs a client for one browser until either side quits
+func (h *handler) serveSession(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	full := h.sessions >= MaxSessions
+	if !full {
+		h.sessions++
+	}
+	h.mu.Unlock()
+	if full {
+		http.Error(w, "Too many players in browsers right now, try again later", http.StatusServiceUnavailable)
+		return
+	}
+	defer func() {
+		h.mu.Lock()
+		h.sessions--
+		h.mu.Unlock()
+	}()
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println("Browser client upgrade error:", err)
+		return
+	}
+	defer conn.Close()
+
+	keys, typed := io.Pipe()
+	p := tea.NewProgram(h.newSession(),
+		tea.WithInput(keys),
+		tea.WithOutput(&screen{conn: conn}),
+		tea.WithAltScreen(),
+		tea.WithoutSignalHandler(),
+	)
+
+	// The browser's keys and size go to the client until it closes the page
+	go func() {
+		defer p.Quit()
+		defer typed.Close()
+		for {
+			kind, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if kind == websocket.BinaryMessage {
+				if _, err := typed.Write(data); err != nil {
+					return // The client has stopped reading keys
+				}
+				continue
+			}
+			var size resize
+			if json.Unmarshal(data, &size) == nil && size.Cols > 0 && size.Rows > 0 {
+				p.Send(tea.WindowSizeMsg{Width: size.Cols, Height: size.Rows})
+			}
+		}
+	}()
+
+	final, err := p.Run()
+	keys.CloseWithError(io.EOF) // Unblocks the goroutine if it's still writing keys
+	if err != nil {
+		log.Printf("Browser client stopped: %v", err)
+	}
+	if s, ok := final.(session); ok {
+		s.Disconnect()
+	}
+}
+
+// newSession makes the client for a browser. Setting it up builds the UI's styles, so
+// it holds drawing too.
+func (h *handler) newSession() session {
+	drawing.Lock()
+	defer drawing.Unlock()
+
+	model := ui.NewModel("ws://memnet/ws")
+	model.SetHosted(h.game.DialContext)
+	cfg := config.Default()
+	cfg.Settings.Desktop = config.DesktopOff
+	cfg.Settings.Language = "en" // Not the server's locale
+	model.SetConfig("", cfg)     // Settings last as long as the tab
+	return session{model}
+}
+
+// drawing is held by whichever session's client is updating or drawing. The UI keeps
+// its theme, language, colors and caches in package variables, so the clients take
+// turns, each putting its own settings back first.
+var drawing sync.Mutex
+
+// session is one browser's client, taking its turn at the UI's shared state
+type session struct {
+	ui.Model
+}
+
+// use puts back the session's drawing settings: full color, as xterm.js shows it, and
+// the model's own. Call it holding drawing.
+func (s session) use() {
+	ui.SetColorProfile(termenv.TrueColor)
+	s.UseSettings()
+}
+
+func (s session) Init() tea.Cmd {
+	drawing.Lock()
+	defer drawing.Unlock()
+	s.use()
+	return s.Model.Init()
+}
+
+func (s session) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	drawing.Lock()
+	defer drawing.Unlock()
+	s.use()
+	next, cmd := s.Model.Update(msg)
+	if m, ok := next.(ui.Model); ok {
+		return session{m}, cmd
+	}
+	return next, cmd
+}
+
+func (s session) View() string {
+	drawing.Lock()
+	defer drawing.Unlock()
+	s.use()
+	return s.Model.View()
+}
+
+// screen writes what the client draws to the browser's terminal
+type screen struct {
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func (s *screen) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.conn.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}